@@ -65,6 +65,45 @@ func TestLookupFn(t *testing.T) {
 	})
 }
 
+func TestTemplateDefinitionsParamTypes(t *testing.T) {
+	for name, def := range AWSTemplatesDefinitions {
+		for key := range def.ParamTypes {
+			var found bool
+			for _, k := range append(def.Required(), def.Extra()...) {
+				if k == key {
+					found = true
+				}
+			}
+			if !found {
+				t.Fatalf("%s: typed param '%s' is neither required nor extra", name, key)
+			}
+		}
+	}
+
+	tcases := []struct {
+		def, key string
+		value    interface{}
+		valid    bool
+	}{
+		{"createvpc", "cidr", "10.0.0.0/16", true},
+		{"createvpc", "cidr", "10.0.0.0", false},
+		{"createinstance", "type", "t2.micro", true},
+		{"createinstance", "type", "t3.micro", true},
+		{"createinstance", "type", "m6i.large", true},
+		{"createinstance", "type", "t2.gigantic", false},
+		{"createinstance", "count", 0, false},
+		{"createlistener", "port", 80, true},
+		{"createlistener", "port", -80, false},
+		{"checkinstance", "timeout", "2m", true},
+	}
+	for i, tcase := range tcases {
+		_, err := AWSTemplatesDefinitions[tcase.def].ParamTypes[tcase.key].Check(tcase.value)
+		if got, want := err == nil, tcase.valid; got != want {
+			t.Fatalf("%d: %s %s=%v: got valid %t, want %t (%v)", i+1, tcase.def, tcase.key, tcase.value, got, want, err)
+		}
+	}
+}
+
 func TestDriver(t *testing.T) {
 	awsMock := &mockEc2{}
 	driv := NewEc2Driver(awsMock).(*Ec2Driver)
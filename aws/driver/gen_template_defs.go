@@ -186,6 +186,9 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "ec2",
 		RequiredParams: []string{"cidr"},
 		ExtraParams:    []string{"name"},
+		ParamTypes: map[string]template.ParamType{
+			"cidr": {Kind: "cidr"},
+		},
 	},
 	"deletevpc": {
 		Action:         "delete",
@@ -193,6 +196,9 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "ec2",
 		RequiredParams: []string{"id"},
 		ExtraParams:    []string{},
+		ParamTypes: map[string]template.ParamType{
+			"id": {Kind: "id", Of: "vpc", Prefix: "vpc-"},
+		},
 	},
 	"createsubnet": {
 		Action:         "create",
@@ -200,6 +206,10 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "ec2",
 		RequiredParams: []string{"cidr", "vpc"},
 		ExtraParams:    []string{"availabilityzone", "name"},
+		ParamTypes: map[string]template.ParamType{
+			"cidr": {Kind: "cidr"},
+			"vpc":  {Kind: "id", Of: "vpc", Prefix: "vpc-"},
+		},
 	},
 	"updatesubnet": {
 		Action:         "update",
@@ -207,6 +217,10 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "ec2",
 		RequiredParams: []string{"id"},
 		ExtraParams:    []string{"public"},
+		ParamTypes: map[string]template.ParamType{
+			"id":     {Kind: "id", Of: "subnet", Prefix: "subnet-"},
+			"public": {Kind: "bool"},
+		},
 	},
 	"deletesubnet": {
 		Action:         "delete",
@@ -214,6 +228,9 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "ec2",
 		RequiredParams: []string{"id"},
 		ExtraParams:    []string{},
+		ParamTypes: map[string]template.ParamType{
+			"id": {Kind: "id", Of: "subnet", Prefix: "subnet-"},
+		},
 	},
 	"createinstance": {
 		Action:         "create",
@@ -221,6 +238,14 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "ec2",
 		RequiredParams: []string{"count", "image", "name", "subnet", "type"},
 		ExtraParams:    []string{"ip", "keypair", "lock", "role", "securitygroup", "userdata"},
+		ParamTypes: map[string]template.ParamType{
+			"count":         {Kind: "int", Min: 1},
+			"image":         {Kind: "id", Of: "image", Prefix: "ami-"},
+			"lock":          {Kind: "bool"},
+			"securitygroup": {Kind: "id", Of: "securitygroup", Prefix: "sg-"},
+			"subnet":        {Kind: "id", Of: "subnet", Prefix: "subnet-"},
+			"type":          {Kind: "instancetype"},
+		},
 	},
 	"updateinstance": {
 		Action:         "update",
@@ -228,6 +253,11 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "ec2",
 		RequiredParams: []string{"id"},
 		ExtraParams:    []string{"lock", "type"},
+		ParamTypes: map[string]template.ParamType{
+			"id":   {Kind: "id", Of: "instance", Prefix: "i-"},
+			"lock": {Kind: "bool"},
+			"type": {Kind: "instancetype"},
+		},
 	},
 	"deleteinstance": {
 		Action:         "delete",
@@ -235,6 +265,9 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "ec2",
 		RequiredParams: []string{"id"},
 		ExtraParams:    []string{},
+		ParamTypes: map[string]template.ParamType{
+			"id": {Kind: "id", Of: "instance", Prefix: "i-"},
+		},
 	},
 	"startinstance": {
 		Action:         "start",
@@ -242,6 +275,9 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "ec2",
 		RequiredParams: []string{"id"},
 		ExtraParams:    []string{},
+		ParamTypes: map[string]template.ParamType{
+			"id": {Kind: "id", Of: "instance", Prefix: "i-"},
+		},
 	},
 	"stopinstance": {
 		Action:         "stop",
@@ -249,6 +285,9 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "ec2",
 		RequiredParams: []string{"id"},
 		ExtraParams:    []string{},
+		ParamTypes: map[string]template.ParamType{
+			"id": {Kind: "id", Of: "instance", Prefix: "i-"},
+		},
 	},
 	"checkinstance": {
 		Action:         "check",
@@ -256,6 +295,11 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "ec2",
 		RequiredParams: []string{"id", "state", "timeout"},
 		ExtraParams:    []string{},
+		ParamTypes: map[string]template.ParamType{
+			"id":      {Kind: "id", Of: "instance", Prefix: "i-"},
+			"state":   {Kind: "enum", Values: []string{"pending", "running", "shutting-down", "terminated", "stopping", "stopped", "not-found"}},
+			"timeout": {Kind: "duration"},
+		},
 	},
 	"attachinstanceprofile": {
 		Action:         "attach",
@@ -263,6 +307,9 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "ec2",
 		RequiredParams: []string{"instance", "name"},
 		ExtraParams:    []string{"replace"},
+		ParamTypes: map[string]template.ParamType{
+			"instance": {Kind: "id", Of: "instance", Prefix: "i-"},
+		},
 	},
 	"detachinstanceprofile": {
 		Action:         "detach",
@@ -270,6 +317,9 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "ec2",
 		RequiredParams: []string{"instance", "name"},
 		ExtraParams:    []string{"replace"},
+		ParamTypes: map[string]template.ParamType{
+			"instance": {Kind: "id", Of: "instance", Prefix: "i-"},
+		},
 	},
 	"createsecuritygroup": {
 		Action:         "create",
@@ -277,6 +327,9 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "ec2",
 		RequiredParams: []string{"description", "name", "vpc"},
 		ExtraParams:    []string{},
+		ParamTypes: map[string]template.ParamType{
+			"vpc": {Kind: "id", Of: "vpc", Prefix: "vpc-"},
+		},
 	},
 	"updatesecuritygroup": {
 		Action:         "update",
@@ -284,6 +337,11 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "ec2",
 		RequiredParams: []string{"id", "protocol"},
 		ExtraParams:    []string{"cidr", "inbound", "outbound", "portrange", "securitygroup"},
+		ParamTypes: map[string]template.ParamType{
+			"cidr":          {Kind: "cidr"},
+			"id":            {Kind: "id", Of: "securitygroup", Prefix: "sg-"},
+			"securitygroup": {Kind: "id", Of: "securitygroup", Prefix: "sg-"},
+		},
 	},
 	"deletesecuritygroup": {
 		Action:         "delete",
@@ -291,6 +349,9 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "ec2",
 		RequiredParams: []string{"id"},
 		ExtraParams:    []string{},
+		ParamTypes: map[string]template.ParamType{
+			"id": {Kind: "id", Of: "securitygroup", Prefix: "sg-"},
+		},
 	},
 	"checksecuritygroup": {
 		Action:         "check",
@@ -298,6 +359,11 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "ec2",
 		RequiredParams: []string{"id", "state", "timeout"},
 		ExtraParams:    []string{},
+		ParamTypes: map[string]template.ParamType{
+			"id":      {Kind: "id", Of: "securitygroup", Prefix: "sg-"},
+			"state":   {Kind: "enum", Values: []string{"unused"}},
+			"timeout": {Kind: "duration"},
+		},
 	},
 	"attachsecuritygroup": {
 		Action:         "attach",
@@ -305,6 +371,10 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "ec2",
 		RequiredParams: []string{"id"},
 		ExtraParams:    []string{"instance"},
+		ParamTypes: map[string]template.ParamType{
+			"id":       {Kind: "id", Of: "securitygroup", Prefix: "sg-"},
+			"instance": {Kind: "id", Of: "instance", Prefix: "i-"},
+		},
 	},
 	"detachsecuritygroup": {
 		Action:         "detach",
@@ -312,6 +382,10 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "ec2",
 		RequiredParams: []string{"id"},
 		ExtraParams:    []string{"instance"},
+		ParamTypes: map[string]template.ParamType{
+			"id":       {Kind: "id", Of: "securitygroup", Prefix: "sg-"},
+			"instance": {Kind: "id", Of: "instance", Prefix: "i-"},
+		},
 	},
 	"copyimage": {
 		Action:         "copy",
@@ -319,6 +393,9 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "ec2",
 		RequiredParams: []string{"name", "source-id", "source-region"},
 		ExtraParams:    []string{"description", "encrypted"},
+		ParamTypes: map[string]template.ParamType{
+			"encrypted": {Kind: "bool"},
+		},
 	},
 	"importimage": {
 		Action:         "import",
@@ -326,6 +403,9 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "ec2",
 		RequiredParams: []string{},
 		ExtraParams:    []string{"architecture", "bucket", "description", "license", "platform", "role", "s3object", "snapshot", "url"},
+		ParamTypes: map[string]template.ParamType{
+			"snapshot": {Kind: "id", Of: "snapshot", Prefix: "snap-"},
+		},
 	},
 	"deleteimage": {
 		Action:         "delete",
@@ -333,6 +413,9 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "ec2",
 		RequiredParams: []string{"delete-snapshots", "id"},
 		ExtraParams:    []string{},
+		ParamTypes: map[string]template.ParamType{
+			"id": {Kind: "id", Of: "image", Prefix: "ami-"},
+		},
 	},
 	"createnetworkinterface": {
 		Action:         "create",
@@ -340,6 +423,10 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "ec2",
 		RequiredParams: []string{"subnet"},
 		ExtraParams:    []string{"description", "privateip", "securitygroups"},
+		ParamTypes: map[string]template.ParamType{
+			"securitygroups": {Kind: "id", Of: "securitygroup", Prefix: "sg-"},
+			"subnet":         {Kind: "id", Of: "subnet", Prefix: "subnet-"},
+		},
 	},
 	"deletenetworkinterface": {
 		Action:         "delete",
@@ -347,6 +434,9 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "ec2",
 		RequiredParams: []string{"id"},
 		ExtraParams:    []string{},
+		ParamTypes: map[string]template.ParamType{
+			"id": {Kind: "id", Of: "networkinterface", Prefix: "eni-"},
+		},
 	},
 	"attachnetworkinterface": {
 		Action:         "attach",
@@ -354,6 +444,11 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "ec2",
 		RequiredParams: []string{"device-index", "id", "instance"},
 		ExtraParams:    []string{},
+		ParamTypes: map[string]template.ParamType{
+			"device-index": {Kind: "int"},
+			"id":           {Kind: "id", Of: "networkinterface", Prefix: "eni-"},
+			"instance":     {Kind: "id", Of: "instance", Prefix: "i-"},
+		},
 	},
 	"detachnetworkinterface": {
 		Action:         "detach",
@@ -361,6 +456,11 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "ec2",
 		RequiredParams: []string{},
 		ExtraParams:    []string{"attachment", "force", "id", "instance"},
+		ParamTypes: map[string]template.ParamType{
+			"force":    {Kind: "bool"},
+			"id":       {Kind: "id", Of: "networkinterface", Prefix: "eni-"},
+			"instance": {Kind: "id", Of: "instance", Prefix: "i-"},
+		},
 	},
 	"checknetworkinterface": {
 		Action:         "check",
@@ -368,6 +468,11 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "ec2",
 		RequiredParams: []string{"id", "state", "timeout"},
 		ExtraParams:    []string{},
+		ParamTypes: map[string]template.ParamType{
+			"id":      {Kind: "id", Of: "networkinterface", Prefix: "eni-"},
+			"state":   {Kind: "enum", Values: []string{"available", "attaching", "detaching", "in-use", "not-found"}},
+			"timeout": {Kind: "duration"},
+		},
 	},
 	"createvolume": {
 		Action:         "create",
@@ -375,6 +480,9 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "ec2",
 		RequiredParams: []string{"availabilityzone", "size"},
 		ExtraParams:    []string{},
+		ParamTypes: map[string]template.ParamType{
+			"size": {Kind: "int", Min: 1, Max: 16384},
+		},
 	},
 	"checkvolume": {
 		Action:         "check",
@@ -382,6 +490,11 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "ec2",
 		RequiredParams: []string{"id", "state", "timeout"},
 		ExtraParams:    []string{},
+		ParamTypes: map[string]template.ParamType{
+			"id":      {Kind: "id", Of: "volume", Prefix: "vol-"},
+			"state":   {Kind: "enum", Values: []string{"available", "in-use", "not-found"}},
+			"timeout": {Kind: "duration"},
+		},
 	},
 	"deletevolume": {
 		Action:         "delete",
@@ -389,6 +502,9 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "ec2",
 		RequiredParams: []string{"id"},
 		ExtraParams:    []string{},
+		ParamTypes: map[string]template.ParamType{
+			"id": {Kind: "id", Of: "volume", Prefix: "vol-"},
+		},
 	},
 	"attachvolume": {
 		Action:         "attach",
@@ -396,6 +512,10 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "ec2",
 		RequiredParams: []string{"device", "id", "instance"},
 		ExtraParams:    []string{},
+		ParamTypes: map[string]template.ParamType{
+			"id":       {Kind: "id", Of: "volume", Prefix: "vol-"},
+			"instance": {Kind: "id", Of: "instance", Prefix: "i-"},
+		},
 	},
 	"detachvolume": {
 		Action:         "detach",
@@ -403,6 +523,11 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "ec2",
 		RequiredParams: []string{"device", "id", "instance"},
 		ExtraParams:    []string{"force"},
+		ParamTypes: map[string]template.ParamType{
+			"force":    {Kind: "bool"},
+			"id":       {Kind: "id", Of: "volume", Prefix: "vol-"},
+			"instance": {Kind: "id", Of: "instance", Prefix: "i-"},
+		},
 	},
 	"createsnapshot": {
 		Action:         "create",
@@ -410,6 +535,9 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "ec2",
 		RequiredParams: []string{"volume"},
 		ExtraParams:    []string{"description"},
+		ParamTypes: map[string]template.ParamType{
+			"volume": {Kind: "id", Of: "volume", Prefix: "vol-"},
+		},
 	},
 	"deletesnapshot": {
 		Action:         "delete",
@@ -417,6 +545,9 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "ec2",
 		RequiredParams: []string{"id"},
 		ExtraParams:    []string{},
+		ParamTypes: map[string]template.ParamType{
+			"id": {Kind: "id", Of: "snapshot", Prefix: "snap-"},
+		},
 	},
 	"copysnapshot": {
 		Action:         "copy",
@@ -424,6 +555,9 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "ec2",
 		RequiredParams: []string{"source-id", "source-region"},
 		ExtraParams:    []string{"description", "encrypted"},
+		ParamTypes: map[string]template.ParamType{
+			"encrypted": {Kind: "bool"},
+		},
 	},
 	"createinternetgateway": {
 		Action:         "create",
@@ -438,6 +572,9 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "ec2",
 		RequiredParams: []string{"id"},
 		ExtraParams:    []string{},
+		ParamTypes: map[string]template.ParamType{
+			"id": {Kind: "id", Of: "internetgateway", Prefix: "igw-"},
+		},
 	},
 	"attachinternetgateway": {
 		Action:         "attach",
@@ -445,6 +582,10 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "ec2",
 		RequiredParams: []string{"id", "vpc"},
 		ExtraParams:    []string{},
+		ParamTypes: map[string]template.ParamType{
+			"id":  {Kind: "id", Of: "internetgateway", Prefix: "igw-"},
+			"vpc": {Kind: "id", Of: "vpc", Prefix: "vpc-"},
+		},
 	},
 	"detachinternetgateway": {
 		Action:         "detach",
@@ -452,6 +593,10 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "ec2",
 		RequiredParams: []string{"id", "vpc"},
 		ExtraParams:    []string{},
+		ParamTypes: map[string]template.ParamType{
+			"id":  {Kind: "id", Of: "internetgateway", Prefix: "igw-"},
+			"vpc": {Kind: "id", Of: "vpc", Prefix: "vpc-"},
+		},
 	},
 	"createnatgateway": {
 		Action:         "create",
@@ -459,6 +604,9 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "ec2",
 		RequiredParams: []string{"elasticip-id", "subnet"},
 		ExtraParams:    []string{},
+		ParamTypes: map[string]template.ParamType{
+			"subnet": {Kind: "id", Of: "subnet", Prefix: "subnet-"},
+		},
 	},
 	"deletenatgateway": {
 		Action:         "delete",
@@ -466,6 +614,9 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "ec2",
 		RequiredParams: []string{"id"},
 		ExtraParams:    []string{},
+		ParamTypes: map[string]template.ParamType{
+			"id": {Kind: "id", Of: "natgateway", Prefix: "nat-"},
+		},
 	},
 	"checknatgateway": {
 		Action:         "check",
@@ -473,6 +624,11 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "ec2",
 		RequiredParams: []string{"id", "state", "timeout"},
 		ExtraParams:    []string{},
+		ParamTypes: map[string]template.ParamType{
+			"id":      {Kind: "id", Of: "natgateway", Prefix: "nat-"},
+			"state":   {Kind: "enum", Values: []string{"pending", "failed", "available", "deleting", "deleted", "not-found"}},
+			"timeout": {Kind: "duration"},
+		},
 	},
 	"createroutetable": {
 		Action:         "create",
@@ -480,6 +636,9 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "ec2",
 		RequiredParams: []string{"vpc"},
 		ExtraParams:    []string{},
+		ParamTypes: map[string]template.ParamType{
+			"vpc": {Kind: "id", Of: "vpc", Prefix: "vpc-"},
+		},
 	},
 	"deleteroutetable": {
 		Action:         "delete",
@@ -487,6 +646,9 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "ec2",
 		RequiredParams: []string{"id"},
 		ExtraParams:    []string{},
+		ParamTypes: map[string]template.ParamType{
+			"id": {Kind: "id", Of: "routetable", Prefix: "rtb-"},
+		},
 	},
 	"attachroutetable": {
 		Action:         "attach",
@@ -494,6 +656,10 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "ec2",
 		RequiredParams: []string{"id", "subnet"},
 		ExtraParams:    []string{},
+		ParamTypes: map[string]template.ParamType{
+			"id":     {Kind: "id", Of: "routetable", Prefix: "rtb-"},
+			"subnet": {Kind: "id", Of: "subnet", Prefix: "subnet-"},
+		},
 	},
 	"detachroutetable": {
 		Action:         "detach",
@@ -508,6 +674,9 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "ec2",
 		RequiredParams: []string{"cidr", "gateway", "table"},
 		ExtraParams:    []string{},
+		ParamTypes: map[string]template.ParamType{
+			"cidr": {Kind: "cidr"},
+		},
 	},
 	"deleteroute": {
 		Action:         "delete",
@@ -515,6 +684,9 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "ec2",
 		RequiredParams: []string{"cidr", "table"},
 		ExtraParams:    []string{},
+		ParamTypes: map[string]template.ParamType{
+			"cidr": {Kind: "cidr"},
+		},
 	},
 	"createtag": {
 		Action:         "create",
@@ -564,6 +736,11 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "ec2",
 		RequiredParams: []string{"id"},
 		ExtraParams:    []string{"allow-reassociation", "instance", "networkinterface", "privateip"},
+		ParamTypes: map[string]template.ParamType{
+			"allow-reassociation": {Kind: "bool"},
+			"instance":            {Kind: "id", Of: "instance", Prefix: "i-"},
+			"networkinterface":    {Kind: "id", Of: "networkinterface", Prefix: "eni-"},
+		},
 	},
 	"detachelasticip": {
 		Action:         "detach",
@@ -578,6 +755,10 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "elbv2",
 		RequiredParams: []string{"name", "subnets"},
 		ExtraParams:    []string{"iptype", "scheme", "securitygroups", "subnet-mappings", "type"},
+		ParamTypes: map[string]template.ParamType{
+			"securitygroups": {Kind: "id", Of: "securitygroup", Prefix: "sg-"},
+			"subnets":        {Kind: "id", Of: "subnet", Prefix: "subnet-"},
+		},
 	},
 	"deleteloadbalancer": {
 		Action:         "delete",
@@ -592,6 +773,10 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "elbv2",
 		RequiredParams: []string{"id", "state", "timeout"},
 		ExtraParams:    []string{},
+		ParamTypes: map[string]template.ParamType{
+			"state":   {Kind: "enum", Values: []string{"provisioning", "active", "failed", "not-found"}},
+			"timeout": {Kind: "duration"},
+		},
 	},
 	"createlistener": {
 		Action:         "create",
@@ -599,6 +784,10 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "elbv2",
 		RequiredParams: []string{"actiontype", "loadbalancer", "port", "protocol", "targetgroup"},
 		ExtraParams:    []string{"certificate", "sslpolicy"},
+		ParamTypes: map[string]template.ParamType{
+			"port":     {Kind: "int", Max: 65535},
+			"protocol": {Kind: "enum", Values: []string{"HTTP", "HTTPS", "TCP"}},
+		},
 	},
 	"deletelistener": {
 		Action:         "delete",
@@ -613,6 +802,16 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "elbv2",
 		RequiredParams: []string{"name", "port", "protocol", "vpc"},
 		ExtraParams:    []string{"healthcheckinterval", "healthcheckpath", "healthcheckport", "healthcheckprotocol", "healthchecktimeout", "healthythreshold", "matcher", "unhealthythreshold"},
+		ParamTypes: map[string]template.ParamType{
+			"healthcheckinterval": {Kind: "int"},
+			"healthcheckprotocol": {Kind: "enum", Values: []string{"HTTP", "HTTPS", "TCP"}},
+			"healthchecktimeout":  {Kind: "int"},
+			"healthythreshold":    {Kind: "int"},
+			"port":                {Kind: "int", Max: 65535},
+			"protocol":            {Kind: "enum", Values: []string{"HTTP", "HTTPS", "TCP"}},
+			"unhealthythreshold":  {Kind: "int"},
+			"vpc":                 {Kind: "id", Of: "vpc", Prefix: "vpc-"},
+		},
 	},
 	"updatetargetgroup": {
 		Action:         "update",
@@ -620,6 +819,13 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "elbv2",
 		RequiredParams: []string{"id"},
 		ExtraParams:    []string{"deregistrationdelay", "healthcheckinterval", "healthcheckpath", "healthcheckport", "healthcheckprotocol", "healthchecktimeout", "healthythreshold", "matcher", "stickiness", "stickinessduration", "unhealthythreshold"},
+		ParamTypes: map[string]template.ParamType{
+			"healthcheckinterval": {Kind: "int"},
+			"healthcheckprotocol": {Kind: "enum", Values: []string{"HTTP", "HTTPS", "TCP"}},
+			"healthchecktimeout":  {Kind: "int"},
+			"healthythreshold":    {Kind: "int"},
+			"unhealthythreshold":  {Kind: "int"},
+		},
 	},
 	"deletetargetgroup": {
 		Action:         "delete",
@@ -634,6 +840,10 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "elbv2",
 		RequiredParams: []string{"id", "targetgroup"},
 		ExtraParams:    []string{"port"},
+		ParamTypes: map[string]template.ParamType{
+			"id":   {Kind: "id", Of: "instance", Prefix: "i-"},
+			"port": {Kind: "int", Max: 65535},
+		},
 	},
 	"detachinstance": {
 		Action:         "detach",
@@ -641,6 +851,9 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "elbv2",
 		RequiredParams: []string{"id", "targetgroup"},
 		ExtraParams:    []string{},
+		ParamTypes: map[string]template.ParamType{
+			"id": {Kind: "id", Of: "instance", Prefix: "i-"},
+		},
 	},
 	"createlaunchconfiguration": {
 		Action:         "create",
@@ -648,6 +861,12 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "autoscaling",
 		RequiredParams: []string{"image", "name", "type"},
 		ExtraParams:    []string{"keypair", "public", "role", "securitygroups", "spotprice", "userdata"},
+		ParamTypes: map[string]template.ParamType{
+			"image":          {Kind: "id", Of: "image", Prefix: "ami-"},
+			"public":         {Kind: "bool"},
+			"securitygroups": {Kind: "id", Of: "securitygroup", Prefix: "sg-"},
+			"type":           {Kind: "instancetype"},
+		},
 	},
 	"deletelaunchconfiguration": {
 		Action:         "delete",
@@ -662,6 +881,15 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "autoscaling",
 		RequiredParams: []string{"launchconfiguration", "max-size", "min-size", "name", "subnets"},
 		ExtraParams:    []string{"cooldown", "desired-capacity", "healthcheck-grace-period", "healthcheck-type", "new-instances-protected", "targetgroups"},
+		ParamTypes: map[string]template.ParamType{
+			"cooldown":                 {Kind: "int"},
+			"desired-capacity":         {Kind: "int"},
+			"healthcheck-grace-period": {Kind: "int"},
+			"max-size":                 {Kind: "int"},
+			"min-size":                 {Kind: "int"},
+			"new-instances-protected":  {Kind: "bool"},
+			"subnets":                  {Kind: "id", Of: "subnet", Prefix: "subnet-"},
+		},
 	},
 	"updatescalinggroup": {
 		Action:         "update",
@@ -669,6 +897,15 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "autoscaling",
 		RequiredParams: []string{"name"},
 		ExtraParams:    []string{"cooldown", "desired-capacity", "healthcheck-grace-period", "healthcheck-type", "launchconfiguration", "max-size", "min-size", "new-instances-protected", "subnets"},
+		ParamTypes: map[string]template.ParamType{
+			"cooldown":                 {Kind: "int"},
+			"desired-capacity":         {Kind: "int"},
+			"healthcheck-grace-period": {Kind: "int"},
+			"max-size":                 {Kind: "int"},
+			"min-size":                 {Kind: "int"},
+			"new-instances-protected":  {Kind: "bool"},
+			"subnets":                  {Kind: "id", Of: "subnet", Prefix: "subnet-"},
+		},
 	},
 	"deletescalinggroup": {
 		Action:         "delete",
@@ -676,6 +913,9 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "autoscaling",
 		RequiredParams: []string{"name"},
 		ExtraParams:    []string{"force"},
+		ParamTypes: map[string]template.ParamType{
+			"force": {Kind: "bool"},
+		},
 	},
 	"checkscalinggroup": {
 		Action:         "check",
@@ -683,6 +923,10 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "autoscaling",
 		RequiredParams: []string{"count", "name", "timeout"},
 		ExtraParams:    []string{},
+		ParamTypes: map[string]template.ParamType{
+			"count":   {Kind: "int", Min: 1},
+			"timeout": {Kind: "duration"},
+		},
 	},
 	"createscalingpolicy": {
 		Action:         "create",
@@ -690,6 +934,11 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "autoscaling",
 		RequiredParams: []string{"adjustment-scaling", "adjustment-type", "name", "scalinggroup"},
 		ExtraParams:    []string{"adjustment-magnitude", "cooldown"},
+		ParamTypes: map[string]template.ParamType{
+			"adjustment-magnitude": {Kind: "int"},
+			"adjustment-scaling":   {Kind: "int"},
+			"cooldown":             {Kind: "int"},
+		},
 	},
 	"deletescalingpolicy": {
 		Action:         "delete",
@@ -704,6 +953,17 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "rds",
 		RequiredParams: []string{"engine", "id", "password", "size", "type", "username"},
 		ExtraParams:    []string{"autoupgrade", "availabilityzone", "backupretention", "backupwindow", "cluster", "dbname", "dbsecuritygroups", "domain", "encrypted", "iamrole", "iops", "license", "maintenancewindow", "multiaz", "optiongroup", "parametergroup", "port", "public", "storagetype", "subnetgroup", "timezone", "version", "vpcsecuritygroups"},
+		ParamTypes: map[string]template.ParamType{
+			"autoupgrade":       {Kind: "bool"},
+			"backupretention":   {Kind: "int"},
+			"encrypted":         {Kind: "bool"},
+			"iops":              {Kind: "int"},
+			"multiaz":           {Kind: "bool"},
+			"port":              {Kind: "int", Max: 65535},
+			"public":            {Kind: "bool"},
+			"size":              {Kind: "int"},
+			"vpcsecuritygroups": {Kind: "id", Of: "securitygroup", Prefix: "sg-"},
+		},
 	},
 	"deletedatabase": {
 		Action:         "delete",
@@ -711,6 +971,10 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "rds",
 		RequiredParams: []string{"id"},
 		ExtraParams:    []string{"skip-snapshot", "snapshot"},
+		ParamTypes: map[string]template.ParamType{
+			"skip-snapshot": {Kind: "bool"},
+			"snapshot":      {Kind: "bool"},
+		},
 	},
 	"checkdatabase": {
 		Action:         "check",
@@ -718,6 +982,10 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "rds",
 		RequiredParams: []string{"id", "state", "timeout"},
 		ExtraParams:    []string{},
+		ParamTypes: map[string]template.ParamType{
+			"state":   {Kind: "enum", Values: []string{"available", "backing-up", "creating", "deleting", "failed", "maintenance", "modifying", "rebooting", "renaming", "resetting-master-credentials", "restore-error", "storage-full", "upgrading", "not-found"}},
+			"timeout": {Kind: "duration"},
+		},
 	},
 	"createdbsubnetgroup": {
 		Action:         "create",
@@ -725,6 +993,9 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "rds",
 		RequiredParams: []string{"description", "name", "subnets"},
 		ExtraParams:    []string{},
+		ParamTypes: map[string]template.ParamType{
+			"subnets": {Kind: "id", Of: "subnet", Prefix: "subnet-"},
+		},
 	},
 	"deletedbsubnetgroup": {
 		Action:         "delete",
@@ -746,6 +1017,9 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "ecr",
 		RequiredParams: []string{"name"},
 		ExtraParams:    []string{"account", "force"},
+		ParamTypes: map[string]template.ParamType{
+			"force": {Kind: "bool"},
+		},
 	},
 	"authenticateregistry": {
 		Action:         "authenticate",
@@ -788,6 +1062,9 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "ecs",
 		RequiredParams: []string{"cluster", "deployment-name"},
 		ExtraParams:    []string{"desired-count", "name"},
+		ParamTypes: map[string]template.ParamType{
+			"desired-count": {Kind: "int"},
+		},
 	},
 	"attachcontainertask": {
 		Action:         "attach",
@@ -823,6 +1100,9 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "acm",
 		RequiredParams: []string{"arn"},
 		ExtraParams:    []string{},
+		ParamTypes: map[string]template.ParamType{
+			"arn": {Kind: "arn"},
+		},
 	},
 	"checkcertificate": {
 		Action:         "check",
@@ -830,6 +1110,11 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "acm",
 		RequiredParams: []string{"arn", "state", "timeout"},
 		ExtraParams:    []string{},
+		ParamTypes: map[string]template.ParamType{
+			"arn":     {Kind: "arn"},
+			"state":   {Kind: "enum", Values: []string{"issued", "pending_validation", "not-found"}},
+			"timeout": {Kind: "duration"},
+		},
 	},
 	"createuser": {
 		Action:         "create",
@@ -879,6 +1164,9 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "iam",
 		RequiredParams: []string{"password", "username"},
 		ExtraParams:    []string{"password-reset"},
+		ParamTypes: map[string]template.ParamType{
+			"password-reset": {Kind: "bool"},
+		},
 	},
 	"updateloginprofile": {
 		Action:         "update",
@@ -886,6 +1174,9 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "iam",
 		RequiredParams: []string{"password", "username"},
 		ExtraParams:    []string{"password-reset"},
+		ParamTypes: map[string]template.ParamType{
+			"password-reset": {Kind: "bool"},
+		},
 	},
 	"deleteloginprofile": {
 		Action:         "delete",
@@ -963,6 +1254,9 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "iam",
 		RequiredParams: []string{"action", "arn", "effect", "resource"},
 		ExtraParams:    []string{"conditions"},
+		ParamTypes: map[string]template.ParamType{
+			"arn": {Kind: "arn"},
+		},
 	},
 	"deletepolicy": {
 		Action:         "delete",
@@ -970,6 +1264,9 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "iam",
 		RequiredParams: []string{"arn"},
 		ExtraParams:    []string{"all-versions"},
+		ParamTypes: map[string]template.ParamType{
+			"arn": {Kind: "arn"},
+		},
 	},
 	"attachpolicy": {
 		Action:         "attach",
@@ -977,6 +1274,9 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "iam",
 		RequiredParams: []string{},
 		ExtraParams:    []string{"access", "arn", "group", "role", "service", "user"},
+		ParamTypes: map[string]template.ParamType{
+			"arn": {Kind: "arn"},
+		},
 	},
 	"detachpolicy": {
 		Action:         "detach",
@@ -984,6 +1284,9 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "iam",
 		RequiredParams: []string{},
 		ExtraParams:    []string{"access", "arn", "group", "role", "service", "user"},
+		ParamTypes: map[string]template.ParamType{
+			"arn": {Kind: "arn"},
+		},
 	},
 	"createmfadevice": {
 		Action:         "create",
@@ -1103,6 +1406,10 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "route53",
 		RequiredParams: []string{"callerreference", "name"},
 		ExtraParams:    []string{"comment", "delegationsetid", "isprivate", "vpcid", "vpcregion"},
+		ParamTypes: map[string]template.ParamType{
+			"isprivate": {Kind: "bool"},
+			"vpcid":     {Kind: "id", Of: "vpc", Prefix: "vpc-"},
+		},
 	},
 	"deletezone": {
 		Action:         "delete",
@@ -1138,6 +1445,11 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "lambda",
 		RequiredParams: []string{"handler", "name", "role", "runtime"},
 		ExtraParams:    []string{"bucket", "description", "memory", "object", "objectversion", "publish", "timeout", "zipfile"},
+		ParamTypes: map[string]template.ParamType{
+			"memory":  {Kind: "int"},
+			"publish": {Kind: "bool"},
+			"timeout": {Kind: "int"},
+		},
 	},
 	"deletefunction": {
 		Action:         "delete",
@@ -1152,6 +1464,11 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "cloudwatch",
 		RequiredParams: []string{"evaluation-periods", "metric", "name", "namespace", "operator", "period", "statistic-function", "threshold"},
 		ExtraParams:    []string{"alarm-actions", "description", "dimensions", "enabled", "insufficientdata-actions", "ok-actions", "unit"},
+		ParamTypes: map[string]template.ParamType{
+			"enabled":            {Kind: "bool"},
+			"evaluation-periods": {Kind: "int"},
+			"period":             {Kind: "int"},
+		},
 	},
 	"deletealarm": {
 		Action:         "delete",
@@ -1201,6 +1518,10 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "cloudfront",
 		RequiredParams: []string{"id", "state", "timeout"},
 		ExtraParams:    []string{},
+		ParamTypes: map[string]template.ParamType{
+			"state":   {Kind: "enum", Values: []string{"deployed", "inprogress", "not-found"}},
+			"timeout": {Kind: "duration"},
+		},
 	},
 	"updatedistribution": {
 		Action:         "update",
@@ -1208,6 +1529,9 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "cloudfront",
 		RequiredParams: []string{"enable", "id"},
 		ExtraParams:    []string{},
+		ParamTypes: map[string]template.ParamType{
+			"enable": {Kind: "bool"},
+		},
 	},
 	"deletedistribution": {
 		Action:         "delete",
@@ -1222,6 +1546,10 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "cloudformation",
 		RequiredParams: []string{"name", "template-file"},
 		ExtraParams:    []string{"capabilities", "disable-rollback", "notifications", "on-failure", "parameters", "policy-file", "resource-types", "role", "timeout"},
+		ParamTypes: map[string]template.ParamType{
+			"disable-rollback": {Kind: "bool"},
+			"timeout":          {Kind: "int"},
+		},
 	},
 	"updatestack": {
 		Action:         "update",
@@ -1229,6 +1557,9 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "cloudformation",
 		RequiredParams: []string{"name"},
 		ExtraParams:    []string{"capabilities", "notifications", "parameters", "policy-file", "policy-update-file", "resource-types", "role", "template-file", "use-previous-template"},
+		ParamTypes: map[string]template.ParamType{
+			"use-previous-template": {Kind: "bool"},
+		},
 	},
 	"deletestack": {
 		Action:         "delete",
@@ -1243,6 +1574,10 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "applicationautoscaling",
 		RequiredParams: []string{"dimension", "max-capacity", "min-capacity", "resource", "role", "service-namespace"},
 		ExtraParams:    []string{},
+		ParamTypes: map[string]template.ParamType{
+			"max-capacity": {Kind: "int"},
+			"min-capacity": {Kind: "int"},
+		},
 	},
 	"deleteappscalingtarget": {
 		Action:         "delete",
@@ -1257,6 +1592,10 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 		Api:            "applicationautoscaling",
 		RequiredParams: []string{"dimension", "name", "resource", "service-namespace", "stepscaling-adjustment-type", "stepscaling-adjustments", "type"},
 		ExtraParams:    []string{"stepscaling-aggregation-type", "stepscaling-cooldown", "stepscaling-min-adjustment-magnitude"},
+		ParamTypes: map[string]template.ParamType{
+			"stepscaling-cooldown":                 {Kind: "int"},
+			"stepscaling-min-adjustment-magnitude": {Kind: "int"},
+		},
 	},
 	"deleteappscalingpolicy": {
 		Action:         "delete",
@@ -0,0 +1,165 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package awsexport
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/wallix/awless/template"
+)
+
+// toCloudFormation renders resources as a CloudFormation YAML stack. Resources are retained
+// on stack deletion, as required to import them: the identifiers to import are listed at the end.
+func toCloudFormation(tplExec *template.TemplateExecution, resources []*resource, skipped []string) []byte {
+	var buff bytes.Buffer
+
+	fmt.Fprintf(&buff, "# Generated by awless from template %s\n", tplExec.ID)
+	writeSkipped(&buff, skipped)
+	buff.WriteString("AWSTemplateFormatVersion: \"2010-09-09\"\n")
+	fmt.Fprintf(&buff, "Description: %s\n", strconv.Quote(description(tplExec)))
+	buff.WriteString("Resources:\n")
+	var imports []string
+	for _, res := range resources {
+		writeCfnResource(&buff, res.cfnName, res.mapping.cfnType, cfnProperties(res))
+		imports = append(imports, fmt.Sprintf("{\"ResourceType\": %q, \"LogicalResourceId\": %q, \"ResourceIdentifier\": {%q: %q}}",
+			res.mapping.cfnType, res.cfnName, res.mapping.cfnID, res.importID))
+		if res.mapping.cfnAttachment != "" && res.attachedTo != "" {
+			name := res.cfnName + "Attachment"
+			writeCfnResource(&buff, name, res.mapping.cfnAttachment, []property{
+				{name: res.mapping.cfnID, value: ref{res: res}}, {name: "VpcId", value: res.params["vpc"]},
+			})
+			imports = append(imports, fmt.Sprintf("{\"ResourceType\": %q, \"LogicalResourceId\": %q, \"ResourceIdentifier\": {\"AttachmentType\": \"IGW\", \"VpcId\": %q}}",
+				res.mapping.cfnAttachment, name, res.attachedTo))
+		}
+	}
+
+	buff.WriteString("\n# Import the existing resources with a change set of type IMPORT, i.e.:\n")
+	buff.WriteString("#   aws cloudformation create-change-set --change-set-type IMPORT --resources-to-import file://resources.json ...\n")
+	buff.WriteString("# where resources.json contains:\n# [\n")
+	for i, imp := range imports {
+		sep := ","
+		if i == len(imports)-1 {
+			sep = ""
+		}
+		fmt.Fprintf(&buff, "#   %s%s\n", imp, sep)
+	}
+	buff.WriteString("# ]\n")
+
+	return buff.Bytes()
+}
+
+func writeCfnResource(buff *bytes.Buffer, name, cfnType string, props []property) {
+	fmt.Fprintf(buff, "  %s:\n", name)
+	fmt.Fprintf(buff, "    Type: %s\n", cfnType)
+	buff.WriteString("    DeletionPolicy: Retain\n")
+	if len(props) > 0 {
+		buff.WriteString("    Properties:\n")
+		for _, p := range props {
+			writeYAMLProperty(buff, 6, p)
+		}
+	}
+}
+
+func cfnProperties(res *resource) (props []property) {
+	for _, f := range res.mapping.fields {
+		if v, ok := res.params[f.param]; ok && f.cfn != "" {
+			props = append(props, property{name: f.cfn, value: v})
+		}
+	}
+	if res.mapping.cfnExtra != nil {
+		props = append(props, res.mapping.cfnExtra(res.params)...)
+	}
+	if name, ok := res.params[res.mapping.nameTag]; ok {
+		props = append(props, property{name: "Tags", value: []interface{}{
+			[]property{{name: "Key", value: "Name"}, {name: "Value", value: name}},
+		}})
+	}
+	return
+}
+
+func writeYAMLProperty(buff *bytes.Buffer, indent int, p property) {
+	pad := strings.Repeat(" ", indent)
+	switch v := p.value.(type) {
+	case []property:
+		fmt.Fprintf(buff, "%s%s:\n", pad, p.name)
+		for _, nested := range v {
+			writeYAMLProperty(buff, indent+2, nested)
+		}
+	case []interface{}:
+		if len(v) == 0 {
+			fmt.Fprintf(buff, "%s%s: []\n", pad, p.name)
+			return
+		}
+		fmt.Fprintf(buff, "%s%s:\n", pad, p.name)
+		for _, elem := range v {
+			nested, isObject := elem.([]property)
+			if !isObject {
+				fmt.Fprintf(buff, "%s  - %s\n", pad, yamlScalar(elem))
+				continue
+			}
+			for i, n := range nested {
+				var itemBuff bytes.Buffer
+				writeYAMLProperty(&itemBuff, indent+4, n)
+				item := itemBuff.String()
+				if i == 0 {
+					item = pad + "  - " + item[indent+4:]
+				}
+				buff.WriteString(item)
+			}
+		}
+	default:
+		fmt.Fprintf(buff, "%s%s: %s\n", pad, p.name, yamlScalar(v))
+	}
+}
+
+func yamlScalar(v interface{}) string {
+	switch vv := v.(type) {
+	case ref:
+		if vv.cfnAttr != "" {
+			return fmt.Sprintf("!GetAtt %s.%s", vv.res.cfnName, vv.cfnAttr)
+		}
+		return "!Ref " + vv.res.cfnName
+	case jsonValue:
+		return vv.String()
+	case string:
+		return strconv.Quote(vv)
+	case nil:
+		return "null"
+	default:
+		return fmt.Sprint(vv)
+	}
+}
+
+func writeSkipped(buff *bytes.Buffer, skipped []string) {
+	if len(skipped) == 0 {
+		return
+	}
+	buff.WriteString("# Commands not exported (only successful creations of supported resources and their attachments are):\n")
+	for _, cmd := range skipped {
+		fmt.Fprintf(buff, "#   skipped: %s\n", cmd)
+	}
+}
+
+func description(tplExec *template.TemplateExecution) string {
+	if tplExec.Message != "" {
+		return tplExec.Message
+	}
+	return fmt.Sprintf("Resources created by awless template %s", tplExec.ID)
+}
@@ -0,0 +1,489 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package awsexport
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/wallix/awless/template"
+)
+
+const (
+	CloudFormation = "cloudformation"
+	Terraform      = "terraform"
+)
+
+// Formats are the supported export formats
+var Formats = []string{CloudFormation, Terraform}
+
+// Export turns the successfully executed create commands of a template execution into
+// an equivalent CloudFormation stack (YAML) or Terraform configuration (HCL). Exported
+// resources reference each other and come with what is needed to import the existing ones.
+// Resources created by reverted commands (1-based indexes) or deleted since, by the execution
+// itself or by the later ones, no longer exist and are not exported.
+func Export(tplExec *template.TemplateExecution, format string, reverted map[int]bool, later ...*template.TemplateExecution) ([]byte, error) {
+	if tplExec.RevertStatus() == template.RevertedStatus {
+		return nil, fmt.Errorf("export: template %s has been reverted by %s: nothing to export", tplExec.ID, tplExec.RevertedBy)
+	}
+	resources, skipped := exportedResources(tplExec, reverted, later)
+	if len(resources) == 0 {
+		return nil, fmt.Errorf("export: no resource created by template %s can be exported", tplExec.ID)
+	}
+
+	switch format {
+	case CloudFormation:
+		return toCloudFormation(tplExec, resources, skipped), nil
+	case Terraform:
+		return toTerraform(tplExec, resources, skipped), nil
+	default:
+		return nil, fmt.Errorf("export: unknown format '%s', expecting one of %s", format, strings.Join(Formats, ", "))
+	}
+}
+
+// resource is a resource created by a command, with its
+// CloudFormation and Terraform names and properties
+type resource struct {
+	entity, id, importID string
+	cfnName, tfName      string
+	mapping              mapping
+	params               map[string]interface{}
+	// attachedTo is the id of the resource it has been attached to by a later command (i.e. the VPC of an internet gateway)
+	attachedTo string
+	// roles are the names of the roles attached to an instance profile by later commands
+	roles []string
+}
+
+// ref references an exported resource in place of its id
+type ref struct {
+	res *resource
+	// cfnAttr is the attribute (i.e. !GetAtt) to reference in CloudFormation rather than !Ref
+	cfnAttr string
+}
+
+// property is a property of an exported resource. Its value is either a scalar,
+// a ref, a list of values or a nested object (i.e. []property)
+type property struct {
+	name  string
+	value interface{}
+	// block renders nested objects as Terraform blocks rather than maps
+	block bool
+}
+
+type field struct {
+	param, cfn, tf string
+}
+
+type mapping struct {
+	cfnType, cfnID string
+	tfType         string
+	// importParam is the param holding the import id, the command result being used when empty
+	importParam string
+	fields      []field
+	// nameTag is the param set as the Name tag of the resource
+	nameTag           string
+	cfnExtra, tfExtra func(params map[string]interface{}) []property
+	// cfnAttachment is the type of the resource attaching it to its VPC in CloudFormation (i.e. for internet gateways)
+	cfnAttachment string
+}
+
+var mappings = map[string]mapping{
+	"vpc": {
+		cfnType: "AWS::EC2::VPC", cfnID: "VpcId", tfType: "aws_vpc",
+		fields:  []field{{"cidr", "CidrBlock", "cidr_block"}},
+		nameTag: "name",
+	},
+	"subnet": {
+		cfnType: "AWS::EC2::Subnet", cfnID: "SubnetId", tfType: "aws_subnet",
+		fields:  []field{{"vpc", "VpcId", "vpc_id"}, {"cidr", "CidrBlock", "cidr_block"}, {"availabilityzone", "AvailabilityZone", "availability_zone"}},
+		nameTag: "name",
+	},
+	"instance": {
+		cfnType: "AWS::EC2::Instance", cfnID: "InstanceId", tfType: "aws_instance",
+		fields: []field{
+			{"image", "ImageId", "ami"}, {"type", "InstanceType", "instance_type"}, {"subnet", "SubnetId", "subnet_id"},
+			{"keypair", "KeyName", "key_name"}, {"securitygroup", "SecurityGroupIds", "vpc_security_group_ids"},
+			{"ip", "PrivateIpAddress", "private_ip"}, {"role", "IamInstanceProfile", "iam_instance_profile"},
+		},
+		nameTag: "name",
+	},
+	"securitygroup": {
+		cfnType: "AWS::EC2::SecurityGroup", cfnID: "GroupId", tfType: "aws_security_group",
+		fields: []field{{"name", "GroupName", "name"}, {"description", "GroupDescription", "description"}, {"vpc", "VpcId", "vpc_id"}},
+	},
+	"internetgateway": {
+		cfnType: "AWS::EC2::InternetGateway", cfnID: "InternetGatewayId", tfType: "aws_internet_gateway",
+		fields:        []field{{"vpc", "", "vpc_id"}},
+		cfnAttachment: "AWS::EC2::VPCGatewayAttachment",
+	},
+	"routetable": {
+		cfnType: "AWS::EC2::RouteTable", cfnID: "RouteTableId", tfType: "aws_route_table",
+		fields: []field{{"vpc", "VpcId", "vpc_id"}},
+	},
+	"keypair": {
+		cfnType: "AWS::EC2::KeyPair", cfnID: "KeyName", tfType: "aws_key_pair",
+		fields: []field{{"name", "KeyName", "key_name"}},
+	},
+	"volume": {
+		cfnType: "AWS::EC2::Volume", cfnID: "VolumeId", tfType: "aws_ebs_volume",
+		fields: []field{{"availabilityzone", "AvailabilityZone", "availability_zone"}, {"size", "Size", "size"}},
+	},
+	"elasticip": {
+		cfnType: "AWS::EC2::EIP", cfnID: "AllocationId", tfType: "aws_eip",
+		fields: []field{{"domain", "Domain", "domain"}},
+	},
+	"natgateway": {
+		cfnType: "AWS::EC2::NatGateway", cfnID: "NatGatewayId", tfType: "aws_nat_gateway",
+		fields: []field{{"elasticip-id", "AllocationId", "allocation_id"}, {"subnet", "SubnetId", "subnet_id"}},
+	},
+	"user": {
+		cfnType: "AWS::IAM::User", cfnID: "UserName", tfType: "aws_iam_user", importParam: "name",
+		fields: []field{{"name", "UserName", "name"}},
+	},
+	"group": {
+		cfnType: "AWS::IAM::Group", cfnID: "GroupName", tfType: "aws_iam_group", importParam: "name",
+		fields: []field{{"name", "GroupName", "name"}},
+	},
+	"role": {
+		cfnType: "AWS::IAM::Role", cfnID: "RoleName", tfType: "aws_iam_role", importParam: "name",
+		fields: []field{{"name", "RoleName", "name"}},
+		cfnExtra: func(params map[string]interface{}) []property {
+			return []property{{name: "AssumeRolePolicyDocument", value: jsonValue(assumeRolePolicy(params))}}
+		},
+		tfExtra: func(params map[string]interface{}) []property {
+			return []property{{name: "assume_role_policy", value: jsonString(assumeRolePolicy(params))}}
+		},
+	},
+	"policy": {
+		cfnType: "AWS::IAM::ManagedPolicy", cfnID: "PolicyArn", tfType: "aws_iam_policy",
+		fields: []field{{"name", "ManagedPolicyName", "name"}, {"description", "Description", "description"}},
+		cfnExtra: func(params map[string]interface{}) []property {
+			return []property{{name: "PolicyDocument", value: jsonValue(policyDocument(params))}}
+		},
+		tfExtra: func(params map[string]interface{}) []property {
+			return []property{{name: "policy", value: jsonString(policyDocument(params))}}
+		},
+	},
+	"instanceprofile": {
+		cfnType: "AWS::IAM::InstanceProfile", cfnID: "InstanceProfileName", tfType: "aws_iam_instance_profile", importParam: "name",
+		fields: []field{{"name", "InstanceProfileName", "name"}},
+		cfnExtra: func(params map[string]interface{}) []property {
+			return []property{{name: "Roles", value: params["roles"]}}
+		},
+		tfExtra: func(params map[string]interface{}) []property {
+			if roles, _ := params["roles"].([]interface{}); len(roles) > 0 {
+				return []property{{name: "role", value: roles[0]}}
+			}
+			return nil
+		},
+	},
+	"bucket": {
+		cfnType: "AWS::S3::Bucket", cfnID: "BucketName", tfType: "aws_s3_bucket", importParam: "name",
+		fields: []field{{"name", "BucketName", "bucket"}},
+	},
+	"loadbalancer": {
+		cfnType: "AWS::ElasticLoadBalancingV2::LoadBalancer", cfnID: "LoadBalancerArn", tfType: "aws_lb",
+		fields: []field{
+			{"name", "Name", "name"}, {"subnets", "Subnets", "subnets"}, {"securitygroups", "SecurityGroups", "security_groups"},
+			{"scheme", "Scheme", ""}, {"type", "Type", "load_balancer_type"}, {"iptype", "IpAddressType", "ip_address_type"},
+		},
+		tfExtra: func(params map[string]interface{}) []property {
+			if scheme, ok := params["scheme"]; ok {
+				return []property{{name: "internal", value: fmt.Sprint(scheme) == "internal"}}
+			}
+			return nil
+		},
+	},
+	"targetgroup": {
+		cfnType: "AWS::ElasticLoadBalancingV2::TargetGroup", cfnID: "TargetGroupArn", tfType: "aws_lb_target_group",
+		fields: []field{{"name", "Name", "name"}, {"port", "Port", "port"}, {"protocol", "Protocol", "protocol"}, {"vpc", "VpcId", "vpc_id"}},
+	},
+	"listener": {
+		cfnType: "AWS::ElasticLoadBalancingV2::Listener", cfnID: "ListenerArn", tfType: "aws_lb_listener",
+		fields: []field{
+			{"loadbalancer", "LoadBalancerArn", "load_balancer_arn"}, {"port", "Port", "port"}, {"protocol", "Protocol", "protocol"},
+			{"sslpolicy", "SslPolicy", "ssl_policy"}, {"certificate", "", "certificate_arn"},
+		},
+		cfnExtra: func(params map[string]interface{}) []property {
+			props := []property{{name: "DefaultActions", value: []interface{}{[]property{
+				{name: "Type", value: params["actiontype"]},
+				{name: "TargetGroupArn", value: params["targetgroup"]},
+			}}}}
+			if cert, ok := params["certificate"]; ok {
+				props = append(props, property{name: "Certificates", value: []interface{}{[]property{{name: "CertificateArn", value: cert}}}})
+			}
+			return props
+		},
+		tfExtra: func(params map[string]interface{}) []property {
+			return []property{{name: "default_action", block: true, value: []property{
+				{name: "type", value: params["actiontype"]},
+				{name: "target_group_arn", value: params["targetgroup"]},
+			}}}
+		},
+	},
+}
+
+// listParams are the params exported as lists, even when given a single value
+var listParams = []string{"securitygroup", "securitygroups", "subnets"}
+
+// exportedResources returns the existing resources created by the template, along with
+// the commands that cannot be exported. Attachments of internet gateways and roles of
+// instance profiles are exported with the resource: instance profiles without role are not.
+func exportedResources(tplExec *template.TemplateExecution, reverted map[int]bool, later []*template.TemplateExecution) (resources []*resource, skipped []string) {
+	byID := make(map[string]*resource)
+	idents := tplExec.CommandIdents()
+	names := make(map[string]int)
+	cmds := tplExec.CommandNodesIterator()
+	exported := make(map[int]bool)
+	reasons := make(map[int]string)
+	createdBy := make(map[*resource]int)
+
+	deletedLater := make(map[string]bool)
+	for _, other := range later {
+		for id := range other.DeletedResources(0) {
+			deletedLater[id] = true
+		}
+	}
+
+	for i, cmd := range cmds {
+		m, ok := mappings[cmd.Entity]
+		result, hasResult := cmd.CmdResult.(string)
+		if !ok || cmd.Action != "create" || cmd.CmdErr != nil || !hasResult || result == "" {
+			continue
+		}
+		params := cmd.ToDriverParams()
+		importID := result
+		if m.importParam != "" {
+			importID = fmt.Sprint(params[m.importParam])
+		}
+		deleted := tplExec.DeletedResources(i + 1)
+		switch {
+		case reverted[i+1]:
+			reasons[i] = "reverted"
+			continue
+		case deleted[result] || deleted[importID] || deletedLater[result] || deletedLater[importID]:
+			reasons[i] = "deleted since"
+			continue
+		}
+		exported[i] = true
+
+		name := idents[cmd]
+		if name == "" {
+			name = fmt.Sprintf("%s%d", cmd.Entity, i+1)
+		}
+		if names[name]++; names[name] > 1 {
+			name = fmt.Sprintf("%s%d", name, names[name])
+		}
+
+		res := &resource{
+			entity: cmd.Entity, id: result, importID: importID,
+			cfnName: cfnName(name), tfName: tfName(name),
+			mapping: m, params: params,
+		}
+		resources = append(resources, res)
+		byID[result] = res
+		createdBy[res] = i
+	}
+
+	for i, cmd := range cmds {
+		if cmd.Action != "attach" || cmd.CmdErr != nil {
+			continue
+		}
+		params := cmd.ToDriverParams()
+		switch cmd.Entity {
+		case "internetgateway":
+			if igw, ok := byID[fmt.Sprint(params["id"])]; ok && igw.entity == "internetgateway" && igw.attachedTo == "" {
+				igw.attachedTo = fmt.Sprint(params["vpc"])
+				exported[i] = true
+			}
+		case "role":
+			if profile := findByName(resources, "instanceprofile", params["instanceprofile"]); profile != nil {
+				profile.roles = append(profile.roles, fmt.Sprint(params["name"]))
+				exported[i] = true
+			}
+		}
+	}
+
+	var withRoles []*resource
+	for _, res := range resources {
+		if res.entity == "instanceprofile" && len(res.roles) == 0 {
+			exported[createdBy[res]] = false
+			reasons[createdBy[res]] = "no role attached"
+			delete(byID, res.id)
+			continue
+		}
+		withRoles = append(withRoles, res)
+	}
+	resources = withRoles
+
+	for i, cmd := range cmds {
+		switch {
+		case exported[i]:
+		case reasons[i] != "":
+			skipped = append(skipped, fmt.Sprintf("%s (%s)", cmd, reasons[i]))
+		default:
+			skipped = append(skipped, cmd.String())
+		}
+	}
+
+	for _, res := range resources {
+		for _, p := range listParams {
+			if v, ok := res.params[p]; ok {
+				switch v.(type) {
+				case []interface{}, []string:
+				default:
+					res.params[p] = []interface{}{v}
+				}
+			}
+		}
+		res.params = resolveRefs(res, byID)
+		if res.attachedTo != "" {
+			res.params["vpc"] = refValue(res.attachedTo, byID)
+		}
+		if len(res.roles) > 0 {
+			var roles []interface{}
+			for _, name := range res.roles {
+				if role := findByName(resources, "role", name); role != nil {
+					roles = append(roles, ref{res: role})
+				} else {
+					roles = append(roles, name)
+				}
+			}
+			res.params["roles"] = roles
+		}
+	}
+	return
+}
+
+// findByName returns the exported resource of the entity created with the given name
+func findByName(resources []*resource, entity string, name interface{}) *resource {
+	for _, res := range resources {
+		if res.entity == entity && fmt.Sprint(res.params["name"]) == fmt.Sprint(name) {
+			return res
+		}
+	}
+	return nil
+}
+
+// resolveRefs replaces the ids of other exported resources in the params of res with references to them.
+// A resource never references itself, as happens when its id is one of its params (i.e. bucket name).
+func resolveRefs(res *resource, byID map[string]*resource) map[string]interface{} {
+	others := make(map[string]*resource)
+	for id, other := range byID {
+		if other != res {
+			others[id] = other
+		}
+	}
+	resolved := make(map[string]interface{})
+	for k, v := range res.params {
+		resolved[k] = refValue(v, others)
+	}
+	return resolved
+}
+
+func refValue(v interface{}, byID map[string]*resource) interface{} {
+	switch vv := v.(type) {
+	case []interface{}:
+		var list []interface{}
+		for _, elem := range vv {
+			list = append(list, refValue(elem, byID))
+		}
+		return list
+	case []string:
+		var list []interface{}
+		for _, elem := range vv {
+			list = append(list, refValue(elem, byID))
+		}
+		return list
+	case string:
+		if res, ok := byID[vv]; ok {
+			r := ref{res: res}
+			if res.entity == "elasticip" {
+				r.cfnAttr = "AllocationId"
+			}
+			return r
+		}
+	}
+	return v
+}
+
+func assumeRolePolicy(params map[string]interface{}) map[string]interface{} {
+	principal := make(map[string]interface{})
+	if v, ok := params["principal-service"]; ok {
+		principal["Service"] = v
+	}
+	if v, ok := params["principal-account"]; ok {
+		principal["AWS"] = fmt.Sprintf("arn:aws:iam::%s:root", v)
+	}
+	if v, ok := params["principal-user"]; ok {
+		principal["AWS"] = v
+	}
+	return map[string]interface{}{
+		"Version":   "2012-10-17",
+		"Statement": []interface{}{map[string]interface{}{"Effect": "Allow", "Principal": principal, "Action": "sts:AssumeRole"}},
+	}
+}
+
+func policyDocument(params map[string]interface{}) map[string]interface{} {
+	effect := strings.Title(strings.ToLower(fmt.Sprint(params["effect"])))
+	return map[string]interface{}{
+		"Version":   "2012-10-17",
+		"Statement": []interface{}{map[string]interface{}{"Effect": effect, "Action": params["action"], "Resource": params["resource"]}},
+	}
+}
+
+// jsonValue is a JSON document, which is also valid YAML
+type jsonValue map[string]interface{}
+
+func jsonString(doc map[string]interface{}) string {
+	b, _ := json.Marshal(doc)
+	return string(b)
+}
+
+func (v jsonValue) String() string {
+	return jsonString(v)
+}
+
+// cfnName returns a CloudFormation logical ID (alphanumeric, i.e. my-vpc to MyVpc)
+func cfnName(name string) string {
+	var out []rune
+	upper := true
+	for _, r := range name {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			upper = true
+		case upper:
+			out = append(out, unicode.ToUpper(r))
+			upper = false
+		default:
+			out = append(out, r)
+		}
+	}
+	return string(out)
+}
+
+// tfName returns a Terraform resource name (letters, digits, underscores and dashes)
+func tfName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_' {
+			return r
+		}
+		return '_'
+	}, name)
+}
@@ -0,0 +1,323 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package awsexport
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/wallix/awless/template"
+)
+
+func executedTemplate(text string, results ...string) *template.TemplateExecution {
+	exec := &template.TemplateExecution{Template: template.MustParse(text)}
+	exec.ID = "01BA7RV6ES86PZYCM3H28WM6KZ"
+	for i, cmd := range exec.CommandNodesIterator() {
+		if i < len(results) {
+			cmd.CmdResult = results[i]
+		}
+	}
+	return exec
+}
+
+const exportedText = `myvpc = create vpc cidr=10.0.0.0/16 name=my-vpc
+sub = create subnet cidr=10.0.0.0/24 vpc=vpc-1
+create securitygroup name=web description=web vpc=vpc-1
+create instance image=ami-123 type=t2.micro subnet=sub-1 securitygroup=sg-1 name=web
+create tag key=env value=prod resource=vpc-1
+create bucket name=my-bucket`
+
+func TestExportTerraform(t *testing.T) {
+	exec := executedTemplate(exportedText, "vpc-1", "sub-1", "sg-1", "i-1", "", "my-bucket")
+	out, err := Export(exec, Terraform, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `# Generated by awless from template 01BA7RV6ES86PZYCM3H28WM6KZ
+# Resources created by awless template 01BA7RV6ES86PZYCM3H28WM6KZ
+# Commands not exported (only successful creations of supported resources and their attachments are):
+#   skipped: create tag key=env resource=vpc-1 value=prod
+
+resource "aws_vpc" "myvpc" {
+  cidr_block = "10.0.0.0/16"
+  tags       = {
+    Name = "my-vpc"
+  }
+}
+
+resource "aws_subnet" "sub" {
+  vpc_id     = aws_vpc.myvpc.id
+  cidr_block = "10.0.0.0/24"
+}
+
+resource "aws_security_group" "securitygroup3" {
+  name        = "web"
+  description = "web"
+  vpc_id      = aws_vpc.myvpc.id
+}
+
+resource "aws_instance" "instance4" {
+  ami                    = "ami-123"
+  instance_type          = "t2.micro"
+  subnet_id              = aws_subnet.sub.id
+  vpc_security_group_ids = [aws_security_group.securitygroup3.id]
+  tags                   = {
+    Name = "web"
+  }
+}
+
+resource "aws_s3_bucket" "bucket6" {
+  bucket = "my-bucket"
+}
+
+import {
+  to = aws_vpc.myvpc
+  id = "vpc-1"
+}
+
+import {
+  to = aws_subnet.sub
+  id = "sub-1"
+}
+
+import {
+  to = aws_security_group.securitygroup3
+  id = "sg-1"
+}
+
+import {
+  to = aws_instance.instance4
+  id = "i-1"
+}
+
+import {
+  to = aws_s3_bucket.bucket6
+  id = "my-bucket"
+}
+`
+	if got, want := string(out), expected; got != want {
+		t.Fatalf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestExportCloudFormation(t *testing.T) {
+	exec := executedTemplate(exportedText, "vpc-1", "sub-1", "sg-1", "i-1", "", "my-bucket")
+	out, err := Export(exec, CloudFormation, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `# Generated by awless from template 01BA7RV6ES86PZYCM3H28WM6KZ
+# Commands not exported (only successful creations of supported resources and their attachments are):
+#   skipped: create tag key=env resource=vpc-1 value=prod
+AWSTemplateFormatVersion: "2010-09-09"
+Description: "Resources created by awless template 01BA7RV6ES86PZYCM3H28WM6KZ"
+Resources:
+  Myvpc:
+    Type: AWS::EC2::VPC
+    DeletionPolicy: Retain
+    Properties:
+      CidrBlock: "10.0.0.0/16"
+      Tags:
+        - Key: "Name"
+          Value: "my-vpc"
+  Sub:
+    Type: AWS::EC2::Subnet
+    DeletionPolicy: Retain
+    Properties:
+      VpcId: !Ref Myvpc
+      CidrBlock: "10.0.0.0/24"
+  Securitygroup3:
+    Type: AWS::EC2::SecurityGroup
+    DeletionPolicy: Retain
+    Properties:
+      GroupName: "web"
+      GroupDescription: "web"
+      VpcId: !Ref Myvpc
+  Instance4:
+    Type: AWS::EC2::Instance
+    DeletionPolicy: Retain
+    Properties:
+      ImageId: "ami-123"
+      InstanceType: "t2.micro"
+      SubnetId: !Ref Sub
+      SecurityGroupIds:
+        - !Ref Securitygroup3
+      Tags:
+        - Key: "Name"
+          Value: "web"
+  Bucket6:
+    Type: AWS::S3::Bucket
+    DeletionPolicy: Retain
+    Properties:
+      BucketName: "my-bucket"
+
+# Import the existing resources with a change set of type IMPORT, i.e.:
+#   aws cloudformation create-change-set --change-set-type IMPORT --resources-to-import file://resources.json ...
+# where resources.json contains:
+# [
+#   {"ResourceType": "AWS::EC2::VPC", "LogicalResourceId": "Myvpc", "ResourceIdentifier": {"VpcId": "vpc-1"}},
+#   {"ResourceType": "AWS::EC2::Subnet", "LogicalResourceId": "Sub", "ResourceIdentifier": {"SubnetId": "sub-1"}},
+#   {"ResourceType": "AWS::EC2::SecurityGroup", "LogicalResourceId": "Securitygroup3", "ResourceIdentifier": {"GroupId": "sg-1"}},
+#   {"ResourceType": "AWS::EC2::Instance", "LogicalResourceId": "Instance4", "ResourceIdentifier": {"InstanceId": "i-1"}},
+#   {"ResourceType": "AWS::S3::Bucket", "LogicalResourceId": "Bucket6", "ResourceIdentifier": {"BucketName": "my-bucket"}}
+# ]
+`
+	if got, want := string(out), expected; got != want {
+		t.Fatalf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestExportLoadBalancing(t *testing.T) {
+	text := `create targetgroup name=tg port=80 protocol=HTTP vpc=vpc-1
+create loadbalancer name=lb subnets=sub-1,sub-2
+create listener loadbalancer=lb-arn port=80 protocol=HTTP targetgroup=tg-arn actiontype=forward`
+	exec := executedTemplate(text, "tg-arn", "lb-arn", "listener-arn")
+	out, err := Export(exec, Terraform, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `resource "aws_lb_listener" "listener3" {
+  load_balancer_arn = aws_lb.loadbalancer2.id
+  port              = 80
+  protocol          = "HTTP"
+
+  default_action {
+    type             = "forward"
+    target_group_arn = aws_lb_target_group.targetgroup1.id
+  }
+}
+`
+	if got := string(out); !strings.Contains(got, expected) {
+		t.Fatalf("got\n%s\nwant to contain\n%s", got, expected)
+	}
+	if got, want := string(out), `subnets = ["sub-1", "sub-2"]`; !strings.Contains(got, want) {
+		t.Fatalf("got\n%s\nwant to contain\n%s", got, want)
+	}
+}
+
+func TestExportAttachments(t *testing.T) {
+	text := `vpc = create vpc cidr=10.0.0.0/16
+igw = create internetgateway
+attach internetgateway id=igw-1 vpc=vpc-1
+role = create role name=web-role principal-service=ec2.amazonaws.com
+profile = create instanceprofile name=web-profile
+attach role name=web-role instanceprofile=web-profile
+create instanceprofile name=lonely`
+	exec := executedTemplate(text, "vpc-1", "igw-1", "", "role-id", "profile-arn", "", "lonely-arn")
+
+	out, err := Export(exec, CloudFormation, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"#   skipped: create instanceprofile name=lonely (no role attached)\n",
+		`  IgwAttachment:
+    Type: AWS::EC2::VPCGatewayAttachment
+    DeletionPolicy: Retain
+    Properties:
+      InternetGatewayId: !Ref Igw
+      VpcId: !Ref Vpc
+`,
+		`  Profile:
+    Type: AWS::IAM::InstanceProfile
+    DeletionPolicy: Retain
+    Properties:
+      InstanceProfileName: "web-profile"
+      Roles:
+        - !Ref Role
+`,
+		`{"ResourceType": "AWS::EC2::VPCGatewayAttachment", "LogicalResourceId": "IgwAttachment", "ResourceIdentifier": {"AttachmentType": "IGW", "VpcId": "vpc-1"}}`,
+	} {
+		if got := string(out); !strings.Contains(got, want) {
+			t.Fatalf("got\n%s\nwant to contain\n%s", got, want)
+		}
+	}
+	for _, unwanted := range []string{"skipped: attach", "Lonely"} {
+		if got := string(out); strings.Contains(got, unwanted) {
+			t.Fatalf("got\n%s\nwant not to contain %s", got, unwanted)
+		}
+	}
+
+	out, err = Export(exec, Terraform, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`resource "aws_internet_gateway" "igw" {
+  vpc_id = aws_vpc.vpc.id
+}
+`,
+		`resource "aws_iam_instance_profile" "profile" {
+  name = "web-profile"
+  role = aws_iam_role.role.id
+}
+`,
+	} {
+		if got := string(out); !strings.Contains(got, want) {
+			t.Fatalf("got\n%s\nwant to contain\n%s", got, want)
+		}
+	}
+}
+
+func TestExportExistingResources(t *testing.T) {
+	text := `create vpc cidr=10.0.0.0/16
+create subnet cidr=10.0.0.0/24 vpc=vpc-1
+create subnet cidr=10.0.1.0/24 vpc=vpc-1
+create subnet cidr=10.0.2.0/24 vpc=vpc-1
+delete subnet id=sub-2`
+	exec := executedTemplate(text, "vpc-1", "sub-1", "sub-2", "sub-3", "")
+	later := executedTemplate("delete subnet id=sub-3")
+
+	out, err := Export(exec, Terraform, map[int]bool{2: true}, later)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"#   skipped: create subnet cidr=10.0.0.0/24 vpc=vpc-1 (reverted)\n",
+		"#   skipped: create subnet cidr=10.0.1.0/24 vpc=vpc-1 (deleted since)\n",
+		"#   skipped: create subnet cidr=10.0.2.0/24 vpc=vpc-1 (deleted since)\n",
+		`resource "aws_vpc" "vpc1"`,
+	} {
+		if got := string(out); !strings.Contains(got, want) {
+			t.Fatalf("got\n%s\nwant to contain\n%s", got, want)
+		}
+	}
+	if got := string(out); strings.Contains(got, "aws_subnet") {
+		t.Fatalf("got\n%s\nwant no subnet exported", got)
+	}
+
+	exec.RevertedBy = "01BA7RV6ES86PZYCM3H28WM6KA"
+	if _, err := Export(exec, Terraform, nil); err == nil {
+		t.Fatal("expected error for reverted template")
+	}
+}
+
+func TestExportErrors(t *testing.T) {
+	exec := executedTemplate("create vpc cidr=10.0.0.0/16\ncreate subnet cidr=10.0.0.0/24 vpc=vpc-1", "vpc-1")
+	if _, err := Export(exec, "ansible", nil); err == nil {
+		t.Fatal("expected error for unknown format")
+	}
+
+	exec = executedTemplate("create vpc cidr=10.0.0.0/16\ndelete subnet id=sub-1", "", "sub-1")
+	for _, cmd := range exec.CommandNodesIterator() {
+		cmd.CmdErr = errors.New("failed")
+	}
+	if _, err := Export(exec, Terraform, nil); err == nil {
+		t.Fatal("expected error when nothing can be exported")
+	}
+}
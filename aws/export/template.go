@@ -0,0 +1,538 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package awsexport
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/wallix/awless/aws/driver"
+	"github.com/wallix/awless/cloud"
+	"github.com/wallix/awless/cloud/properties"
+	"github.com/wallix/awless/graph"
+	"github.com/wallix/awless/template"
+)
+
+type valueFunc func(g *graph.Graph, res *graph.Resource) (interface{}, bool)
+
+// templatedEntity describes how to recreate a resource with awless commands
+type templatedEntity struct {
+	// params maps the params of the create command to their value on the resource
+	params map[string]valueFunc
+	// prepare returns a command to run before the creation, along with the params it provides (i.e. an elastic IP for a NAT gateway)
+	prepare func(name string) (string, map[string]string)
+	// configure returns the commands to run once all resources are created (i.e. attachments, firewall rules)
+	configure func(t *templater, res *graph.Resource) []string
+}
+
+// templatedEntities are listed in creation order, resources only referencing the ones created before them
+var templatedEntities = []string{
+	cloud.Vpc, cloud.InternetGateway, cloud.Subnet, cloud.RouteTable, cloud.SecurityGroup, cloud.Keypair, cloud.NatGateway,
+	cloud.LaunchConfiguration, cloud.Instance, cloud.TargetGroup, cloud.LoadBalancer, cloud.Listener, cloud.ScalingGroup,
+}
+
+var templatedEntitiesDefs = map[string]templatedEntity{
+	cloud.Vpc: {
+		params: map[string]valueFunc{"cidr": prop(properties.CIDR), "name": prop(properties.Name)},
+	},
+	cloud.InternetGateway: {
+		configure: attachInternetGateway,
+	},
+	cloud.Subnet: {
+		params: map[string]valueFunc{
+			"cidr": prop(properties.CIDR), "vpc": prop(properties.Vpc),
+			"availabilityzone": prop(properties.AvailabilityZone), "name": prop(properties.Name),
+		},
+		configure: publicSubnet,
+	},
+	cloud.RouteTable: {
+		params:    map[string]valueFunc{"vpc": prop(properties.Vpc)},
+		configure: routeTableAssociationsAndRoutes,
+	},
+	cloud.SecurityGroup: {
+		params: map[string]valueFunc{
+			"name": prop(properties.Name), "description": prop(properties.Description), "vpc": prop(properties.Vpc),
+		},
+		configure: securityGroupInboundRules,
+	},
+	cloud.Keypair: {
+		params: map[string]valueFunc{"name": resourceID},
+	},
+	cloud.NatGateway: {
+		params: map[string]valueFunc{"subnet": prop(properties.Subnet)},
+		prepare: func(name string) (string, map[string]string) {
+			eip := name + "-eip"
+			return fmt.Sprintf("%s = create elasticip domain=vpc", eip), map[string]string{"elasticip-id": "$" + eip}
+		},
+	},
+	cloud.LaunchConfiguration: {
+		params: map[string]valueFunc{
+			"name": prop(properties.Name), "image": prop(properties.Image), "type": prop(properties.Type),
+			"keypair": prop(properties.KeyPair), "securitygroups": prop(properties.SecurityGroups), "role": prop(properties.Profile),
+			"public": prop(properties.Public), "spotprice": prop(properties.SpotPrice),
+		},
+	},
+	cloud.Instance: {
+		params: map[string]valueFunc{
+			"name": prop(properties.Name), "image": prop(properties.Image), "type": prop(properties.Type),
+			"subnet": prop(properties.Subnet), "keypair": prop(properties.KeyPair), "securitygroup": prop(properties.SecurityGroups),
+			"count": constant(1),
+		},
+	},
+	cloud.TargetGroup: {
+		params: map[string]valueFunc{
+			"name": prop(properties.Name), "port": prop(properties.Port), "protocol": prop(properties.Protocol), "vpc": prop(properties.Vpc),
+			"healthcheckinterval": prop(properties.CheckInterval), "healthcheckpath": prop(properties.CheckPath),
+			"healthcheckport": prop(properties.CheckPort), "healthcheckprotocol": prop(properties.CheckProtocol),
+			"healthchecktimeout": prop(properties.CheckTimeout), "matcher": prop(properties.CheckHTTPCode),
+			"healthythreshold": prop(properties.HealthyThresholdCount), "unhealthythreshold": prop(properties.UnhealthyThresholdCount),
+		},
+	},
+	cloud.LoadBalancer: {
+		params: map[string]valueFunc{
+			"name": prop(properties.Name), "subnets": prop(properties.Subnets), "securitygroups": prop(properties.SecurityGroups),
+			"scheme": prop(properties.Scheme), "type": prop(properties.Type), "iptype": prop(properties.IPType),
+		},
+	},
+	cloud.Listener: {
+		params: map[string]valueFunc{
+			"loadbalancer": prop(properties.LoadBalancer), "port": prop(properties.Port), "protocol": prop(properties.Protocol),
+			"actiontype": firstOf(properties.Actions), "certificate": firstOf(properties.Certificates), "sslpolicy": prop(properties.CipherSuite),
+		},
+	},
+	cloud.ScalingGroup: {
+		params: map[string]valueFunc{
+			"name": prop(properties.Name), "launchconfiguration": prop(properties.LaunchConfigurationName),
+			"max-size": prop(properties.MaxSize), "min-size": prop(properties.MinSize), "desired-capacity": prop(properties.DesiredCapacity),
+			"cooldown": prop(properties.DefaultCooldown), "healthcheck-type": prop(properties.HealthCheckType),
+			"healthcheck-grace-period": prop(properties.HealthCheckGracePeriod), "new-instances-protected": prop(properties.NewInstancesProtected),
+			"subnets": scalingGroupSubnets,
+		},
+	},
+}
+
+// regionalParams only have a meaning in the region of the resources (i.e. AMIs, availability zones):
+// their values become holes for the template to run in any region
+var regionalParams = map[string]bool{"availabilityzone": true, "image": true}
+
+// implicitName returns the hole suffix of resources created along with their VPC (i.e. its default
+// security group and main route table): they are not recreated but referenced through a hole of the VPC
+func implicitName(res *graph.Resource) (string, bool) {
+	switch {
+	case res.Type() == cloud.SecurityGroup && res.Properties[properties.Name] == "default":
+		return "defaultsecuritygroup", true
+	case res.Type() == cloud.RouteTable && res.Properties[properties.Main] == true:
+		return "mainroutetable", true
+	}
+	return "", false
+}
+
+// AsTemplate returns an awless template recreating the slice of infrastructure made of the given resource,
+// its children and the resources depending on them. References between these resources become template
+// variables, references to other resources are kept as is, and required params that cannot be deduced
+// from the resources are left as holes, as are regional params (with their original value in a comment).
+func AsTemplate(g *graph.Graph, root *graph.Resource) (string, error) {
+	if _, ok := templatedEntitiesDefs[root.Type()]; !ok {
+		return "", fmt.Errorf("as template: cannot recreate %s: supported types are %s", root.Type(), strings.Join(templatedEntities, ", "))
+	}
+	if _, ok := implicitName(root); ok {
+		return "", fmt.Errorf("as template: cannot recreate %s %s: it is created along with its vpc", root.Type(), root.Id())
+	}
+	slice, implicit, skipped, err := collectSlice(g, root)
+	if err != nil {
+		return "", err
+	}
+
+	t := newTemplater(g, slice, implicit)
+	var buff bytes.Buffer
+	fmt.Fprintf(&buff, "# Recreates %s %s and its %d related resources\n", root.Type(), root.Id(), len(slice)-1)
+	for _, res := range skipped {
+		fmt.Fprintf(&buff, "# not templated: %s %s\n", res.Type(), res.Id())
+	}
+	for _, res := range implicit {
+		if hole, ok := t.implicitHoles[res.Id()]; ok {
+			fmt.Fprintf(&buff, "# %s was %s (created with its vpc)\n", hole, res.Id())
+		}
+	}
+	for _, res := range slice {
+		buff.WriteString("\n")
+		for _, line := range t.create(res) {
+			buff.WriteString(line + "\n")
+		}
+	}
+
+	var configured bool
+	for _, res := range slice {
+		def := templatedEntitiesDefs[res.Type()]
+		if def.configure == nil {
+			continue
+		}
+		for _, line := range def.configure(t, res) {
+			if !configured {
+				buff.WriteString("\n")
+				configured = true
+			}
+			buff.WriteString(line + "\n")
+		}
+	}
+
+	return template.Format(buff.String())
+}
+
+// collectSlice walks the children of the root and, transitively, the resources depending on them.
+// Instances launched by a scaling group are left to the scaling group. Resources created along with
+// their VPC and resources that cannot be recreated are returned apart.
+func collectSlice(g *graph.Graph, root *graph.Resource) (slice, implicit, skipped []*graph.Resource, err error) {
+	seen := make(map[string]bool)
+	var queue []*graph.Resource
+	add := func(res *graph.Resource) {
+		if seen[res.Id()] {
+			return
+		}
+		seen[res.Id()] = true
+		if _, ok := implicitName(res); ok {
+			implicit = append(implicit, res)
+			return
+		}
+		if _, ok := templatedEntitiesDefs[res.Type()]; !ok {
+			if res.Type() != cloud.Region && res.Type() != cloud.AvailabilityZone && res.Type() != cloud.Image {
+				skipped = append(skipped, res)
+			}
+			return
+		}
+		slice = append(slice, res)
+		queue = append(queue, res)
+	}
+
+	err = g.Accept(&graph.ChildrenVisitor{From: root, IncludeFrom: true, Each: func(res *graph.Resource, depth int) error {
+		add(res)
+		return nil
+	}})
+	if err != nil {
+		return
+	}
+
+	for len(queue) > 0 {
+		res := queue[0]
+		queue = queue[1:]
+		var dependents []*graph.Resource
+		if dependents, err = g.ListResourcesDependingOn(res); err != nil {
+			return
+		}
+		for _, dep := range dependents {
+			if res.Type() == cloud.ScalingGroup && dep.Type() == cloud.Instance {
+				continue
+			}
+			add(dep)
+		}
+		if res.Type() == cloud.ScalingGroup {
+			var launchConfigs []*graph.Resource
+			if launchConfigs, err = g.FindResourcesByProperty(properties.Name, res.Properties[properties.LaunchConfigurationName]); err != nil {
+				return
+			}
+			for _, lc := range launchConfigs {
+				if lc.Type() == cloud.LaunchConfiguration {
+					add(lc)
+				}
+			}
+		}
+	}
+
+	order := make(map[string]int)
+	for i, entity := range templatedEntities {
+		order[entity] = i
+	}
+	sort.SliceStable(slice, func(i, j int) bool {
+		if slice[i].Type() != slice[j].Type() {
+			return order[slice[i].Type()] < order[slice[j].Type()]
+		}
+		return slice[i].Id() < slice[j].Id()
+	})
+	sort.SliceStable(implicit, func(i, j int) bool { return implicit[i].Id() < implicit[j].Id() })
+	sort.SliceStable(skipped, func(i, j int) bool { return skipped[i].Id() < skipped[j].Id() })
+	return
+}
+
+type templater struct {
+	g *graph.Graph
+	// names are the variable names of the resources in the slice
+	names map[*graph.Resource]string
+	// refs maps the ids, ARNs (and names when used as ids) of the resources in the slice to their variable
+	refs map[string]string
+	// regionalHoles maps the original values of regional params to their hole, shared by the resources
+	regionalHoles map[string]string
+	// implicitHoles maps the ids of the resources created along with a VPC of the slice to their hole
+	implicitHoles map[string]string
+}
+
+func newTemplater(g *graph.Graph, slice, implicit []*graph.Resource) *templater {
+	t := &templater{g: g, names: make(map[*graph.Resource]string), refs: make(map[string]string), regionalHoles: make(map[string]string), implicitHoles: make(map[string]string)}
+	used := make(map[string]int)
+	for _, res := range slice {
+		name := variableName(res)
+		if used[name]++; used[name] > 1 {
+			name = fmt.Sprintf("%s-%d", name, used[name])
+		}
+		t.names[res] = name
+		t.refs[res.Id()] = name
+		if arn, ok := res.Properties[properties.Arn].(string); ok && arn != "" {
+			t.refs[arn] = name
+		}
+		if res.Type() == cloud.LaunchConfiguration {
+			t.refs[fmt.Sprint(res.Properties[properties.Name])] = name
+		}
+	}
+	for _, res := range implicit {
+		suffix, _ := implicitName(res)
+		if vpc, ok := t.refs[fmt.Sprint(res.Properties[properties.Vpc])]; ok {
+			t.implicitHoles[res.Id()] = fmt.Sprintf("{%s.%s}", vpc, suffix)
+		}
+	}
+	return t
+}
+
+// create returns the commands creating the resource, required params without value and regional params being holes
+func (t *templater) create(res *graph.Resource) (lines []string) {
+	def := templatedEntitiesDefs[res.Type()]
+	name := t.names[res]
+
+	params := make(map[string]string)
+	if def.prepare != nil {
+		line, provided := def.prepare(name)
+		lines = append(lines, line)
+		for k, v := range provided {
+			params[k] = v
+		}
+	}
+	regionalValues := make(map[string]string)
+	for param, valueFn := range def.params {
+		if v, ok := valueFn(t.g, res); ok {
+			params[param] = t.valueExcept(v, name)
+			if regionalParams[param] {
+				regionalValues[param] = fmt.Sprint(v)
+			}
+		}
+	}
+	if d, ok := awsdriver.AWSLookupDefinitions("create" + res.Type()); ok {
+		for _, required := range d.RequiredParams {
+			if _, ok := params[required]; !ok {
+				params[required] = fmt.Sprintf("{%s.%s}", name, required)
+			}
+		}
+	}
+
+	var keys []string
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		original, ok := regionalValues[k]
+		if !ok {
+			continue
+		}
+		hole, seen := t.regionalHoles[original]
+		if !seen {
+			hole = fmt.Sprintf("{%s.%s}", name, k)
+			t.regionalHoles[original] = hole
+			lines = append(lines, fmt.Sprintf("# %s was %s (region specific)", hole, original))
+		}
+		params[k] = hole
+	}
+	var buff bytes.Buffer
+	fmt.Fprintf(&buff, "%s = create %s", name, res.Type())
+	for _, k := range keys {
+		fmt.Fprintf(&buff, " %s=%s", k, params[k])
+	}
+	return append(lines, buff.String())
+}
+
+// value formats a param value, referencing by variable the resources of the slice
+func (t *templater) value(v interface{}) string {
+	return t.valueExcept(v, "")
+}
+
+// valueExcept formats a param value without referencing the given variable,
+// as a resource never references itself (i.e. a keypair named after its id)
+func (t *templater) valueExcept(v interface{}, self string) string {
+	switch vv := v.(type) {
+	case []string:
+		// lists are stored unordered in the graph
+		sorted := append([]string(nil), vv...)
+		sort.Strings(sorted)
+		var elems []string
+		for _, s := range sorted {
+			elems = append(elems, t.valueExcept(s, self))
+		}
+		return "[" + strings.Join(elems, ",") + "]"
+	case string:
+		if hole, ok := t.implicitHoles[vv]; ok {
+			return hole
+		}
+		if name, ok := t.refs[vv]; ok && name != self {
+			return "$" + name
+		}
+		return quoteValue(vv)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// unquotedValue is in sync with the template grammar for unquoted values
+var unquotedValue = regexp.MustCompile(`^[a-zA-Z0-9-._:/+;~<>*]+$`)
+
+func quoteValue(s string) string {
+	switch {
+	case unquotedValue.MatchString(s):
+		return s
+	case strings.Contains(s, "'"):
+		return fmt.Sprintf("\"%s\"", s)
+	default:
+		return fmt.Sprintf("'%s'", s)
+	}
+}
+
+func variableName(res *graph.Resource) string {
+	name, _ := res.Properties[properties.Name].(string)
+	if res.Type() == cloud.Keypair {
+		name = res.Id()
+	}
+	name = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		case r == ' ':
+			return '-'
+		default:
+			return -1
+		}
+	}, name)
+	if name == "" {
+		return res.Type()
+	}
+	return strings.ToLower(name)
+}
+
+func attachInternetGateway(t *templater, res *graph.Resource) (lines []string) {
+	vpcs, _ := res.Properties[properties.Vpcs].([]string)
+	for _, vpc := range vpcs {
+		lines = append(lines, fmt.Sprintf("attach internetgateway id=$%s vpc=%s", t.names[res], t.value(vpc)))
+	}
+	return
+}
+
+func publicSubnet(t *templater, res *graph.Resource) []string {
+	if public, _ := res.Properties[properties.Public].(bool); public {
+		return []string{fmt.Sprintf("update subnet id=$%s public=true", t.names[res])}
+	}
+	return nil
+}
+
+func routeTableAssociationsAndRoutes(t *templater, res *graph.Resource) (lines []string) {
+	name := t.names[res]
+	assocs, _ := res.Properties[properties.Associations].([]*graph.KeyValue)
+	for _, assoc := range assocs {
+		if assoc.Value != "" {
+			lines = append(lines, fmt.Sprintf("attach routetable id=$%s subnet=%s", name, t.value(assoc.Value)))
+		}
+	}
+	routes, _ := res.Properties[properties.Routes].([]*graph.Route)
+	for _, route := range routes {
+		if route.Destination == nil || len(route.Targets) == 0 || route.Targets[0].Ref == "local" {
+			continue
+		}
+		target := route.Targets[0]
+		if target.Type != graph.GatewayTarget {
+			lines = append(lines, fmt.Sprintf("# route to %s in %s not templated: only gateway routes are", route.Destination, name))
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("create route table=$%s cidr=%s gateway=%s", name, route.Destination, t.value(target.Ref)))
+	}
+	return
+}
+
+func securityGroupInboundRules(t *templater, res *graph.Resource) (lines []string) {
+	name := t.names[res]
+	rules, _ := res.Properties[properties.InboundRules].([]*graph.FirewallRule)
+	for _, rule := range rules {
+		portrange := "any"
+		switch {
+		case rule.PortRange.Any:
+		case rule.PortRange.FromPort == rule.PortRange.ToPort:
+			portrange = fmt.Sprint(rule.PortRange.FromPort)
+		default:
+			portrange = fmt.Sprintf("%d-%d", rule.PortRange.FromPort, rule.PortRange.ToPort)
+		}
+		for _, cidr := range rule.IPRanges {
+			lines = append(lines, fmt.Sprintf("update securitygroup id=$%s inbound=authorize protocol=%s cidr=%s portrange=%s", name, rule.Protocol, cidr, portrange))
+		}
+		for _, source := range rule.Sources {
+			lines = append(lines, fmt.Sprintf("update securitygroup id=$%s inbound=authorize protocol=%s securitygroup=%s portrange=%s", name, rule.Protocol, t.value(source), portrange))
+		}
+	}
+	return
+}
+
+func prop(key string) valueFunc {
+	return func(g *graph.Graph, res *graph.Resource) (interface{}, bool) {
+		v, ok := res.Properties[key]
+		if !ok || v == nil || fmt.Sprint(v) == "" {
+			return nil, false
+		}
+		if list, isList := v.([]string); isList && len(list) == 0 {
+			return nil, false
+		}
+		return v, true
+	}
+}
+
+func firstOf(key string) valueFunc {
+	return func(g *graph.Graph, res *graph.Resource) (interface{}, bool) {
+		if list, ok := res.Properties[key].([]string); ok && len(list) > 0 {
+			return list[0], true
+		}
+		return nil, false
+	}
+}
+
+func constant(v interface{}) valueFunc {
+	return func(*graph.Graph, *graph.Resource) (interface{}, bool) {
+		return v, true
+	}
+}
+
+func resourceID(g *graph.Graph, res *graph.Resource) (interface{}, bool) {
+	return res.Id(), true
+}
+
+func scalingGroupSubnets(g *graph.Graph, res *graph.Resource) (interface{}, bool) {
+	appliedOn, err := g.ListResourcesAppliedOn(res)
+	if err != nil {
+		return nil, false
+	}
+	var subnets []string
+	for _, r := range appliedOn {
+		if r.Type() == cloud.Subnet {
+			subnets = append(subnets, r.Id())
+		}
+	}
+	sort.Strings(subnets)
+	return subnets, len(subnets) > 0
+}
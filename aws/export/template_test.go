@@ -0,0 +1,225 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package awsexport
+
+import (
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/wallix/awless/cloud"
+	"github.com/wallix/awless/cloud/properties"
+	"github.com/wallix/awless/graph"
+)
+
+func TestAsTemplate(t *testing.T) {
+	g := graph.NewGraph()
+	newResource := func(entity, id string, props map[string]interface{}) *graph.Resource {
+		res := graph.InitResource(entity, id)
+		for k, v := range props {
+			res.Properties[k] = v
+		}
+		if err := g.AddResource(res); err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+
+	vpc := newResource(cloud.Vpc, "vpc-1", map[string]interface{}{properties.Name: "my vpc", properties.CIDR: "10.0.0.0/16"})
+	sub := newResource(cloud.Subnet, "subnet-1", map[string]interface{}{properties.Name: "public", properties.CIDR: "10.0.1.0/24", properties.Vpc: "vpc-1", properties.AvailabilityZone: "eu-west-1a", properties.Public: true})
+	_, dest, _ := net.ParseCIDR("0.0.0.0/0")
+	rt := newResource(cloud.RouteTable, "rtb-1", map[string]interface{}{
+		properties.Vpc:          "vpc-1",
+		properties.Associations: []*graph.KeyValue{{KeyName: "rtbassoc-1", Value: "subnet-1"}},
+		properties.Routes:       []*graph.Route{{Destination: dest, Targets: []*graph.RouteTarget{{Type: graph.GatewayTarget, Ref: "igw-1"}}}},
+	})
+	igw := newResource(cloud.InternetGateway, "igw-1", map[string]interface{}{properties.Vpcs: []string{"vpc-1"}})
+	_, anywhere, _ := net.ParseCIDR("0.0.0.0/0")
+	sg := newResource(cloud.SecurityGroup, "sg-1", map[string]interface{}{
+		properties.Name: "web", properties.Description: "web access", properties.Vpc: "vpc-1",
+		properties.InboundRules: []*graph.FirewallRule{{Protocol: "tcp", PortRange: graph.PortRange{FromPort: 80, ToPort: 80}, IPRanges: []*net.IPNet{anywhere}}},
+	})
+	keypair := newResource(cloud.Keypair, "mykey", nil)
+	inst := newResource(cloud.Instance, "i-1", map[string]interface{}{
+		properties.Name: "web", properties.Image: "ami-123", properties.Type: "t2.micro", properties.Subnet: "subnet-1",
+		properties.KeyPair: "mykey", properties.SecurityGroups: []string{"sg-1"},
+	})
+	vol := newResource(cloud.Volume, "vol-1", nil)
+
+	g.AddParentRelation(vpc, sub)
+	g.AddParentRelation(vpc, rt)
+	g.AddParentRelation(vpc, sg)
+	g.AddParentRelation(sub, inst)
+	g.AddAppliesOnRelation(igw, vpc)
+	g.AddAppliesOnRelation(rt, sub)
+	g.AddAppliesOnRelation(sg, inst)
+	g.AddAppliesOnRelation(keypair, inst)
+	g.AddAppliesOnRelation(vol, inst)
+
+	out, err := AsTemplate(g, vpc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `# Recreates vpc vpc-1 and its 6 related resources
+# not templated: volume vol-1
+
+my-vpc = create vpc cidr=10.0.0.0/16 name='my vpc'
+
+internetgateway = create internetgateway
+
+# {public.availabilityzone} was eu-west-1a (region specific)
+public = create subnet availabilityzone={public.availabilityzone} cidr=10.0.1.0/24 name=public vpc=$my-vpc
+
+routetable = create routetable vpc=$my-vpc
+
+web = create securitygroup description='web access' name=web vpc=$my-vpc
+
+mykey = create keypair name=mykey
+
+# {web-2.image} was ami-123 (region specific)
+web-2 = create instance count=1 image={web-2.image} keypair=$mykey name=web securitygroup=[$web] subnet=$public type=t2.micro
+
+attach internetgateway id=$internetgateway vpc=$my-vpc
+update subnet id=$public public=true
+attach routetable id=$routetable subnet=$public
+create route cidr=0.0.0.0/0 gateway=$internetgateway table=$routetable
+update securitygroup cidr=0.0.0.0/0 id=$web inbound=authorize portrange=80 protocol=tcp
+`
+	if got, want := out, expected; got != want {
+		t.Fatalf("got\n%s\nwant\n%s", got, want)
+	}
+
+	if _, err := AsTemplate(g, vol); err == nil {
+		t.Fatal("expected error for unsupported resource type")
+	}
+}
+
+func TestAsTemplateHolesForMissingRequiredParams(t *testing.T) {
+	g := graph.NewGraph()
+	listener := graph.InitResource(cloud.Listener, "arn:listener")
+	listener.Properties[properties.Port] = 80
+	listener.Properties[properties.Protocol] = "HTTP"
+	listener.Properties[properties.LoadBalancer] = "arn:lb"
+	listener.Properties[properties.Actions] = []string{"forward"}
+	g.AddResource(listener)
+
+	out, err := AsTemplate(g, listener)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := out, "# Recreates listener arn:listener and its 0 related resources\n\nlistener = create listener actiontype=forward loadbalancer=arn:lb port=80 protocol=HTTP targetgroup={listener.targetgroup}\n"; got != want {
+		t.Fatalf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestAsTemplateSharesRegionalHoles(t *testing.T) {
+	g := graph.NewGraph()
+	sub := graph.InitResource(cloud.Subnet, "subnet-1")
+	sub.Properties[properties.CIDR] = "10.0.1.0/24"
+	g.AddResource(sub)
+	for i, image := range []string{"ami-1", "ami-2", "ami-1"} {
+		inst := graph.InitResource(cloud.Instance, fmt.Sprintf("i-%d", i+1))
+		inst.Properties[properties.Image] = image
+		inst.Properties[properties.Type] = "t2.micro"
+		inst.Properties[properties.Name] = fmt.Sprintf("web%d", i+1)
+		inst.Properties[properties.Subnet] = "subnet-1"
+		g.AddResource(inst)
+		g.AddParentRelation(sub, inst)
+	}
+
+	out, err := AsTemplate(g, sub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `# Recreates subnet subnet-1 and its 3 related resources
+
+subnet = create subnet cidr=10.0.1.0/24 vpc={subnet.vpc}
+
+# {web1.image} was ami-1 (region specific)
+web1 = create instance count=1 image={web1.image} name=web1 subnet=$subnet type=t2.micro
+
+# {web2.image} was ami-2 (region specific)
+web2 = create instance count=1 image={web2.image} name=web2 subnet=$subnet type=t2.micro
+
+web3 = create instance count=1 image={web1.image} name=web3 subnet=$subnet type=t2.micro
+`
+	if got, want := out, expected; got != want {
+		t.Fatalf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestAsTemplateReferencesResourcesCreatedWithVpc(t *testing.T) {
+	g := graph.NewGraph()
+	newResource := func(entity, id string, props map[string]interface{}) *graph.Resource {
+		res := graph.InitResource(entity, id)
+		for k, v := range props {
+			res.Properties[k] = v
+		}
+		if err := g.AddResource(res); err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+
+	vpc := newResource(cloud.Vpc, "vpc-1", map[string]interface{}{properties.Name: "main", properties.CIDR: "10.0.0.0/16"})
+	sub := newResource(cloud.Subnet, "subnet-1", map[string]interface{}{properties.CIDR: "10.0.1.0/24", properties.Vpc: "vpc-1"})
+	mainRt := newResource(cloud.RouteTable, "rtb-main", map[string]interface{}{properties.Vpc: "vpc-1", properties.Main: true})
+	defaultSg := newResource(cloud.SecurityGroup, "sg-default", map[string]interface{}{
+		properties.Name: "default", properties.Description: "default VPC security group", properties.Vpc: "vpc-1",
+	})
+	sg := newResource(cloud.SecurityGroup, "sg-1", map[string]interface{}{
+		properties.Name: "web", properties.Description: "web access", properties.Vpc: "vpc-1",
+		properties.InboundRules: []*graph.FirewallRule{{Protocol: "tcp", PortRange: graph.PortRange{FromPort: 22, ToPort: 22}, Sources: []string{"sg-default"}}},
+	})
+	inst := newResource(cloud.Instance, "i-1", map[string]interface{}{
+		properties.Name: "web", properties.Type: "t2.micro", properties.Subnet: "subnet-1", properties.SecurityGroups: []string{"sg-default", "sg-1"},
+	})
+
+	g.AddParentRelation(vpc, sub)
+	g.AddParentRelation(vpc, mainRt)
+	g.AddParentRelation(vpc, defaultSg)
+	g.AddParentRelation(vpc, sg)
+	g.AddParentRelation(sub, inst)
+	g.AddAppliesOnRelation(defaultSg, inst)
+	g.AddAppliesOnRelation(sg, inst)
+
+	out, err := AsTemplate(g, vpc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `# Recreates vpc vpc-1 and its 3 related resources
+# {main.mainroutetable} was rtb-main (created with its vpc)
+# {main.defaultsecuritygroup} was sg-default (created with its vpc)
+
+main = create vpc cidr=10.0.0.0/16 name=main
+
+subnet = create subnet cidr=10.0.1.0/24 vpc=$main
+
+web = create securitygroup description='web access' name=web vpc=$main
+
+web-2 = create instance count=1 image={web-2.image} name=web securitygroup=[$web,{main.defaultsecuritygroup}] subnet=$subnet type=t2.micro
+
+update securitygroup id=$web inbound=authorize portrange=22 protocol=tcp securitygroup={main.defaultsecuritygroup}
+`
+	if got, want := out, expected; got != want {
+		t.Fatalf("got\n%s\nwant\n%s", got, want)
+	}
+
+	if _, err := AsTemplate(g, defaultSg); err == nil {
+		t.Fatal("expected error for default security group")
+	}
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package awsexport
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/wallix/awless/template"
+)
+
+// toTerraform renders resources as a Terraform configuration, with an import block
+// for each resource so that a plan adopts the existing resources instead of creating new ones
+func toTerraform(tplExec *template.TemplateExecution, resources []*resource, skipped []string) []byte {
+	var buff bytes.Buffer
+
+	fmt.Fprintf(&buff, "# Generated by awless from template %s\n", tplExec.ID)
+	fmt.Fprintf(&buff, "# %s\n", description(tplExec))
+	writeSkipped(&buff, skipped)
+	for _, res := range resources {
+		fmt.Fprintf(&buff, "\nresource %q %q {\n", res.mapping.tfType, res.tfName)
+		writeHCLBody(&buff, 2, tfProperties(res))
+		buff.WriteString("}\n")
+	}
+	for _, res := range resources {
+		fmt.Fprintf(&buff, "\nimport {\n  to = %s.%s\n  id = %s\n}\n", res.mapping.tfType, res.tfName, hclValue(res.importID, 2))
+	}
+
+	return buff.Bytes()
+}
+
+func tfProperties(res *resource) (props []property) {
+	for _, f := range res.mapping.fields {
+		if v, ok := res.params[f.param]; ok && f.tf != "" {
+			props = append(props, property{name: f.tf, value: v})
+		}
+	}
+	if res.mapping.tfExtra != nil {
+		props = append(props, res.mapping.tfExtra(res.params)...)
+	}
+	if name, ok := res.params[res.mapping.nameTag]; ok {
+		props = append(props, property{name: "tags", value: []property{{name: "Name", value: name}}})
+	}
+	return
+}
+
+// writeHCLBody writes attributes aligned on their equal sign as terraform fmt does, then nested blocks
+func writeHCLBody(buff *bytes.Buffer, indent int, props []property) {
+	pad := strings.Repeat(" ", indent)
+	var width int
+	for _, p := range props {
+		if !p.block && len(p.name) > width {
+			width = len(p.name)
+		}
+	}
+	for _, p := range props {
+		if !p.block {
+			fmt.Fprintf(buff, "%s%-*s = %s\n", pad, width, p.name, hclValue(p.value, indent))
+		}
+	}
+	for _, p := range props {
+		if p.block {
+			fmt.Fprintf(buff, "\n%s%s {\n", pad, p.name)
+			nested, _ := p.value.([]property)
+			writeHCLBody(buff, indent+2, nested)
+			fmt.Fprintf(buff, "%s}\n", pad)
+		}
+	}
+}
+
+func hclValue(v interface{}, indent int) string {
+	switch vv := v.(type) {
+	case ref:
+		return fmt.Sprintf("%s.%s.id", vv.res.mapping.tfType, vv.res.tfName)
+	case []property:
+		var buff bytes.Buffer
+		buff.WriteString("{\n")
+		writeHCLBody(&buff, indent+2, vv)
+		buff.WriteString(strings.Repeat(" ", indent) + "}")
+		return buff.String()
+	case []interface{}:
+		var elems []string
+		for _, elem := range vv {
+			elems = append(elems, hclValue(elem, indent))
+		}
+		return "[" + strings.Join(elems, ", ") + "]"
+	case string:
+		quoted := strconv.Quote(vv)
+		quoted = strings.Replace(quoted, "${", "$${", -1)
+		return strings.Replace(quoted, "%{", "%%{", -1)
+	case nil:
+		return "null"
+	default:
+		return fmt.Sprint(vv)
+	}
+}
@@ -0,0 +1,244 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package awssim simulates the AWS services on a graph of resources persisted locally,
+// so that awless commands (list, show, run, revert, sync, ...) work without an AWS account.
+package awssim
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	awsdriver "github.com/wallix/awless/aws/driver"
+	"github.com/wallix/awless/aws/services"
+	"github.com/wallix/awless/cloud"
+	"github.com/wallix/awless/graph"
+	"github.com/wallix/awless/logger"
+	"github.com/wallix/awless/template/driver"
+	"github.com/wallix/awless/template/templatetest"
+)
+
+const globalRegion = "global"
+
+// Init registers the simulated services of the region in the cloud registry, in place of the AWS ones.
+// The simulated resources are persisted in the given directory.
+func Init(dir, region string, log *logger.Logger) error {
+	c, err := Open(dir, region)
+	if err != nil {
+		return err
+	}
+	log.Verbosef("simulated backend: resources of region %s persisted in %s", region, dir)
+
+	for _, name := range awsservices.ServiceNames {
+		cloud.ServiceRegistry[name] = c.Service(name)
+	}
+	awsservices.AccessService = cloud.ServiceRegistry["access"]
+	awsservices.InfraService = cloud.ServiceRegistry["infra"]
+	awsservices.StorageService = cloud.ServiceRegistry["storage"]
+	awsservices.MessagingService = cloud.ServiceRegistry["messaging"]
+	awsservices.DnsService = cloud.ServiceRegistry["dns"]
+	awsservices.LambdaService = cloud.ServiceRegistry["lambda"]
+	awsservices.MonitoringService = cloud.ServiceRegistry["monitoring"]
+	awsservices.CdnService = cloud.ServiceRegistry["cdn"]
+	awsservices.CloudformationService = cloud.ServiceRegistry["cloudformation"]
+
+	return nil
+}
+
+// Cloud holds the simulated resources of a region and of the global services. The commands
+// run by its drivers change the resources (see templatetest.Driver), persisted after each command.
+type Cloud struct {
+	mu     sync.Mutex
+	dir    string
+	region string
+	driver *templatetest.Driver
+}
+
+// Open loads the simulated resources of the region persisted in the directory (none on first use)
+func Open(dir, region string) (*Cloud, error) {
+	c := &Cloud{dir: dir, region: region}
+
+	g := graph.NewGraph()
+	for _, r := range []string{globalRegion, region} {
+		path := c.resourcesPath(r)
+		if info, err := os.Stat(path); os.IsNotExist(err) || (err == nil && info.Size() == 0) {
+			continue
+		}
+		loaded, err := graph.NewGraphFromFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("loading simulated resources: %s", err)
+		}
+		g.AddGraph(loaded)
+	}
+	c.driver = templatetest.NewDriver(g)
+
+	records, err := ioutil.ReadFile(c.recordsPath())
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if err == nil {
+		if err = c.driver.UnmarshalRecords(records); err != nil {
+			return nil, fmt.Errorf("loading simulated records: %s", err)
+		}
+	}
+
+	return c, nil
+}
+
+// Service returns the simulation of the AWS service with the given name (i.e. infra, access)
+func (c *Cloud) Service(name string) cloud.Service {
+	return &service{name: name, cloud: c}
+}
+
+func (c *Cloud) fetch(types ...string) *graph.Graph {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.driver.Graph().Subgraph(types...)
+}
+
+// save persists the resources of the global services and of the region in separate files,
+// as for synced resources, along with the records of the driver
+func (c *Cloud) save() error {
+	if err := os.MkdirAll(c.dir, 0700); err != nil {
+		return err
+	}
+
+	var globalTypes, regionTypes []string
+	for _, t := range awsservices.ResourceTypes {
+		if awsservices.IsGlobalService(awsservices.ServicePerResourceType[t]) {
+			globalTypes = append(globalTypes, t)
+		} else {
+			regionTypes = append(regionTypes, t)
+		}
+	}
+	for r, types := range map[string][]string{globalRegion: globalTypes, c.region: regionTypes} {
+		if err := writeGraph(c.resourcesPath(r), c.driver.Graph().Subgraph(types...)); err != nil {
+			return err
+		}
+	}
+
+	records, err := c.driver.MarshalRecords()
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(c.recordsPath(), records, 0600)
+}
+
+func (c *Cloud) resourcesPath(region string) string {
+	return filepath.Join(c.dir, region+".triples")
+}
+
+func (c *Cloud) recordsPath() string {
+	return filepath.Join(c.dir, c.region+".records.json")
+}
+
+func writeGraph(path string, g *graph.Graph) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return g.MarshalTo(f)
+}
+
+type service struct {
+	name  string
+	cloud *Cloud
+}
+
+func (s *service) Name() string {
+	return s.name
+}
+
+func (s *service) Region() string {
+	if awsservices.IsGlobalService(s.name) {
+		return globalRegion
+	}
+	return s.cloud.region
+}
+
+func (s *service) Drivers() []driver.Driver {
+	return []driver.Driver{&serviceDriver{service: s.name, cloud: s.cloud}}
+}
+
+func (s *service) ResourceTypes() []string {
+	types := awsservices.ResourceTypesPerServiceName()[s.name]
+	sort.Strings(types)
+	return types
+}
+
+// Fetch returns the simulated resources of the service along with the region, as the AWS services do
+func (s *service) Fetch(context.Context) (*graph.Graph, error) {
+	g := s.cloud.fetch(s.ResourceTypes()...)
+	return g, g.AddResource(graph.InitResource(cloud.Region, s.Region()))
+}
+
+func (s *service) FetchByType(ctx context.Context, t string) (*graph.Graph, error) {
+	return s.cloud.fetch(t), nil
+}
+
+func (s *service) IsSyncDisabled() bool {
+	return false
+}
+
+// serviceDriver runs on the simulated resources the commands of the APIs of a service,
+// so that each command is found in a single driver as with the AWS drivers
+type serviceDriver struct {
+	service string
+	cloud   *Cloud
+	dryRun  bool
+}
+
+func (d *serviceDriver) SetDryRun(dry bool) {
+	d.dryRun = dry
+	d.cloud.driver.SetDryRun(dry)
+}
+
+func (d *serviceDriver) SetLogger(l *logger.Logger) {
+	d.cloud.driver.SetLogger(l)
+}
+
+func (d *serviceDriver) Lookup(lookups ...string) (driver.DriverFn, error) {
+	if len(lookups) != 2 {
+		return nil, driver.ErrDriverFnNotFound
+	}
+	api, ok := awsdriver.APIPerTemplateDefName[lookups[0]+lookups[1]]
+	if !ok || awsservices.ServicePerAPI[api] != d.service {
+		return nil, driver.ErrDriverFnNotFound
+	}
+	fn, err := d.cloud.driver.Lookup(lookups...)
+	if err != nil || d.dryRun {
+		return fn, err
+	}
+
+	return func(ctx driver.Context, params map[string]interface{}) (interface{}, error) {
+		d.cloud.mu.Lock()
+		defer d.cloud.mu.Unlock()
+		result, err := fn(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		if err = d.cloud.save(); err != nil {
+			return result, fmt.Errorf("persisting simulated resources: %s", err)
+		}
+		return result, nil
+	}, nil
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package awssim
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	awsdriver "github.com/wallix/awless/aws/driver"
+	"github.com/wallix/awless/aws/services"
+	"github.com/wallix/awless/cloud/properties"
+	"github.com/wallix/awless/template"
+	"github.com/wallix/awless/template/driver"
+)
+
+func runOn(t *testing.T, c *Cloud, text string) *template.Template {
+	var drivers []driver.Driver
+	for _, name := range awsservices.ServiceNames {
+		drivers = append(drivers, c.Service(name).Drivers()...)
+	}
+	env := template.NewEnv()
+	env.DefLookupFunc = awsdriver.AWSLookupDefinitions
+	env.Driver = driver.NewMultiDriver(drivers...)
+
+	tpl, env, err := template.Compile(template.MustParse(text), env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = tpl.DryRun(env); err != nil {
+		t.Fatal(err)
+	}
+	if tpl, err = tpl.Run(env); err != nil {
+		t.Fatal(err)
+	}
+	for _, cmd := range tpl.CommandNodesIterator() {
+		if err := cmd.Err(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return tpl
+}
+
+func TestSimulatedCloud(t *testing.T) {
+	dir, err := ioutil.TempDir("", "awless-sim")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	c, err := Open(dir, "eu-west-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	runOn(t, c, `vpc = create vpc cidr=10.0.0.0/16 name=myvpc
+subnet = create subnet cidr=10.0.1.0/24 vpc=$vpc
+create instance subnet=$subnet image=ami-1234 type=t2.micro count=1 name=web
+create volume availabilityzone=eu-west-1a size=10
+create user name=jdoe`)
+
+	for path, exists := range map[string]bool{"eu-west-1.triples": true, "global.triples": true, "eu-west-1.records.json": true} {
+		if _, err := os.Stat(filepath.Join(dir, path)); os.IsNotExist(err) == exists {
+			t.Fatalf("%s: expected file existence %t", path, exists)
+		}
+	}
+
+	t.Run("fetch per service and type", func(t *testing.T) {
+		reopened, err := Open(dir, "eu-west-1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		infra, err := reopened.Service("infra").Fetch(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		resources, _ := infra.GetAllResources("vpc", "subnet", "instance", "volume", "user")
+		if got, want := len(resources), 4; got != want {
+			t.Fatalf("got %d, want %d", got, want)
+		}
+		inst, err := infra.GetResource("instance", "i-1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := inst.Properties[properties.State], "running"; got != want {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+		if parent := infra.FindAncestor(inst, "vpc"); parent == nil || parent.Id() != "vpc-1" {
+			t.Fatalf("expected vpc-1 ancestor of instance, got %v", parent)
+		}
+
+		users, err := reopened.Service("access").FetchByType(context.Background(), "user")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if all, _ := users.GetAllResources("user"); len(all) != 1 {
+			t.Fatalf("expected 1 user, got %v", all)
+		}
+		if got, want := reopened.Service("access").Region(), "global"; got != want {
+			t.Fatalf("got %s, want %s", got, want)
+		}
+	})
+
+	t.Run("attach and detach across sessions", func(t *testing.T) {
+		attaching, err := Open(dir, "eu-west-1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		runOn(t, attaching, "attach volume id=vol-1 instance=i-1 device=/dev/sdh")
+
+		detaching, err := Open(dir, "eu-west-1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		vol, _ := detaching.fetch("volume").GetResource("volume", "vol-1")
+		if got, want := vol.Properties[properties.State], "in-use"; got != want {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+		runOn(t, detaching, "detach volume id=vol-1 instance=i-1 device=/dev/sdh")
+
+		vol, _ = detaching.fetch("volume").GetResource("volume", "vol-1")
+		if got, want := vol.Properties[properties.State], "available"; got != want {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("other regions are separate", func(t *testing.T) {
+		other, err := Open(dir, "us-east-1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if all, _ := other.fetch("vpc", "user").GetAllResources("vpc", "user"); len(all) != 1 || all[0].Type() != "user" {
+			t.Fatalf("expected only the global user, got %v", all)
+		}
+	})
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/wallix/awless/cloud"
+	"github.com/wallix/awless/config"
+	"github.com/wallix/awless/database"
+	"github.com/wallix/awless/logger"
+	"github.com/wallix/awless/sync"
+	"github.com/wallix/awless/template"
+)
+
+// driftExitCode is the exit status when drift is found, errors exiting with 1
+const driftExitCode = 2
+
+func init() {
+	RootCmd.AddCommand(driftCmd)
+}
+
+var driftCmd = &cobra.Command{
+	Use:   "drift [REVERTID]",
+	Short: "Check that the resources created or updated by your templates have not drifted since (see `awless log`)",
+	Long: `Compare the resources created or updated by a template execution (or by all the executions
+logged in the current region) with your synced resources, and report the deleted resources,
+the properties changed since and the relations (i.e. subnet, security groups) missing or unexpected.
+
+Reverted commands, and resources deleted or updated by later templates, are not checked.
+A full sync runs first, unless using --local, and drift is not checked when it fails.
+Exits with status 2 when drift is found.`,
+	Example: `  awless drift
+  awless drift 01BA7RV6ES86PZYCM3H28WM6KZ
+  awless drift --local || mail -s "awless drift" ops@example.com`,
+	PersistentPreRun:  applyHooks(initLoggerHook, initAwlessEnvHook, initCloudServicesHook, initSyncerHook, firstInstallDoneHook),
+	PersistentPostRun: applyHooks(verifyNewVersionHook, onVersionUpgrade, networkMonitorHook),
+
+	RunE: func(c *cobra.Command, args []string) error {
+		if len(args) > 1 {
+			return errors.New("expecting at most one REVERTID (see `awless log` to list revert ids)")
+		}
+
+		var all []*database.LoadedTemplate
+		exitOn(database.Execute(func(db *database.DB) (dberr error) {
+			all, dberr = db.ListTemplates()
+			return
+		}))
+
+		var executions []*template.TemplateExecution
+		for _, loaded := range all {
+			if loaded.Err != nil {
+				logger.Verbosef("skipping template '%s' in error: %s", loaded.Key, loaded.Err)
+				continue
+			}
+			if loc := loaded.TplExec.Locale; loc != "" && loc != config.GetAWSRegion() {
+				continue
+			}
+			if sync.AtRevision != nil && loaded.TplExec.Date().Unix() > sync.AtRevision.Date.Unix() {
+				continue
+			}
+			executions = append(executions, loaded.TplExec)
+		}
+
+		checked := executions
+		if len(args) == 1 {
+			checked = nil
+			for _, tplExec := range executions {
+				if tplExec.ID == args[0] {
+					checked = append(checked, tplExec)
+				}
+			}
+			if len(checked) == 0 {
+				exitOn(driftTemplateNotFound(args[0], all))
+			}
+			if checked[0].RevertStatus() == template.RevertedStatus {
+				exitOn(fmt.Errorf("template %s has been reverted by %s: nothing to check", checked[0].ID, checked[0].RevertedBy))
+			}
+		}
+
+		if !localGlobalFlag {
+			logger.Info("Running full sync before checking drift (disable it with --local flag)")
+			var services []cloud.Service
+			for _, srv := range cloud.ServiceRegistry {
+				services = append(services, srv)
+			}
+			if _, err := sync.DefaultSyncer.Sync(services...); err != nil {
+				exitOn(fmt.Errorf("sync failed, not checking drift against stale resources (use --local to check the last synced ones): %s", err))
+			}
+		}
+
+		g, err := sync.LoadLocalGraphs(config.GetAWSRegion())
+		exitOn(err)
+
+		var driftCount int
+		for _, tplExec := range checked {
+			if tplExec.RevertStatus() == template.RevertedStatus {
+				continue
+			}
+			reverted, err := revertedCommands(tplExec)
+			exitOn(err)
+			drifts, err := tplExec.Drifts(g, reverted)
+			exitOn(err)
+
+			var kept []*template.Drift
+			for _, d := range drifts {
+				if !changedByLaterTemplate(d, tplExec, executions) {
+					kept = append(kept, d)
+				}
+			}
+			if len(kept) > 0 {
+				displayDrifts(tplExec, kept)
+				driftCount += len(kept)
+			}
+		}
+
+		if driftCount == 0 {
+			fmt.Printf("No drift found in %d template(s)\n", len(checked))
+			return nil
+		}
+		os.Exit(driftExitCode)
+		return nil
+	},
+}
+
+func driftTemplateNotFound(id string, all []*database.LoadedTemplate) error {
+	for _, loaded := range all {
+		if loaded.Key != id {
+			continue
+		}
+		if loaded.Err != nil {
+			return fmt.Errorf("template '%s' in error: %s", id, loaded.Err)
+		}
+		loc := loaded.TplExec.Locale
+		return fmt.Errorf("template %s was run in region %s, you are currently in region %s (use the region flag: `awless drift %s -r %s`)", id, loc, config.GetAWSRegion(), id, loc)
+	}
+	return fmt.Errorf("no template with id '%s' (see `awless log` to list revert ids)", id)
+}
+
+// revertedCommands returns the commands of the execution reverted by its partial reverts
+func revertedCommands(tplExec *template.TemplateExecution) (map[int]bool, error) {
+	if tplExec.RevertStatus() != template.PartiallyRevertedStatus {
+		return nil, nil
+	}
+	var partialReverts []*template.TemplateExecution
+	err := database.Execute(func(db *database.DB) (dberr error) {
+		partialReverts, dberr = db.GetPartialReverts(tplExec)
+		return
+	})
+	return template.RevertedCommands(partialReverts...), err
+}
+
+// changedByLaterTemplate reports whether the drifted resource has been changed on purpose
+// by a template executed (and not reverted) after the one checked
+func changedByLaterTemplate(d *template.Drift, tplExec *template.TemplateExecution, executions []*template.TemplateExecution) bool {
+	for _, other := range executions {
+		if other.ID > tplExec.ID && other.RevertStatus() != template.RevertedStatus && other.ChangesResource(d.Resource) {
+			return true
+		}
+	}
+	return false
+}
+
+func displayDrifts(tplExec *template.TemplateExecution, drifts []*template.Drift) {
+	fmt.Printf("Template %s (%s)", tplExec.ID, tplExec.Date().Format("Mon Jan 2 15:04:05 2006"))
+	if tplExec.Message != "" {
+		fmt.Printf(": %s", tplExec.Message)
+	}
+	fmt.Println()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	for _, d := range drifts {
+		fmt.Fprintf(w, "  %d\t%s %s\t%s\n", d.Command, d.Entity, d.Resource, renderRedFn(d))
+	}
+	w.Flush()
+	fmt.Println()
+}
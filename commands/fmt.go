@@ -0,0 +1,97 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wallix/awless/template"
+)
+
+var (
+	writeFmtFlag bool
+	listFmtFlag  bool
+)
+
+func init() {
+	RootCmd.AddCommand(fmtCmd)
+
+	fmtCmd.Flags().BoolVarP(&writeFmtFlag, "write", "w", false, "Write the formatted template to its file instead of stdout")
+	fmtCmd.Flags().BoolVar(&listFmtFlag, "list", false, "List the templates whose formatting differs")
+}
+
+var fmtCmd = &cobra.Command{
+	Use:   "fmt PATH...",
+	Short: "Rewrite templates in canonical form, preserving comments",
+	Long: `Rewrite templates in canonical form: params sorted and consistently quoted,
+blocks indented, declarations and trailing comments aligned, comments preserved
+and blank lines between groups of statements collapsed.`,
+	Example:          "  awless fmt ~/templates/my-infra.aws\n  awless fmt -w ~/templates/*.aws",
+	PersistentPreRun: applyHooks(initLoggerHook),
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return errors.New("missing PATH arg (filepath or url)")
+		}
+
+		for _, path := range args {
+			exitOn(formatTemplate(path))
+		}
+		return nil
+	},
+}
+
+func formatTemplate(path string) error {
+	content, fullPath, err := getTemplateText(path)
+	if err != nil {
+		return err
+	}
+
+	formatted, err := template.Format(string(content))
+	if err != nil {
+		return fmt.Errorf("%s: %s", path, err)
+	}
+
+	if listFmtFlag {
+		if formatted != string(content) {
+			fmt.Println(fullPath)
+		}
+		return nil
+	}
+
+	if writeFmtFlag {
+		if strings.HasPrefix(path, "http") || strings.HasPrefix(path, "repo:") {
+			return fmt.Errorf("%s: cannot write remote template", path)
+		}
+		if formatted == string(content) {
+			return nil
+		}
+		info, err := os.Stat(fullPath)
+		if err != nil {
+			return err
+		}
+		return ioutil.WriteFile(fullPath, []byte(formatted), info.Mode())
+	}
+
+	fmt.Print(formatted)
+	return nil
+}
@@ -17,109 +17,179 @@ limitations under the License.
 package commands
 
 import (
+	"errors"
 	"fmt"
 	"os"
-
-	"github.com/wallix/awless/aws/services"
+	"strings"
+	"text/tabwriter"
+	"time"
 
 	"github.com/spf13/cobra"
-	"github.com/wallix/awless/cloud"
-	"github.com/wallix/awless/config"
-	"github.com/wallix/awless/console"
-	"github.com/wallix/awless/graph"
 	"github.com/wallix/awless/sync"
+	"github.com/wallix/awless/sync/repo"
 )
 
 var (
-	showProperties bool
+	historyPropertiesFlag bool
+	historySinceFlag      string
+	historyUntilFlag      string
 )
 
 func init() {
 	RootCmd.AddCommand(historyCmd)
 
-	historyCmd.Flags().BoolVar(&showProperties, "properties", false, "Full diff with resources properties")
+	historyCmd.Flags().BoolVar(&historyPropertiesFlag, "properties", false, "Show the properties of the resource when it appeared")
+	historyCmd.Flags().StringVar(&historySinceFlag, "since", "", "Only show changes synced from this date. Ex: \"2017-09-01\", \"2017-09-01 12:00\"")
+	historyCmd.Flags().StringVar(&historyUntilFlag, "until", "", "Only show changes synced up to this date. Ex: \"2017-09-30\", \"2017-09-30 18:00\"")
 }
 
 var historyCmd = &cobra.Command{
-	Use:               "history",
-	Hidden:            true,
-	Short:             "(in progress) Show a infra resource history & changes using your locally sync snapshots",
-	PersistentPreRun:  applyHooks(initLoggerHook, initAwlessEnvHook, initCloudServicesHook, initSyncerHook, firstInstallDoneHook),
-	PersistentPostRun: applyHooks(verifyNewVersionHook, onVersionUpgrade, networkMonitorHook),
+	Use:   "history RESOURCE",
+	Short: "Show when a resource appeared, changed and disappeared, using your locally synced revisions (offline)",
+	Long: `Show when a resource (by id or name) appeared, changed property by property, and disappeared,
+across all services and regions, by comparing the revisions recorded at each sync.`,
+	Example: `  awless history i-0d7bbc52cd8a6e8cf
+  awless history @my-instance --since "2017-09-01" --until "2017-09-30 18:00"
+  awless history my-vpc --properties`,
+	PersistentPreRun:  applyHooks(initLoggerHook, initAwlessEnvHook),
+	PersistentPostRun: applyHooks(verifyNewVersionHook, onVersionUpgrade),
 
 	RunE: func(cmd *cobra.Command, args []string) error {
-		region := config.GetAWSRegion()
-
-		root := graph.InitResource(cloud.Region, region)
+		if len(args) != 1 {
+			return errors.New("expecting a RESOURCE (id or name) arg")
+		}
 
-		var diffs []*sync.Diff
+		var since, until time.Time
+		var err error
+		if historySinceFlag != "" {
+			since, err = parseDateFlag("since", historySinceFlag)
+			exitOn(err)
+		}
+		if historyUntilFlag != "" {
+			until, err = parseUntilFlag(historyUntilFlag)
+			exitOn(err)
+		}
 
-		all, err := sync.DefaultSyncer.List()
+		r, err := repo.New()
 		exitOn(err)
+		all, err := r.List()
+		exitOn(err)
+		if len(all) == 0 {
+			exitOn(errors.New("no synced revisions found: revisions are recorded at each `awless sync`"))
+		}
 
-		for i := 1; i < len(all); i++ {
-			from, err := sync.DefaultSyncer.LoadRev(all[i-1].Id)
-			exitOn(err)
-
-			to, err := sync.DefaultSyncer.LoadRev(all[i].Id)
+		var revs []*repo.Rev
+		for _, rev := range revisionsBetween(all, since, until) {
+			loaded, err := r.LoadRev(rev.Id)
 			exitOn(err)
+			revs = append(revs, loaded)
+		}
 
-			d, err := sync.BuildDiff(from, to, root.Id())
-			exitOn(err)
+		id, events, err := sync.ResourceHistory(args[0], revs)
+		exitOn(err)
 
-			diffs = append(diffs, d)
+		var selected []*sync.ResourceEvent
+		for _, ev := range events {
+			if !since.IsZero() && ev.Rev.Date.Before(since) {
+				continue
+			}
+			selected = append(selected, ev)
 		}
-
-		for _, diff := range diffs {
-			displayRevisionDiff(diff, awsservices.InfraService.Name(), root, verboseGlobalFlag)
+		if len(selected) == 0 {
+			fmt.Printf("No changes of '%s' in synced revisions\n", id)
+			return nil
 		}
 
+		displayResourceHistory(id, selected)
 		return nil
 	},
 }
 
-func displayRevisionDiff(diff *sync.Diff, cloudService string, root *graph.Resource, verbose bool) {
-	fromRevision := "repository creation"
-	if diff.From.Id != "" {
-		fromRevision = diff.From.Id[:7] + " on " + diff.From.Date.Format("Monday January 2, 15:04")
+// revisionsBetween returns the revisions (sorted by date) up to until, starting from
+// the last revision before since to compare the first changes against it
+func revisionsBetween(all []*repo.Rev, since, until time.Time) (revs []*repo.Rev) {
+	start := 0
+	for i, rev := range all {
+		if !since.IsZero() && rev.Date.Before(since) {
+			start = i
+		}
+	}
+	for _, rev := range all[start:] {
+		if !until.IsZero() && rev.Date.After(until) {
+			break
+		}
+		revs = append(revs, rev)
 	}
+	return
+}
 
-	var graphdiff *graph.Diff
-	if cloudService == awsservices.InfraService.Name() {
-		graphdiff = diff.InfraDiff
+func displayResourceHistory(id string, events []*sync.ResourceEvent) {
+	last := events[len(events)-1]
+	fmt.Printf("%s %s", last.Type, id)
+	if last.Region != "" {
+		fmt.Printf(" (%s)", last.Region)
 	}
+	fmt.Println()
 
-	if showProperties {
-		if graphdiff.HasDiff() {
-			fmt.Println("▶", cloudService, "properties, from", fromRevision,
-				"to", diff.To.Id[:7], "on", diff.To.Date.Format("Monday January 2, 15:04"))
-			displayer, err := console.BuildOptions(
-				console.WithFormat("table"),
-				console.WithRootNode(root),
-			).SetSource(graphdiff).Build()
-			exitOn(err)
-			exitOn(displayer.Print(os.Stdout))
-			fmt.Println()
-		} else if verbose {
-			fmt.Println("▶", cloudService, "properties, from", fromRevision,
-				"to", diff.To.Id[:7], "on", diff.To.Date.Format("Monday January 2, 15:04"))
-			fmt.Println("No changes.")
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	for _, ev := range events {
+		shortId := ev.Rev.Id
+		if len(shortId) > 7 {
+			shortId = shortId[:7]
 		}
-	} else {
-		if graphdiff.HasDiff() {
-			fmt.Println("▶", cloudService, "resources, from", fromRevision,
-				"to", diff.To.Id[:7], "on", diff.To.Date.Format("Monday January 2, 15:04"))
-			displayer, err := console.BuildOptions(
-				console.WithFormat("tree"),
-				console.WithRootNode(root),
-			).SetSource(graphdiff).Build()
-			exitOn(err)
-			exitOn(displayer.Print(os.Stdout))
-			fmt.Println()
-		} else if verbose {
-			fmt.Println("▶", cloudService, "resources, from", fromRevision,
-				"to", diff.To.Id[:7], "on", diff.To.Date.Format("Monday January 2, 15:04"))
-			fmt.Println("No resource changes.")
+		fmt.Fprintf(w, "%s\t%s\t%s", ev.Rev.DateString(), shortId, ev.Kind)
+
+		var lines []string
+		for _, change := range ev.Changes {
+			switch {
+			case ev.Kind == sync.Appeared && !historyPropertiesFlag:
+			case ev.Kind == sync.Appeared:
+				lines = append(lines, fmt.Sprintf("%s: %s", change.Property, historyValue(change.To)))
+			default:
+				lines = append(lines, fmt.Sprintf("%s: %s -> %s", change.Property, historyValue(change.From), historyValue(change.To)))
+			}
+		}
+		for i, line := range lines {
+			if i > 0 {
+				fmt.Fprint(w, "\t\t")
+			}
+			fmt.Fprintf(w, "\t%s\n", line)
 		}
+		if len(lines) == 0 {
+			fmt.Fprintln(w)
+		}
+	}
+	w.Flush()
+}
+
+func historyValue(v interface{}) string {
+	switch vv := v.(type) {
+	case nil:
+		return "(none)"
+	case []string:
+		return "[" + strings.Join(vv, ", ") + "]"
+	case time.Time:
+		return vv.Local().Format("Mon Jan 2 15:04:05 2006")
+	}
+	return fmt.Sprint(v)
+}
+
+var dateFlagLayouts = []string{"2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02", time.RFC3339}
+
+// parseDateFlag parses a date given in local time, with or without time of day
+func parseDateFlag(name, value string) (time.Time, error) {
+	for _, layout := range dateFlagLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date '%s' for --%s: expecting YYYY-MM-DD, optionally followed by HH:MM[:SS]", value, name)
+}
+
+// parseUntilFlag parses an upper bound date, a day given without time of day including the whole day
+func parseUntilFlag(value string) (time.Time, error) {
+	if day, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return day.AddDate(0, 0, 1).Add(-time.Nanosecond), nil
 	}
+	return parseDateFlag("until", value)
 }
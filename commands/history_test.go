@@ -0,0 +1,56 @@
+package commands
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/wallix/awless/sync/repo"
+)
+
+func TestRevisionsBetween(t *testing.T) {
+	date := func(s string) time.Time {
+		d, err := time.ParseInLocation("2006-01-02 15:04", s, time.Local)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+	all := []*repo.Rev{
+		{Id: "1", Date: date("2017-09-29 10:00")},
+		{Id: "2", Date: date("2017-09-30 09:00")},
+		{Id: "3", Date: date("2017-09-30 23:30")},
+		{Id: "4", Date: date("2017-10-01 08:00")},
+	}
+	tcases := []struct {
+		since, until string
+		exp          []string
+	}{
+		{exp: []string{"1", "2", "3", "4"}},
+		{until: "2017-09-30", exp: []string{"1", "2", "3"}},
+		{until: "2017-09-30 18:00", exp: []string{"1", "2"}},
+		{since: "2017-09-30", exp: []string{"1", "2", "3", "4"}},
+		{since: "2017-09-30 10:00", until: "2017-09-30", exp: []string{"2", "3"}},
+	}
+	for i, tcase := range tcases {
+		var since, until time.Time
+		var err error
+		if tcase.since != "" {
+			if since, err = parseDateFlag("since", tcase.since); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if tcase.until != "" {
+			if until, err = parseUntilFlag(tcase.until); err != nil {
+				t.Fatal(err)
+			}
+		}
+		var ids []string
+		for _, rev := range revisionsBetween(all, since, until) {
+			ids = append(ids, rev.Id)
+		}
+		if got, want := ids, tcase.exp; !reflect.DeepEqual(got, want) {
+			t.Fatalf("%d: got %v, want %v", i+1, got, want)
+		}
+	}
+}
@@ -17,18 +17,23 @@ limitations under the License.
 package commands
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 	"github.com/wallix/awless/aws/services"
+	"github.com/wallix/awless/aws/sim"
 	"github.com/wallix/awless/cloud"
 	"github.com/wallix/awless/config"
 	"github.com/wallix/awless/database"
 	"github.com/wallix/awless/logger"
 	"github.com/wallix/awless/sync"
+	"github.com/wallix/awless/sync/repo"
 )
 
 func applyHooks(funcs ...func(*cobra.Command, []string) error) func(*cobra.Command, []string) {
@@ -41,8 +46,26 @@ func applyHooks(funcs ...func(*cobra.Command, []string) error) func(*cobra.Comma
 	}
 }
 
+const (
+	awsBackend = "aws"
+	simBackend = "sim"
+)
+
 func initAwlessEnvHook(cmd *cobra.Command, args []string) error {
-	if err := config.InitAwlessEnv(); err != nil {
+	var err error
+	switch backendGlobalFlag {
+	case awsBackend:
+		err = config.InitAwlessEnv()
+	case simBackend:
+		region := awsRegionGlobalFlag
+		if region == "" {
+			region = os.Getenv("AWS_DEFAULT_REGION")
+		}
+		err = config.InitSimulatedEnv(region)
+	default:
+		return fmt.Errorf("unknown backend '%s' (expecting %s or %s)", backendGlobalFlag, awsBackend, simBackend)
+	}
+	if err != nil {
 		return fmt.Errorf("cannot init awless environment: %s", err)
 	}
 	if awsRegionGlobalFlag != "" {
@@ -71,6 +94,55 @@ func initAwlessEnvHook(cmd *cobra.Command, args []string) error {
 		color.NoColor = false
 	}
 
+	return loadRevisionFromFlags(cmd)
+}
+
+var revisionCommands = map[string]bool{"list": true, "show": true, "query": true, "inspect": true, "drift": true}
+
+// loadRevisionFromFlags makes commands read local graphs from the revision given with --at or --rev.
+// These commands then work offline, as the cloud cannot be fetched in the past.
+func loadRevisionFromFlags(cmd *cobra.Command) error {
+	if atGlobalFlag == "" && revGlobalFlag == "" {
+		return nil
+	}
+	if atGlobalFlag != "" && revGlobalFlag != "" {
+		return errors.New("--at and --rev cannot be used together")
+	}
+	top := cmd
+	for top.HasParent() && top.Parent() != RootCmd {
+		top = top.Parent()
+	}
+	if !revisionCommands[top.Name()] {
+		return fmt.Errorf("--at and --rev only apply to list, show, query, inspect and drift, not to %s", top.Name())
+	}
+
+	r, err := repo.New()
+	if err != nil {
+		return err
+	}
+	all, err := r.List()
+	if err != nil {
+		return err
+	}
+
+	var rev *repo.Rev
+	if revGlobalFlag != "" {
+		rev, err = repo.FindRev(all, revGlobalFlag)
+	} else {
+		var at time.Time
+		if at, err = parseDateFlag("at", atGlobalFlag); err == nil {
+			rev, err = repo.RevAt(all, at)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	if sync.AtRevision, err = r.LoadRev(rev.Id); err != nil {
+		return err
+	}
+	localGlobalFlag, noSyncGlobalFlag = true, true
+	logger.Infof("reading resources synced on %s (revision %s)", rev.DateString(), rev.Id[:7])
 	return nil
 }
 
@@ -79,10 +151,15 @@ func initCloudServicesHook(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 	awsConf := config.GetConfigWithPrefix("aws.")
-	logger.Verbosef("awless %s - loading AWS session with profile '%v' and region '%v'", config.Version, awsConf[config.ProfileConfigKey], awsConf[config.RegionConfigKey])
-
-	if err := awsservices.Init(awsConf, logger.DefaultLogger, config.SetProfileCallback, networkMonitorFlag); err != nil {
-		return err
+	if backendGlobalFlag == simBackend {
+		if err := awssim.Init(filepath.Join(config.AwlessHome, "cloud"), config.GetAWSRegion(), logger.DefaultLogger); err != nil {
+			return err
+		}
+	} else {
+		logger.Verbosef("awless %s - loading AWS session with profile '%v' and region '%v'", config.Version, awsConf[config.ProfileConfigKey], awsConf[config.RegionConfigKey])
+		if err := awsservices.Init(awsConf, logger.DefaultLogger, config.SetProfileCallback, networkMonitorFlag); err != nil {
+			return err
+		}
 	}
 
 	if config.TriggerSyncOnConfigUpdate && !strings.HasPrefix(cmd.Name(), "sync") {
@@ -155,7 +232,7 @@ func onVersionUpgrade(cmd *cobra.Command, args []string) error {
 }
 
 func verifyNewVersionHook(cmd *cobra.Command, args []string) error {
-	if localGlobalFlag {
+	if localGlobalFlag || backendGlobalFlag == simBackend {
 		return nil
 	}
 	config.VerifyNewVersionAvailable("https://updates.awless.io", os.Stderr)
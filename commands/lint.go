@@ -0,0 +1,98 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wallix/awless/aws/driver"
+	"github.com/wallix/awless/config"
+	"github.com/wallix/awless/logger"
+	"github.com/wallix/awless/template"
+)
+
+func init() {
+	RootCmd.AddCommand(lintCmd)
+}
+
+var lintCmd = &cobra.Command{
+	Use:   "lint PATH [param=value ...]",
+	Short: "Check a template for mistakes without running it (no credentials needed)",
+	Long: `Check a template for mistakes without running it nor accessing your cloud:
+undefined or unused references, unknown commands and params, holes never filled,
+duplicate names, instances without keypair and commands that cannot be reverted.
+
+Exits with a non-zero status when errors are found.`,
+	Example:          "  awless lint ~/templates/my-infra.txt\n  awless lint repo:create_vpc vpc.cidr=10.0.0.0/16",
+	PersistentPreRun: applyHooks(initLoggerHook),
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return errors.New("missing PATH arg (filepath or url)")
+		}
+
+		content, fullPath, err := getTemplateText(args[0])
+		exitOn(err)
+
+		extraParams, err := template.ParseParams(strings.Join(args[1:], " "))
+		exitOn(err)
+
+		issues, err := template.Lint(string(content), template.LintRules(awsdriver.AWSLookupDefinitions, lintFillers(extraParams))...)
+		exitOn(err)
+
+		var errCount int
+		for _, issue := range issues {
+			severity := renderYellowFn(issue.Severity)
+			if issue.Severity == template.LintError {
+				errCount++
+				severity = renderRedFn(issue.Severity)
+			}
+			position := fullPath
+			if issue.Line > 0 {
+				position = fmt.Sprintf("%s:%d:%d", fullPath, issue.Line, issue.Column)
+			}
+			fmt.Printf("%s: %s: %s [%s]\n", position, severity, issue.Message, issue.Rule)
+		}
+
+		if errCount > 0 {
+			exitOn(fmt.Errorf("%d error(s) found in template", errCount))
+		}
+		return nil
+	},
+}
+
+// lintFillers returns the given params along with the configured defaults, without
+// initializing the awless environment as it would require to resolve the region.
+func lintFillers(params map[string]interface{}) map[string]interface{} {
+	fillers := make(map[string]interface{})
+	if _, err := os.Stat(config.DBPath); err == nil {
+		if err = config.LoadConfig(); err != nil {
+			logger.Verbosef("cannot load config defaults: %s", err)
+		}
+		for k, v := range config.Defaults {
+			fillers[k] = v
+		}
+	}
+	for k, v := range params {
+		fillers[k] = v
+	}
+	return fillers
+}
@@ -17,12 +17,16 @@ limitations under the License.
 package commands
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/wallix/awless/aws/export"
 	"github.com/wallix/awless/database"
 	"github.com/wallix/awless/logger"
+	"github.com/wallix/awless/template"
 )
 
 var (
@@ -31,6 +35,7 @@ var (
 	limitLogCountFlag             int
 	rawJSONLogFlag, idOnlyLogFlag bool
 	fullLogFlag, shortLogFlag     bool
+	exportLogFlag                 string
 )
 
 func init() {
@@ -43,6 +48,7 @@ func init() {
 	logCmd.Flags().BoolVar(&shortLogFlag, "short", false, "Display one or more template log with less info")
 	logCmd.Flags().BoolVar(&fullLogFlag, "full", false, "Display template logs with full info")
 	logCmd.Flags().BoolVar(&idOnlyLogFlag, "id-only", false, "Show only log template IDs (i.e. revert IDs)")
+	logCmd.Flags().StringVar(&exportLogFlag, "export", "", fmt.Sprintf("Export the resources created by the given template as infrastructure as code: %s", strings.Join(awsexport.Formats, ", ")))
 }
 
 var logCmd = &cobra.Command{
@@ -54,6 +60,14 @@ var logCmd = &cobra.Command{
 	RunE: func(c *cobra.Command, args []string) error {
 		var all []*database.LoadedTemplate
 
+		if exportLogFlag != "" {
+			if len(args) != 1 {
+				return errors.New("export requires a single REVERTID")
+			}
+			exportLog(args[0], exportLogFlag)
+			return nil
+		}
+
 		printer := getPrinter(args)
 
 		if len(args) > 0 {
@@ -120,6 +134,36 @@ func print(all []*database.LoadedTemplate, printer logPrinter) {
 	}
 }
 
+func exportLog(id, format string) {
+	var loaded *database.LoadedTemplate
+	exitOn(database.Execute(func(db *database.DB) (dberr error) {
+		loaded, dberr = db.GetLoadedTemplate(id)
+		return
+	}))
+	if loaded.Err != nil {
+		exitOn(fmt.Errorf("template '%s' in error: %s", loaded.Key, loaded.Err))
+	}
+
+	reverted, err := revertedCommands(loaded.TplExec)
+	exitOn(err)
+
+	var all []*database.LoadedTemplate
+	exitOn(database.Execute(func(db *database.DB) (dberr error) {
+		all, dberr = db.ListTemplates()
+		return
+	}))
+	var later []*template.TemplateExecution
+	for _, other := range all {
+		if other.Err == nil && other.TplExec.ID > loaded.TplExec.ID {
+			later = append(later, other.TplExec)
+		}
+	}
+
+	out, err := awsexport.Export(loaded.TplExec, format, reverted, later...)
+	exitOn(err)
+	os.Stdout.Write(out)
+}
+
 func getPrinter(args []string) logPrinter {
 	var defaultPrinter logPrinter
 	if len(args) > 0 {
@@ -28,7 +28,7 @@ func (p *fullLogPrinter) print(t *template.TemplateExecution) error {
 		fmt.Fprintf(p.w, "\t%s\n\n", t.Message)
 	}
 
-	for _, cmd := range t.CommandNodesIterator() {
+	for i, cmd := range t.CommandNodesIterator() {
 		var status string
 		if cmd.CmdErr != nil {
 			status = renderRedFn("KO")
@@ -38,14 +38,14 @@ func (p *fullLogPrinter) print(t *template.TemplateExecution) error {
 
 		var line string
 		if v, ok := cmd.CmdResult.(string); ok && v != "" {
-			line = fmt.Sprintf("    %s\t%s\t[%s]", status, cmd.String(), v)
+			line = fmt.Sprintf("    %s\t%d. %s\t[%s]", status, i+1, cmd.String(), v)
 		} else {
-			line = fmt.Sprintf("    %s\t%s", status, cmd.String())
+			line = fmt.Sprintf("    %s\t%d. %s", status, i+1, cmd.String())
 		}
 
 		fmt.Fprintln(p.w, line)
 
-		writeError(cmd.Err(), p.w)
+		writeError(positionedErr(t, cmd.Err(), cmd.Pos), p.w)
 	}
 	return nil
 }
@@ -98,7 +98,7 @@ func (p *defaultPrinter) print(t *template.TemplateExecution) error {
 		}
 
 		fmt.Fprintln(p.w, line)
-		writeError(cmd.Err(), p.w)
+		writeError(positionedErr(t, cmd.Err(), cmd.Pos), p.w)
 	}
 	return nil
 }
@@ -144,9 +144,15 @@ func writeLogHeader(t *template.TemplateExecution, w io.Writer) {
 	if t.Locale != "" {
 		fmt.Fprintf(w, " in %s", renderBlueFn(t.Locale))
 	}
-	if !template.IsRevertible(t.Template) {
+	switch status := t.RevertStatus(); {
+	case status != template.AppliedStatus:
+		fmt.Fprintf(w, " (%s)", status)
+	case !template.IsRevertible(t.Template):
 		fmt.Fprintf(w, " (not revertible)")
 	}
+	if t.RevertOf != "" {
+		fmt.Fprintf(w, " revert of %s", renderYellowFn(t.RevertOf))
+	}
 }
 
 func writeMultilineLogHeader(t *template.TemplateExecution, w io.Writer) {
@@ -167,6 +173,21 @@ func writeMultilineLogHeader(t *template.TemplateExecution, w io.Writer) {
 	if t.Locale != "" {
 		fmt.Fprintf(w, "Region: %s\n", t.Locale)
 	}
+	switch t.RevertStatus() {
+	case template.RevertedStatus:
+		fmt.Fprintf(w, "Status: reverted by %s\n", t.RevertedBy)
+	case template.PartiallyRevertedStatus:
+		fmt.Fprintf(w, "Status: partially reverted by %s\n", strings.Join(t.PartiallyRevertedBy, ", "))
+	default:
+		fmt.Fprintf(w, "Status: %s\n", template.AppliedStatus)
+	}
+	if t.RevertOf != "" {
+		fmt.Fprintf(w, "Revert of: %s", t.RevertOf)
+		if len(t.RevertedCommands) > 0 {
+			fmt.Fprintf(w, " (commands %s)", joinInts(t.RevertedCommands))
+		}
+		fmt.Fprintln(w)
+	}
 	fmt.Fprintln(w)
 }
 
@@ -178,6 +199,15 @@ func writeError(err error, w io.Writer) {
 	}
 }
 
+// positionedErr prefixes the error of a command with its position in the template,
+// unless the template is a one-liner
+func positionedErr(t *template.TemplateExecution, err error, pos fmt.Stringer) error {
+	if err == nil || t.IsOneLiner() || pos.String() == "" {
+		return err
+	}
+	return fmt.Errorf("%s: %s", pos, err)
+}
+
 func formatMultiLineErrMsg(msg string) []string {
 	notabs := strings.Replace(msg, "\t", "", -1)
 	var indented []string
@@ -0,0 +1,155 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"github.com/wallix/awless/config"
+	"github.com/wallix/awless/console"
+	"github.com/wallix/awless/graph"
+	"github.com/wallix/awless/graph/query"
+	"github.com/wallix/awless/graph/query/sparql"
+	"github.com/wallix/awless/sync"
+)
+
+var (
+	queryFormatFlag  string
+	queryColumnsFlag []string
+	queryIDsOnlyFlag bool
+	querySortByFlag  []string
+	queryReverseFlag bool
+	querySPARQLFlag  bool
+)
+
+func init() {
+	RootCmd.AddCommand(queryCmd)
+
+	queryCmd.Flags().StringVar(&queryFormatFlag, "format", "table", "Output format: table, csv, tsv, json (default to table)")
+	queryCmd.Flags().StringSliceVar(&queryColumnsFlag, "columns", []string{}, "Select the properties to display in the columns. Ex: --columns id,name,cidr")
+	queryCmd.Flags().BoolVar(&queryIDsOnlyFlag, "ids", false, "List only ids")
+	queryCmd.Flags().StringSliceVar(&querySortByFlag, "sort", []string{"Id"}, "Sort tables by column(s) name(s)")
+	queryCmd.Flags().BoolVar(&queryReverseFlag, "reverse", false, "Use in conjunction with --sort to reverse sort")
+	queryCmd.Flags().BoolVar(&querySPARQLFlag, "sparql", false, "Run a SPARQL SELECT or ASK query over the resources synced in all regions (formats: table, csv, json)")
+}
+
+var queryCmd = &cobra.Command{
+	Use:   "query QUERY",
+	Short: "Query your locally synced resources with conditions across their relations (offline)",
+	Long: `Query your locally synced resources (see awless sync) with an expression language:
+
+    RESOURCETYPE [where CONDITION]
+
+Conditions compare properties (case insensitive) and tag values (tag.KEY)
+with =, !=, <, >, <=, >=, ~ (regex), !~ and in [v1, v2], test their
+existence with exists, and combine with and, or, not and parentheses.
+
+Paths traverse relations before the property: a resource type goes to related
+resources of this type (i.e. subnet.vpc.tag.Env), parent and children to the
+direct ones, appliedon to the resources a resource is applied on, and dependents
+to the resources applied on it. Related resources compare by id, or by name with @.
+
+With --sparql, run a SPARQL SELECT or ASK query (basic graph patterns, FILTER,
+OPTIONAL, ORDER BY, LIMIT and OFFSET) over the RDF triples synced in all regions.
+Terms use the prefixed names of the local store (i.e. cloud:name, cloud-owl:Instance).`,
+	Example: `  awless query 'instances where State = running and Type in [t2.micro, t2.small]'
+  awless query 'instances where subnet.vpc.tag.Env = prod'
+  awless query 'securitygroups where not exists appliedon'
+  awless query 'volumes where Size >= 100 or Name ~ "^db-"' --format csv
+  awless query --sparql 'SELECT ?id ?name WHERE { ?i a cloud-owl:Instance ; cloud:id ?id . OPTIONAL { ?i cloud:name ?name } }'`,
+	PersistentPreRun:  applyHooks(initLoggerHook, initAwlessEnvHook),
+	PersistentPostRun: applyHooks(verifyNewVersionHook, onVersionUpgrade),
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return errors.New("missing QUERY arg")
+		}
+
+		if querySPARQLFlag {
+			return runSPARQLQuery(strings.Join(args, " "))
+		}
+
+		q, err := query.Parse(strings.Join(args, " "))
+		exitOn(err)
+
+		g, err := sync.LoadLocalGraphs(config.GetAWSRegion())
+		exitOn(err)
+
+		resources, err := q.Run(g)
+		exitOn(err)
+
+		matching := graph.NewGraph()
+		exitOn(matching.AddResource(resources...))
+
+		displayer, err := console.BuildOptions(
+			console.WithRdfType(q.Type),
+			console.WithColumns(queryColumnsFlag),
+			console.WithMaxWidth(console.GetTerminalWidth()),
+			console.WithFormat(queryFormatFlag),
+			console.WithIDsOnly(queryIDsOnlyFlag),
+			console.WithSortBy(querySortByFlag...),
+			console.WithReverseSort(queryReverseFlag),
+		).SetSource(matching).Build()
+		exitOn(err)
+
+		return displayer.Print(os.Stdout)
+	},
+}
+
+func runSPARQLQuery(text string) error {
+	q, err := sparql.Parse(text)
+	exitOn(err)
+
+	g, err := sync.LoadAllLocalGraphs()
+	exitOn(err)
+
+	result, err := q.Run(g)
+	exitOn(err)
+
+	switch queryFormatFlag {
+	case "json":
+		return result.WriteJSON(os.Stdout)
+	case "csv":
+		return result.WriteCSV(os.Stdout)
+	case "table":
+	default:
+		return fmt.Errorf("unknown format '%s' for SPARQL results (expecting table, csv or json)", queryFormatFlag)
+	}
+
+	if result.Ask {
+		fmt.Println(result.Boolean)
+		return nil
+	}
+	if result.Len() == 0 {
+		fmt.Println("No results found.")
+		return nil
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
+	table.SetCenterSeparator("|")
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAutoFormatHeaders(false)
+	table.SetHeader(result.Vars)
+	table.AppendBulk(result.Rows())
+	table.Render()
+	return nil
+}
@@ -19,6 +19,10 @@ package commands
 import (
 	"errors"
 	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
 
 	"github.com/spf13/cobra"
 	"github.com/wallix/awless/config"
@@ -27,14 +31,27 @@ import (
 	"github.com/wallix/awless/template"
 )
 
+var (
+	revertDryRunFlag bool
+	revertOnlyFlag   []string
+	revertSkipFlag   []string
+)
+
 func init() {
 	RootCmd.AddCommand(revertCmd)
+
+	revertCmd.Flags().BoolVar(&revertDryRunFlag, "dry-run", false, "Show the revert template and its order of execution without running it")
+	revertCmd.Flags().StringSliceVar(&revertOnlyFlag, "only", nil, "Revert only the commands selected by index (see `awless log`), entity or variable name")
+	revertCmd.Flags().StringSliceVar(&revertSkipFlag, "skip", nil, "Do not revert the commands selected by index (see `awless log`), entity or variable name")
 }
 
 var revertCmd = &cobra.Command{
-	Use:               "revert REVERTID",
-	Short:             "Revert a template execution given a revert ID (see `awless log` to list revert ids)",
-	Example:           "  awless revert 01BA7RV6ES86PZYCM3H28WM6KZ",
+	Use:   "revert REVERTID",
+	Short: "Revert a template execution given a revert ID (see `awless log` to list revert ids)",
+	Example: `  awless revert 01BA7RV6ES86PZYCM3H28WM6KZ
+  awless revert 01BA7RV6ES86PZYCM3H28WM6KZ --dry-run
+  awless revert 01BA7RV6ES86PZYCM3H28WM6KZ --only instance,3
+  awless revert 01BA7RV6ES86PZYCM3H28WM6KZ --skip subnet --skip mydb`,
 	PersistentPreRun:  applyHooks(initLoggerHook, initAwlessEnvHook, initCloudServicesHook, initSyncerHook, firstInstallDoneHook),
 	PersistentPostRun: applyHooks(verifyNewVersionHook, onVersionUpgrade, networkMonitorHook),
 
@@ -57,19 +74,136 @@ var revertCmd = &cobra.Command{
 			exitOn(errors.New("region mismatched"))
 		}
 
-		reverted, err := loaded.Template.Revert()
+		selected, indexes, err := loaded.Template.SelectCommands(revertOnlyFlag, revertSkipFlag)
+		exitOn(err)
+		exitOn(checkAlreadyReverted(loaded, indexes))
+		partial := len(indexes) < len(loaded.CommandNodesIterator())
+		if partial {
+			warnOnKeptDependents(loaded.Template, indexes)
+		}
+
+		reverted, err := selected.Revert()
 		exitOn(err)
 
+		if revertDryRunFlag {
+			printRevertPlan(loaded.Template, indexes, reverted)
+			return nil
+		}
+
 		tplExec := &template.TemplateExecution{
 			Template: reverted,
 			Locale:   config.GetAWSRegion(),
 			Profile:  config.GetAWSProfile(),
 			Source:   reverted.String(),
+			RevertOf: loaded.ID,
+		}
+		if partial {
+			tplExec.RevertedCommands = indexes
+			tplExec.SetMessage(fmt.Sprintf("Partial revert (commands %s): %s", joinInts(indexes), loaded.Message))
+		} else {
+			tplExec.SetMessage(fmt.Sprintf("Revert: %s", loaded.Message))
 		}
-		tplExec.SetMessage(fmt.Sprintf("Revert: %s", loaded.Message))
 
 		exitOn(runTemplate(tplExec))
 
 		return nil
 	},
 }
+
+// checkAlreadyReverted refuses to revert commands that have already been reverted,
+// unless forced or only previewing the revert
+func checkAlreadyReverted(loaded *template.TemplateExecution, indexes []int) error {
+	var msg string
+	switch loaded.RevertStatus() {
+	case template.RevertedStatus:
+		msg = fmt.Sprintf("template %s has already been reverted by %s", loaded.ID, loaded.RevertedBy)
+	case template.PartiallyRevertedStatus:
+		var partialReverts []*template.TemplateExecution
+		if err := database.Execute(func(db *database.DB) (dberr error) {
+			partialReverts, dberr = db.GetPartialReverts(loaded)
+			return
+		}); err != nil {
+			return err
+		}
+		reverted := template.RevertedCommands(partialReverts...)
+		var again []int
+		for _, i := range indexes {
+			if reverted[i] {
+				again = append(again, i)
+			}
+		}
+		if len(again) > 0 {
+			msg = fmt.Sprintf("commands %s of template %s have already been reverted (see `awless log %s`)", joinInts(again), loaded.ID, loaded.ID)
+		}
+	}
+
+	switch {
+	case msg == "":
+		return nil
+	case forceGlobalFlag || revertDryRunFlag:
+		logger.Warning(msg)
+		return nil
+	default:
+		return fmt.Errorf("%s. Use --force to revert again", msg)
+	}
+}
+
+// warnOnKeptDependents warns when reverting commands whose result is used by commands left in place
+func warnOnKeptDependents(tpl *template.Template, indexes []int) {
+	selected := make(map[int]bool)
+	for _, i := range indexes {
+		selected[i] = true
+	}
+	cmds := tpl.CommandNodesIterator()
+	for i, dependents := range tpl.RevertDependencies() {
+		if !selected[i] {
+			continue
+		}
+		for _, j := range dependents {
+			if !selected[j] {
+				logger.Warningf("reverting command %d (%s %s) while command %d (%s %s) which depends on it is not reverted", i, cmds[i-1].Action, cmds[i-1].Entity, j, cmds[j-1].Action, cmds[j-1].Entity)
+			}
+		}
+	}
+}
+
+// printRevertPlan shows the selected commands in their order of revert, with the
+// commands that have to be reverted first, followed by the revert template
+func printRevertPlan(tpl *template.Template, indexes []int, reverted *template.Template) {
+	cmds := tpl.CommandNodesIterator()
+	deps := tpl.RevertDependencies()
+	selected := make(map[int]bool)
+	for _, i := range indexes {
+		selected[i] = true
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(w, "Revert order of %s:\n", tpl.ID)
+	for k := len(indexes) - 1; k >= 0; k-- {
+		i := indexes[k]
+		var after []string
+		for _, j := range deps[i] {
+			if selected[j] {
+				after = append(after, strconv.Itoa(j))
+			}
+		}
+		var order string
+		if len(after) > 0 {
+			order = fmt.Sprintf("after %s", strings.Join(after, ", "))
+		}
+		fmt.Fprintf(w, "  %d\t%s\t%s\n", i, cmds[i-1], order)
+	}
+	w.Flush()
+
+	fmt.Println()
+	fmt.Println("Revert template:")
+	fmt.Println(renderGreenFn(reverted))
+}
+
+func joinInts(ints []int) string {
+	var strs []string
+	for _, i := range ints {
+		strs = append(strs, strconv.Itoa(i))
+	}
+	return strings.Join(strs, ",")
+}
@@ -32,6 +32,9 @@ var (
 	awsRegionGlobalFlag    string
 	awsProfileGlobalFlag   string
 	awsColorGlobalFlag     string
+	backendGlobalFlag      string
+	atGlobalFlag           string
+	revGlobalFlag          string
 	networkMonitorFlag     bool
 
 	renderGreenFn    = color.New(color.FgGreen).SprintFunc()
@@ -53,6 +56,9 @@ func init() {
 	RootCmd.PersistentFlags().StringVarP(&awsProfileGlobalFlag, "aws-profile", "p", "", "Override AWS profile temporarily for the current command")
 	RootCmd.PersistentFlags().SetAnnotation("aws-profile", cobra.BashCompCustom, []string{"__awless_profile_list"})
 	RootCmd.PersistentFlags().StringVar(&awsColorGlobalFlag, "color", "auto", "Force enabling/disabling colors in display (auto, never, always)")
+	RootCmd.PersistentFlags().StringVar(&backendGlobalFlag, "backend", awsBackend, "Cloud backend: 'aws', or 'sim' for resources simulated locally (no AWS account needed, kept in ~/.awless/sim)")
+	RootCmd.PersistentFlags().StringVar(&atGlobalFlag, "at", "", "Read resources as synced at this date (offline, for list, show, query, inspect and drift). Ex: --at \"2017-09-01 12:00\"")
+	RootCmd.PersistentFlags().StringVar(&revGlobalFlag, "rev", "", "Read resources as synced in this revision of the local repository (offline, for list, show, query, inspect and drift)")
 	RootCmd.PersistentFlags().BoolVar(&networkMonitorFlag, "network-monitor", false, "Debug requests with network monitor")
 	RootCmd.PersistentFlags().MarkHidden("network-monitor")
 
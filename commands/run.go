@@ -19,12 +19,14 @@ package commands
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -54,6 +56,12 @@ var (
 	scheduleRevertInFlag    string
 	runLogMessage           string
 	listRemoteTemplatesFlag bool
+	runParallelFlag         int
+	rollbackOnFailureFlag   bool
+	runOutputFlag           string
+	runParamsFilesFlag      []string
+	// runSecretParams are the params read from environment variables, masked when printed and in logs
+	runSecretParams []string
 )
 
 func init() {
@@ -62,6 +70,10 @@ func init() {
 	runCmd.Flags().StringVar(&scheduleRunInFlag, "run-in", "", "Postpone the execution of this template")
 	runCmd.Flags().StringVar(&scheduleRevertInFlag, "revert-in", "", "Schedule the revertion of this template")
 	runCmd.Flags().StringVarP(&runLogMessage, "message", "m", "", "Add a message for this template execution to be persisted in your logs")
+	runCmd.Flags().IntVar(&runParallelFlag, "parallel", 1, "Maximum number of independent commands to run concurrently")
+	runCmd.Flags().BoolVar(&rollbackOnFailureFlag, "rollback-on-failure", false, "Revert the successfully executed commands when the template fails")
+	runCmd.Flags().StringVarP(&runOutputFlag, "output", "o", "", "Print a machine-readable result of the run on stdout: json")
+	runCmd.Flags().StringSliceVar(&runParamsFilesFlag, "params", nil, "Fill holes from YAML, JSON or .env files, later files overriding earlier ones (i.e. --params common.yml --params staging.yml)")
 
 	var actions []string
 	for a := range awsdriver.DriverSupportedActions() {
@@ -75,6 +87,7 @@ func init() {
 		cmd := createDriverCommands(action, entities)
 		cmd.PersistentFlags().StringVar(&scheduleRunInFlag, "run-in", "", "Postpone the execution of this command")
 		cmd.PersistentFlags().StringVar(&scheduleRevertInFlag, "revert-in", "", "Schedule the revertion of this command")
+		cmd.PersistentFlags().BoolVar(&rollbackOnFailureFlag, "rollback-on-failure", false, "Revert the successfully executed commands when the command fails")
 		RootCmd.AddCommand(cmd)
 	}
 }
@@ -84,7 +97,7 @@ const maxMsgLen = 140
 var runCmd = &cobra.Command{
 	Use:               "run PATH",
 	Short:             "Run a template given a filepath or URL",
-	Example:           "  awless run ~/templates/my-infra.txt\n  awless run https://raw.githubusercontent.com/wallix/awless-templates/master/create_vpc.awls\n  awless run repo:create_vpc",
+	Example:           "  awless run ~/templates/my-infra.txt\n  awless run https://raw.githubusercontent.com/wallix/awless-templates/master/create_vpc.awls\n  awless run repo:create_vpc\n  awless run my-infra.aws --params common.yml --params staging.yml instance.type=t2.small",
 	PersistentPreRun:  applyHooks(initLoggerHook, initAwlessEnvHook, initCloudServicesHook, initSyncerHook, firstInstallDoneHook),
 	PersistentPostRun: applyHooks(verifyNewVersionHook, onVersionUpgrade, networkMonitorHook),
 
@@ -101,6 +114,10 @@ var runCmd = &cobra.Command{
 			exitOn(fmt.Errorf("message to be persisted should not exceed %d characters", maxMsgLen))
 		}
 
+		if runOutputFlag != "" && runOutputFlag != "json" {
+			return fmt.Errorf("unsupported output format '%s' (expecting json)", runOutputFlag)
+		}
+
 		content, fullPath, err := getTemplateText(args[0])
 		exitOn(err)
 
@@ -109,8 +126,18 @@ var runCmd = &cobra.Command{
 		templ, err := template.Parse(string(content))
 		exitOn(err)
 
+		fileParams, secrets, err := template.LoadParamsFiles(runParamsFilesFlag...)
+		exitOn(err)
+		runSecretParams = secrets
+
 		extraParams, err := template.ParseParams(strings.Join(args[1:], " "))
 		exitOn(err)
+		for k := range extraParams {
+			runSecretParams = removeString(runSecretParams, k)
+		}
+		if isSchedulingMode() && len(runSecretParams) > 0 {
+			exitOn(fmt.Errorf("cannot schedule a template with secret params (%s): they would be sent in clear to the scheduler", strings.Join(runSecretParams, ", ")))
+		}
 
 		tplExec := &template.TemplateExecution{
 			Template: templ,
@@ -121,7 +148,7 @@ var runCmd = &cobra.Command{
 			Source:   templ.String(),
 		}
 
-		exitOn(runTemplate(tplExec, config.Defaults, extraParams))
+		exitOn(runTemplate(tplExec, config.Defaults, fileParams, extraParams))
 
 		return nil
 	},
@@ -131,7 +158,7 @@ func missingHolesStdinFunc() func(string) interface{} {
 	var count int
 	return func(hole string) (response interface{}) {
 		if count < 1 {
-			fmt.Println("Please specify (Ctrl+C to quit, Tab for completion):")
+			fmt.Fprintln(runHumanOutput(), "Please specify (Ctrl+C to quit, Tab for completion):")
 		}
 
 		var err error
@@ -200,6 +227,14 @@ func (l *onceLoader) mustLoad() *graph.Graph {
 
 var allGraphsOnce = &onceLoader{}
 
+func fetchGraphFunc(entity string) (*graph.Graph, error) {
+	srv, err := cloud.GetServiceForType(entity)
+	if err != nil {
+		return nil, err
+	}
+	return srv.FetchByType(context.WithValue(context.Background(), "force", true), entity)
+}
+
 func runTemplate(tplExec *template.TemplateExecution, fillers ...map[string]interface{}) error {
 	env := template.NewEnv()
 	env.Log = logger.DefaultLogger
@@ -207,9 +242,14 @@ func runTemplate(tplExec *template.TemplateExecution, fillers ...map[string]inte
 	env.DefLookupFunc = awsdriver.AWSLookupDefinitions
 	env.AliasFunc = resolveAliasFunc
 	env.MissingHolesFunc = missingHolesStdinFunc()
+	env.IncludeFunc = includeTemplateFunc(tplExec.Path)
+	env.ReadFileFunc = readTemplateFileFunc(tplExec.Path)
+	env.FetchGraphFunc = fetchGraphFunc
+	env.Concurrency = runParallelFlag
+	env.Secrets = runSecretParams
 
 	if len(env.Fillers) > 0 {
-		logger.ExtraVerbosef("default/given holes fillers: %s", sprintProcessedParams(env.Fillers))
+		logger.ExtraVerbosef("default/given holes fillers: %s", sprintProcessedParams(maskSecretParams(env.Fillers)))
 	}
 
 	var err error
@@ -217,6 +257,7 @@ func runTemplate(tplExec *template.TemplateExecution, fillers ...map[string]inte
 	exitOn(err)
 
 	tplExec.Fillers = env.GetProcessedFillers()
+	tplExec.Includes = env.GetIncludedSources()
 
 	validateTemplate(tplExec.Template)
 
@@ -240,29 +281,31 @@ func runTemplate(tplExec *template.TemplateExecution, fillers ...map[string]inte
 		exitOn(errors.New("Dry run failed"))
 	}
 
-	fmt.Printf("%s\n", renderGreenFn(tplExec.Template))
+	fmt.Fprintf(runHumanOutput(), "%s\n", renderGreenFn(tplExec.Template))
 
 	var yesorno string
 	if forceGlobalFlag {
 		yesorno = "y"
 	} else {
-		fmt.Println()
+		fmt.Fprintln(runHumanOutput())
 		if isSchedulingMode() {
-			fmt.Print("Confirm scheduling? (y/n): ")
+			fmt.Fprint(runHumanOutput(), "Confirm scheduling? (y/n): ")
 		} else {
-			fmt.Print("Confirm? (y/n): ")
+			fmt.Fprint(runHumanOutput(), "Confirm? (y/n): ")
 		}
 		_, err = fmt.Scanln(&yesorno)
 		exitOn(err)
 	}
 
 	if strings.TrimSpace(yesorno) == "y" {
-		me, err := awsservices.AccessService.(*awsservices.Access).GetIdentity()
-		if err != nil {
-			logger.Warningf("cannot resolve template author identity: %s", err)
-		} else {
-			tplExec.Author = me.ResourcePath
-			logger.ExtraVerbosef("resolved template author: %s", tplExec.Author)
+		if access, ok := awsservices.AccessService.(*awsservices.Access); ok {
+			me, err := access.GetIdentity()
+			if err != nil {
+				logger.Warningf("cannot resolve template author identity: %s", err)
+			} else {
+				tplExec.Author = me.ResourcePath
+				logger.ExtraVerbosef("resolved template author: %s", tplExec.Author)
+			}
 		}
 
 		if isSchedulingMode() {
@@ -274,7 +317,7 @@ func runTemplate(tplExec *template.TemplateExecution, fillers ...map[string]inte
 			logger.Errorf("Running template error: %s", err)
 		}
 
-		newDefaultTemplatePrinter(os.Stdout).print(tplExec)
+		newDefaultTemplatePrinter(runHumanOutput()).print(tplExec)
 
 		if tplExec.Message == "" {
 			if tplExec.IsOneLiner() {
@@ -289,18 +332,40 @@ func runTemplate(tplExec *template.TemplateExecution, fillers ...map[string]inte
 			}
 		}
 
+		var rollback *template.TemplateExecution
+		if rollbackOnFailureFlag && (err != nil || tplExec.Stats().KOCount > 0) {
+			rollback = rollbackTemplate(tplExec, env)
+		}
+
 		if err = database.Execute(func(db *database.DB) error {
+			if rollback != nil {
+				if err := db.AddTemplate(rollback); err != nil {
+					return err
+				}
+			}
+			if tplExec.RevertOf != "" {
+				return db.AddRevert(tplExec)
+			}
 			return db.AddTemplate(tplExec)
 		}); err != nil {
 			logger.Errorf("Cannot save executed template in awless logs: %s", err)
 		}
 
-		if template.IsRevertible(tplExec.Template) {
-			fmt.Println()
+		if rollback != nil && rollback.Stats().KOCount > 0 {
+			fmt.Fprintln(runHumanOutput())
+			logger.Errorf("Rollback failed. Revert it manually with `awless revert %s -r %s -p %s`", tplExec.Template.ID, config.GetAWSRegion(), config.GetAWSProfile())
+		} else if rollback == nil && template.IsRevertible(tplExec.Template) {
+			fmt.Fprintln(runHumanOutput())
 			logger.Infof("Revert this template with `awless revert %s -r %s -p %s`", tplExec.Template.ID, config.GetAWSRegion(), config.GetAWSProfile())
 		}
 
 		runSyncFor(tplExec)
+
+		if runOutputFlag == "json" {
+			result, err := tplExec.ResultJSON()
+			exitOn(err)
+			fmt.Printf("%s\n", result)
+		}
 	}
 
 	if tplExec.Stats().KOCount > 0 {
@@ -310,6 +375,62 @@ func runTemplate(tplExec *template.TemplateExecution, fillers ...map[string]inte
 	return nil
 }
 
+// rollbackTemplate immediately runs the revert of the successfully executed commands
+// of a failed template, returning the rollback execution linked to the failed one
+func rollbackTemplate(tplExec *template.TemplateExecution, env *template.Env) *template.TemplateExecution {
+	if !template.IsRevertible(tplExec.Template) {
+		logger.Info("Nothing to rollback: no successfully executed command can be reverted")
+		return nil
+	}
+
+	reverted, err := tplExec.Template.Revert()
+	if err != nil {
+		logger.Errorf("Cannot rollback template: %s", err)
+		return nil
+	}
+
+	revertEnv := template.NewEnv()
+	revertEnv.Log = logger.DefaultLogger
+	revertEnv.DefLookupFunc = awsdriver.AWSLookupDefinitions
+	revertEnv.Driver = env.Driver
+	revertEnv.FetchGraphFunc = fetchGraphFunc
+
+	if reverted, _, err = template.Compile(reverted, revertEnv); err != nil {
+		logger.Errorf("Cannot rollback template: %s", err)
+		return nil
+	}
+
+	fmt.Fprintln(runHumanOutput())
+	logger.Info("Rolling back successfully executed commands ...")
+
+	rollback := &template.TemplateExecution{
+		Author:   tplExec.Author,
+		Locale:   tplExec.Locale,
+		Profile:  tplExec.Profile,
+		Source:   reverted.String(),
+		RevertOf: tplExec.Template.ID,
+	}
+	rollback.SetMessage(fmt.Sprintf("Rollback: %s", tplExec.Message))
+
+	if rollback.Template, err = reverted.Run(revertEnv); err != nil {
+		logger.Errorf("Running rollback error: %s", err)
+	}
+	tplExec.LinkRevert(rollback)
+
+	newDefaultTemplatePrinter(runHumanOutput()).print(rollback)
+
+	return rollback
+}
+
+// runHumanOutput is where the human readable output of a run is written.
+// It is stderr when printing a machine-readable result to keep stdout parsable.
+func runHumanOutput() io.Writer {
+	if runOutputFlag == "json" {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
 func validateTemplate(tpl *template.Template) {
 	unicityRule := &template.UniqueNameValidator{LookupGraph: func(key string) (*graph.Graph, bool) {
 		g := sync.LoadLocalGraphForService(awsservices.ServicePerResourceType[key], config.GetAWSRegion())
@@ -507,6 +628,29 @@ func sprintProcessedParams(processed map[string]interface{}) string {
 	return strings.Join(str, ", ")
 }
 
+// maskSecretParams returns a copy of the params with the values of secrets masked
+func maskSecretParams(params map[string]interface{}) map[string]interface{} {
+	masked := make(map[string]interface{}, len(params))
+	for k, v := range params {
+		masked[k] = v
+	}
+	for _, k := range runSecretParams {
+		if _, ok := masked[k]; ok {
+			masked[k] = template.MaskedSecret
+		}
+	}
+	return masked
+}
+
+func removeString(list []string, s string) (out []string) {
+	for _, e := range list {
+		if e != s {
+			out = append(out, e)
+		}
+	}
+	return
+}
+
 func oneLinerShortDesc(action string, entities []string) string {
 	if len(entities) > 5 {
 		return fmt.Sprintf("%s, \u2026 (see `awless %s -h` for more)", strings.Join(entities[0:5], ", "), action)
@@ -528,8 +672,7 @@ type templateMetadata struct {
 
 func getTemplateText(path string) (content []byte, expanded string, err error) {
 	if strings.HasPrefix(path, "repo:") {
-		path = fmt.Sprintf("%s/%s", DEFAULT_REPO_PREFIX, strings.TrimPrefix(path[5:], "/"))
-		path = fmt.Sprintf("%s%s", strings.TrimSuffix(path, FILE_EXT), FILE_EXT)
+		path = fmt.Sprintf("%s%s", strings.TrimSuffix(repoURL(path), FILE_EXT), FILE_EXT)
 	}
 
 	expanded = path
@@ -567,6 +710,70 @@ func getTemplateText(path string) (content []byte, expanded string, err error) {
 	return content, expanded, nil
 }
 
+func includeTemplateFunc(rootPath string) func(path, from string) (string, string, error) {
+	return func(path, from string) (string, string, error) {
+		if from == "" {
+			from = rootPath
+		}
+		content, fullPath, err := getTemplateText(resolveIncludePath(path, from))
+		if err != nil {
+			return "", "", err
+		}
+		logger.ExtraVerbosef("included template '%s'", fullPath)
+		return string(content), fullPath, nil
+	}
+}
+
+// readTemplateFileFunc reads the files given to the file() function, relative to the template.
+// Remote templates only read files relative to their URL, never local files.
+func readTemplateFileFunc(rootPath string) func(path string) ([]byte, error) {
+	remote := strings.HasPrefix(rootPath, "http")
+	return func(path string) ([]byte, error) {
+		if remote && !isRelativePath(path) {
+			return nil, fmt.Errorf("file '%s': remote template '%s' can only read files relative to it", path, rootPath)
+		}
+		path = resolveIncludePath(path, rootPath)
+		if strings.HasPrefix(path, "repo:") {
+			path = repoURL(path)
+		}
+		if strings.HasPrefix(path, "http") {
+			return readHttpContent(path)
+		}
+		return ioutil.ReadFile(path)
+	}
+}
+
+// repoURL returns the URL of a path in the awless templates repository, i.e. repo:userdata/web.sh
+func repoURL(path string) string {
+	return fmt.Sprintf("%s/%s", DEFAULT_REPO_PREFIX, strings.TrimPrefix(path[5:], "/"))
+}
+
+func isRelativePath(path string) bool {
+	if path == "" || strings.HasPrefix(path, "/") || strings.HasPrefix(path, "\\") || strings.HasPrefix(path, "~") || filepath.IsAbs(path) {
+		return false
+	}
+	u, err := url.Parse(path)
+	return err == nil && !u.IsAbs() && u.Host == ""
+}
+
+func resolveIncludePath(path, from string) string {
+	if strings.HasPrefix(path, "repo:") || strings.HasPrefix(path, "http") || filepath.IsAbs(path) || from == "" {
+		return path
+	}
+	if strings.HasPrefix(from, "http") {
+		base, err := url.Parse(from)
+		if err != nil {
+			return path
+		}
+		rel, err := url.Parse(path)
+		if err != nil {
+			return path
+		}
+		return base.ResolveReference(rel).String()
+	}
+	return filepath.Join(filepath.Dir(from), path)
+}
+
 func removeComments(b []byte) []byte {
 	scn := bufio.NewScanner(bytes.NewReader(b))
 	var cleaned bytes.Buffer
@@ -1,6 +1,12 @@
 package commands
 
-import "testing"
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
 
 func TestIsCSV(t *testing.T) {
 	tcases := []struct {
@@ -42,3 +48,53 @@ func TestJoinSentence(t *testing.T) {
 		}
 	}
 }
+
+func TestResolveIncludePath(t *testing.T) {
+	tcases := []struct {
+		path, from string
+		exp        string
+	}{
+		{path: "network.aws", from: "", exp: "network.aws"},
+		{path: "network.aws", from: "/home/user/infra/main.aws", exp: "/home/user/infra/network.aws"},
+		{path: "../common/network.aws", from: "/home/user/infra/main.aws", exp: "/home/user/common/network.aws"},
+		{path: "/tmp/network.aws", from: "/home/user/infra/main.aws", exp: "/tmp/network.aws"},
+		{path: "repo:network", from: "/home/user/infra/main.aws", exp: "repo:network"},
+		{path: "network.aws", from: "https://example.com/tpl/main.aws", exp: "https://example.com/tpl/network.aws"},
+		{path: "https://other.com/network.aws", from: "https://example.com/tpl/main.aws", exp: "https://other.com/network.aws"},
+	}
+	for i, tcase := range tcases {
+		if got, want := resolveIncludePath(tcase.path, tcase.from), tcase.exp; got != want {
+			t.Errorf("%d. got %q, want %q", i+1, got, want)
+		}
+	}
+}
+
+func TestReadTemplateFileFunc(t *testing.T) {
+	dir, err := ioutil.TempDir("", "awless-file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err = ioutil.WriteFile(filepath.Join(dir, "userdata.sh"), []byte("echo hello"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := readTemplateFileFunc(filepath.Join(dir, "main.aws"))("userdata.sh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(content), "echo hello"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+
+	readRemote := readTemplateFileFunc("https://example.com/tpl/main.aws")
+	for _, path := range []string{filepath.Join(dir, "userdata.sh"), "~/.ssh/id_rsa", "file:///etc/passwd", "//other.com/userdata.sh", "https://other.com/userdata.sh", "repo:userdata.sh"} {
+		if _, err := readRemote(path); err == nil || !strings.Contains(err.Error(), "can only read files relative to it") {
+			t.Errorf("%s: expected remote template not to read it, got %v", path, err)
+		}
+	}
+
+	if got, want := repoURL("repo:/userdata/web.sh"), DEFAULT_REPO_PREFIX+"/userdata/web.sh"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
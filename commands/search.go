@@ -18,6 +18,7 @@ package commands
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
@@ -59,7 +60,11 @@ var awsImagesCmd = &cobra.Command{
 			exitOn(fmt.Errorf("expecting image query string. Expecting: %s (with everything optional expect for the owner)", awsservices.ImageQuerySpec))
 		}
 
-		resolver := &awsservices.ImageResolver{InfraService: awsservices.InfraService.(*awsservices.Infra)}
+		infra, ok := awsservices.InfraService.(*awsservices.Infra)
+		if !ok {
+			exitOn(errors.New("search: images only available with the AWS backend"))
+		}
+		resolver := &awsservices.ImageResolver{InfraService: infra}
 
 		query, err := awsservices.ParseImageQuery(args[0])
 		exitOn(err)
@@ -26,6 +26,7 @@ import (
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+	"github.com/wallix/awless/aws/export"
 	"github.com/wallix/awless/cloud"
 	"github.com/wallix/awless/config"
 	"github.com/wallix/awless/console"
@@ -38,6 +39,7 @@ var (
 	listAllSiblingsFlag          bool
 	noAliasFlag                  bool
 	showPropertiesValuesOnlyFlag []string
+	showAsTemplateFlag           bool
 )
 
 func init() {
@@ -45,6 +47,7 @@ func init() {
 	showCmd.Flags().BoolVar(&listAllSiblingsFlag, "siblings", false, "List all the resource's siblings")
 	showCmd.Flags().BoolVar(&noAliasFlag, "no-alias", false, "Disable the resolution of ID to alias")
 	showCmd.Flags().StringSliceVar(&showPropertiesValuesOnlyFlag, "values-for", []string{}, "Output values only for given properties keys")
+	showCmd.Flags().BoolVar(&showAsTemplateFlag, "as-template", false, "Output a template recreating the resource, its children and the resources depending on them")
 }
 
 var showCmd = &cobra.Command{
@@ -53,7 +56,8 @@ var showCmd = &cobra.Command{
 	Example: `  awless show i-8d43b21b            # show an instance via its ref
   awless show AIDAJ3Z24GOKHTZO4OIX6 # show a user via its ref
   awless show jsmith                # show a user via its ref,
-  awless show @jsmith               # forcing search by name`,
+  awless show @jsmith               # forcing search by name
+  awless show my-vpc --as-template  # template recreating the vpc and its resources (i.e. in another region)`,
 	PersistentPreRun:  applyHooks(initLoggerHook, initAwlessEnvHook, initCloudServicesHook, initSyncerHook, firstInstallDoneHook),
 	PersistentPostRun: applyHooks(verifyNewVersionHook, onVersionUpgrade, networkMonitorHook),
 
@@ -98,9 +102,14 @@ var showCmd = &cobra.Command{
 		}
 
 		if resource != nil {
-			if len(showPropertiesValuesOnlyFlag) > 0 {
+			switch {
+			case showAsTemplateFlag:
+				tpl, err := awsexport.AsTemplate(gph, resource)
+				exitOn(err)
+				fmt.Print(tpl)
+			case len(showPropertiesValuesOnlyFlag) > 0:
 				showResourceValuesOnlyFor(resource, showPropertiesValuesOnlyFlag)
-			} else {
+			default:
 				showResource(resource, gph)
 			}
 		}
@@ -0,0 +1,163 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wallix/awless/graph"
+	"github.com/wallix/awless/template"
+	"github.com/wallix/awless/template/templatetest"
+)
+
+var (
+	testParamsFilesFlag []string
+	testGraphFlag       string
+	testNoRevertFlag    bool
+)
+
+func init() {
+	RootCmd.AddCommand(testCmd)
+
+	testCmd.Flags().StringSliceVar(&testParamsFilesFlag, "params", nil, "Fill holes from YAML, JSON or .env files, later files overriding earlier ones")
+	testCmd.Flags().StringVar(&testGraphFlag, "graph", "", "Simulate the templates on the resources of a graph file (i.e. as synced in ~/.awless/aws/rdf) instead of no resources")
+	testCmd.Flags().BoolVar(&testNoRevertFlag, "no-revert", false, "Do not check that the templates can be reverted")
+}
+
+var testCmd = &cobra.Command{
+	Use:   "test PATH [param=value ...]",
+	Short: "Run templates against simulated resources to test them (no credentials needed)",
+	Long: `Run templates against an in-memory simulation of the cloud resources, without
+accessing your cloud: resources are created with generated ids, updated, attached
+and deleted in a graph. A template passes when all its commands succeed and its
+revert leaves the resources as they were before the run.
+
+PATH is a template file or a directory holding templates (.aws files).
+Exits with a non-zero status when a template fails.`,
+	Example:          "  awless test ~/templates/my-infra.aws vpc.cidr=10.0.0.0/16\n  awless test ./templates --params ci.yml\n  awless test my-infra.aws --graph ~/.awless/aws/rdf/eu-west-1/infra.triples",
+	PersistentPreRun: applyHooks(initLoggerHook),
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return errors.New("missing PATH arg (file or directory)")
+		}
+
+		paths, err := testTemplatePaths(args[0])
+		exitOn(err)
+
+		fileParams, _, err := template.LoadParamsFiles(testParamsFilesFlag...)
+		exitOn(err)
+		extraParams, err := template.ParseParams(strings.Join(args[1:], " "))
+		exitOn(err)
+		for k, v := range extraParams {
+			fileParams[k] = v
+		}
+		fillers := lintFillers(fileParams)
+
+		initial := graph.NewGraph()
+		if testGraphFlag != "" {
+			initial, err = graph.NewGraphFromFile(testGraphFlag)
+			exitOn(err)
+		}
+
+		var failed int
+		for _, path := range paths {
+			changes, err := testTemplate(path, initial, fillers)
+			if err != nil {
+				failed++
+				fmt.Printf("%s %s: %s\n", renderRedFn("FAIL"), path, err)
+				continue
+			}
+			fmt.Printf("%s %s\n", renderGreenFn("PASS"), path)
+			for _, change := range changes {
+				fmt.Printf("\t%s\n", change)
+			}
+		}
+
+		if failed > 0 {
+			exitOn(fmt.Errorf("%d of %d template(s) failed", failed, len(paths)))
+		}
+		return nil
+	},
+}
+
+// testTemplate runs the template against the simulated resources, returning the changes
+// of the run on the resources, and checks that its revert restores them
+func testTemplate(path string, initial *graph.Graph, fillers map[string]interface{}) ([]string, error) {
+	content, fullPath, err := getTemplateText(path)
+	if err != nil {
+		return nil, err
+	}
+	tpl, err := template.Parse(string(content))
+	if err != nil {
+		return nil, err
+	}
+
+	env := template.NewEnv()
+	env.AddFillers(fillers)
+	env.IncludeFunc = includeTemplateFunc(fullPath)
+	env.ReadFileFunc = readTemplateFileFunc(fullPath)
+
+	result, err := templatetest.Run(tpl, initial, env)
+	if err != nil {
+		return nil, err
+	}
+	if err = result.Err(); err != nil {
+		return nil, err
+	}
+	changes := templatetest.Changes(initial, result.Graph)
+
+	if !testNoRevertFlag {
+		if err = result.CheckRevertible(); err != nil {
+			return changes, err
+		}
+	}
+	return changes, nil
+}
+
+// testTemplatePaths returns the path of a template, or the templates files of a directory
+func testTemplatePaths(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil || !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	var paths []string
+	err = filepath.Walk(path, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() && filepath.Ext(p) == FILE_EXT {
+			paths = append(paths, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no template (%s file) found in %s", FILE_EXT, path)
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
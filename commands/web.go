@@ -38,6 +38,8 @@ var webCmd = &cobra.Command{
 	Hidden: true,
 	Short:  "Browse your cloud data through a web ui",
 
+	PersistentPreRun: applyHooks(initLoggerHook, initAwlessEnvHook),
+
 	Run: func(cmd *cobra.Command, args []string) {
 		if !strings.HasPrefix(webPortFlag, ":") {
 			webPortFlag = ":" + webPortFlag
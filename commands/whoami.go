@@ -17,6 +17,7 @@ limitations under the License.
 package commands
 
 import (
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net"
@@ -60,7 +61,11 @@ var whoamiCmd = &cobra.Command{
 			return
 		}
 
-		me, err := awsservices.AccessService.(*awsservices.Access).GetIdentity()
+		access, ok := awsservices.AccessService.(*awsservices.Access)
+		if !ok {
+			exitOn(errors.New("whoami: identity only available with the AWS backend"))
+		}
+		me, err := access.GetIdentity()
 		exitOn(err)
 
 		if me.IsRoot() {
@@ -94,7 +99,7 @@ var whoamiCmd = &cobra.Command{
 
 		fmt.Printf("Username: %s, Id: %s, Account: %s\n", me.Resource, me.UserId, me.Account)
 
-		policies, err := awsservices.AccessService.(*awsservices.Access).GetUserPolicies(me.Resource)
+		policies, err := access.GetUserPolicies(me.Resource)
 		if err != nil {
 			logger.Error(err)
 			return
@@ -23,6 +23,7 @@ import (
 
 	"strconv"
 
+	"github.com/wallix/awless/aws/config"
 	"github.com/wallix/awless/aws/services"
 	"github.com/wallix/awless/database"
 )
@@ -33,14 +34,54 @@ var (
 	Dir                = filepath.Join(AwlessHome, "aws")
 	KeysDir            = filepath.Join(AwlessHome, "keys")
 	AwlessFirstInstall bool
+
+	// SimulatedHome holds the config, synced resources and logs of the simulated backend,
+	// apart from the ones of the AWS accounts
+	SimulatedHome = filepath.Join(AwlessHome, "sim")
 )
 
 func init() {
+	setAwlessHome(AwlessHome)
+}
+
+func setAwlessHome(home string) {
+	AwlessHome = home
+	DBPath = filepath.Join(AwlessHome, database.Filename)
+	Dir = filepath.Join(AwlessHome, "aws")
+	KeysDir = filepath.Join(AwlessHome, "keys")
+
 	os.Setenv("__AWLESS_HOME", AwlessHome)
 	os.Setenv("__AWLESS_CACHE", filepath.Join(AwlessHome, "cache"))
 	os.Setenv("__AWLESS_KEYS_DIR", KeysDir)
 }
 
+// InitSimulatedEnv inits the awless environment of the simulated backend in SimulatedHome.
+// On first use, the region is the given one (us-east-1 when empty) instead of being resolved from the AWS environment.
+func InitSimulatedEnv(region string) error {
+	setAwlessHome(SimulatedHome)
+
+	if _, err := os.Stat(DBPath); os.IsNotExist(err) {
+		if region == "" {
+			region = "us-east-1"
+		}
+		if err = os.MkdirAll(AwlessHome, 0700); err != nil {
+			return err
+		}
+		fromEnv := map[string]string{RegionConfigKey: region, instanceImageDefaultsKey: awsconfig.AmiPerRegion[region]}
+		if err = InitConfig(fromEnv); err != nil {
+			return err
+		}
+		err = database.Execute(func(db *database.DB) error {
+			return db.SetStringValue("current.version", Version)
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "cannot store current version in db: %s\n", err)
+		}
+	}
+
+	return InitAwlessEnv()
+}
+
 func InitAwlessEnv() error {
 	_, err := os.Stat(DBPath)
 
@@ -29,24 +29,87 @@ const TEMPLATES_BUCKET = "templates"
 
 func (db *DB) AddTemplate(tplExec *template.TemplateExecution) error {
 	return db.bolt.Update(func(tx *bolt.Tx) error {
-		if tplExec.ID == "" {
-			return errors.New("cannot persist template with empty ID")
-		}
+		return putTemplate(tx, tplExec)
+	})
+}
 
-		bucket, err := tx.CreateBucketIfNotExists([]byte(TEMPLATES_BUCKET))
-		if err != nil {
-			return fmt.Errorf("create bucket %s: %s", TEMPLATES_BUCKET, err)
+// AddRevert persists the execution of a revert, full or partial, and links
+// the template execution it reverts (see RevertOf) to it
+func (db *DB) AddRevert(revert *template.TemplateExecution) error {
+	return db.bolt.Update(func(tx *bolt.Tx) error {
+		if revert.RevertOf == "" {
+			return errors.New("cannot persist revert: reverted template ID is empty")
+		}
+		if err := putTemplate(tx, revert); err != nil {
+			return err
 		}
 
-		b, err := tplExec.MarshalJSON()
+		bucket := tx.Bucket([]byte(TEMPLATES_BUCKET))
+		reverted, err := getTemplate(bucket, revert.RevertOf)
 		if err != nil {
-			return err
+			return fmt.Errorf("reverted template: %s", err)
+		}
+		var partialReverts []*template.TemplateExecution
+		for _, id := range reverted.PartiallyRevertedBy {
+			partial, err := getTemplate(bucket, id)
+			if err != nil {
+				return fmt.Errorf("partial revert: %s", err)
+			}
+			partialReverts = append(partialReverts, partial)
 		}
+		reverted.LinkRevert(revert, partialReverts...)
 
-		return bucket.Put([]byte(tplExec.ID), b)
+		return putTemplate(tx, reverted)
 	})
 }
 
+// GetPartialReverts returns the partial or failed reverts of the given template execution
+func (db *DB) GetPartialReverts(tplExec *template.TemplateExecution) ([]*template.TemplateExecution, error) {
+	var reverts []*template.TemplateExecution
+	err := db.bolt.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(TEMPLATES_BUCKET))
+		if b == nil {
+			return errors.New("no templates stored yet")
+		}
+		for _, id := range tplExec.PartiallyRevertedBy {
+			revert, err := getTemplate(b, id)
+			if err != nil {
+				return err
+			}
+			reverts = append(reverts, revert)
+		}
+		return nil
+	})
+	return reverts, err
+}
+
+func getTemplate(b *bolt.Bucket, id string) (*template.TemplateExecution, error) {
+	content := b.Get([]byte(id))
+	if content == nil {
+		return nil, fmt.Errorf("no content for id '%s'", id)
+	}
+	tplExec := &template.TemplateExecution{}
+	return tplExec, tplExec.UnmarshalJSON(content)
+}
+
+func putTemplate(tx *bolt.Tx, tplExec *template.TemplateExecution) error {
+	if tplExec.ID == "" {
+		return errors.New("cannot persist template with empty ID")
+	}
+
+	bucket, err := tx.CreateBucketIfNotExists([]byte(TEMPLATES_BUCKET))
+	if err != nil {
+		return fmt.Errorf("create bucket %s: %s", TEMPLATES_BUCKET, err)
+	}
+
+	b, err := tplExec.MarshalJSON()
+	if err != nil {
+		return err
+	}
+
+	return bucket.Put([]byte(tplExec.ID), b)
+}
+
 func (db *DB) GetTemplate(id string) (*template.TemplateExecution, error) {
 	tplExec := &template.TemplateExecution{}
 
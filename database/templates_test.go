@@ -0,0 +1,88 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/wallix/awless/template"
+)
+
+func TestAddRevertLinksRevertedTemplate(t *testing.T) {
+	db, close := newTestDb()
+	defer close()
+
+	original := &template.TemplateExecution{Template: template.MustParse("create vpc cidr=10.0.0.0/16\ncreate subnet cidr=10.0.0.0/24")}
+	original.ID = "01BA7RV6ES86PZYCM3H28WM6KZ"
+	for i, cmd := range original.CommandNodesIterator() {
+		cmd.CmdResult = []string{"vpc-1234", "subnet-1234"}[i]
+	}
+	if err := db.AddTemplate(original); err != nil {
+		t.Fatal(err)
+	}
+
+	partial := &template.TemplateExecution{Template: template.MustParse("delete subnet id=subnet-1234"), RevertOf: original.ID, RevertedCommands: []int{2}}
+	partial.ID = "01BA7S3X0ZJ7S8W2R0C8ZPXJ6Y"
+	if err := db.AddRevert(partial); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := db.GetTemplate(original.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := loaded.RevertStatus(), template.PartiallyRevertedStatus; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := loaded.PartiallyRevertedBy, []string{partial.ID}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	reverts, err := db.GetPartialReverts(loaded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := template.RevertedCommands(reverts...), map[int]bool{2: true}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	last := &template.TemplateExecution{Template: template.MustParse("delete vpc id=vpc-1234"), RevertOf: original.ID, RevertedCommands: []int{1}}
+	last.ID = "01BA7SBN4A6ZHK4YB6TA4RT9FT"
+	if err := db.AddRevert(last); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err = db.GetTemplate(original.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := loaded.RevertedBy, last.ID; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	loaded, err = db.GetTemplate(last.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := loaded.RevertOf, original.ID; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+
+	unknown := &template.TemplateExecution{Template: template.MustParse("delete vpc id=vpc-1234"), RevertOf: "unknown"}
+	unknown.ID = "01BA7SGS3CXPBGXH7RBE5V2Z3Y"
+	if err := db.AddRevert(unknown); err == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package aws
 
 import (
+	"fmt"
 	"sort"
 
 	"github.com/wallix/awless/cloud"
@@ -26,6 +27,58 @@ type param struct {
 	AwsField, AwsType string
 	TemplateName      string
 	AsAwsTag          bool
+	Type              paramType
+}
+
+// paramType describes the values accepted for a template param. See template.ParamType
+type paramType struct {
+	Kind     string
+	Min, Max int
+	Values   []string
+	Of       string
+	Prefix   string
+}
+
+var (
+	cidrType         = paramType{Kind: "cidr"}
+	boolType         = paramType{Kind: "bool"}
+	intType          = paramType{Kind: "int"}
+	positiveIntType  = paramType{Kind: "int", Min: 1}
+	portType         = intRange(0, 65535)
+	arnType          = paramType{Kind: "arn"}
+	durationType     = paramType{Kind: "duration"}
+	elbProtocolType  = enumOf("HTTP", "HTTPS", "TCP")
+	instanceTypeType = paramType{Kind: "instancetype"}
+)
+
+func intRange(min, max int) paramType {
+	return paramType{Kind: "int", Min: min, Max: max}
+}
+
+func enumOf(values ...string) paramType {
+	return paramType{Kind: "enum", Values: values}
+}
+
+var idPrefixes = map[string]string{
+	cloud.Vpc:              "vpc-",
+	cloud.Subnet:           "subnet-",
+	cloud.Instance:         "i-",
+	cloud.SecurityGroup:    "sg-",
+	cloud.Image:            "ami-",
+	cloud.Volume:           "vol-",
+	cloud.Snapshot:         "snap-",
+	cloud.InternetGateway:  "igw-",
+	cloud.NatGateway:       "nat-",
+	cloud.RouteTable:       "rtb-",
+	cloud.NetworkInterface: "eni-",
+}
+
+func idOf(entity string) paramType {
+	prefix, ok := idPrefixes[entity]
+	if !ok {
+		panic(fmt.Sprintf("no id prefix for entity %s", entity))
+	}
+	return paramType{Kind: "id", Of: entity, Prefix: prefix}
 }
 
 type driver struct {
@@ -55,6 +108,20 @@ func (d *driver) ExtraKeys() []string {
 	return sortUnique(keys)
 }
 
+// TypedParams returns the params having a type, unique per template name
+func (d *driver) TypedParams() (typed []param) {
+	unique := make(map[string]bool)
+	for _, p := range append(d.RequiredParams, d.ExtraParams...) {
+		if p.Type.Kind == "" || unique[p.TemplateName] {
+			continue
+		}
+		unique[p.TemplateName] = true
+		typed = append(typed, p)
+	}
+	sort.Slice(typed, func(i, j int) bool { return typed[i].TemplateName < typed[j].TemplateName })
+	return
+}
+
 type driversDef struct {
 	Api     string
 	Drivers []driver
@@ -82,7 +149,7 @@ var DriversDefs = []driversDef{
 			{
 				Action: "create", Entity: cloud.Vpc, ApiMethod: "CreateVpc", Input: "CreateVpcInput", Output: "CreateVpcOutput", OutputExtractor: "aws.StringValue(output.Vpc.VpcId)",
 				RequiredParams: []param{
-					{AwsField: "CidrBlock", TemplateName: "cidr", AwsType: "awsstr"},
+					{AwsField: "CidrBlock", TemplateName: "cidr", AwsType: "awsstr", Type: cidrType},
 				},
 				ExtraParams: []param{
 					{AwsField: "Name", TemplateName: "name", AsAwsTag: true},
@@ -91,7 +158,7 @@ var DriversDefs = []driversDef{
 			{
 				Action: "delete", Entity: cloud.Vpc, ApiMethod: "DeleteVpc", Input: "DeleteVpcInput", Output: "DeleteVpcOutput",
 				RequiredParams: []param{
-					{AwsField: "VpcId", TemplateName: "id", AwsType: "awsstr"},
+					{AwsField: "VpcId", TemplateName: "id", AwsType: "awsstr", Type: idOf(cloud.Vpc)},
 				},
 			},
 
@@ -99,8 +166,8 @@ var DriversDefs = []driversDef{
 			{
 				Action: "create", Entity: cloud.Subnet, ApiMethod: "CreateSubnet", Input: "CreateSubnetInput", Output: "CreateSubnetOutput", OutputExtractor: "aws.StringValue(output.Subnet.SubnetId)",
 				RequiredParams: []param{
-					{AwsField: "CidrBlock", TemplateName: "cidr", AwsType: "awsstr"},
-					{AwsField: "VpcId", TemplateName: "vpc", AwsType: "awsstr"},
+					{AwsField: "CidrBlock", TemplateName: "cidr", AwsType: "awsstr", Type: cidrType},
+					{AwsField: "VpcId", TemplateName: "vpc", AwsType: "awsstr", Type: idOf(cloud.Vpc)},
 				},
 				ExtraParams: []param{
 					{AwsField: "AvailabilityZone", TemplateName: "availabilityzone", AwsType: "awsstr"},
@@ -110,16 +177,16 @@ var DriversDefs = []driversDef{
 			{
 				Action: "update", Entity: cloud.Subnet, ApiMethod: "ModifySubnetAttribute", Input: "ModifySubnetAttributeInput", Output: "ModifySubnetAttributeOutput", DryRunUnsupported: true,
 				RequiredParams: []param{
-					{AwsField: "SubnetId", TemplateName: "id", AwsType: "awsstr"},
+					{AwsField: "SubnetId", TemplateName: "id", AwsType: "awsstr", Type: idOf(cloud.Subnet)},
 				},
 				ExtraParams: []param{
-					{AwsField: "MapPublicIpOnLaunch", TemplateName: "public", AwsType: "awsboolattribute"},
+					{AwsField: "MapPublicIpOnLaunch", TemplateName: "public", AwsType: "awsboolattribute", Type: boolType},
 				},
 			},
 			{
 				Action: "delete", Entity: cloud.Subnet, ApiMethod: "DeleteSubnet", Input: "DeleteSubnetInput", Output: "DeleteSubnetOutput",
 				RequiredParams: []param{
-					{AwsField: "SubnetId", TemplateName: "id", AwsType: "awsstr"},
+					{AwsField: "SubnetId", TemplateName: "id", AwsType: "awsstr", Type: idOf(cloud.Subnet)},
 				},
 			},
 
@@ -127,63 +194,63 @@ var DriversDefs = []driversDef{
 			{
 				Action: "create", Entity: cloud.Instance, ApiMethod: "RunInstances", Input: "RunInstancesInput", Output: "Reservation", OutputExtractor: "aws.StringValue(output.Instances[0].InstanceId)",
 				RequiredParams: []param{
-					{AwsField: "ImageId", TemplateName: "image", AwsType: "awsstr"},
-					{AwsField: "MaxCount", TemplateName: "count", AwsType: "awsint64"},
-					{AwsField: "MinCount", TemplateName: "count", AwsType: "awsint64"},
-					{AwsField: "InstanceType", TemplateName: "type", AwsType: "awsstr"},
-					{AwsField: "SubnetId", TemplateName: "subnet", AwsType: "awsstr"},
+					{AwsField: "ImageId", TemplateName: "image", AwsType: "awsstr", Type: idOf(cloud.Image)},
+					{AwsField: "MaxCount", TemplateName: "count", AwsType: "awsint64", Type: positiveIntType},
+					{AwsField: "MinCount", TemplateName: "count", AwsType: "awsint64", Type: positiveIntType},
+					{AwsField: "InstanceType", TemplateName: "type", AwsType: "awsstr", Type: instanceTypeType},
+					{AwsField: "SubnetId", TemplateName: "subnet", AwsType: "awsstr", Type: idOf(cloud.Subnet)},
 					{AwsField: "Name", TemplateName: "name", AsAwsTag: true},
 				},
 				ExtraParams: []param{
 					{AwsField: "KeyName", TemplateName: "keypair", AwsType: "awsstr"},
 					{AwsField: "PrivateIpAddress", TemplateName: "ip", AwsType: "awsstr"},
 					{AwsField: "UserData", TemplateName: "userdata", AwsType: "awsfiletobase64"},
-					{AwsField: "SecurityGroupIds", TemplateName: "securitygroup", AwsType: "awsstringslice"},
-					{AwsField: "DisableApiTermination", TemplateName: "lock", AwsType: "awsbool"},
+					{AwsField: "SecurityGroupIds", TemplateName: "securitygroup", AwsType: "awsstringslice", Type: idOf(cloud.SecurityGroup)},
+					{AwsField: "DisableApiTermination", TemplateName: "lock", AwsType: "awsbool", Type: boolType},
 					{AwsField: "IamInstanceProfile.Name", TemplateName: "role", AwsType: "awsstr"},
 				},
 			},
 			{
 				Action: "update", Entity: cloud.Instance, ApiMethod: "ModifyInstanceAttribute", Input: "ModifyInstanceAttributeInput", Output: "ModifyInstanceAttributeOutput",
 				RequiredParams: []param{
-					{AwsField: "InstanceId", TemplateName: "id", AwsType: "awsstr"},
+					{AwsField: "InstanceId", TemplateName: "id", AwsType: "awsstr", Type: idOf(cloud.Instance)},
 				},
 				ExtraParams: []param{
-					{AwsField: "InstanceType.Value", TemplateName: "type", AwsType: "awsstr"},
-					{AwsField: "DisableApiTermination", TemplateName: "lock", AwsType: "awsboolattribute"},
+					{AwsField: "InstanceType.Value", TemplateName: "type", AwsType: "awsstr", Type: instanceTypeType},
+					{AwsField: "DisableApiTermination", TemplateName: "lock", AwsType: "awsboolattribute", Type: boolType},
 				},
 			},
 			{
 				Action: "delete", Entity: cloud.Instance, ApiMethod: "TerminateInstances", Input: "TerminateInstancesInput", Output: "TerminateInstancesOutput",
 				RequiredParams: []param{
-					{AwsField: "InstanceIds", TemplateName: "id", AwsType: "awsstringslice"},
+					{AwsField: "InstanceIds", TemplateName: "id", AwsType: "awsstringslice", Type: idOf(cloud.Instance)},
 				},
 			},
 			{
 				Action: "start", Entity: cloud.Instance, ApiMethod: "StartInstances", Input: "StartInstancesInput", Output: "StartInstancesOutput", OutputExtractor: "aws.StringValue(output.StartingInstances[0].InstanceId)",
 				RequiredParams: []param{
-					{AwsField: "InstanceIds", TemplateName: "id", AwsType: "awsstringslice"},
+					{AwsField: "InstanceIds", TemplateName: "id", AwsType: "awsstringslice", Type: idOf(cloud.Instance)},
 				},
 			},
 			{
 				Action: "stop", Entity: cloud.Instance, ApiMethod: "StopInstances", Input: "StopInstancesInput", Output: "StopInstancesOutput", OutputExtractor: "aws.StringValue(output.StoppingInstances[0].InstanceId)",
 				RequiredParams: []param{
-					{AwsField: "InstanceIds", TemplateName: "id", AwsType: "awsstringslice"},
+					{AwsField: "InstanceIds", TemplateName: "id", AwsType: "awsstringslice", Type: idOf(cloud.Instance)},
 				},
 			},
 			{
 				Action: "check", Entity: cloud.Instance, ManualFuncDefinition: true,
 				RequiredParams: []param{
-					{TemplateName: "id"},
-					{TemplateName: "state"},
-					{TemplateName: "timeout"},
+					{TemplateName: "id", Type: idOf(cloud.Instance)},
+					{TemplateName: "state", Type: enumOf("pending", "running", "shutting-down", "terminated", "stopping", "stopped", "not-found")},
+					{TemplateName: "timeout", Type: durationType},
 				},
 			},
 			// InstanceProfile
 			{
 				Action: "attach", Entity: cloud.InstanceProfile, ManualFuncDefinition: true,
 				RequiredParams: []param{
-					{AwsField: "InstanceId", TemplateName: "instance", AwsType: "awsstr"},
+					{AwsField: "InstanceId", TemplateName: "instance", AwsType: "awsstr", Type: idOf(cloud.Instance)},
 					{AwsField: "IamInstanceProfile.Name", TemplateName: "name", AwsType: "awsstr"},
 				},
 				ExtraParams: []param{
@@ -193,7 +260,7 @@ var DriversDefs = []driversDef{
 			{
 				Action: "detach", Entity: cloud.InstanceProfile, ManualFuncDefinition: true,
 				RequiredParams: []param{
-					{TemplateName: "instance"},
+					{TemplateName: "instance", Type: idOf(cloud.Instance)},
 					{TemplateName: "name"},
 				},
 				ExtraParams: []param{
@@ -206,19 +273,19 @@ var DriversDefs = []driversDef{
 				Action: "create", Entity: cloud.SecurityGroup, ApiMethod: "CreateSecurityGroup", Input: "CreateSecurityGroupInput", Output: "CreateSecurityGroupOutput", OutputExtractor: "aws.StringValue(output.GroupId)",
 				RequiredParams: []param{
 					{AwsField: "GroupName", TemplateName: "name", AwsType: "awsstr"},
-					{AwsField: "VpcId", TemplateName: "vpc", AwsType: "awsstr"},
+					{AwsField: "VpcId", TemplateName: "vpc", AwsType: "awsstr", Type: idOf(cloud.Vpc)},
 					{AwsField: "Description", TemplateName: "description", AwsType: "awsstr"},
 				},
 			},
 			{
 				Action: "update", Entity: cloud.SecurityGroup, ManualFuncDefinition: true,
 				RequiredParams: []param{
-					{TemplateName: "id"},
+					{TemplateName: "id", Type: idOf(cloud.SecurityGroup)},
 					{TemplateName: "protocol"},
 				},
 				ExtraParams: []param{
-					{TemplateName: "cidr"},
-					{TemplateName: "securitygroup"},
+					{TemplateName: "cidr", Type: cidrType},
+					{TemplateName: "securitygroup", Type: idOf(cloud.SecurityGroup)},
 					{TemplateName: "inbound"}, // either inbound or outbound = either authorize or revoke
 					{TemplateName: "outbound"},
 					{TemplateName: "portrange"},
@@ -227,33 +294,33 @@ var DriversDefs = []driversDef{
 			{
 				Action: "delete", Entity: cloud.SecurityGroup, ApiMethod: "DeleteSecurityGroup", Input: "DeleteSecurityGroupInput", Output: "DeleteSecurityGroupOutput",
 				RequiredParams: []param{
-					{AwsField: "GroupId", TemplateName: "id", AwsType: "awsstr"},
+					{AwsField: "GroupId", TemplateName: "id", AwsType: "awsstr", Type: idOf(cloud.SecurityGroup)},
 				},
 			},
 			{
 				Action: "check", Entity: cloud.SecurityGroup, ManualFuncDefinition: true,
 				RequiredParams: []param{
-					{TemplateName: "id"},
-					{TemplateName: "state"},
-					{TemplateName: "timeout"},
+					{TemplateName: "id", Type: idOf(cloud.SecurityGroup)},
+					{TemplateName: "state", Type: enumOf("unused")},
+					{TemplateName: "timeout", Type: durationType},
 				},
 			},
 			{
 				Action: "attach", Entity: cloud.SecurityGroup, ManualFuncDefinition: true,
 				RequiredParams: []param{
-					{TemplateName: "id"},
+					{TemplateName: "id", Type: idOf(cloud.SecurityGroup)},
 				},
 				ExtraParams: []param{
-					{TemplateName: "instance"},
+					{TemplateName: "instance", Type: idOf(cloud.Instance)},
 				},
 			},
 			{
 				Action: "detach", Entity: cloud.SecurityGroup, ManualFuncDefinition: true,
 				RequiredParams: []param{
-					{TemplateName: "id"},
+					{TemplateName: "id", Type: idOf(cloud.SecurityGroup)},
 				},
 				ExtraParams: []param{
-					{TemplateName: "instance"},
+					{TemplateName: "instance", Type: idOf(cloud.Instance)},
 				},
 			},
 			// IMAGES
@@ -265,7 +332,7 @@ var DriversDefs = []driversDef{
 					{AwsField: "SourceRegion", TemplateName: "source-region", AwsType: "awsstr"},
 				},
 				ExtraParams: []param{
-					{AwsField: "Encrypted", TemplateName: "encrypted", AwsType: "awsbool"},
+					{AwsField: "Encrypted", TemplateName: "encrypted", AwsType: "awsbool", Type: boolType},
 					{AwsField: "Description", TemplateName: "description", AwsType: "awsstr"},
 				},
 			},
@@ -278,7 +345,7 @@ var DriversDefs = []driversDef{
 					{AwsField: "LicenseType", TemplateName: "license", AwsType: "awsstr"},
 					{AwsField: "Platform", TemplateName: "platform", AwsType: "awsstr"},
 					{AwsField: "RoleName", TemplateName: "role", AwsType: "awsstr"},
-					{AwsField: "DiskContainers[0]SnapshotId", TemplateName: "snapshot", AwsType: "awsslicestruct"},
+					{AwsField: "DiskContainers[0]SnapshotId", TemplateName: "snapshot", AwsType: "awsslicestruct", Type: idOf(cloud.Snapshot)},
 					{AwsField: "DiskContainers[0]Url", TemplateName: "url", AwsType: "awsslicestruct"},
 					{AwsField: "DiskContainers[0]UserBucket.S3Bucket", TemplateName: "bucket", AwsType: "awsslicestruct"},
 					{AwsField: "DiskContainers[0]UserBucket.S3Key", TemplateName: "s3object", AwsType: "awsslicestruct"},
@@ -287,7 +354,7 @@ var DriversDefs = []driversDef{
 			{
 				Action: "delete", Entity: cloud.Image, ManualFuncDefinition: true,
 				RequiredParams: []param{
-					{TemplateName: "id"},
+					{TemplateName: "id", Type: idOf(cloud.Image)},
 					{TemplateName: "delete-snapshots"},
 				},
 			},
@@ -296,43 +363,43 @@ var DriversDefs = []driversDef{
 			{
 				Action: "create", Entity: cloud.NetworkInterface, ApiMethod: "CreateNetworkInterface", Input: "CreateNetworkInterfaceInput", Output: "CreateNetworkInterfaceOutput", OutputExtractor: "aws.StringValue(output.NetworkInterface.NetworkInterfaceId)",
 				RequiredParams: []param{
-					{AwsField: "SubnetId", TemplateName: "subnet", AwsType: "awsstr"},
+					{AwsField: "SubnetId", TemplateName: "subnet", AwsType: "awsstr", Type: idOf(cloud.Subnet)},
 				},
 				ExtraParams: []param{
 					{AwsField: "Description", TemplateName: "description", AwsType: "awsstr"},
-					{AwsField: "Groups", TemplateName: "securitygroups", AwsType: "awsstringslice"},
+					{AwsField: "Groups", TemplateName: "securitygroups", AwsType: "awsstringslice", Type: idOf(cloud.SecurityGroup)},
 					{AwsField: "PrivateIpAddress", TemplateName: "privateip", AwsType: "awsstr"},
 				},
 			},
 			{
 				Action: "delete", Entity: cloud.NetworkInterface, ApiMethod: "DeleteNetworkInterface", Input: "DeleteNetworkInterfaceInput", Output: "DeleteNetworkInterfaceOutput",
 				RequiredParams: []param{
-					{AwsField: "NetworkInterfaceId", TemplateName: "id", AwsType: "awsstr"},
+					{AwsField: "NetworkInterfaceId", TemplateName: "id", AwsType: "awsstr", Type: idOf(cloud.NetworkInterface)},
 				},
 			},
 			{
 				Action: "attach", Entity: cloud.NetworkInterface, ApiMethod: "AttachNetworkInterface", Input: "AttachNetworkInterfaceInput", Output: "AttachNetworkInterfaceOutput", OutputExtractor: "aws.StringValue(output.AttachmentId)",
 				RequiredParams: []param{
-					{AwsField: "NetworkInterfaceId", TemplateName: "id", AwsType: "awsstr"},
-					{AwsField: "InstanceId", TemplateName: "instance", AwsType: "awsstr"},
-					{AwsField: "DeviceIndex", TemplateName: "device-index", AwsType: "awsint64"},
+					{AwsField: "NetworkInterfaceId", TemplateName: "id", AwsType: "awsstr", Type: idOf(cloud.NetworkInterface)},
+					{AwsField: "InstanceId", TemplateName: "instance", AwsType: "awsstr", Type: idOf(cloud.Instance)},
+					{AwsField: "DeviceIndex", TemplateName: "device-index", AwsType: "awsint64", Type: intType},
 				},
 			},
 			{
 				Action: "detach", Entity: cloud.NetworkInterface, ManualFuncDefinition: true,
 				ExtraParams: []param{
 					{AwsField: "AttachmentId", TemplateName: "attachment", AwsType: "awsstr"},
-					{AwsField: "InstanceId", TemplateName: "instance", AwsType: "awsstr"},
-					{AwsField: "NetworkInterfaceId", TemplateName: "id", AwsType: "awsstr"},
-					{AwsField: "Force", TemplateName: "force", AwsType: "awsbool"},
+					{AwsField: "InstanceId", TemplateName: "instance", AwsType: "awsstr", Type: idOf(cloud.Instance)},
+					{AwsField: "NetworkInterfaceId", TemplateName: "id", AwsType: "awsstr", Type: idOf(cloud.NetworkInterface)},
+					{AwsField: "Force", TemplateName: "force", AwsType: "awsbool", Type: boolType},
 				},
 			},
 			{
 				Action: "check", Entity: cloud.NetworkInterface, ManualFuncDefinition: true,
 				RequiredParams: []param{
-					{TemplateName: "id"},
-					{TemplateName: "state"},
-					{TemplateName: "timeout"},
+					{TemplateName: "id", Type: idOf(cloud.NetworkInterface)},
+					{TemplateName: "state", Type: enumOf("available", "attaching", "detaching", "in-use", "not-found")},
+					{TemplateName: "timeout", Type: durationType},
 				},
 			},
 
@@ -341,47 +408,47 @@ var DriversDefs = []driversDef{
 				Action: "create", Entity: cloud.Volume, ApiMethod: "CreateVolume", Input: "CreateVolumeInput", Output: "Volume", OutputExtractor: "aws.StringValue(output.VolumeId)",
 				RequiredParams: []param{
 					{AwsField: "AvailabilityZone", TemplateName: "availabilityzone", AwsType: "awsstr"},
-					{AwsField: "Size", TemplateName: "size", AwsType: "awsint64"},
+					{AwsField: "Size", TemplateName: "size", AwsType: "awsint64", Type: intRange(1, 16384)},
 				},
 			},
 			{
 				Action: "check", Entity: cloud.Volume, ManualFuncDefinition: true,
 				RequiredParams: []param{
-					{TemplateName: "id"},
-					{TemplateName: "state"},
-					{TemplateName: "timeout"},
+					{TemplateName: "id", Type: idOf(cloud.Volume)},
+					{TemplateName: "state", Type: enumOf("available", "in-use", "not-found")},
+					{TemplateName: "timeout", Type: durationType},
 				},
 			},
 			{
 				Action: "delete", Entity: cloud.Volume, ApiMethod: "DeleteVolume", Input: "DeleteVolumeInput", Output: "DeleteVolumeOutput",
 				RequiredParams: []param{
-					{AwsField: "VolumeId", TemplateName: "id", AwsType: "awsstr"},
+					{AwsField: "VolumeId", TemplateName: "id", AwsType: "awsstr", Type: idOf(cloud.Volume)},
 				},
 			},
 			{
 				Action: "attach", Entity: cloud.Volume, ApiMethod: "AttachVolume", Input: "AttachVolumeInput", Output: "VolumeAttachment", OutputExtractor: "aws.StringValue(output.VolumeId)",
 				RequiredParams: []param{
 					{AwsField: "Device", TemplateName: "device", AwsType: "awsstr"},
-					{AwsField: "VolumeId", TemplateName: "id", AwsType: "awsstr"},
-					{AwsField: "InstanceId", TemplateName: "instance", AwsType: "awsstr"},
+					{AwsField: "VolumeId", TemplateName: "id", AwsType: "awsstr", Type: idOf(cloud.Volume)},
+					{AwsField: "InstanceId", TemplateName: "instance", AwsType: "awsstr", Type: idOf(cloud.Instance)},
 				},
 			},
 			{
 				Action: "detach", Entity: cloud.Volume, ApiMethod: "DetachVolume", Input: "DetachVolumeInput", Output: "VolumeAttachment", OutputExtractor: "aws.StringValue(output.VolumeId)",
 				RequiredParams: []param{
 					{AwsField: "Device", TemplateName: "device", AwsType: "awsstr"},
-					{AwsField: "VolumeId", TemplateName: "id", AwsType: "awsstr"},
-					{AwsField: "InstanceId", TemplateName: "instance", AwsType: "awsstr"},
+					{AwsField: "VolumeId", TemplateName: "id", AwsType: "awsstr", Type: idOf(cloud.Volume)},
+					{AwsField: "InstanceId", TemplateName: "instance", AwsType: "awsstr", Type: idOf(cloud.Instance)},
 				},
 				ExtraParams: []param{
-					{AwsField: "Force", TemplateName: "force", AwsType: "awsbool"},
+					{AwsField: "Force", TemplateName: "force", AwsType: "awsbool", Type: boolType},
 				},
 			},
 			// Snapshot
 			{
 				Action: "create", Entity: cloud.Snapshot, ApiMethod: "CreateSnapshot", Input: "CreateSnapshotInput", Output: "Snapshot", OutputExtractor: "aws.StringValue(output.SnapshotId)",
 				RequiredParams: []param{
-					{AwsField: "VolumeId", TemplateName: "volume", AwsType: "awsstr"},
+					{AwsField: "VolumeId", TemplateName: "volume", AwsType: "awsstr", Type: idOf(cloud.Volume)},
 				},
 				ExtraParams: []param{
 					{AwsField: "Description", TemplateName: "description", AwsType: "awsstr"},
@@ -390,7 +457,7 @@ var DriversDefs = []driversDef{
 			{
 				Action: "delete", Entity: cloud.Snapshot, ApiMethod: "DeleteSnapshot", Input: "DeleteSnapshotInput", Output: "DeleteSnapshotOutput",
 				RequiredParams: []param{
-					{AwsField: "SnapshotId", TemplateName: "id", AwsType: "awsstr"},
+					{AwsField: "SnapshotId", TemplateName: "id", AwsType: "awsstr", Type: idOf(cloud.Snapshot)},
 				},
 			},
 			{
@@ -400,7 +467,7 @@ var DriversDefs = []driversDef{
 					{AwsField: "SourceRegion", TemplateName: "source-region", AwsType: "awsstr"},
 				},
 				ExtraParams: []param{
-					{AwsField: "Encrypted", TemplateName: "encrypted", AwsType: "awsbool"},
+					{AwsField: "Encrypted", TemplateName: "encrypted", AwsType: "awsbool", Type: boolType},
 					{AwsField: "Description", TemplateName: "description", AwsType: "awsstr"},
 				},
 			},
@@ -411,21 +478,21 @@ var DriversDefs = []driversDef{
 			{
 				Action: "delete", Entity: cloud.InternetGateway, ApiMethod: "DeleteInternetGateway", Input: "DeleteInternetGatewayInput", Output: "DeleteInternetGatewayOutput",
 				RequiredParams: []param{
-					{AwsField: "InternetGatewayId", TemplateName: "id", AwsType: "awsstr"},
+					{AwsField: "InternetGatewayId", TemplateName: "id", AwsType: "awsstr", Type: idOf(cloud.InternetGateway)},
 				},
 			},
 			{
 				Action: "attach", Entity: cloud.InternetGateway, ApiMethod: "AttachInternetGateway", Input: "AttachInternetGatewayInput", Output: "AttachInternetGatewayOutput",
 				RequiredParams: []param{
-					{AwsField: "InternetGatewayId", TemplateName: "id", AwsType: "awsstr"},
-					{AwsField: "VpcId", TemplateName: "vpc", AwsType: "awsstr"},
+					{AwsField: "InternetGatewayId", TemplateName: "id", AwsType: "awsstr", Type: idOf(cloud.InternetGateway)},
+					{AwsField: "VpcId", TemplateName: "vpc", AwsType: "awsstr", Type: idOf(cloud.Vpc)},
 				},
 			},
 			{
 				Action: "detach", Entity: cloud.InternetGateway, ApiMethod: "DetachInternetGateway", Input: "DetachInternetGatewayInput", Output: "DetachInternetGatewayOutput",
 				RequiredParams: []param{
-					{AwsField: "InternetGatewayId", TemplateName: "id", AwsType: "awsstr"},
-					{AwsField: "VpcId", TemplateName: "vpc", AwsType: "awsstr"},
+					{AwsField: "InternetGatewayId", TemplateName: "id", AwsType: "awsstr", Type: idOf(cloud.InternetGateway)},
+					{AwsField: "VpcId", TemplateName: "vpc", AwsType: "awsstr", Type: idOf(cloud.Vpc)},
 				},
 			},
 			// NAT GATEWAYS
@@ -433,40 +500,40 @@ var DriversDefs = []driversDef{
 				Action: "create", Entity: cloud.NatGateway, ApiMethod: "CreateNatGateway", Input: "CreateNatGatewayInput", Output: "CreateNatGatewayOutput", OutputExtractor: "aws.StringValue(output.NatGateway.NatGatewayId)", DryRunUnsupported: true,
 				RequiredParams: []param{
 					{AwsField: "AllocationId", TemplateName: "elasticip-id", AwsType: "awsstr"},
-					{AwsField: "SubnetId", TemplateName: "subnet", AwsType: "awsstr"},
+					{AwsField: "SubnetId", TemplateName: "subnet", AwsType: "awsstr", Type: idOf(cloud.Subnet)},
 				},
 			},
 			{
 				Action: "delete", Entity: cloud.NatGateway, ApiMethod: "DeleteNatGateway", Input: "DeleteNatGatewayInput", Output: "DeleteNatGatewayOutput", DryRunUnsupported: true,
 				RequiredParams: []param{
-					{AwsField: "NatGatewayId", TemplateName: "id", AwsType: "awsstr"},
+					{AwsField: "NatGatewayId", TemplateName: "id", AwsType: "awsstr", Type: idOf(cloud.NatGateway)},
 				},
 			},
 			{
 				Action: "check", Entity: cloud.NatGateway, ManualFuncDefinition: true,
 				RequiredParams: []param{
-					{TemplateName: "id"},
-					{TemplateName: "state"},
-					{TemplateName: "timeout"},
+					{TemplateName: "id", Type: idOf(cloud.NatGateway)},
+					{TemplateName: "state", Type: enumOf("pending", "failed", "available", "deleting", "deleted", "not-found")},
+					{TemplateName: "timeout", Type: durationType},
 				},
 			},
 			// ROUTE TABLES
 			{
 				Action: "create", Entity: cloud.RouteTable, ApiMethod: "CreateRouteTable", Input: "CreateRouteTableInput", Output: "CreateRouteTableOutput", OutputExtractor: "aws.StringValue(output.RouteTable.RouteTableId)",
 				RequiredParams: []param{
-					{AwsField: "VpcId", TemplateName: "vpc", AwsType: "awsstr"}},
+					{AwsField: "VpcId", TemplateName: "vpc", AwsType: "awsstr", Type: idOf(cloud.Vpc)}},
 			},
 			{
 				Action: "delete", Entity: cloud.RouteTable, ApiMethod: "DeleteRouteTable", Input: "DeleteRouteTableInput", Output: "DeleteRouteTableOutput",
 				RequiredParams: []param{
-					{AwsField: "RouteTableId", TemplateName: "id", AwsType: "awsstr"},
+					{AwsField: "RouteTableId", TemplateName: "id", AwsType: "awsstr", Type: idOf(cloud.RouteTable)},
 				},
 			},
 			{
 				Action: "attach", Entity: cloud.RouteTable, ApiMethod: "AssociateRouteTable", Input: "AssociateRouteTableInput", Output: "AssociateRouteTableOutput", OutputExtractor: "aws.StringValue(output.AssociationId)",
 				RequiredParams: []param{
-					{AwsField: "RouteTableId", TemplateName: "id", AwsType: "awsstr"},
-					{AwsField: "SubnetId", TemplateName: "subnet", AwsType: "awsstr"},
+					{AwsField: "RouteTableId", TemplateName: "id", AwsType: "awsstr", Type: idOf(cloud.RouteTable)},
+					{AwsField: "SubnetId", TemplateName: "subnet", AwsType: "awsstr", Type: idOf(cloud.Subnet)},
 				},
 			},
 			{
@@ -480,7 +547,7 @@ var DriversDefs = []driversDef{
 				Action: "create", Entity: "route", ApiMethod: "CreateRoute", Input: "CreateRouteInput", Output: "CreateRouteOutput",
 				RequiredParams: []param{
 					{AwsField: "RouteTableId", TemplateName: "table", AwsType: "awsstr"},
-					{AwsField: "DestinationCidrBlock", TemplateName: "cidr", AwsType: "awsstr"},
+					{AwsField: "DestinationCidrBlock", TemplateName: "cidr", AwsType: "awsstr", Type: cidrType},
 					{AwsField: "GatewayId", TemplateName: "gateway", AwsType: "awsstr"},
 				},
 			},
@@ -488,7 +555,7 @@ var DriversDefs = []driversDef{
 				Action: "delete", Entity: "route", ApiMethod: "DeleteRoute", Input: "DeleteRouteInput", Output: "DeleteRouteOutput",
 				RequiredParams: []param{
 					{AwsField: "RouteTableId", TemplateName: "table", AwsType: "awsstr"},
-					{AwsField: "DestinationCidrBlock", TemplateName: "cidr", AwsType: "awsstr"},
+					{AwsField: "DestinationCidrBlock", TemplateName: "cidr", AwsType: "awsstr", Type: cidrType},
 				},
 			},
 			// TAG
@@ -547,10 +614,10 @@ var DriversDefs = []driversDef{
 					{AwsField: "AllocationId", TemplateName: "id", AwsType: "awsstr"},
 				},
 				ExtraParams: []param{
-					{AwsField: "InstanceId", TemplateName: "instance", AwsType: "awsstr"},
-					{AwsField: "NetworkInterfaceId", TemplateName: "networkinterface", AwsType: "awsstr"},
+					{AwsField: "InstanceId", TemplateName: "instance", AwsType: "awsstr", Type: idOf(cloud.Instance)},
+					{AwsField: "NetworkInterfaceId", TemplateName: "networkinterface", AwsType: "awsstr", Type: idOf(cloud.NetworkInterface)},
 					{AwsField: "PrivateIpAddress", TemplateName: "privateip", AwsType: "awsstr"},
-					{AwsField: "AllowReassociation", TemplateName: "allow-reassociation", AwsType: "awsbool"},
+					{AwsField: "AllowReassociation", TemplateName: "allow-reassociation", AwsType: "awsbool", Type: boolType},
 				},
 			},
 			{
@@ -569,13 +636,13 @@ var DriversDefs = []driversDef{
 				Action: "create", Entity: cloud.LoadBalancer, ApiMethod: "CreateLoadBalancer", Input: "CreateLoadBalancerInput", Output: "CreateLoadBalancerOutput", DryRunUnsupported: true, OutputExtractor: "aws.StringValue(output.LoadBalancers[0].LoadBalancerArn)",
 				RequiredParams: []param{
 					{AwsField: "Name", TemplateName: "name", AwsType: "awsstr"},
-					{AwsField: "Subnets", TemplateName: "subnets", AwsType: "awsstringslice"},
+					{AwsField: "Subnets", TemplateName: "subnets", AwsType: "awsstringslice", Type: idOf(cloud.Subnet)},
 				},
 				ExtraParams: []param{
 					{AwsField: "SubnetMappings", TemplateName: "subnet-mappings", AwsType: "awssubnetmappings"},
 					{AwsField: "IpAddressType", TemplateName: "iptype", AwsType: "awsstr"},
 					{AwsField: "Scheme", TemplateName: "scheme", AwsType: "awsstr"},
-					{AwsField: "SecurityGroups", TemplateName: "securitygroups", AwsType: "awsstringslice"},
+					{AwsField: "SecurityGroups", TemplateName: "securitygroups", AwsType: "awsstringslice", Type: idOf(cloud.SecurityGroup)},
 					{AwsField: "Type", TemplateName: "type", AwsType: "awsstr"},
 				},
 			},
@@ -589,8 +656,8 @@ var DriversDefs = []driversDef{
 				Action: "check", Entity: cloud.LoadBalancer, ManualFuncDefinition: true,
 				RequiredParams: []param{
 					{TemplateName: "id"},
-					{TemplateName: "state"},
-					{TemplateName: "timeout"},
+					{TemplateName: "state", Type: enumOf("provisioning", "active", "failed", "not-found")},
+					{TemplateName: "timeout", Type: durationType},
 				},
 			},
 			// Listener
@@ -600,8 +667,8 @@ var DriversDefs = []driversDef{
 					{AwsField: "DefaultActions[0]Type", TemplateName: "actiontype", AwsType: "awsslicestruct"}, //always forward
 					{AwsField: "DefaultActions[0]TargetGroupArn", TemplateName: "targetgroup", AwsType: "awsslicestruct"},
 					{AwsField: "LoadBalancerArn", TemplateName: "loadbalancer", AwsType: "awsstr"},
-					{AwsField: "Port", TemplateName: "port", AwsType: "awsint64"},
-					{AwsField: "Protocol", TemplateName: "protocol", AwsType: "awsstr"}, // TCP, HTTP, HTTPS
+					{AwsField: "Port", TemplateName: "port", AwsType: "awsint64", Type: portType},
+					{AwsField: "Protocol", TemplateName: "protocol", AwsType: "awsstr", Type: elbProtocolType}, // TCP, HTTP, HTTPS
 				},
 				ExtraParams: []param{
 					{AwsField: "Certificates[0]CertificateArn", TemplateName: "certificate", AwsType: "awsslicestruct"},
@@ -619,18 +686,18 @@ var DriversDefs = []driversDef{
 				Action: "create", Entity: cloud.TargetGroup, ApiMethod: "CreateTargetGroup", Input: "CreateTargetGroupInput", Output: "CreateTargetGroupOutput", DryRunUnsupported: true, OutputExtractor: "aws.StringValue(output.TargetGroups[0].TargetGroupArn)",
 				RequiredParams: []param{
 					{AwsField: "Name", TemplateName: "name", AwsType: "awsstr"},
-					{AwsField: "Port", TemplateName: "port", AwsType: "awsint64"},
-					{AwsField: "Protocol", TemplateName: "protocol", AwsType: "awsstr"},
-					{AwsField: "VpcId", TemplateName: "vpc", AwsType: "awsstr"},
+					{AwsField: "Port", TemplateName: "port", AwsType: "awsint64", Type: portType},
+					{AwsField: "Protocol", TemplateName: "protocol", AwsType: "awsstr", Type: elbProtocolType},
+					{AwsField: "VpcId", TemplateName: "vpc", AwsType: "awsstr", Type: idOf(cloud.Vpc)},
 				},
 				ExtraParams: []param{
-					{AwsField: "HealthCheckIntervalSeconds", TemplateName: "healthcheckinterval", AwsType: "awsint64"},
+					{AwsField: "HealthCheckIntervalSeconds", TemplateName: "healthcheckinterval", AwsType: "awsint64", Type: intType},
 					{AwsField: "HealthCheckPath", TemplateName: "healthcheckpath", AwsType: "awsstr"},
 					{AwsField: "HealthCheckPort", TemplateName: "healthcheckport", AwsType: "awsstr"},
-					{AwsField: "HealthCheckProtocol", TemplateName: "healthcheckprotocol", AwsType: "awsstr"},
-					{AwsField: "HealthCheckTimeoutSeconds", TemplateName: "healthchecktimeout", AwsType: "awsint64"},
-					{AwsField: "HealthyThresholdCount", TemplateName: "healthythreshold", AwsType: "awsint64"},
-					{AwsField: "UnhealthyThresholdCount", TemplateName: "unhealthythreshold", AwsType: "awsint64"},
+					{AwsField: "HealthCheckProtocol", TemplateName: "healthcheckprotocol", AwsType: "awsstr", Type: elbProtocolType},
+					{AwsField: "HealthCheckTimeoutSeconds", TemplateName: "healthchecktimeout", AwsType: "awsint64", Type: intType},
+					{AwsField: "HealthyThresholdCount", TemplateName: "healthythreshold", AwsType: "awsint64", Type: intType},
+					{AwsField: "UnhealthyThresholdCount", TemplateName: "unhealthythreshold", AwsType: "awsint64", Type: intType},
 					{AwsField: "Matcher.HttpCode", TemplateName: "matcher", AwsType: "awsstr"},
 				},
 			},
@@ -643,13 +710,13 @@ var DriversDefs = []driversDef{
 					{TemplateName: "deregistrationdelay", AwsType: "awsstr"},
 					{TemplateName: "stickiness", AwsType: "awsstr"},
 					{TemplateName: "stickinessduration", AwsType: "awsstr"},
-					{AwsField: "HealthCheckIntervalSeconds", TemplateName: "healthcheckinterval", AwsType: "awsint64"},
+					{AwsField: "HealthCheckIntervalSeconds", TemplateName: "healthcheckinterval", AwsType: "awsint64", Type: intType},
 					{AwsField: "HealthCheckPath", TemplateName: "healthcheckpath", AwsType: "awsstr"},
 					{AwsField: "HealthCheckPort", TemplateName: "healthcheckport", AwsType: "awsstr"},
-					{AwsField: "HealthCheckProtocol", TemplateName: "healthcheckprotocol", AwsType: "awsstr"},
-					{AwsField: "HealthCheckTimeoutSeconds", TemplateName: "healthchecktimeout", AwsType: "awsint64"},
-					{AwsField: "HealthyThresholdCount", TemplateName: "healthythreshold", AwsType: "awsint64"},
-					{AwsField: "UnhealthyThresholdCount", TemplateName: "unhealthythreshold", AwsType: "awsint64"},
+					{AwsField: "HealthCheckProtocol", TemplateName: "healthcheckprotocol", AwsType: "awsstr", Type: elbProtocolType},
+					{AwsField: "HealthCheckTimeoutSeconds", TemplateName: "healthchecktimeout", AwsType: "awsint64", Type: intType},
+					{AwsField: "HealthyThresholdCount", TemplateName: "healthythreshold", AwsType: "awsint64", Type: intType},
+					{AwsField: "UnhealthyThresholdCount", TemplateName: "unhealthythreshold", AwsType: "awsint64", Type: intType},
 					{AwsField: "Matcher.HttpCode", TemplateName: "matcher", AwsType: "awsstr"},
 				},
 			},
@@ -663,17 +730,17 @@ var DriversDefs = []driversDef{
 				Action: "attach", Entity: cloud.Instance, ApiMethod: "RegisterTargets", Input: "RegisterTargetsInput", Output: "RegisterTargetsOutput", DryRunUnsupported: true,
 				RequiredParams: []param{
 					{AwsField: "TargetGroupArn", TemplateName: "targetgroup", AwsType: "awsstr"},
-					{AwsField: "Targets[0]Id", TemplateName: "id", AwsType: "awsslicestruct"},
+					{AwsField: "Targets[0]Id", TemplateName: "id", AwsType: "awsslicestruct", Type: idOf(cloud.Instance)},
 				},
 				ExtraParams: []param{
-					{AwsField: "Targets[0]Port", TemplateName: "port", AwsType: "awsslicestructint64"},
+					{AwsField: "Targets[0]Port", TemplateName: "port", AwsType: "awsslicestructint64", Type: portType},
 				},
 			},
 			{
 				Action: "detach", Entity: cloud.Instance, ApiMethod: "DeregisterTargets", Input: "DeregisterTargetsInput", Output: "DeregisterTargetsOutput", DryRunUnsupported: true,
 				RequiredParams: []param{
 					{AwsField: "TargetGroupArn", TemplateName: "targetgroup", AwsType: "awsstr"},
-					{AwsField: "Targets[0]Id", TemplateName: "id", AwsType: "awsslicestruct"},
+					{AwsField: "Targets[0]Id", TemplateName: "id", AwsType: "awsslicestruct", Type: idOf(cloud.Instance)},
 				},
 			},
 		},
@@ -684,15 +751,15 @@ var DriversDefs = []driversDef{
 			{
 				Action: "create", Entity: cloud.LaunchConfiguration, ApiMethod: "CreateLaunchConfiguration", Input: "CreateLaunchConfigurationInput", Output: "CreateLaunchConfigurationOutput", DryRunUnsupported: true, OutputExtractor: "params[\"name\"]",
 				RequiredParams: []param{
-					{AwsField: "ImageId", TemplateName: "image", AwsType: "awsstr"},
-					{AwsField: "InstanceType", TemplateName: "type", AwsType: "awsstr"},
+					{AwsField: "ImageId", TemplateName: "image", AwsType: "awsstr", Type: idOf(cloud.Image)},
+					{AwsField: "InstanceType", TemplateName: "type", AwsType: "awsstr", Type: instanceTypeType},
 					{AwsField: "LaunchConfigurationName", TemplateName: "name", AwsType: "awsstr"},
 				},
 				ExtraParams: []param{
-					{AwsField: "AssociatePublicIpAddress", TemplateName: "public", AwsType: "awsbool"},
+					{AwsField: "AssociatePublicIpAddress", TemplateName: "public", AwsType: "awsbool", Type: boolType},
 					{AwsField: "KeyName", TemplateName: "keypair", AwsType: "awsstr"},
 					{AwsField: "UserData", TemplateName: "userdata", AwsType: "awsfiletobase64"},
-					{AwsField: "SecurityGroups", TemplateName: "securitygroups", AwsType: "awsstringslice"},
+					{AwsField: "SecurityGroups", TemplateName: "securitygroups", AwsType: "awsstringslice", Type: idOf(cloud.SecurityGroup)},
 					{AwsField: "IamInstanceProfile", TemplateName: "role", AwsType: "awsstr"},
 					{AwsField: "SpotPrice", TemplateName: "spotprice", AwsType: "awsstr"},
 				},
@@ -708,16 +775,16 @@ var DriversDefs = []driversDef{
 				RequiredParams: []param{
 					{AwsField: "AutoScalingGroupName", TemplateName: "name", AwsType: "awsstr"},
 					{AwsField: "LaunchConfigurationName", TemplateName: "launchconfiguration", AwsType: "awsstr"},
-					{AwsField: "MaxSize", TemplateName: "max-size", AwsType: "awsint64"},
-					{AwsField: "MinSize", TemplateName: "min-size", AwsType: "awsint64"},
-					{AwsField: "VPCZoneIdentifier", TemplateName: "subnets", AwsType: "awscsvstr"},
+					{AwsField: "MaxSize", TemplateName: "max-size", AwsType: "awsint64", Type: intType},
+					{AwsField: "MinSize", TemplateName: "min-size", AwsType: "awsint64", Type: intType},
+					{AwsField: "VPCZoneIdentifier", TemplateName: "subnets", AwsType: "awscsvstr", Type: idOf(cloud.Subnet)},
 				},
 				ExtraParams: []param{
-					{AwsField: "DefaultCooldown", TemplateName: "cooldown", AwsType: "awsint64"},
-					{AwsField: "DesiredCapacity", TemplateName: "desired-capacity", AwsType: "awsint64"},
-					{AwsField: "HealthCheckGracePeriod", TemplateName: "healthcheck-grace-period", AwsType: "awsint64"},
+					{AwsField: "DefaultCooldown", TemplateName: "cooldown", AwsType: "awsint64", Type: intType},
+					{AwsField: "DesiredCapacity", TemplateName: "desired-capacity", AwsType: "awsint64", Type: intType},
+					{AwsField: "HealthCheckGracePeriod", TemplateName: "healthcheck-grace-period", AwsType: "awsint64", Type: intType},
 					{AwsField: "HealthCheckType", TemplateName: "healthcheck-type", AwsType: "awsstr"},
-					{AwsField: "NewInstancesProtectedFromScaleIn", TemplateName: "new-instances-protected", AwsType: "awsbool"},
+					{AwsField: "NewInstancesProtectedFromScaleIn", TemplateName: "new-instances-protected", AwsType: "awsbool", Type: boolType},
 					{AwsField: "TargetGroupARNs", TemplateName: "targetgroups", AwsType: "awsstringslice"},
 				},
 			},
@@ -727,15 +794,15 @@ var DriversDefs = []driversDef{
 					{AwsField: "AutoScalingGroupName", TemplateName: "name", AwsType: "awsstr"},
 				},
 				ExtraParams: []param{
-					{AwsField: "DefaultCooldown", TemplateName: "cooldown", AwsType: "awsint64"},
-					{AwsField: "DesiredCapacity", TemplateName: "desired-capacity", AwsType: "awsint64"},
-					{AwsField: "HealthCheckGracePeriod", TemplateName: "healthcheck-grace-period", AwsType: "awsint64"},
+					{AwsField: "DefaultCooldown", TemplateName: "cooldown", AwsType: "awsint64", Type: intType},
+					{AwsField: "DesiredCapacity", TemplateName: "desired-capacity", AwsType: "awsint64", Type: intType},
+					{AwsField: "HealthCheckGracePeriod", TemplateName: "healthcheck-grace-period", AwsType: "awsint64", Type: intType},
 					{AwsField: "HealthCheckType", TemplateName: "healthcheck-type", AwsType: "awsstr"},
 					{AwsField: "LaunchConfigurationName", TemplateName: "launchconfiguration", AwsType: "awsstr"},
-					{AwsField: "MaxSize", TemplateName: "max-size", AwsType: "awsint64"},
-					{AwsField: "MinSize", TemplateName: "min-size", AwsType: "awsint64"},
-					{AwsField: "NewInstancesProtectedFromScaleIn", TemplateName: "new-instances-protected", AwsType: "awsbool"},
-					{AwsField: "VPCZoneIdentifier", TemplateName: "subnets", AwsType: "awscsvstr"},
+					{AwsField: "MaxSize", TemplateName: "max-size", AwsType: "awsint64", Type: intType},
+					{AwsField: "MinSize", TemplateName: "min-size", AwsType: "awsint64", Type: intType},
+					{AwsField: "NewInstancesProtectedFromScaleIn", TemplateName: "new-instances-protected", AwsType: "awsbool", Type: boolType},
+					{AwsField: "VPCZoneIdentifier", TemplateName: "subnets", AwsType: "awscsvstr", Type: idOf(cloud.Subnet)},
 				},
 			},
 			{
@@ -744,15 +811,15 @@ var DriversDefs = []driversDef{
 					{AwsField: "AutoScalingGroupName", TemplateName: "name", AwsType: "awsstr"},
 				},
 				ExtraParams: []param{
-					{AwsField: "ForceDelete", TemplateName: "force", AwsType: "awsbool"},
+					{AwsField: "ForceDelete", TemplateName: "force", AwsType: "awsbool", Type: boolType},
 				},
 			},
 			{
 				Action: "check", Entity: cloud.ScalingGroup, ManualFuncDefinition: true,
 				RequiredParams: []param{
 					{TemplateName: "name"},
-					{TemplateName: "count"},
-					{TemplateName: "timeout"},
+					{TemplateName: "count", Type: positiveIntType},
+					{TemplateName: "timeout", Type: durationType},
 				},
 			},
 			{
@@ -761,11 +828,11 @@ var DriversDefs = []driversDef{
 					{AwsField: "AdjustmentType", TemplateName: "adjustment-type", AwsType: "awsstr"},
 					{AwsField: "AutoScalingGroupName", TemplateName: "scalinggroup", AwsType: "awsstr"},
 					{AwsField: "PolicyName", TemplateName: "name", AwsType: "awsstr"},
-					{AwsField: "ScalingAdjustment", TemplateName: "adjustment-scaling", AwsType: "awsint64"},
+					{AwsField: "ScalingAdjustment", TemplateName: "adjustment-scaling", AwsType: "awsint64", Type: intType},
 				},
 				ExtraParams: []param{
-					{AwsField: "Cooldown", TemplateName: "cooldown", AwsType: "awsint64"},
-					{AwsField: "MinAdjustmentMagnitude", TemplateName: "adjustment-magnitude", AwsType: "awsint64"},
+					{AwsField: "Cooldown", TemplateName: "cooldown", AwsType: "awsint64", Type: intType},
+					{AwsField: "MinAdjustmentMagnitude", TemplateName: "adjustment-magnitude", AwsType: "awsint64", Type: intType},
 				},
 			},
 			{
@@ -788,12 +855,12 @@ var DriversDefs = []driversDef{
 					{AwsField: "Engine", TemplateName: "engine", AwsType: "awsstr"},
 					{AwsField: "MasterUserPassword", TemplateName: "password", AwsType: "awsstr"},
 					{AwsField: "MasterUsername", TemplateName: "username", AwsType: "awsstr"},
-					{AwsField: "AllocatedStorage", TemplateName: "size", AwsType: "awsint64"},
+					{AwsField: "AllocatedStorage", TemplateName: "size", AwsType: "awsint64", Type: intType},
 				},
 				ExtraParams: []param{
-					{AwsField: "AutoMinorVersionUpgrade", TemplateName: "autoupgrade", AwsType: "awsbool"},
+					{AwsField: "AutoMinorVersionUpgrade", TemplateName: "autoupgrade", AwsType: "awsbool", Type: boolType},
 					{AwsField: "AvailabilityZone", TemplateName: "availabilityzone", AwsType: "awsstr"},
-					{AwsField: "BackupRetentionPeriod", TemplateName: "backupretention", AwsType: "awsint64"},
+					{AwsField: "BackupRetentionPeriod", TemplateName: "backupretention", AwsType: "awsint64", Type: intType},
 					{AwsField: "DBClusterIdentifier", TemplateName: "cluster", AwsType: "awsstr"},
 					{AwsField: "DBName", TemplateName: "dbname", AwsType: "awsstr"},
 					{AwsField: "DBParameterGroupName", TemplateName: "parametergroup", AwsType: "awsstr"},
@@ -802,18 +869,18 @@ var DriversDefs = []driversDef{
 					{AwsField: "Domain", TemplateName: "domain", AwsType: "awsstr"},
 					{AwsField: "DomainIAMRoleName", TemplateName: "iamrole", AwsType: "awsstr"},
 					{AwsField: "EngineVersion", TemplateName: "version", AwsType: "awsstr"},
-					{AwsField: "Iops", TemplateName: "iops", AwsType: "awsint64"},
+					{AwsField: "Iops", TemplateName: "iops", AwsType: "awsint64", Type: intType},
 					{AwsField: "LicenseModel", TemplateName: "license", AwsType: "awsstr"}, // license-included | bring-your-own-license | general-public-license
-					{AwsField: "MultiAZ", TemplateName: "multiaz", AwsType: "awsbool"},
+					{AwsField: "MultiAZ", TemplateName: "multiaz", AwsType: "awsbool", Type: boolType},
 					{AwsField: "OptionGroupName", TemplateName: "optiongroup", AwsType: "awsstr"},
-					{AwsField: "Port", TemplateName: "port", AwsType: "awsint64"},
+					{AwsField: "Port", TemplateName: "port", AwsType: "awsint64", Type: portType},
 					{AwsField: "PreferredBackupWindow", TemplateName: "backupwindow", AwsType: "awsstr"},
 					{AwsField: "PreferredMaintenanceWindow", TemplateName: "maintenancewindow", AwsType: "awsstr"},
-					{AwsField: "PubliclyAccessible", TemplateName: "public", AwsType: "awsbool"},
-					{AwsField: "StorageEncrypted", TemplateName: "encrypted", AwsType: "awsbool"},
+					{AwsField: "PubliclyAccessible", TemplateName: "public", AwsType: "awsbool", Type: boolType},
+					{AwsField: "StorageEncrypted", TemplateName: "encrypted", AwsType: "awsbool", Type: boolType},
 					{AwsField: "StorageType", TemplateName: "storagetype", AwsType: "awsstr"},
 					{AwsField: "Timezone", TemplateName: "timezone", AwsType: "awsstr"},
-					{AwsField: "VpcSecurityGroupIds", TemplateName: "vpcsecuritygroups", AwsType: "awsstringslice"},
+					{AwsField: "VpcSecurityGroupIds", TemplateName: "vpcsecuritygroups", AwsType: "awsstringslice", Type: idOf(cloud.SecurityGroup)},
 				},
 			},
 			{
@@ -822,16 +889,16 @@ var DriversDefs = []driversDef{
 					{AwsField: "DBInstanceIdentifier", TemplateName: "id", AwsType: "awsstr"},
 				},
 				ExtraParams: []param{
-					{AwsField: "SkipFinalSnapshot", TemplateName: "skip-snapshot", AwsType: "awsbool"},
-					{AwsField: "FinalDBSnapshotIdentifier", TemplateName: "snapshot", AwsType: "awsbool"},
+					{AwsField: "SkipFinalSnapshot", TemplateName: "skip-snapshot", AwsType: "awsbool", Type: boolType},
+					{AwsField: "FinalDBSnapshotIdentifier", TemplateName: "snapshot", AwsType: "awsbool", Type: boolType},
 				},
 			},
 			{
 				Action: "check", Entity: cloud.Database, ManualFuncDefinition: true,
 				RequiredParams: []param{
 					{TemplateName: "id"},
-					{TemplateName: "state"},
-					{TemplateName: "timeout"},
+					{TemplateName: "state", Type: enumOf("available", "backing-up", "creating", "deleting", "failed", "maintenance", "modifying", "rebooting", "renaming", "resetting-master-credentials", "restore-error", "storage-full", "upgrading", "not-found")},
+					{TemplateName: "timeout", Type: durationType},
 				},
 			},
 			{
@@ -839,7 +906,7 @@ var DriversDefs = []driversDef{
 				RequiredParams: []param{
 					{AwsField: "DBSubnetGroupDescription", TemplateName: "description", AwsType: "awsstr"},
 					{AwsField: "DBSubnetGroupName", TemplateName: "name", AwsType: "awsstr"},
-					{AwsField: "SubnetIds", TemplateName: "subnets", AwsType: "awsstringslice"},
+					{AwsField: "SubnetIds", TemplateName: "subnets", AwsType: "awsstringslice", Type: idOf(cloud.Subnet)},
 				},
 			},
 			{
@@ -866,7 +933,7 @@ var DriversDefs = []driversDef{
 					{AwsField: "RepositoryName", TemplateName: "name", AwsType: "awsstr"},
 				},
 				ExtraParams: []param{
-					{AwsField: "Force", TemplateName: "force", AwsType: "awsbool"},
+					{AwsField: "Force", TemplateName: "force", AwsType: "awsbool", Type: boolType},
 					{AwsField: "RegistryId", TemplateName: "account", AwsType: "awsstr"},
 				},
 			},
@@ -931,7 +998,7 @@ var DriversDefs = []driversDef{
 					{AwsField: "Service", TemplateName: "deployment-name", AwsType: "awsstr"},
 				},
 				ExtraParams: []param{
-					{AwsField: "DesiredCount", TemplateName: "desired-count", AwsType: "awsint64"},
+					{AwsField: "DesiredCount", TemplateName: "desired-count", AwsType: "awsint64", Type: intType},
 					{AwsField: "TaskDefinition", TemplateName: "name", AwsType: "awsstr"},
 				},
 			},
@@ -985,15 +1052,15 @@ var DriversDefs = []driversDef{
 			{
 				Action: "delete", Entity: cloud.Certificate, ApiMethod: "DeleteCertificate", Input: "DeleteCertificateInput", Output: "DeleteCertificateOutput", DryRunUnsupported: true,
 				RequiredParams: []param{
-					{AwsField: "CertificateArn", TemplateName: "arn", AwsType: "awsstr"},
+					{AwsField: "CertificateArn", TemplateName: "arn", AwsType: "awsstr", Type: arnType},
 				},
 			},
 			{
 				Action: "check", Entity: cloud.Certificate, ManualFuncDefinition: true,
 				RequiredParams: []param{
-					{TemplateName: "arn"},
-					{TemplateName: "state"},
-					{TemplateName: "timeout"},
+					{TemplateName: "arn", Type: arnType},
+					{TemplateName: "state", Type: enumOf("issued", "pending_validation", "not-found")},
+					{TemplateName: "timeout", Type: durationType},
 				},
 			},
 		},
@@ -1058,7 +1125,7 @@ var DriversDefs = []driversDef{
 					{AwsField: "Password", TemplateName: "password", AwsType: "awsstr"},
 				},
 				ExtraParams: []param{
-					{AwsField: "PasswordResetRequired", TemplateName: "password-reset", AwsType: "awsbool"},
+					{AwsField: "PasswordResetRequired", TemplateName: "password-reset", AwsType: "awsbool", Type: boolType},
 				},
 			},
 			{
@@ -1068,7 +1135,7 @@ var DriversDefs = []driversDef{
 					{AwsField: "Password", TemplateName: "password", AwsType: "awsstr"},
 				},
 				ExtraParams: []param{
-					{AwsField: "PasswordResetRequired", TemplateName: "password-reset", AwsType: "awsbool"},
+					{AwsField: "PasswordResetRequired", TemplateName: "password-reset", AwsType: "awsbool", Type: boolType},
 				},
 			},
 			{
@@ -1158,7 +1225,7 @@ var DriversDefs = []driversDef{
 			{
 				Action: "update", Entity: cloud.Policy, ManualFuncDefinition: true,
 				RequiredParams: []param{
-					{TemplateName: "arn"},
+					{TemplateName: "arn", Type: arnType},
 					{TemplateName: "effect"},
 					{TemplateName: "action"},
 					{TemplateName: "resource"},
@@ -1170,7 +1237,7 @@ var DriversDefs = []driversDef{
 			{
 				Action: "delete", Entity: cloud.Policy, ManualFuncDefinition: true, DryRunUnsupported: true, Input: "DeletePolicyInput", Output: "DeletePolicyOutput", ApiMethod: "DeletePolicy",
 				RequiredParams: []param{
-					{AwsField: "PolicyArn", TemplateName: "arn", AwsType: "awsstr"},
+					{AwsField: "PolicyArn", TemplateName: "arn", AwsType: "awsstr", Type: arnType},
 				},
 				ExtraParams: []param{
 					{TemplateName: "all-versions"},
@@ -1179,7 +1246,7 @@ var DriversDefs = []driversDef{
 			{
 				Action: "attach", Entity: cloud.Policy, ManualFuncDefinition: true,
 				ExtraParams: []param{
-					{TemplateName: "arn"},
+					{TemplateName: "arn", Type: arnType},
 					{TemplateName: "service"},
 					{TemplateName: "access"},
 					{TemplateName: "user"},
@@ -1190,7 +1257,7 @@ var DriversDefs = []driversDef{
 			{
 				Action: "detach", Entity: cloud.Policy, ManualFuncDefinition: true,
 				ExtraParams: []param{
-					{TemplateName: "arn"},
+					{TemplateName: "arn", Type: arnType},
 					{TemplateName: "service"},
 					{TemplateName: "access"},
 					{TemplateName: "user"},
@@ -1365,8 +1432,8 @@ var DriversDefs = []driversDef{
 				ExtraParams: []param{
 					{AwsField: "DelegationSetId", TemplateName: "delegationsetid", AwsType: "awsstr"},
 					{AwsField: "HostedZoneConfig.Comment", TemplateName: "comment", AwsType: "awsstr"},
-					{AwsField: "HostedZoneConfig.PrivateZone", TemplateName: "isprivate", AwsType: "awsbool"},
-					{AwsField: "VPC.VPCId", TemplateName: "vpcid", AwsType: "awsstr"},
+					{AwsField: "HostedZoneConfig.PrivateZone", TemplateName: "isprivate", AwsType: "awsbool", Type: boolType},
+					{AwsField: "VPC.VPCId", TemplateName: "vpcid", AwsType: "awsstr", Type: idOf(cloud.Vpc)},
 					{AwsField: "VPC.VPCRegion", TemplateName: "vpcregion", AwsType: "awsstr"},
 				},
 			},
@@ -1428,9 +1495,9 @@ var DriversDefs = []driversDef{
 					{AwsField: "Code.S3ObjectVersion", TemplateName: "objectversion", AwsType: "awsstr"},
 					{AwsField: "Code.ZipFile", TemplateName: "zipfile", AwsType: "awsfiletobyteslice"},
 					{AwsField: "Description", TemplateName: "description", AwsType: "awsstr"},
-					{AwsField: "MemorySize", TemplateName: "memory", AwsType: "awsint64"},
-					{AwsField: "Publish", TemplateName: "publish", AwsType: "awsbool"},
-					{AwsField: "Timeout", TemplateName: "timeout", AwsType: "awsint64"},
+					{AwsField: "MemorySize", TemplateName: "memory", AwsType: "awsint64", Type: intType},
+					{AwsField: "Publish", TemplateName: "publish", AwsType: "awsbool", Type: boolType},
+					{AwsField: "Timeout", TemplateName: "timeout", AwsType: "awsint64", Type: intType},
 				},
 			},
 			{
@@ -1454,13 +1521,13 @@ var DriversDefs = []driversDef{
 					{AwsField: "ComparisonOperator", TemplateName: "operator", AwsType: "awsstr"}, // [GreaterThanThreshold, LessThanThreshold, LessThanOrEqualToThreshold, GreaterThanOrEqualToThreshold]
 					{AwsField: "MetricName", TemplateName: "metric", AwsType: "awsstr"},
 					{AwsField: "Namespace", TemplateName: "namespace", AwsType: "awsstr"},
-					{AwsField: "EvaluationPeriods", TemplateName: "evaluation-periods", AwsType: "awsint64"},
-					{AwsField: "Period", TemplateName: "period", AwsType: "awsint64"},
+					{AwsField: "EvaluationPeriods", TemplateName: "evaluation-periods", AwsType: "awsint64", Type: intType},
+					{AwsField: "Period", TemplateName: "period", AwsType: "awsint64", Type: intType},
 					{AwsField: "Statistic", TemplateName: "statistic-function", AwsType: "awsstr"}, // Minimum, Maximum, Sum, Average, SampleCount, pNN.NN
 					{AwsField: "Threshold", TemplateName: "threshold", AwsType: "awsfloat"},
 				},
 				ExtraParams: []param{
-					{AwsField: "ActionsEnabled", TemplateName: "enabled", AwsType: "awsbool"},
+					{AwsField: "ActionsEnabled", TemplateName: "enabled", AwsType: "awsbool", Type: boolType},
 					{AwsField: "AlarmActions", TemplateName: "alarm-actions", AwsType: "awsstringslice"},
 					{AwsField: "InsufficientDataActions", TemplateName: "insufficientdata-actions", AwsType: "awsstringslice"},
 					{AwsField: "OKActions", TemplateName: "ok-actions", AwsType: "awsstringslice"},
@@ -1529,15 +1596,15 @@ var DriversDefs = []driversDef{
 				Action: "check", Entity: cloud.Distribution, ManualFuncDefinition: true,
 				RequiredParams: []param{
 					{TemplateName: "id"},
-					{TemplateName: "state"},
-					{TemplateName: "timeout"},
+					{TemplateName: "state", Type: enumOf("deployed", "inprogress", "not-found")},
+					{TemplateName: "timeout", Type: durationType},
 				},
 			},
 			{
 				Action: "update", Entity: cloud.Distribution, ManualFuncDefinition: true,
 				RequiredParams: []param{
 					{AwsField: "Id", TemplateName: "id", AwsType: "awsstr"},
-					{AwsField: "DistributionConfig.Enabled", TemplateName: "enable", AwsType: "awsbool"},
+					{AwsField: "DistributionConfig.Enabled", TemplateName: "enable", AwsType: "awsbool", Type: boolType},
 				},
 			},
 			{
@@ -1559,14 +1626,14 @@ var DriversDefs = []driversDef{
 				},
 				ExtraParams: []param{
 					{AwsField: "Capabilities", TemplateName: "capabilities", AwsType: "awsstringslice"}, //CAPABILITY_IAM and CAPABILITY_NAMED_IAM
-					{AwsField: "DisableRollback", TemplateName: "disable-rollback", AwsType: "awsbool"},
+					{AwsField: "DisableRollback", TemplateName: "disable-rollback", AwsType: "awsbool", Type: boolType},
 					{AwsField: "NotificationARNs", TemplateName: "notifications", AwsType: "awsstringslice"},
 					{AwsField: "OnFailure", TemplateName: "on-failure", AwsType: "awsstr"},                 //DO_NOTHING, ROLLBACK, or DELETE
 					{AwsField: "Parameters", TemplateName: "parameters", AwsType: "awsparameterslice"},     //Format, key1:val1,key2:val2,...
 					{AwsField: "ResourceTypes", TemplateName: "resource-types", AwsType: "awsstringslice"}, //AWS::EC2::Instance, AWS::EC2::*, or Custom::MyCustomInstance or Custom::*
 					{AwsField: "RoleARN", TemplateName: "role", AwsType: "awsstr"},
 					{AwsField: "StackPolicyBody", TemplateName: "policy-file", AwsType: "awsfiletostring"},
-					{AwsField: "TimeoutInMinutes", TemplateName: "timeout", AwsType: "awsint64"},
+					{AwsField: "TimeoutInMinutes", TemplateName: "timeout", AwsType: "awsint64", Type: intType},
 				},
 			},
 			{
@@ -1583,7 +1650,7 @@ var DriversDefs = []driversDef{
 					{AwsField: "StackPolicyBody", TemplateName: "policy-file", AwsType: "awsfiletostring"},
 					{AwsField: "StackPolicyDuringUpdateBody", TemplateName: "policy-update-file", AwsType: "awsfiletostring"},
 					{AwsField: "TemplateBody", TemplateName: "template-file", AwsType: "awsfiletostring"},
-					{AwsField: "UsePreviousTemplate", TemplateName: "use-previous-template", AwsType: "awsbool"},
+					{AwsField: "UsePreviousTemplate", TemplateName: "use-previous-template", AwsType: "awsbool", Type: boolType},
 				},
 			},
 			{
@@ -1603,8 +1670,8 @@ var DriversDefs = []driversDef{
 			{
 				Action: "create", Entity: cloud.AppScalingTarget, DryRunUnsupported: true, ApiMethod: "RegisterScalableTarget", Input: "RegisterScalableTargetInput", Output: "RegisterScalableTargetOutput",
 				RequiredParams: []param{
-					{AwsField: "MaxCapacity", TemplateName: "max-capacity", AwsType: "awsint64"},
-					{AwsField: "MinCapacity", TemplateName: "min-capacity", AwsType: "awsint64"},
+					{AwsField: "MaxCapacity", TemplateName: "max-capacity", AwsType: "awsint64", Type: intType},
+					{AwsField: "MinCapacity", TemplateName: "min-capacity", AwsType: "awsint64", Type: intType},
 					{AwsField: "ResourceId", TemplateName: "resource", AwsType: "awsstr"},
 					{AwsField: "RoleARN", TemplateName: "role", AwsType: "awsstr"},
 					{AwsField: "ScalableDimension", TemplateName: "dimension", AwsType: "awsstr"},
@@ -1631,9 +1698,9 @@ var DriversDefs = []driversDef{
 					{AwsField: "StepScalingPolicyConfiguration.StepAdjustments", TemplateName: "stepscaling-adjustments", AwsType: "awsstepadjustments"},
 				},
 				ExtraParams: []param{
-					{AwsField: "StepScalingPolicyConfiguration.Cooldown", TemplateName: "stepscaling-cooldown", AwsType: "awsint64"},
+					{AwsField: "StepScalingPolicyConfiguration.Cooldown", TemplateName: "stepscaling-cooldown", AwsType: "awsint64", Type: intType},
 					{AwsField: "StepScalingPolicyConfiguration.MetricAggregationType", TemplateName: "stepscaling-aggregation-type", AwsType: "awsstr"},
-					{AwsField: "StepScalingPolicyConfiguration.MinAdjustmentMagnitude", TemplateName: "stepscaling-min-adjustment-magnitude", AwsType: "awsint64"},
+					{AwsField: "StepScalingPolicyConfiguration.MinAdjustmentMagnitude", TemplateName: "stepscaling-min-adjustment-magnitude", AwsType: "awsint64", Type: intType},
 				},
 			},
 			{
@@ -124,6 +124,18 @@ var AWSTemplatesDefinitions = map[string]template.Definition{
 			Api: "{{ $service.Api }}",
 			RequiredParams: []string{ {{- range $key := $def.RequiredKeys }}"{{ $key }}", {{- end}} },
 			ExtraParams: []string{ {{- range $key := $def.ExtraKeys }}"{{ $key }}", {{- end}} },
+			{{- with $def.TypedParams }}
+			ParamTypes: map[string]template.ParamType{
+			{{- range $param := . }}
+				"{{ $param.TemplateName }}": {Kind: "{{ $param.Type.Kind }}"
+				{{- if $param.Type.Min }}, Min: {{ $param.Type.Min }}{{ end }}
+				{{- if $param.Type.Max }}, Max: {{ $param.Type.Max }}{{ end }}
+				{{- with $param.Type.Values }}, Values: []string{ {{- range $val := . }}"{{ $val }}", {{- end }} }{{ end }}
+				{{- with $param.Type.Of }}, Of: "{{ . }}"{{ end }}
+				{{- with $param.Type.Prefix }}, Prefix: "{{ . }}"{{ end }}},
+			{{- end }}
+			},
+			{{- end }}
 		},
 {{- end }}
 {{- end }}
@@ -89,6 +89,50 @@ func (g *Graph) AddAppliesOnRelation(parent, child *Resource) error {
 	return g.addRelation(parent, child, rdf.ApplyOn)
 }
 
+// RemoveAppliesOnRelation removes the applies-on relation added with AddAppliesOnRelation
+func (g *Graph) RemoveAppliesOnRelation(parent, child *Resource) {
+	g.store.Remove(tstore.SubjPred(parent.Id(), rdf.ApplyOn).Resource(child.Id()))
+}
+
+// DeleteResource removes the resource with its properties and all the relations from or to it
+func (g *Graph) DeleteResource(res *Resource) {
+	snap := g.store.Snapshot()
+	g.store.Remove(snap.WithSubject(res.Id())...)
+	g.store.Remove(snap.WithObject(tstore.Resource(res.Id()))...)
+}
+
+// UpdateResource sets the given properties of a resource in the graph, leaving its
+// other properties and its relations unchanged. A nil property value removes the property.
+func (g *Graph) UpdateResource(res *Resource) error {
+	triples, err := res.marshalFullRDF()
+	if err != nil {
+		return err
+	}
+	snap := g.store.Snapshot()
+	for key := range res.Properties {
+		propId, err := rdf.Properties.GetRDFId(key)
+		if err != nil {
+			return fmt.Errorf("resource %s: updating property: %s", res, err)
+		}
+		g.store.Remove(snap.WithSubjPred(res.Id(), propId)...)
+	}
+	g.store.Add(triples...)
+	return nil
+}
+
+// Subgraph returns a graph holding the resources of the given types, with their properties
+// and the relations from them to other resources
+func (g *Graph) Subgraph(typs ...string) *Graph {
+	sub := NewGraph()
+	snap := g.store.Snapshot()
+	for _, t := range typs {
+		for _, tri := range snap.WithPredObj(rdf.RdfType, tstore.Resource(namespacedResourceType(t))) {
+			sub.store.Add(snap.WithSubject(tri.Subject())...)
+		}
+	}
+	return sub
+}
+
 func (g *Graph) GetResource(t string, id string) (*Resource, error) {
 	resource := InitResource(t, id)
 	snap := g.store.Snapshot()
@@ -101,3 +101,83 @@ func TestAddGraphRelation(t *testing.T) {
 		}
 	})
 }
+
+func TestUpdateAndDeleteResources(t *testing.T) {
+	newGraph := func() *Graph {
+		g := NewGraph()
+		vpc, sub, inst := InitResource("vpc", "vpc_1"), InitResource("subnet", "subnet_1"), InitResource("instance", "inst_1")
+		inst.Properties["Name"] = "web"
+		g.AddResource(vpc, sub, inst)
+		g.AddParentRelation(vpc, sub)
+		g.AddParentRelation(sub, inst)
+		g.AddAppliesOnRelation(InitResource("securitygroup", "sg_1"), inst)
+		return g
+	}
+
+	t.Run("Update", func(t *testing.T) {
+		g := newGraph()
+		inst := InitResource("instance", "inst_1")
+		inst.Properties["State"] = "running"
+		inst.Properties["Name"] = nil
+		if err := g.UpdateResource(inst); err != nil {
+			t.Fatal(err)
+		}
+
+		expTriples := tstore.Triples([]tstore.Triple{
+			tstore.SubjPred("vpc_1", "rdf:type").Resource("cloud-owl:Vpc"),
+			tstore.SubjPred("vpc_1", "cloud:id").StringLiteral("vpc_1"),
+			tstore.SubjPred("subnet_1", "rdf:type").Resource("cloud-owl:Subnet"),
+			tstore.SubjPred("subnet_1", "cloud:id").StringLiteral("subnet_1"),
+			tstore.SubjPred("inst_1", "rdf:type").Resource("cloud-owl:Instance"),
+			tstore.SubjPred("inst_1", "cloud:id").StringLiteral("inst_1"),
+			tstore.SubjPred("inst_1", "cloud:state").StringLiteral("running"),
+			tstore.SubjPred("vpc_1", "cloud-rel:parentOf").Resource("subnet_1"),
+			tstore.SubjPred("subnet_1", "cloud-rel:parentOf").Resource("inst_1"),
+			tstore.SubjPred("sg_1", "cloud-rel:applyOn").Resource("inst_1"),
+		})
+		if got, want := tstore.Triples(g.store.Snapshot().Triples()), expTriples; !got.Equal(want) {
+			t.Fatalf("got\n%v\nwant\n%v\n", got, want)
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		g := newGraph()
+		g.DeleteResource(InitResource("instance", "inst_1"))
+
+		expTriples := tstore.Triples([]tstore.Triple{
+			tstore.SubjPred("vpc_1", "rdf:type").Resource("cloud-owl:Vpc"),
+			tstore.SubjPred("vpc_1", "cloud:id").StringLiteral("vpc_1"),
+			tstore.SubjPred("subnet_1", "rdf:type").Resource("cloud-owl:Subnet"),
+			tstore.SubjPred("subnet_1", "cloud:id").StringLiteral("subnet_1"),
+			tstore.SubjPred("vpc_1", "cloud-rel:parentOf").Resource("subnet_1"),
+		})
+		if got, want := tstore.Triples(g.store.Snapshot().Triples()), expTriples; !got.Equal(want) {
+			t.Fatalf("got\n%v\nwant\n%v\n", got, want)
+		}
+	})
+
+	t.Run("Subgraph", func(t *testing.T) {
+		g := newGraph()
+
+		expTriples := tstore.Triples([]tstore.Triple{
+			tstore.SubjPred("vpc_1", "rdf:type").Resource("cloud-owl:Vpc"),
+			tstore.SubjPred("vpc_1", "cloud:id").StringLiteral("vpc_1"),
+			tstore.SubjPred("inst_1", "rdf:type").Resource("cloud-owl:Instance"),
+			tstore.SubjPred("inst_1", "cloud:id").StringLiteral("inst_1"),
+			tstore.SubjPred("inst_1", "cloud:name").StringLiteral("web"),
+			tstore.SubjPred("vpc_1", "cloud-rel:parentOf").Resource("subnet_1"),
+		})
+		if got, want := tstore.Triples(g.Subgraph("vpc", "instance").store.Snapshot().Triples()), expTriples; !got.Equal(want) {
+			t.Fatalf("got\n%v\nwant\n%v\n", got, want)
+		}
+	})
+
+	t.Run("Remove applies on", func(t *testing.T) {
+		g := newGraph()
+		g.RemoveAppliesOnRelation(InitResource("securitygroup", "sg_1"), InitResource("instance", "inst_1"))
+
+		if got, want := len(g.store.Snapshot().WithPredicate("cloud-rel:applyOn")), 0; got != want {
+			t.Fatalf("got %d, want %d", got, want)
+		}
+	})
+}
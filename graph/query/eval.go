@@ -0,0 +1,345 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package query
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wallix/awless/aws/services"
+	"github.com/wallix/awless/cloud/properties"
+	"github.com/wallix/awless/cloud/rdf"
+	"github.com/wallix/awless/graph"
+	tstore "github.com/wallix/triplestore"
+)
+
+var (
+	resourceTypes = make(map[string]bool)
+	relations     = map[string]bool{"parent": true, "children": true, "appliedon": true, "dependents": true}
+	// propertyLabels indexes the properties labels by their lowercased name
+	propertyLabels = make(map[string]string)
+
+	timeLayouts = []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02"}
+)
+
+func init() {
+	for _, t := range awsservices.ResourceTypes {
+		resourceTypes[t] = true
+	}
+	for label := range rdf.Labels {
+		propertyLabels[strings.ToLower(label)] = label
+	}
+}
+
+// source is the graph queried, with its RDF snapshot taken once for the whole run
+type source struct {
+	g    *graph.Graph
+	snap tstore.RDFGraph
+}
+
+type node interface {
+	eval(src *source, res *graph.Resource) (bool, error)
+}
+
+type andNode struct {
+	left, right node
+}
+
+func (n *andNode) eval(src *source, res *graph.Resource) (bool, error) {
+	ok, err := n.left.eval(src, res)
+	if err != nil || !ok {
+		return false, err
+	}
+	return n.right.eval(src, res)
+}
+
+type orNode struct {
+	left, right node
+}
+
+func (n *orNode) eval(src *source, res *graph.Resource) (bool, error) {
+	ok, err := n.left.eval(src, res)
+	if err != nil || ok {
+		return ok, err
+	}
+	return n.right.eval(src, res)
+}
+
+type notNode struct {
+	n node
+}
+
+func (n *notNode) eval(src *source, res *graph.Resource) (bool, error) {
+	ok, err := n.n.eval(src, res)
+	return !ok, err
+}
+
+type existsNode struct {
+	path *path
+}
+
+func (n *existsNode) eval(src *source, res *graph.Resource) (bool, error) {
+	values, err := n.path.values(src, res)
+	return len(values) > 0, err
+}
+
+// compareNode holds when a value of the path compares to the value. Negated operators
+// (!= and !~) hold when no value of the path is equal or matches.
+type compareNode struct {
+	path      *path
+	op, value string
+	regex     *regexp.Regexp
+}
+
+func (n *compareNode) eval(src *source, res *graph.Resource) (bool, error) {
+	values, err := n.path.values(src, res)
+	if err != nil {
+		return false, err
+	}
+	op, negated := n.op, false
+	switch op {
+	case "!=":
+		op, negated = "=", true
+	case "!~":
+		op, negated = "~", true
+	}
+	for _, v := range values {
+		if op == "~" && matchRegex(v, n.regex) || op != "~" && compare(v, op, n.value) {
+			return !negated, nil
+		}
+	}
+	return negated, nil
+}
+
+type inNode struct {
+	path   *path
+	values []string
+}
+
+func (n *inNode) eval(src *source, res *graph.Resource) (bool, error) {
+	values, err := n.path.values(src, res)
+	if err != nil {
+		return false, err
+	}
+	for _, v := range values {
+		for _, candidate := range n.values {
+			if compare(v, "=", candidate) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// path goes through relations from a resource, then to a property or a tag value
+type path struct {
+	text          string
+	relations     []string
+	property, tag string
+}
+
+// values returns the values of the property or tag of the resources reached through the relations,
+// or these resources when the path ends with a relation. List properties give all their elements.
+func (p *path) values(src *source, res *graph.Resource) (values []interface{}, err error) {
+	resources := []*graph.Resource{res}
+	for _, rel := range p.relations {
+		var next []*graph.Resource
+		seen := make(map[string]bool)
+		for _, r := range resources {
+			related, err := relatedResources(src, r, rel)
+			if err != nil {
+				return nil, err
+			}
+			for _, other := range related {
+				if !seen[other.Id()] {
+					seen[other.Id()] = true
+					next = append(next, other)
+				}
+			}
+		}
+		resources = next
+	}
+
+	for _, r := range resources {
+		switch {
+		case p.tag != "":
+			tags, _ := r.Properties[properties.Tags].([]string)
+			for _, t := range tags {
+				if splits := strings.SplitN(t, "=", 2); len(splits) == 2 && splits[0] == p.tag {
+					values = append(values, splits[1])
+				}
+			}
+		case p.property != "":
+			if v, ok := r.Properties[p.property]; ok {
+				values = append(values, flatten(v)...)
+			}
+		default:
+			values = append(values, r)
+		}
+	}
+	return values, nil
+}
+
+func relatedResources(src *source, res *graph.Resource, rel string) ([]*graph.Resource, error) {
+	g, snap := src.g, src.snap
+	switch rel {
+	case "parent":
+		var ids []string
+		for _, tri := range snap.WithPredObj(rdf.ParentOf, tstore.Resource(res.Id())) {
+			ids = append(ids, tri.Subject())
+		}
+		return findResources(g, ids)
+	case "children":
+		var ids []string
+		for _, tri := range snap.WithSubjPred(res.Id(), rdf.ParentOf) {
+			if id, ok := tri.Object().Resource(); ok {
+				ids = append(ids, id)
+			}
+		}
+		return findResources(g, ids)
+	case "appliedon":
+		return g.ListResourcesAppliedOn(res)
+	case "dependents":
+		return g.ListResourcesDependingOn(res)
+	}
+
+	// a resource type: ancestors, descendants and applies on relations in both directions
+	var related []*graph.Resource
+	collect := func(r *graph.Resource, depth int) error {
+		if r.Type() == rel {
+			related = append(related, r)
+		}
+		return nil
+	}
+	if err := g.Accept(&graph.ParentsVisitor{From: res, Each: collect}); err != nil {
+		return nil, err
+	}
+	if err := g.Accept(&graph.ChildrenVisitor{From: res, Each: collect}); err != nil {
+		return nil, err
+	}
+	for _, list := range []func(*graph.Resource) ([]*graph.Resource, error){g.ListResourcesAppliedOn, g.ListResourcesDependingOn} {
+		applied, err := list(res)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range applied {
+			collect(r, 0)
+		}
+	}
+	return related, nil
+}
+
+func findResources(g *graph.Graph, ids []string) (resources []*graph.Resource, err error) {
+	for _, id := range ids {
+		res, err := g.FindResource(id)
+		if err != nil {
+			return resources, err
+		}
+		if res != nil {
+			resources = append(resources, res)
+		}
+	}
+	return
+}
+
+func flatten(v interface{}) (values []interface{}) {
+	if v == nil {
+		return nil
+	}
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Slice {
+		for i := 0; i < rv.Len(); i++ {
+			values = append(values, rv.Index(i).Interface())
+		}
+		return
+	}
+	return []interface{}{v}
+}
+
+// compare a value of a path with a value of the query: as numbers, dates or booleans
+// when both are, as strings otherwise
+func compare(v interface{}, op, literal string) bool {
+	switch vv := v.(type) {
+	case *graph.Resource:
+		if strings.HasPrefix(literal, "@") {
+			name, _ := vv.Properties[properties.Name].(string)
+			return compareStrings(name, op, literal[1:])
+		}
+		return compareStrings(vv.Id(), op, literal)
+	case bool:
+		b, err := strconv.ParseBool(literal)
+		return err == nil && op == "=" && vv == b
+	case time.Time:
+		for _, layout := range timeLayouts {
+			if t, err := time.ParseInLocation(layout, literal, time.Local); err == nil {
+				return compareNumbers(float64(vv.Unix()), op, float64(t.Unix()))
+			}
+		}
+		return false
+	}
+
+	s := fmt.Sprint(v)
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		if lit, err := strconv.ParseFloat(literal, 64); err == nil {
+			return compareNumbers(f, op, lit)
+		}
+	}
+	return compareStrings(s, op, literal)
+}
+
+func compareNumbers(a float64, op string, b float64) bool {
+	switch op {
+	case "=":
+		return a == b
+	case "<":
+		return a < b
+	case ">":
+		return a > b
+	case "<=":
+		return a <= b
+	case ">=":
+		return a >= b
+	}
+	return false
+}
+
+func compareStrings(a, op, b string) bool {
+	switch op {
+	case "=":
+		return a == b
+	case "<":
+		return a < b
+	case ">":
+		return a > b
+	case "<=":
+		return a <= b
+	case ">=":
+		return a >= b
+	}
+	return false
+}
+
+func matchRegex(v interface{}, regex *regexp.Regexp) bool {
+	if res, ok := v.(*graph.Resource); ok {
+		name, _ := res.Properties[properties.Name].(string)
+		return regex.MatchString(res.Id()) || name != "" && regex.MatchString(name)
+	}
+	return regex.MatchString(fmt.Sprint(v))
+}
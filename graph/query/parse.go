@@ -0,0 +1,271 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package query
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	eofToken tokenKind = iota
+	wordToken
+	stringToken
+	operatorToken
+	punctToken
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (t token) isKeyword(kw string) bool {
+	return t.kind == wordToken && strings.EqualFold(t.text, kw)
+}
+
+func (t token) is(kind tokenKind, text string) bool {
+	return t.kind == kind && t.text == text
+}
+
+var operators = []string{"!=", "<=", ">=", "!~", "&&", "||", "=", "<", ">", "~", "!"}
+
+const punctuation = "()[],"
+
+// lex splits a query in words (types, paths, keywords and unquoted values),
+// quoted strings, operators and punctuation
+func lex(text string) (tokens []token, err error) {
+	for i := 0; i < len(text); {
+		c := rune(text[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case strings.ContainsRune(punctuation, c):
+			tokens = append(tokens, token{kind: punctToken, text: string(c), pos: i})
+			i++
+		case c == '"' || c == '\'':
+			end := strings.IndexRune(text[i+1:], c)
+			if end < 0 {
+				return nil, fmt.Errorf("column %d: unterminated string", i+1)
+			}
+			tokens = append(tokens, token{kind: stringToken, text: text[i+1 : i+1+end], pos: i})
+			i += end + 2
+		default:
+			if op := operatorAt(text[i:]); op != "" {
+				tokens = append(tokens, token{kind: operatorToken, text: op, pos: i})
+				i += len(op)
+				continue
+			}
+			start := i
+			for i < len(text) && isWordChar(rune(text[i])) {
+				i++
+			}
+			if i == start {
+				return nil, fmt.Errorf("column %d: unexpected '%c'", i+1, c)
+			}
+			tokens = append(tokens, token{kind: wordToken, text: text[start:i], pos: start})
+		}
+	}
+	return append(tokens, token{kind: eofToken, pos: len(text)}), nil
+}
+
+func operatorAt(s string) string {
+	for _, op := range operators {
+		if strings.HasPrefix(s, op) {
+			return op
+		}
+	}
+	return ""
+}
+
+func isWordChar(c rune) bool {
+	return !unicode.IsSpace(c) && !strings.ContainsRune(punctuation+"\"'=!<>~&|", c)
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != eofToken {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) errorf(tok token, format string, args ...interface{}) error {
+	if tok.kind == eofToken {
+		return fmt.Errorf("end of query: %s", fmt.Sprintf(format, args...))
+	}
+	return fmt.Errorf("column %d: %s", tok.pos+1, fmt.Sprintf(format, args...))
+}
+
+// parseExpr parses: and-expr { (or | ||) and-expr }
+func (p *parser) parseExpr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for tok := p.peek(); tok.isKeyword("or") || tok.is(operatorToken, "||"); tok = p.peek() {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &orNode{left, right}
+	}
+	return left, nil
+}
+
+// parseAnd parses: unary { (and | &&) unary }
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for tok := p.peek(); tok.isKeyword("and") || tok.is(operatorToken, "&&"); tok = p.peek() {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &andNode{left, right}
+	}
+	return left, nil
+}
+
+// parseUnary parses: (not | !) unary | ( expr ) | exists path | path op value | path in list
+func (p *parser) parseUnary() (node, error) {
+	tok := p.next()
+	switch {
+	case tok.isKeyword("not") || tok.is(operatorToken, "!"):
+		n, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{n}, nil
+	case tok.is(punctToken, "("):
+		n, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); !closing.is(punctToken, ")") {
+			return nil, p.errorf(closing, "expecting ')'")
+		}
+		return n, nil
+	case tok.isKeyword("exists"):
+		pathTok := p.next()
+		path, err := p.parsePath(pathTok)
+		if err != nil {
+			return nil, err
+		}
+		return &existsNode{path}, nil
+	}
+
+	path, err := p.parsePath(tok)
+	if err != nil {
+		return nil, err
+	}
+	opTok := p.next()
+	switch {
+	case opTok.isKeyword("in"):
+		values, err := p.parseList()
+		if err != nil {
+			return nil, err
+		}
+		return &inNode{path: path, values: values}, nil
+	case opTok.kind == operatorToken && opTok.text != "!" && opTok.text != "&&" && opTok.text != "||":
+		valTok := p.next()
+		if valTok.kind != wordToken && valTok.kind != stringToken {
+			return nil, p.errorf(valTok, "expecting a value after '%s'", opTok.text)
+		}
+		cmp := &compareNode{path: path, op: opTok.text, value: valTok.text}
+		if cmp.op == "~" || cmp.op == "!~" {
+			if cmp.regex, err = regexp.Compile(valTok.text); err != nil {
+				return nil, p.errorf(valTok, "invalid regular expression: %s", err)
+			}
+		}
+		return cmp, nil
+	default:
+		return nil, p.errorf(opTok, "expecting an operator (=, !=, <, >, <=, >=, ~, !~ or in) after '%s'", tok.text)
+	}
+}
+
+func (p *parser) parseList() ([]string, error) {
+	if tok := p.next(); !tok.is(punctToken, "[") {
+		return nil, p.errorf(tok, "expecting '[' to start a list")
+	}
+	var values []string
+	for {
+		tok := p.next()
+		if tok.kind != wordToken && tok.kind != stringToken {
+			return nil, p.errorf(tok, "expecting a value in list")
+		}
+		values = append(values, tok.text)
+		switch sep := p.next(); {
+		case sep.is(punctToken, "]"):
+			return values, nil
+		case !sep.is(punctToken, ","):
+			return nil, p.errorf(sep, "expecting ',' or ']' in list")
+		}
+	}
+}
+
+// parsePath parses dot separated segments: relations, then a property or tag.KEY
+func (p *parser) parsePath(tok token) (*path, error) {
+	if tok.kind != wordToken {
+		return nil, p.errorf(tok, "expecting a property or relation")
+	}
+	segments := strings.Split(tok.text, ".")
+	path := &path{text: tok.text}
+	for i := 0; i < len(segments); i++ {
+		seg := segments[i]
+		last := i == len(segments)-1
+		switch lower := strings.ToLower(seg); {
+		case seg == "":
+			return nil, p.errorf(tok, "empty segment in path '%s'", tok.text)
+		case relations[lower] || resourceTypes[lower]:
+			path.relations = append(path.relations, lower)
+		case lower == "tag":
+			if i != len(segments)-2 {
+				return nil, p.errorf(tok, "expecting tag.KEY at the end of path '%s'", tok.text)
+			}
+			path.tag = segments[i+1]
+			return path, nil
+		case last:
+			label, ok := propertyLabels[lower]
+			if !ok {
+				return nil, p.errorf(tok, "unknown property or relation '%s'", seg)
+			}
+			path.property = label
+		default:
+			return nil, p.errorf(tok, "'%s' is not a relation: properties can only end a path", seg)
+		}
+	}
+	return path, nil
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package query selects resources of a graph with a small expression language:
+//
+//	instance where State = running and Type in [t2.micro, t2.small]
+//	instance where subnet.vpc.tag.Env = prod
+//	securitygroup where not exists appliedon
+//	volume where Size >= 100 or Name ~ "^db-"
+//
+// A query is a resource type followed by an optional condition. Conditions compare
+// the values of paths (with =, !=, <, >, <=, >=, ~ for regex matches, !~ and in),
+// test their existence (exists) and combine with and, or, not and parentheses.
+//
+// A path is a property (i.e. State, case insensitive), a tag value (tag.KEY) or
+// relations traversed from the resource, optionally ending with a property:
+//   - a resource type (i.e. subnet, vpc) goes to the related resources of this type:
+//     ancestors, descendants, and resources applied on or depending on the resource
+//   - parent and children go to the direct parent and children
+//   - appliedon goes to the resources the resource is applied on (i.e. instances of a securitygroup)
+//   - dependents goes to the resources applied on the resource
+//
+// A path ending with relations compares the related resources by id, or by name
+// when the value starts with @ (as aliases in templates). A comparison holds when
+// any value of the path (related resources, list properties) satisfies it.
+package query
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/wallix/awless/cloud"
+	"github.com/wallix/awless/graph"
+)
+
+// Query selects the resources of a type satisfying a condition
+type Query struct {
+	Type string
+	cond node
+	text string
+}
+
+// Parse parses a query: a resource type (singular or plural) optionally followed by 'where' and a condition
+func Parse(text string) (*Query, error) {
+	tokens, err := lex(text)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+
+	typ := p.next()
+	if typ.kind != wordToken {
+		return nil, p.errorf(typ, "expecting a resource type")
+	}
+	q := &Query{Type: resourceType(typ.text), text: text}
+	if q.Type == "" {
+		return nil, p.errorf(typ, "unknown resource type '%s'", typ.text)
+	}
+
+	if tok := p.next(); tok.kind != eofToken {
+		if !tok.isKeyword("where") {
+			return nil, p.errorf(tok, "expecting 'where'")
+		}
+		if q.cond, err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+		if tok = p.next(); tok.kind != eofToken {
+			return nil, p.errorf(tok, "unexpected '%s'", tok.text)
+		}
+	}
+	return q, nil
+}
+
+// Run returns the resources of the graph matching the query
+func (q *Query) Run(g *graph.Graph) ([]*graph.Resource, error) {
+	all, err := g.GetAllResources(q.Type)
+	if err != nil {
+		return nil, err
+	}
+	if q.cond == nil {
+		return all, nil
+	}
+
+	src := &source{g: g, snap: g.AsRDFGraphSnaphot()}
+	var matching []*graph.Resource
+	for _, res := range all {
+		ok, err := q.cond.eval(src, res)
+		if err != nil {
+			return matching, fmt.Errorf("%s: %s", res.Id(), err)
+		}
+		if ok {
+			matching = append(matching, res)
+		}
+	}
+	return matching, nil
+}
+
+func (q *Query) String() string {
+	return q.text
+}
+
+func resourceType(word string) string {
+	word = strings.ToLower(word)
+	for _, candidate := range []string{word, cloud.SingularizeResource(word)} {
+		if resourceTypes[candidate] {
+			return candidate
+		}
+	}
+	return ""
+}
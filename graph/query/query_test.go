@@ -0,0 +1,124 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package query
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wallix/awless/cloud/properties"
+	"github.com/wallix/awless/graph"
+	"github.com/wallix/awless/graph/resourcetest"
+)
+
+func TestRunQueries(t *testing.T) {
+	g := graph.NewGraph()
+	launched := time.Date(2017, 6, 1, 12, 0, 0, 0, time.UTC)
+	g.AddResource(
+		resourcetest.VPC("vpc_prod").Prop(properties.Name, "prod").Prop(properties.Tags, []string{"Env=prod"}).Build(),
+		resourcetest.VPC("vpc_dev").Prop(properties.Tags, []string{"Env=dev", "Team=web"}).Build(),
+		resourcetest.Subnet("sub_prod").Prop(properties.Public, true).Build(),
+		resourcetest.Subnet("sub_dev").Prop(properties.Public, false).Build(),
+		resourcetest.Instance("inst_1").Prop(properties.Name, "web-1").Prop(properties.State, "running").Prop(properties.Type, "t2.micro").Prop(properties.Launched, launched).Build(),
+		resourcetest.Instance("inst_2").Prop(properties.Name, "db-1").Prop(properties.State, "stopped").Prop(properties.Type, "m4.large").Build(),
+		resourcetest.Instance("inst_3").Prop(properties.Name, "web-2").Prop(properties.State, "running").Prop(properties.Type, "t2.small").Build(),
+		resourcetest.SecurityGroup("sg_web").Build(),
+		resourcetest.SecurityGroup("sg_unused").Build(),
+		resourcetest.Volume("vol_1").Prop(properties.Size, 100).Build(),
+		resourcetest.Volume("vol_2").Prop(properties.Size, 8).Build(),
+	)
+	g.AddParentRelation(resourcetest.VPC("vpc_prod").Build(), resourcetest.Subnet("sub_prod").Build())
+	g.AddParentRelation(resourcetest.VPC("vpc_dev").Build(), resourcetest.Subnet("sub_dev").Build())
+	g.AddParentRelation(resourcetest.Subnet("sub_prod").Build(), resourcetest.Instance("inst_1").Build())
+	g.AddParentRelation(resourcetest.Subnet("sub_prod").Build(), resourcetest.Instance("inst_2").Build())
+	g.AddParentRelation(resourcetest.Subnet("sub_dev").Build(), resourcetest.Instance("inst_3").Build())
+	g.AddAppliesOnRelation(resourcetest.SecurityGroup("sg_web").Build(), resourcetest.Instance("inst_1").Build())
+	g.AddAppliesOnRelation(resourcetest.SecurityGroup("sg_web").Build(), resourcetest.Instance("inst_3").Build())
+
+	tcases := []struct {
+		query string
+		exp   []string
+	}{
+		{"instances", []string{"inst_1", "inst_2", "inst_3"}},
+		{"instance where State = running", []string{"inst_1", "inst_3"}},
+		{"instance where state != running", []string{"inst_2"}},
+		{"instance where Type in [t2.micro, m4.large]", []string{"inst_1", "inst_2"}},
+		{`instance where name ~ "^web-"`, []string{"inst_1", "inst_3"}},
+		{`instance where name !~ "^web-"`, []string{"inst_2"}},
+		{"volume where size >= 10", []string{"vol_1"}},
+		{"volume where size < 10 or size > 50", []string{"vol_1", "vol_2"}},
+		{"instance where launched < 2017-07-01 and launched > '2017-05-31 23:00'", []string{"inst_1"}},
+		{"subnet where public = true", []string{"sub_prod"}},
+		{"instance where subnet.vpc.tag.Env = prod", []string{"inst_1", "inst_2"}},
+		{"instance where vpc = @prod && !(state = stopped)", []string{"inst_1"}},
+		{"instance where parent = sub_dev", []string{"inst_3"}},
+		{"vpc where exists tag.Team", []string{"vpc_dev"}},
+		{"vpc where instance.state = stopped", []string{"vpc_prod"}},
+		{"vpc where children.children.name ~ web", []string{"vpc_dev", "vpc_prod"}},
+		{"securitygroup where not exists appliedon", []string{"sg_unused"}},
+		{"securitygroup where appliedon.vpc = vpc_dev", []string{"sg_web"}},
+		{"instance where not exists dependents", []string{"inst_2"}},
+		{"instance where securitygroup = sg_web and subnet.public = false", []string{"inst_3"}},
+	}
+
+	for _, tcase := range tcases {
+		q, err := Parse(tcase.query)
+		if err != nil {
+			t.Fatalf("%s: %s", tcase.query, err)
+		}
+		resources, err := q.Run(g)
+		if err != nil {
+			t.Fatalf("%s: %s", tcase.query, err)
+		}
+		ids := graph.Resources(resources).Map(func(r *graph.Resource) string { return r.Id() })
+		sort.Strings(ids)
+		if got, want := ids, tcase.exp; !reflect.DeepEqual(got, want) {
+			t.Fatalf("%s: got %v, want %v", tcase.query, got, want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tcases := []struct {
+		query, expErr string
+	}{
+		{"", "end of query: expecting a resource type"},
+		{"unknown", "column 1: unknown resource type 'unknown'"},
+		{"instance State = running", "column 10: expecting 'where'"},
+		{"instance where", "end of query: expecting a property or relation"},
+		{"instance where Stat = running", "column 16: unknown property or relation 'Stat'"},
+		{"instance where State.Name = running", "column 16: 'State' is not a relation: properties can only end a path"},
+		{"instance where tag.Env.Key = prod", "column 16: expecting tag.KEY at the end of path 'tag.Env.Key'"},
+		{"instance where State running", "column 22: expecting an operator"},
+		{"instance where Name ~ '('", "column 23: invalid regular expression"},
+		{"instance where Type in [t2.micro", "end of query: expecting ',' or ']' in list"},
+		{"instance where (State = running", "end of query: expecting ')'"},
+		{"instance where State = running)", "column 31: unexpected ')'"},
+		{"instance where Name = 'web", "column 23: unterminated string"},
+		{"instance where State = running & Type = t2.micro", "column 32: unexpected '&'"},
+	}
+
+	for _, tcase := range tcases {
+		_, err := Parse(tcase.query)
+		if err == nil || !strings.Contains(err.Error(), tcase.expErr) {
+			t.Fatalf("%q: got %v, want error containing %q", tcase.query, err, tcase.expErr)
+		}
+	}
+}
@@ -0,0 +1,500 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparql
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	tstore "github.com/wallix/triplestore"
+)
+
+type termKind int
+
+const (
+	varTerm termKind = iota
+	iriTerm
+	literalTerm
+)
+
+// term is a variable, an IRI or a literal, in patterns as in solutions
+type term struct {
+	kind     termKind
+	value    string
+	datatype tstore.XsdType
+	lang     string
+}
+
+func variable(name string) term { return term{kind: varTerm, value: name} }
+
+func iri(value string) term { return term{kind: iriTerm, value: value} }
+
+func literal(value string, typ tstore.XsdType) term {
+	return term{kind: literalTerm, value: value, datatype: typ}
+}
+
+func objectTerm(obj tstore.Object) term {
+	if lit, ok := obj.Literal(); ok {
+		return term{kind: literalTerm, value: lit.Value(), datatype: lit.Type(), lang: lit.Lang()}
+	}
+	res, _ := obj.Resource()
+	return iri(res)
+}
+
+// solution binds variables to IRIs or literals
+type solution map[string]term
+
+func (s solution) with(name string, t term) solution {
+	extended := make(solution, len(s)+1)
+	for k, v := range s {
+		extended[k] = v
+	}
+	extended[name] = t
+	return extended
+}
+
+type element interface {
+	eval(snap tstore.RDFGraph, solutions []solution) ([]solution, error)
+}
+
+// group joins its elements in order, then keeps the solutions satisfying all its filters
+type group struct {
+	elements []element
+	filters  []expression
+}
+
+func (g *group) eval(snap tstore.RDFGraph, solutions []solution) ([]solution, error) {
+	var err error
+	for _, el := range g.elements {
+		if solutions, err = el.eval(snap, solutions); err != nil {
+			return nil, err
+		}
+	}
+	if len(g.filters) == 0 {
+		return solutions, nil
+	}
+
+	var filtered []solution
+	for _, sol := range solutions {
+		if g.satisfies(sol) {
+			filtered = append(filtered, sol)
+		}
+	}
+	return filtered, nil
+}
+
+func (g *group) satisfies(sol solution) bool {
+	for _, f := range g.filters {
+		if ok, err := effectiveBoolean(f, sol); err != nil || !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// optionalElement extends each solution with the ones of its group, keeping solutions it does not match
+type optionalElement struct {
+	*group
+}
+
+func (o *optionalElement) eval(snap tstore.RDFGraph, solutions []solution) ([]solution, error) {
+	var result []solution
+	for _, sol := range solutions {
+		extended, err := o.group.eval(snap, []solution{sol})
+		if err != nil {
+			return nil, err
+		}
+		if len(extended) == 0 {
+			result = append(result, sol)
+		} else {
+			result = append(result, extended...)
+		}
+	}
+	return result, nil
+}
+
+type triplePattern struct {
+	subject, predicate, object term
+}
+
+func (tp *triplePattern) eval(snap tstore.RDFGraph, solutions []solution) ([]solution, error) {
+	var result []solution
+	for _, sol := range solutions {
+		sub, pred, obj := tp.subject.bind(sol), tp.predicate.bind(sol), tp.object.bind(sol)
+		for _, tri := range candidates(snap, sub, pred, obj) {
+			extended, ok := sol, true
+			for _, m := range []struct{ pattern, value term }{
+				{sub, iri(tri.Subject())},
+				{pred, iri(tri.Predicate())},
+				{obj, objectTerm(tri.Object())},
+			} {
+				if extended, ok = match(extended, m.pattern, m.value); !ok {
+					break
+				}
+			}
+			if ok {
+				result = append(result, extended)
+			}
+		}
+	}
+	return result, nil
+}
+
+// bind replaces a variable bound in the solution by its value
+func (t term) bind(sol solution) term {
+	if t.kind == varTerm {
+		if v, ok := sol[t.value]; ok {
+			return v
+		}
+	}
+	return t
+}
+
+func match(sol solution, pattern, value term) (solution, bool) {
+	if pattern.kind != varTerm {
+		return sol, pattern == value
+	}
+	if bound, ok := sol[pattern.value]; ok {
+		return sol, bound == value
+	}
+	return sol.with(pattern.value, value), true
+}
+
+// candidates uses the indexes of the graph to get the triples that may match
+func candidates(snap tstore.RDFGraph, sub, pred, obj term) []tstore.Triple {
+	switch {
+	case sub.kind == iriTerm && pred.kind == iriTerm:
+		return snap.WithSubjPred(sub.value, pred.value)
+	case sub.kind == iriTerm:
+		return snap.WithSubject(sub.value)
+	case pred.kind == iriTerm && obj.kind == iriTerm:
+		return snap.WithPredObj(pred.value, tstore.Resource(obj.value))
+	case pred.kind == iriTerm:
+		return snap.WithPredicate(pred.value)
+	case obj.kind == iriTerm:
+		return snap.WithObject(tstore.Resource(obj.value))
+	}
+	return snap.Triples()
+}
+
+var errUnbound = errors.New("unbound variable")
+
+// iriValue distinguishes IRIs from string literals in expressions
+type iriValue string
+
+// expression evaluates to an iriValue, a string, a float64, a bool or a time.Time
+type expression interface {
+	eval(sol solution) (interface{}, error)
+}
+
+type termExpr struct {
+	term term
+}
+
+func (e *termExpr) eval(sol solution) (interface{}, error) {
+	t := e.term.bind(sol)
+	if t.kind == varTerm {
+		return nil, errUnbound
+	}
+	return termValue(t), nil
+}
+
+func termValue(t term) interface{} {
+	if t.kind == iriTerm {
+		return iriValue(t.value)
+	}
+	switch t.datatype {
+	case tstore.XsdBoolean:
+		if b, err := strconv.ParseBool(t.value); err == nil {
+			return b
+		}
+	case tstore.XsdInteger, tstore.XsdDouble, tstore.XsdFloat, tstore.XsdByte, tstore.XsdShort,
+		tstore.XsdUinteger, tstore.XsdUnsignedByte, tstore.XsdUnsignedShort, "xsd:int", "xsd:decimal", "xsd:long":
+		if f, err := strconv.ParseFloat(t.value, 64); err == nil {
+			return f
+		}
+	case tstore.XsdDateTime:
+		if tm, err := time.Parse(time.RFC3339Nano, t.value); err == nil {
+			return tm
+		}
+	}
+	return t.value
+}
+
+type logicalExpr struct {
+	op          string
+	left, right expression
+}
+
+// eval follows SPARQL: an error on one side is ignored when the other side decides
+func (e *logicalExpr) eval(sol solution) (interface{}, error) {
+	left, lerr := effectiveBoolean(e.left, sol)
+	right, rerr := effectiveBoolean(e.right, sol)
+	decisive := e.op == "||"
+	switch {
+	case lerr == nil && left == decisive, rerr == nil && right == decisive:
+		return decisive, nil
+	case lerr != nil:
+		return nil, lerr
+	case rerr != nil:
+		return nil, rerr
+	}
+	return !decisive, nil
+}
+
+type notExpr struct {
+	expr expression
+}
+
+func (e *notExpr) eval(sol solution) (interface{}, error) {
+	b, err := effectiveBoolean(e.expr, sol)
+	return !b, err
+}
+
+type comparisonExpr struct {
+	op          string
+	left, right expression
+}
+
+func (e *comparisonExpr) eval(sol solution) (interface{}, error) {
+	left, err := e.left.eval(sol)
+	if err != nil {
+		return nil, err
+	}
+	right, err := e.right.eval(sol)
+	if err != nil {
+		return nil, err
+	}
+
+	cmp, err := compareValues(left, right)
+	if err != nil {
+		switch e.op {
+		case "=":
+			return false, nil
+		case "!=":
+			return true, nil
+		}
+		return nil, err
+	}
+	switch e.op {
+	case "=":
+		return cmp == 0, nil
+	case "!=":
+		return cmp != 0, nil
+	case "<":
+		return cmp < 0, nil
+	case ">":
+		return cmp > 0, nil
+	case "<=":
+		return cmp <= 0, nil
+	case ">=":
+		return cmp >= 0, nil
+	}
+	return nil, fmt.Errorf("unknown operator '%s'", e.op)
+}
+
+func compareValues(a, b interface{}) (int, error) {
+	switch aa := a.(type) {
+	case float64:
+		if bb, ok := b.(float64); ok {
+			return compareFloats(aa, bb), nil
+		}
+	case string:
+		if bb, ok := b.(string); ok {
+			return strings.Compare(aa, bb), nil
+		}
+	case iriValue:
+		if bb, ok := b.(iriValue); ok {
+			return strings.Compare(string(aa), string(bb)), nil
+		}
+	case bool:
+		if bb, ok := b.(bool); ok {
+			return compareFloats(boolToFloat(aa), boolToFloat(bb)), nil
+		}
+	case time.Time:
+		if bb, ok := b.(time.Time); ok {
+			return compareFloats(float64(aa.UnixNano()), float64(bb.UnixNano())), nil
+		}
+	}
+	return 0, fmt.Errorf("cannot compare %v with %v", a, b)
+}
+
+func compareFloats(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// functions gives the minimum and maximum number of arguments of the supported functions
+var functions = map[string][2]int{
+	"bound":     {1, 1},
+	"regex":     {2, 3},
+	"str":       {1, 1},
+	"lcase":     {1, 1},
+	"ucase":     {1, 1},
+	"contains":  {2, 2},
+	"strstarts": {2, 2},
+	"strends":   {2, 2},
+	"isiri":     {1, 1},
+	"isuri":     {1, 1},
+	"isliteral": {1, 1},
+}
+
+type callExpr struct {
+	name  string
+	args  []expression
+	regex *regexp.Regexp
+}
+
+// compileRegex compiles once the pattern of a regex call when it is a literal
+func (e *callExpr) compileRegex() (err error) {
+	pattern, ok := e.args[1].(*termExpr)
+	if !ok || pattern.term.kind != literalTerm {
+		return nil
+	}
+	var flags string
+	if len(e.args) == 3 {
+		if f, ok := e.args[2].(*termExpr); ok {
+			flags = f.term.value
+		}
+	}
+	e.regex, err = compileRegex(pattern.term.value, flags)
+	return err
+}
+
+func compileRegex(pattern, flags string) (*regexp.Regexp, error) {
+	if flags != "" {
+		if strings.Trim(flags, "ims") != "" {
+			return nil, fmt.Errorf("unsupported flags '%s'", flags)
+		}
+		pattern = fmt.Sprintf("(?%s)%s", flags, pattern)
+	}
+	return regexp.Compile(pattern)
+}
+
+func (e *callExpr) eval(sol solution) (interface{}, error) {
+	if e.name == "bound" {
+		_, ok := sol[e.args[0].(*termExpr).term.value]
+		return ok, nil
+	}
+
+	var args []interface{}
+	for _, arg := range e.args {
+		v, err := arg.eval(sol)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, v)
+	}
+
+	switch e.name {
+	case "isiri", "isuri":
+		_, ok := args[0].(iriValue)
+		return ok, nil
+	case "isliteral":
+		_, ok := args[0].(iriValue)
+		return !ok, nil
+	case "str":
+		return str(args[0]), nil
+	case "lcase":
+		return strings.ToLower(str(args[0])), nil
+	case "ucase":
+		return strings.ToUpper(str(args[0])), nil
+	case "contains":
+		return strings.Contains(str(args[0]), str(args[1])), nil
+	case "strstarts":
+		return strings.HasPrefix(str(args[0]), str(args[1])), nil
+	case "strends":
+		return strings.HasSuffix(str(args[0]), str(args[1])), nil
+	case "regex":
+		regex := e.regex
+		if regex == nil {
+			var flags string
+			if len(args) == 3 {
+				flags = str(args[2])
+			}
+			var err error
+			if regex, err = compileRegex(str(args[1]), flags); err != nil {
+				return nil, err
+			}
+		}
+		return regex.MatchString(str(args[0])), nil
+	}
+	return nil, fmt.Errorf("unknown function '%s'", e.name)
+}
+
+// str gives the lexical form of a value
+func str(v interface{}) string {
+	switch vv := v.(type) {
+	case iriValue:
+		return string(vv)
+	case time.Time:
+		return vv.UTC().Format(time.RFC3339Nano)
+	}
+	return fmt.Sprint(v)
+}
+
+func effectiveBoolean(e expression, sol solution) (bool, error) {
+	v, err := e.eval(sol)
+	if err != nil {
+		return false, err
+	}
+	switch vv := v.(type) {
+	case bool:
+		return vv, nil
+	case string:
+		return vv != "", nil
+	case float64:
+		return vv != 0, nil
+	}
+	return false, fmt.Errorf("no boolean value for %v", v)
+}
+
+type orderKey struct {
+	variable string
+	desc     bool
+}
+
+// orderTerms orders unbound variables first, then IRIs before literals,
+// then compares values of the same type or their lexical forms
+func orderTerms(a, b term, aok, bok bool) int {
+	switch {
+	case !aok || !bok:
+		return compareFloats(boolToFloat(aok), boolToFloat(bok))
+	case a.kind != b.kind:
+		return compareFloats(float64(a.kind), float64(b.kind))
+	}
+	if cmp, err := compareValues(termValue(a), termValue(b)); err == nil {
+		return cmp
+	}
+	return strings.Compare(a.value, b.value)
+}
@@ -0,0 +1,587 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparql
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/wallix/awless/cloud/rdf"
+	tstore "github.com/wallix/triplestore"
+)
+
+type tokenKind int
+
+const (
+	eofToken tokenKind = iota
+	wordToken
+	varToken
+	iriToken
+	stringToken
+	numberToken
+	operatorToken
+	punctToken
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (t token) isKeyword(kw string) bool {
+	return t.kind == wordToken && strings.EqualFold(t.text, kw)
+}
+
+func (t token) is(kind tokenKind, text string) bool {
+	return t.kind == kind && t.text == text
+}
+
+var (
+	operators = []string{"!=", "<=", ">=", "&&", "||", "^^", "=", "<", ">", "!"}
+	iriRef    = regexp.MustCompile("^<[^<>\"{}|^`\\\\\\s]*>")
+	number    = regexp.MustCompile(`^[+-]?[0-9]+(\.[0-9]+)?`)
+)
+
+const punctuation = "{}().;,*"
+
+// lex splits a query in words (keywords, prefixed names), variables, IRIs,
+// strings (unescaped), numbers, operators and punctuation
+func lex(text string) (tokens []token, err error) {
+	for i := 0; i < len(text); {
+		c := rune(text[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '#':
+			for i < len(text) && text[i] != '\n' {
+				i++
+			}
+		case c == '?' || c == '$':
+			start := i
+			i++
+			for i < len(text) && isNameChar(rune(text[i])) {
+				i++
+			}
+			if i == start+1 {
+				return nil, fmt.Errorf("column %d: expecting a variable name", start+1)
+			}
+			tokens = append(tokens, token{kind: varToken, text: text[start+1 : i], pos: start})
+		case c == '<' && iriRef.MatchString(text[i:]):
+			iri := iriRef.FindString(text[i:])
+			tokens = append(tokens, token{kind: iriToken, text: iri[1 : len(iri)-1], pos: i})
+			i += len(iri)
+		case c == '"' || c == '\'':
+			s, n, err := unquote(text[i:])
+			if err != nil {
+				return nil, fmt.Errorf("column %d: %s", i+1, err)
+			}
+			tokens = append(tokens, token{kind: stringToken, text: s, pos: i})
+			i += n
+		case number.MatchString(text[i:]) && (c != '+' && c != '-' || len(tokens) == 0 || tokens[len(tokens)-1].kind == operatorToken || tokens[len(tokens)-1].kind == punctToken):
+			num := number.FindString(text[i:])
+			tokens = append(tokens, token{kind: numberToken, text: num, pos: i})
+			i += len(num)
+		case strings.ContainsRune(punctuation, c):
+			tokens = append(tokens, token{kind: punctToken, text: string(c), pos: i})
+			i++
+		default:
+			if op := operatorAt(text[i:]); op != "" {
+				tokens = append(tokens, token{kind: operatorToken, text: op, pos: i})
+				i += len(op)
+				continue
+			}
+			start := i
+			for i < len(text) && (isNameChar(rune(text[i])) || text[i] == ':' || text[i] == '.' || text[i] == '@') {
+				i++
+			}
+			// a dot ending a name terminates the triple
+			for i > start && text[i-1] == '.' {
+				i--
+			}
+			if i == start {
+				return nil, fmt.Errorf("column %d: unexpected '%c'", i+1, c)
+			}
+			tokens = append(tokens, token{kind: wordToken, text: text[start:i], pos: start})
+		}
+	}
+	return append(tokens, token{kind: eofToken, pos: len(text)}), nil
+}
+
+func operatorAt(s string) string {
+	for _, op := range operators {
+		if strings.HasPrefix(s, op) {
+			return op
+		}
+	}
+	return ""
+}
+
+func isNameChar(c rune) bool {
+	return unicode.IsLetter(c) || unicode.IsDigit(c) || c == '_' || c == '-'
+}
+
+// unquote reads a quoted string at the start of s, returning its unescaped content and its length in s
+func unquote(s string) (string, int, error) {
+	quote := s[0]
+	var buf bytes.Buffer
+	for i := 1; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == quote:
+			return buf.String(), i + 1, nil
+		case c == '\\' && i+1 < len(s):
+			i++
+			switch s[i] {
+			case 'n':
+				buf.WriteByte('\n')
+			case 't':
+				buf.WriteByte('\t')
+			case 'r':
+				buf.WriteByte('\r')
+			default:
+				buf.WriteByte(s[i])
+			}
+		default:
+			buf.WriteByte(c)
+		}
+	}
+	return "", 0, fmt.Errorf("unterminated string")
+}
+
+type parser struct {
+	tokens   []token
+	pos      int
+	prefixes map[string]string
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != eofToken {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) expect(kind tokenKind, text string) error {
+	if tok := p.next(); !tok.is(kind, text) {
+		return p.errorf(tok, "expecting '%s'", text)
+	}
+	return nil
+}
+
+func (p *parser) errorf(tok token, format string, args ...interface{}) error {
+	if tok.kind == eofToken {
+		return fmt.Errorf("end of query: %s", fmt.Sprintf(format, args...))
+	}
+	return fmt.Errorf("column %d: %s", tok.pos+1, fmt.Sprintf(format, args...))
+}
+
+// parseQuery parses: (PREFIX pname: <iri>)* (SELECT [DISTINCT] (vars | *) | ASK) [WHERE] group modifiers
+func (p *parser) parseQuery(q *Query) (err error) {
+	for p.peek().isKeyword("prefix") {
+		p.next()
+		name := p.next()
+		if name.kind != wordToken || !strings.HasSuffix(name.text, ":") {
+			return p.errorf(name, "expecting a prefix name ending with ':'")
+		}
+		iri := p.next()
+		if iri.kind != iriToken {
+			return p.errorf(iri, "expecting an IRI for prefix '%s'", name.text)
+		}
+		p.prefixes[strings.TrimSuffix(name.text, ":")] = iri.text
+	}
+
+	switch tok := p.next(); {
+	case tok.isKeyword("select"):
+		if p.peek().isKeyword("distinct") {
+			p.next()
+			q.distinct = true
+		}
+		if p.peek().is(punctToken, "*") {
+			p.next()
+		} else {
+			for p.peek().kind == varToken {
+				q.vars = append(q.vars, p.next().text)
+			}
+			if len(q.vars) == 0 {
+				return p.errorf(p.peek(), "expecting variables or '*' to select")
+			}
+		}
+	case tok.isKeyword("ask"):
+		q.ask = true
+	default:
+		return p.errorf(tok, "expecting SELECT or ASK")
+	}
+
+	if p.peek().isKeyword("where") {
+		p.next()
+	}
+	if q.where, err = p.parseGroup(); err != nil {
+		return err
+	}
+
+	if p.peek().isKeyword("order") {
+		p.next()
+		if tok := p.next(); !tok.isKeyword("by") {
+			return p.errorf(tok, "expecting BY after ORDER")
+		}
+		if q.orderBy, err = p.parseOrderKeys(); err != nil {
+			return err
+		}
+	}
+
+	for tok := p.peek(); tok.isKeyword("limit") || tok.isKeyword("offset"); tok = p.peek() {
+		p.next()
+		num := p.next()
+		n, err := strconv.Atoi(num.text)
+		if num.kind != numberToken || err != nil || n < 0 {
+			return p.errorf(num, "expecting a positive integer after %s", strings.ToUpper(tok.text))
+		}
+		if tok.isKeyword("limit") {
+			q.limit = n
+		} else {
+			q.offset = n
+		}
+	}
+
+	if tok := p.next(); tok.kind != eofToken {
+		return p.errorf(tok, "unexpected '%s'", tok.text)
+	}
+	return nil
+}
+
+// parseOrderKeys parses: (?var | ASC(?var) | DESC(?var))+
+func (p *parser) parseOrderKeys() (keys []orderKey, err error) {
+	for {
+		tok := p.peek()
+		switch {
+		case tok.kind == varToken:
+			p.next()
+			keys = append(keys, orderKey{variable: tok.text})
+		case tok.isKeyword("asc") || tok.isKeyword("desc"):
+			p.next()
+			if err = p.expect(punctToken, "("); err != nil {
+				return nil, err
+			}
+			v := p.next()
+			if v.kind != varToken {
+				return nil, p.errorf(v, "expecting a variable to order by")
+			}
+			if err = p.expect(punctToken, ")"); err != nil {
+				return nil, err
+			}
+			keys = append(keys, orderKey{variable: v.text, desc: tok.isKeyword("desc")})
+		case len(keys) == 0:
+			return nil, p.errorf(tok, "expecting a variable to order by")
+		default:
+			return keys, nil
+		}
+	}
+}
+
+// parseGroup parses: { (triples | OPTIONAL group | FILTER constraint | group)* }
+func (p *parser) parseGroup() (*group, error) {
+	if err := p.expect(punctToken, "{"); err != nil {
+		return nil, err
+	}
+	g := &group{}
+	for {
+		tok := p.peek()
+		switch {
+		case tok.is(punctToken, "}"):
+			p.next()
+			return g, nil
+		case tok.kind == eofToken:
+			return nil, p.errorf(tok, "expecting '}'")
+		case tok.is(punctToken, "."):
+			p.next()
+		case tok.isKeyword("optional"):
+			p.next()
+			opt, err := p.parseGroup()
+			if err != nil {
+				return nil, err
+			}
+			g.elements = append(g.elements, &optionalElement{opt})
+		case tok.isKeyword("filter"):
+			p.next()
+			var expr expression
+			var err error
+			if p.peek().is(punctToken, "(") {
+				expr, err = p.parseBrackettedExpr()
+			} else {
+				expr, err = p.parsePrimary()
+			}
+			if err != nil {
+				return nil, err
+			}
+			g.filters = append(g.filters, expr)
+		case tok.is(punctToken, "{"):
+			sub, err := p.parseGroup()
+			if err != nil {
+				return nil, err
+			}
+			g.elements = append(g.elements, sub)
+		default:
+			patterns, err := p.parseTriples()
+			if err != nil {
+				return nil, err
+			}
+			for _, pattern := range patterns {
+				g.elements = append(g.elements, pattern)
+			}
+		}
+	}
+}
+
+// parseTriples parses: subject predicate object (, object)* (; predicate object (, object)*)*
+func (p *parser) parseTriples() (patterns []*triplePattern, err error) {
+	subject, err := p.parseTerm(false)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		predicate, err := p.parsePredicate()
+		if err != nil {
+			return nil, err
+		}
+		for {
+			object, err := p.parseTerm(true)
+			if err != nil {
+				return nil, err
+			}
+			patterns = append(patterns, &triplePattern{subject, predicate, object})
+			if !p.peek().is(punctToken, ",") {
+				break
+			}
+			p.next()
+		}
+		if !p.peek().is(punctToken, ";") {
+			return patterns, nil
+		}
+		p.next()
+		if tok := p.peek(); tok.is(punctToken, ".") || tok.is(punctToken, "}") {
+			return patterns, nil
+		}
+	}
+}
+
+func (p *parser) parsePredicate() (term, error) {
+	if tok := p.peek(); tok.kind == wordToken && tok.text == "a" {
+		p.next()
+		return iri(rdf.RdfType), nil
+	}
+	return p.parseTerm(false)
+}
+
+// parseTerm parses a variable, an IRI (<iri> or prefixed name) or, when allowed, a literal
+func (p *parser) parseTerm(allowLiteral bool) (term, error) {
+	tok := p.next()
+	switch tok.kind {
+	case varToken:
+		return variable(tok.text), nil
+	case iriToken:
+		return iri(p.compact(tok.text)), nil
+	case wordToken:
+		if strings.Contains(tok.text, ":") {
+			return iri(tok.text), nil
+		}
+		if allowLiteral && (tok.text == "true" || tok.text == "false") {
+			return literal(tok.text, tstore.XsdBoolean), nil
+		}
+	case stringToken, numberToken:
+		if allowLiteral {
+			return p.parseLiteral(tok)
+		}
+		return term{}, p.errorf(tok, "literals can only be objects")
+	}
+	return term{}, p.errorf(tok, "expecting a variable, an IRI or a literal")
+}
+
+// parseLiteral parses a string with an optional language tag or datatype, or a number
+func (p *parser) parseLiteral(tok token) (term, error) {
+	if tok.kind == numberToken {
+		if strings.Contains(tok.text, ".") {
+			return literal(tok.text, tstore.XsdDouble), nil
+		}
+		return literal(strings.TrimPrefix(tok.text, "+"), tstore.XsdInteger), nil
+	}
+
+	lit := literal(tok.text, tstore.XsdString)
+	switch next := p.peek(); {
+	case next.kind == wordToken && strings.HasPrefix(next.text, "@"):
+		p.next()
+		lit.lang = next.text[1:]
+	case next.is(operatorToken, "^^"):
+		p.next()
+		typ := p.next()
+		switch typ.kind {
+		case iriToken:
+			lit.datatype = tstore.XsdType(p.compact(typ.text))
+		case wordToken:
+			lit.datatype = tstore.XsdType(typ.text)
+		default:
+			return term{}, p.errorf(typ, "expecting a datatype after '^^'")
+		}
+	}
+	return lit, nil
+}
+
+// compact turns full IRIs into the prefixed names used in the store
+// (i.e. rdf:type for http://www.w3.org/1999/02/22-rdf-syntax-ns#type)
+func (p *parser) compact(iri string) string {
+	for _, prefixes := range []map[string]string{p.prefixes, tstore.RDFContext.Prefixes} {
+		for name, ns := range prefixes {
+			if ns != "" && strings.HasPrefix(iri, ns) {
+				return name + ":" + strings.TrimPrefix(iri, ns)
+			}
+		}
+	}
+	return iri
+}
+
+func (p *parser) parseBrackettedExpr() (expression, error) {
+	if err := p.expect(punctToken, "("); err != nil {
+		return nil, err
+	}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	return expr, p.expect(punctToken, ")")
+}
+
+func (p *parser) parseOr() (expression, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().is(operatorToken, "||") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalExpr{op: "||", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (expression, error) {
+	left, err := p.parseComparison()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().is(operatorToken, "&&") {
+		p.next()
+		right, err := p.parseComparison()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalExpr{op: "&&", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseComparison() (expression, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	switch tok := p.peek(); {
+	case tok.kind == operatorToken && tok.text != "!" && tok.text != "&&" && tok.text != "||" && tok.text != "^^":
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &comparisonExpr{op: tok.text, left: left, right: right}, nil
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (expression, error) {
+	if p.peek().is(operatorToken, "!") {
+		p.next()
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notExpr{expr}, nil
+	}
+	return p.parsePrimary()
+}
+
+// parsePrimary parses: ( expr ) | function call | variable | IRI | literal
+func (p *parser) parsePrimary() (expression, error) {
+	tok := p.peek()
+	switch {
+	case tok.is(punctToken, "("):
+		return p.parseBrackettedExpr()
+	case tok.kind == wordToken && !strings.Contains(tok.text, ":") && tok.text != "true" && tok.text != "false":
+		p.next()
+		name := strings.ToLower(tok.text)
+		arity, ok := functions[name]
+		if !ok {
+			return nil, p.errorf(tok, "unknown function '%s'", tok.text)
+		}
+		if err := p.expect(punctToken, "("); err != nil {
+			return nil, err
+		}
+		call := &callExpr{name: name}
+		for !p.peek().is(punctToken, ")") {
+			if len(call.args) > 0 {
+				if err := p.expect(punctToken, ","); err != nil {
+					return nil, err
+				}
+			}
+			arg, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			call.args = append(call.args, arg)
+		}
+		p.next()
+		if len(call.args) < arity[0] || len(call.args) > arity[1] {
+			return nil, p.errorf(tok, "wrong number of arguments for %s", tok.text)
+		}
+		if name == "bound" {
+			if v, ok := call.args[0].(*termExpr); !ok || v.term.kind != varTerm {
+				return nil, p.errorf(tok, "bound expects a variable")
+			}
+		}
+		if name == "regex" {
+			if err := call.compileRegex(); err != nil {
+				return nil, p.errorf(tok, "invalid regular expression: %s", err)
+			}
+		}
+		return call, nil
+	}
+
+	t, err := p.parseTerm(true)
+	if err != nil {
+		return nil, err
+	}
+	return &termExpr{t}, nil
+}
@@ -0,0 +1,257 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package sparql evaluates a subset of SPARQL 1.1 over the triples of a graph:
+//
+//	SELECT ?id ?name WHERE {
+//	  ?inst a cloud-owl:Instance ; cloud:id ?id ; cloud:state "running" .
+//	  OPTIONAL { ?inst cloud:name ?name }
+//	  FILTER (regex(?id, "^i-") && ?id != "i-1234")
+//	} ORDER BY DESC(?name) LIMIT 10
+//
+// Supported are SELECT (with DISTINCT, variables or *) and ASK queries, PREFIX
+// declarations, basic graph patterns (with 'a', ';' and ',' shorthands), nested and
+// OPTIONAL groups, FILTER (with =, !=, <, >, <=, >=, &&, ||, ! and the functions
+// bound, regex, str, lcase, ucase, contains, strstarts, strends, isIRI and isLiteral),
+// ORDER BY (ASC or DESC), LIMIT and OFFSET.
+//
+// Terms of the store are identified by prefixed names (i.e. cloud:name, cloud-owl:Vpc,
+// rdf:type) and resources by their ids (i.e. <vpc-1234>). Full IRIs of declared prefixes,
+// and of the rdf, rdfs and xsd namespaces, are turned into these prefixed names.
+package sparql
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/wallix/awless/graph"
+	tstore "github.com/wallix/triplestore"
+)
+
+// Query is a parsed SELECT or ASK query
+type Query struct {
+	vars          []string
+	distinct, ask bool
+	where         *group
+	orderBy       []orderKey
+	limit, offset int
+	text          string
+}
+
+// Parse parses a SELECT or ASK query
+func Parse(text string) (*Query, error) {
+	tokens, err := lex(text)
+	if err != nil {
+		return nil, err
+	}
+	q := &Query{text: text, limit: -1}
+	p := &parser{tokens: tokens, prefixes: make(map[string]string)}
+	if err := p.parseQuery(q); err != nil {
+		return nil, err
+	}
+	if len(q.vars) == 0 {
+		q.vars = q.where.variables(nil)
+	}
+	return q, nil
+}
+
+// IsAsk returns true for ASK queries
+func (q *Query) IsAsk() bool {
+	return q.ask
+}
+
+func (q *Query) String() string {
+	return q.text
+}
+
+// Run evaluates the query over the triples of the graph
+func (q *Query) Run(g *graph.Graph) (*Result, error) {
+	solutions, err := q.where.eval(g.AsRDFGraphSnaphot(), []solution{{}})
+	if err != nil {
+		return nil, err
+	}
+	if q.ask {
+		return &Result{Ask: true, Boolean: len(solutions) > 0}, nil
+	}
+
+	if len(q.orderBy) > 0 {
+		sort.SliceStable(solutions, func(i, j int) bool {
+			for _, key := range q.orderBy {
+				a, aok := solutions[i][key.variable]
+				b, bok := solutions[j][key.variable]
+				cmp := orderTerms(a, b, aok, bok)
+				if key.desc {
+					cmp = -cmp
+				}
+				if cmp != 0 {
+					return cmp < 0
+				}
+			}
+			return false
+		})
+	}
+
+	result := &Result{Vars: q.vars}
+	seen := make(map[string]bool)
+	for _, sol := range solutions {
+		projected := make(solution)
+		for _, v := range q.vars {
+			if t, ok := sol[v]; ok {
+				projected[v] = t
+			}
+		}
+		if q.distinct {
+			key := fmt.Sprint(result.row(projected))
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+		}
+		result.solutions = append(result.solutions, projected)
+	}
+
+	if q.offset >= len(result.solutions) {
+		result.solutions = nil
+	} else {
+		result.solutions = result.solutions[q.offset:]
+	}
+	if q.limit >= 0 && q.limit < len(result.solutions) {
+		result.solutions = result.solutions[:q.limit]
+	}
+	return result, nil
+}
+
+// variables lists the variables of the group in order of appearance
+func (g *group) variables(vars []string) []string {
+	add := func(t term) {
+		if t.kind != varTerm {
+			return
+		}
+		for _, v := range vars {
+			if v == t.value {
+				return
+			}
+		}
+		vars = append(vars, t.value)
+	}
+	for _, el := range g.elements {
+		switch e := el.(type) {
+		case *triplePattern:
+			add(e.subject)
+			add(e.predicate)
+			add(e.object)
+		case *optionalElement:
+			vars = e.group.variables(vars)
+		case *group:
+			vars = e.variables(vars)
+		}
+	}
+	return vars
+}
+
+// Result holds the solutions of a SELECT query or the answer of an ASK query
+type Result struct {
+	Vars      []string
+	Ask       bool
+	Boolean   bool
+	solutions []solution
+}
+
+// Len returns the number of solutions
+func (r *Result) Len() int {
+	return len(r.solutions)
+}
+
+// Rows returns the values of the selected variables for each solution (empty when unbound)
+func (r *Result) Rows() (rows [][]string) {
+	for _, sol := range r.solutions {
+		rows = append(rows, r.row(sol))
+	}
+	return
+}
+
+func (r *Result) row(sol solution) []string {
+	row := make([]string, len(r.Vars))
+	for i, v := range r.Vars {
+		row[i] = sol[v].value
+	}
+	return row
+}
+
+// WriteJSON writes the result in the SPARQL 1.1 query results JSON format
+func (r *Result) WriteJSON(w io.Writer) error {
+	type jsonTerm struct {
+		Type     string `json:"type"`
+		Value    string `json:"value"`
+		Datatype string `json:"datatype,omitempty"`
+		Lang     string `json:"xml:lang,omitempty"`
+	}
+	type jsonResult struct {
+		Head struct {
+			Vars []string `json:"vars,omitempty"`
+		} `json:"head"`
+		Boolean *bool `json:"boolean,omitempty"`
+		Results *struct {
+			Bindings []map[string]jsonTerm `json:"bindings"`
+		} `json:"results,omitempty"`
+	}
+
+	var out jsonResult
+	if r.Ask {
+		out.Boolean = &r.Boolean
+	} else {
+		out.Head.Vars = r.Vars
+		out.Results = &struct {
+			Bindings []map[string]jsonTerm `json:"bindings"`
+		}{Bindings: []map[string]jsonTerm{}}
+		for _, sol := range r.solutions {
+			binding := make(map[string]jsonTerm)
+			for name, t := range sol {
+				jt := jsonTerm{Type: "uri", Value: t.value}
+				if t.kind == literalTerm {
+					jt.Type, jt.Lang = "literal", t.lang
+					if t.datatype != tstore.XsdString && t.lang == "" {
+						jt.Datatype = t.datatype.NTriplesNamespaced()
+					}
+				}
+				binding[name] = jt
+			}
+			out.Results.Bindings = append(out.Results.Bindings, binding)
+		}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+// WriteCSV writes the result in the SPARQL 1.1 query results CSV format
+// (ASK queries give a single true or false line)
+func (r *Result) WriteCSV(w io.Writer) error {
+	if r.Ask {
+		_, err := fmt.Fprintln(w, r.Boolean)
+		return err
+	}
+	enc := csv.NewWriter(w)
+	enc.UseCRLF = true
+	if err := enc.Write(r.Vars); err != nil {
+		return err
+	}
+	return enc.WriteAll(r.Rows())
+}
@@ -0,0 +1,263 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sparql
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wallix/awless/cloud/properties"
+	"github.com/wallix/awless/graph"
+	"github.com/wallix/awless/graph/resourcetest"
+)
+
+func TestRunQueries(t *testing.T) {
+	g := graph.NewGraph()
+	g.AddResource(
+		resourcetest.VPC("vpc_1").Prop(properties.Name, "prod").Build(),
+		resourcetest.Subnet("sub_1").Prop(properties.Public, true).Build(),
+		resourcetest.Instance("inst_1").Prop(properties.Name, "web-1").Prop(properties.State, "running").Prop(properties.Launched, time.Date(2017, 6, 1, 12, 0, 0, 0, time.UTC)).Build(),
+		resourcetest.Instance("inst_2").Prop(properties.Name, "db-1").Prop(properties.State, "stopped").Build(),
+		resourcetest.Instance("inst_3").Prop(properties.State, "running").Build(),
+		resourcetest.Volume("vol_1").Prop(properties.Size, 100).Build(),
+		resourcetest.Volume("vol_2").Prop(properties.Size, 8).Build(),
+	)
+	g.AddParentRelation(resourcetest.VPC("vpc_1").Build(), resourcetest.Subnet("sub_1").Build())
+	g.AddParentRelation(resourcetest.Subnet("sub_1").Build(), resourcetest.Instance("inst_1").Build())
+	g.AddParentRelation(resourcetest.Subnet("sub_1").Build(), resourcetest.Instance("inst_2").Build())
+
+	tcases := []struct {
+		query string
+		vars  []string
+		rows  [][]string
+	}{
+		{
+			query: `SELECT ?id WHERE { ?i a cloud-owl:Instance ; cloud:id ?id } ORDER BY ?id`,
+			vars:  []string{"id"},
+			rows:  [][]string{{"inst_1"}, {"inst_2"}, {"inst_3"}},
+		},
+		{
+			query: `SELECT ?i ?name { ?i a cloud-owl:Instance ; cloud:state "running" . OPTIONAL { ?i cloud:name ?name } } ORDER BY DESC(?i)`,
+			vars:  []string{"i", "name"},
+			rows:  [][]string{{"inst_3", ""}, {"inst_1", "web-1"}},
+		},
+		{
+			query: `SELECT ?i WHERE { ?i cloud:state ?s . OPTIONAL { ?i cloud:name ?n } FILTER (!bound(?n)) }`,
+			vars:  []string{"i"},
+			rows:  [][]string{{"inst_3"}},
+		},
+		{
+			query: `SELECT ?v ?size WHERE { ?v rdf:type cloud-owl:Volume ; cloud:size ?size FILTER(?size >= 10) }`,
+			vars:  []string{"v", "size"},
+			rows:  [][]string{{"vol_1", "100"}},
+		},
+		{
+			query: `SELECT ?v { ?v cloud:size 8 }`,
+			vars:  []string{"v"},
+			rows:  [][]string{{"vol_2"}},
+		},
+		{
+			query: `SELECT ?name WHERE { ?i cloud:name ?name FILTER (regex(?name, "^WEB", "i") || contains(?name, "db")) } ORDER BY ?name`,
+			vars:  []string{"name"},
+			rows:  [][]string{{"db-1"}, {"web-1"}},
+		},
+		{
+			query: `SELECT ?i WHERE { ?i cloud:launched ?l FILTER (?l > "2017-01-01T00:00:00Z"^^xsd:dateTime) }`,
+			vars:  []string{"i"},
+			rows:  [][]string{{"inst_1"}},
+		},
+		{
+			query: `PREFIX owl: <http://awless.io/owl#>
+			SELECT ?vpcname ?inst WHERE {
+			  ?vpc <http://www.w3.org/1999/02/22-rdf-syntax-ns#type> cloud-owl:Vpc ; cloud:name ?vpcname .
+			  ?vpc cloud-rel:parentOf ?sub . ?sub cloud-rel:parentOf ?inst .
+			  ?sub cloud:public true .
+			} ORDER BY ?inst LIMIT 1 OFFSET 1`,
+			vars: []string{"vpcname", "inst"},
+			rows: [][]string{{"prod", "inst_2"}},
+		},
+		{
+			query: `SELECT DISTINCT ?type WHERE { ?s a ?type FILTER (?type != cloud-owl:Volume && ?type != cloud-owl:Instance) }`,
+			vars:  []string{"type"},
+			rows:  [][]string{{"cloud-owl:Vpc"}, {"cloud-owl:Subnet"}},
+		},
+		{
+			query: `SELECT * WHERE { <vpc_1> ?p ?o FILTER (isLiteral(?o) && ?p != cloud:id) }`,
+			vars:  []string{"p", "o"},
+			rows:  [][]string{{"cloud:name", "prod"}},
+		},
+	}
+
+	for _, tcase := range tcases {
+		q, err := Parse(tcase.query)
+		if err != nil {
+			t.Fatalf("%s: %s", tcase.query, err)
+		}
+		result, err := q.Run(g)
+		if err != nil {
+			t.Fatalf("%s: %s", tcase.query, err)
+		}
+		if got, want := result.Vars, tcase.vars; !reflect.DeepEqual(got, want) {
+			t.Fatalf("%s: vars: got %v, want %v", tcase.query, got, want)
+		}
+		rows := result.Rows()
+		if !strings.Contains(tcase.query, "ORDER BY") {
+			sortRows(rows)
+			sortRows(tcase.rows)
+		}
+		if got, want := rows, tcase.rows; !reflect.DeepEqual(got, want) {
+			t.Fatalf("%s: got %v, want %v", tcase.query, got, want)
+		}
+	}
+
+	t.Run("ask", func(t *testing.T) {
+		for query, exp := range map[string]bool{
+			`ASK { <inst_2> cloud:state "stopped" }`:                           true,
+			`ASK WHERE { ?i cloud:state "terminated" }`:                        false,
+			`ASK { ?s cloud-rel:parentOf <inst_3> }`:                           false,
+			`ASK { ?v cloud:size ?s FILTER (?s < 10 && ?s > "a") }`:            false,
+			`ASK { ?v a cloud-owl:Volume ; cloud:size ?s FILTER(?s = 100.0) }`: true,
+		} {
+			q, err := Parse(query)
+			if err != nil {
+				t.Fatalf("%s: %s", query, err)
+			}
+			result, err := q.Run(g)
+			if err != nil {
+				t.Fatalf("%s: %s", query, err)
+			}
+			if !result.Ask || result.Boolean != exp {
+				t.Fatalf("%s: got %t, want %t", query, result.Boolean, exp)
+			}
+		}
+	})
+
+	t.Run("results formats", func(t *testing.T) {
+		q, err := Parse(`SELECT ?v ?size ?name WHERE { ?v cloud:size ?size OPTIONAL { ?v cloud:name ?name } } ORDER BY ?size`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result, err := q.Run(g)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var buff bytes.Buffer
+		if err = result.WriteCSV(&buff); err != nil {
+			t.Fatal(err)
+		}
+		if got, want := buff.String(), "v,size,name\r\nvol_2,8,\r\nvol_1,100,\r\n"; got != want {
+			t.Fatalf("got %q, want %q", got, want)
+		}
+
+		buff.Reset()
+		if err = result.WriteJSON(&buff); err != nil {
+			t.Fatal(err)
+		}
+		expJSON := `{
+  "head": {
+    "vars": [
+      "v",
+      "size",
+      "name"
+    ]
+  },
+  "results": {
+    "bindings": [
+      {
+        "size": {
+          "type": "literal",
+          "value": "8",
+          "datatype": "http://www.w3.org/2001/XMLSchema#integer"
+        },
+        "v": {
+          "type": "uri",
+          "value": "vol_2"
+        }
+      },
+      {
+        "size": {
+          "type": "literal",
+          "value": "100",
+          "datatype": "http://www.w3.org/2001/XMLSchema#integer"
+        },
+        "v": {
+          "type": "uri",
+          "value": "vol_1"
+        }
+      }
+    ]
+  }
+}
+`
+		if got, want := buff.String(), expJSON; got != want {
+			t.Fatalf("got\n%s\nwant\n%s", got, want)
+		}
+
+		q, _ = Parse(`ASK { ?v cloud:size 8 }`)
+		result, _ = q.Run(g)
+		buff.Reset()
+		if err = result.WriteJSON(&buff); err != nil {
+			t.Fatal(err)
+		}
+		if got, want := buff.String(), "{\n  \"head\": {},\n  \"boolean\": true\n}\n"; got != want {
+			t.Fatalf("got %q, want %q", got, want)
+		}
+	})
+}
+
+func TestParseErrors(t *testing.T) {
+	tcases := []struct {
+		query, expErr string
+	}{
+		{"", "end of query: expecting SELECT or ASK"},
+		{"DESCRIBE ?s", "column 1: expecting SELECT or ASK"},
+		{"SELECT WHERE { ?s ?p ?o }", "column 8: expecting variables or '*' to select"},
+		{"SELECT ?s WHERE ?s ?p ?o", "column 17: expecting '{'"},
+		{"SELECT ?s WHERE { ?s ?p ?o", "end of query: expecting '}'"},
+		{`SELECT ?s WHERE { "lit" ?p ?o }`, "column 19: literals can only be objects"},
+		{"SELECT ?s WHERE { ?s ?p ?o FILTER (?o = }", "column 41: expecting a variable, an IRI or a literal"},
+		{"SELECT ?s WHERE { ?s ?p ?o FILTER (unknown(?o)) }", "column 36: unknown function 'unknown'"},
+		{"SELECT ?s WHERE { ?s ?p ?o FILTER (bound(?o, ?s)) }", "column 36: wrong number of arguments for bound"},
+		{"SELECT ?s WHERE { ?s ?p ?o FILTER (bound('x')) }", "column 36: bound expects a variable"},
+		{"SELECT ?s WHERE { ?s ?p ?o FILTER regex(?o, '(') }", "column 35: invalid regular expression"},
+		{"SELECT ?s WHERE { ?s ?p ?o } ORDER ?s", "column 36: expecting BY after ORDER"},
+		{"SELECT ?s WHERE { ?s ?p ?o } LIMIT x", "column 36: expecting a positive integer after LIMIT"},
+		{"SELECT ?s WHERE { ?s ?p ?o } GROUP BY ?s", "column 30: unexpected 'GROUP'"},
+		{"SELECT ?s WHERE { ?s ?p 'o }", "column 25: unterminated string"},
+		{"PREFIX cloud <http://awless.io/> SELECT ?s { ?s ?p ?o }", "column 8: expecting a prefix name ending with ':'"},
+	}
+
+	for _, tcase := range tcases {
+		_, err := Parse(tcase.query)
+		if err == nil || !strings.Contains(err.Error(), tcase.expErr) {
+			t.Fatalf("%q: got %v, want error containing %q", tcase.query, err, tcase.expErr)
+		}
+	}
+}
+
+func sortRows(rows [][]string) {
+	for i := range rows {
+		for j := i + 1; j < len(rows); j++ {
+			if strings.Join(rows[j], ",") < strings.Join(rows[i], ",") {
+				rows[i], rows[j] = rows[j], rows[i]
+			}
+		}
+	}
+}
@@ -42,6 +42,10 @@ func KeyPair(id string) *rBuilder {
 	return new("keypair", id)
 }
 
+func Volume(id string) *rBuilder {
+	return new("volume", id)
+}
+
 func InternetGw(id string) *rBuilder {
 	return new("internetgateway", id)
 }
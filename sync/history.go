@@ -0,0 +1,180 @@
+/*
+Copyright 2017 WALLIX
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sync
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/wallix/awless/cloud/properties"
+	"github.com/wallix/awless/graph"
+	"github.com/wallix/awless/sync/repo"
+)
+
+type EventKind string
+
+const (
+	Appeared    EventKind = "appeared"
+	Changed     EventKind = "changed"
+	Disappeared EventKind = "disappeared"
+)
+
+// PropertyChange holds the values of a property before and after a change (nil when absent)
+type PropertyChange struct {
+	Property string
+	From, To interface{}
+}
+
+// ResourceEvent is a change of a resource seen at a revision, compared to the previous one
+type ResourceEvent struct {
+	Rev     *repo.Rev
+	Kind    EventKind
+	Type    string
+	Region  string
+	Changes []*PropertyChange
+}
+
+// ResourceHistory finds a resource by id (or else by name) in the revisions, sorted by date, and returns its id
+// and its events: when it appeared (with its properties), changed property by property, and disappeared
+func ResourceHistory(ref string, revs []*repo.Rev) (string, []*ResourceEvent, error) {
+	id, err := resolveResourceId(ref, revs)
+	if err != nil {
+		return id, nil, err
+	}
+
+	var events []*ResourceEvent
+	var previous *graph.Resource
+	var previousRegion string
+	for _, rev := range revs {
+		current, region, err := findInRev(rev, id)
+		if err != nil {
+			return id, events, err
+		}
+		switch {
+		case previous == nil && current != nil:
+			events = append(events, &ResourceEvent{Rev: rev, Kind: Appeared, Type: current.Type(), Region: region, Changes: diffProperties(nil, current)})
+		case previous != nil && current == nil:
+			events = append(events, &ResourceEvent{Rev: rev, Kind: Disappeared, Type: previous.Type(), Region: previousRegion})
+		case previous != nil && current != nil:
+			if changes := diffProperties(previous, current); len(changes) > 0 {
+				events = append(events, &ResourceEvent{Rev: rev, Kind: Changed, Type: current.Type(), Region: region, Changes: changes})
+			}
+		}
+		previous, previousRegion = current, region
+	}
+	return id, events, nil
+}
+
+func resolveResourceId(ref string, revs []*repo.Rev) (string, error) {
+	ref = strings.TrimPrefix(ref, "@")
+	ids := make(map[string]bool)
+	for _, rev := range revs {
+		for _, g := range rev.Graphs {
+			if res, err := g.FindResource(ref); err != nil {
+				return ref, err
+			} else if res != nil {
+				return ref, nil
+			}
+			named, err := g.FindResourcesByProperty(properties.Name, ref)
+			if err != nil {
+				return ref, err
+			}
+			for _, res := range named {
+				ids[res.Id()] = true
+			}
+		}
+	}
+
+	switch len(ids) {
+	case 0:
+		return ref, fmt.Errorf("resource '%s' not found in synced revisions", ref)
+	case 1:
+		for id := range ids {
+			return id, nil
+		}
+	}
+	var all []string
+	for id := range ids {
+		all = append(all, id)
+	}
+	sort.Strings(all)
+	return ref, fmt.Errorf("resources %s are named '%s': use an id", strings.Join(all, ", "), ref)
+}
+
+// findInRev returns the resource in the revision, merging its properties across synced files, and its region
+func findInRev(rev *repo.Rev, id string) (*graph.Resource, string, error) {
+	var paths []string
+	for path := range rev.Graphs {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var found *graph.Resource
+	var region string
+	for _, path := range paths {
+		res, err := rev.Graphs[path].FindResource(id)
+		if err != nil {
+			return nil, "", err
+		}
+		if res == nil {
+			continue
+		}
+		if found == nil {
+			found, region = res, repo.Region(path)
+			continue
+		}
+		for k, v := range res.Properties {
+			if _, ok := found.Properties[k]; !ok {
+				found.Properties[k] = v
+			}
+		}
+	}
+	return found, region, nil
+}
+
+func diffProperties(from, to *graph.Resource) (changes []*PropertyChange) {
+	keys := make(map[string]bool)
+	for _, res := range []*graph.Resource{from, to} {
+		if res == nil {
+			continue
+		}
+		for k := range res.Properties {
+			keys[k] = true
+		}
+	}
+	var sorted []string
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	for _, k := range sorted {
+		var before, after interface{}
+		if from != nil {
+			before = from.Properties[k]
+		}
+		if to != nil {
+			after = to.Properties[k]
+		}
+		if !reflect.DeepEqual(before, after) {
+			changes = append(changes, &PropertyChange{Property: k, From: before, To: after})
+		}
+	}
+	return
+}
@@ -0,0 +1,102 @@
+package sync
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wallix/awless/cloud/properties"
+	"github.com/wallix/awless/graph"
+	"github.com/wallix/awless/graph/resourcetest"
+	"github.com/wallix/awless/sync/repo"
+)
+
+func TestResourceHistory(t *testing.T) {
+	newRev := func(id string, day int, graphs map[string]*graph.Resource) *repo.Rev {
+		rev := &repo.Rev{Id: id, Date: time.Date(2017, 9, day, 12, 0, 0, 0, time.UTC), Graphs: make(map[string]*graph.Graph)}
+		for path, res := range graphs {
+			g := graph.NewGraph()
+			if res != nil {
+				g.AddResource(res)
+			}
+			rev.Graphs[path] = g
+		}
+		return rev
+	}
+
+	revs := []*repo.Rev{
+		newRev("1", 1, map[string]*graph.Resource{"eu-west-1/infra.triples": nil}),
+		newRev("2", 2, map[string]*graph.Resource{
+			"eu-west-1/infra.triples": resourcetest.Instance("inst_1").Prop(properties.Name, "web").Prop(properties.State, "pending").Build(),
+		}),
+		newRev("3", 3, map[string]*graph.Resource{
+			"eu-west-1/infra.triples": resourcetest.Instance("inst_1").Prop(properties.Name, "web").Prop(properties.State, "pending").Build(),
+			"global/access.triples":   resourcetest.User("user_1").Build(),
+		}),
+		newRev("4", 4, map[string]*graph.Resource{
+			"eu-west-1/infra.triples": resourcetest.Instance("inst_1").Prop(properties.Name, "web").Prop(properties.State, "running").Prop(properties.Type, "t2.micro").Build(),
+		}),
+		newRev("5", 5, map[string]*graph.Resource{"eu-west-1/infra.triples": nil}),
+	}
+
+	id, events, err := ResourceHistory("web", revs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := id, "inst_1"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := len(events), 3; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+
+	if got, want := events[0].Kind, Appeared; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := events[0].Rev.Id, "2"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := events[0].Region, "eu-west-1"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := len(events[0].Changes), 3; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+
+	if got, want := events[1].Kind, Changed; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := events[1].Rev.Id, "4"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	expChanges := []*PropertyChange{
+		{Property: properties.State, From: "pending", To: "running"},
+		{Property: properties.Type, From: nil, To: "t2.micro"},
+	}
+	if got, want := events[1].Changes, expChanges; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %#v, want %#v", got, want)
+	}
+
+	if got, want := events[2].Kind, Disappeared; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := events[2].Type, "instance"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+
+	_, events, err = ResourceHistory("user_1", revs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(events), 2; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+	if got, want := events[0].Region, "global"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+
+	if _, _, err = ResourceHistory("unknown", revs); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Fatalf("got %v, want not found error", err)
+	}
+}
@@ -38,12 +38,71 @@ type Rev struct {
 
 	Infra  *graph.Graph
 	Access *graph.Graph
+
+	// Graphs holds the graph of each synced file by its path in the repository (i.e. us-east-1/infra.triples)
+	Graphs map[string]*graph.Graph
+}
+
+// Region returns the region of a synced file path ("global" for global services)
+func Region(path string) string {
+	if dir := filepath.Dir(filepath.FromSlash(path)); dir != "." {
+		return filepath.ToSlash(dir)
+	}
+	return ""
+}
+
+// Graph merges the graphs synced in the given regions and global services, or all synced graphs when no region is given
+func (r *Rev) Graph(regions ...string) *graph.Graph {
+	g := graph.NewGraph()
+	for path, fileGraph := range r.Graphs {
+		region := Region(path)
+		include := len(regions) == 0 || region == "global" || region == ""
+		for _, reg := range regions {
+			include = include || region == reg
+		}
+		if include {
+			g.AddGraph(fileGraph)
+		}
+	}
+	return g
 }
 
 func (r *Rev) DateString() string {
 	return r.Date.Format("Mon Jan 2 15:04:05")
 }
 
+// FindRev returns the revision whose id starts with the given (abbreviated) id
+func FindRev(revs []*Rev, id string) (*Rev, error) {
+	var found []*Rev
+	for _, rev := range revs {
+		if strings.HasPrefix(rev.Id, id) {
+			found = append(found, rev)
+		}
+	}
+	switch {
+	case id == "" || len(found) == 0:
+		return nil, fmt.Errorf("no synced revision '%s'", id)
+	case len(found) > 1:
+		return nil, fmt.Errorf("revision '%s' is ambiguous: matches %d revisions", id, len(found))
+	}
+	return found[0], nil
+}
+
+// RevAt returns the last revision synced at or before the given date, within revisions sorted by date
+func RevAt(revs []*Rev, date time.Time) (*Rev, error) {
+	var found *Rev
+	for _, rev := range revs {
+		if rev.Date.After(date) {
+			break
+		}
+		found = rev
+	}
+	if found == nil {
+		return nil, fmt.Errorf("no revision synced before %s", date.Format("Mon Jan 2 15:04:05 2006"))
+	}
+	return found, nil
+}
+
 type Repo interface {
 	Commit(files ...string) error
 	List() ([]*Rev, error)
@@ -91,7 +150,11 @@ func (r *gitRepo) BaseDir() string {
 func (r *gitRepo) List() ([]*Rev, error) {
 	var all []*Rev
 
-	iter, err := r.repo.CommitObjects()
+	// walk the history from HEAD as commits synced in the same second share their date
+	iter, err := r.repo.Log(&git.LogOptions{})
+	if err == plumbing.ErrReferenceNotFound {
+		return all, nil
+	}
 	if err != nil {
 		return all, err
 	}
@@ -106,10 +169,10 @@ func (r *gitRepo) List() ([]*Rev, error) {
 			panic(fmt.Sprintf("error listing repo revisions: %s", err))
 		}
 
-		all = append(all, &Rev{Id: commit.Hash.String(), Date: commit.Committer.When})
+		all = append([]*Rev{{Id: commit.Hash.String(), Date: commit.Committer.When}}, all...)
 	}
 
-	sort.Slice(all, func(i, j int) bool { return all[i].Date.Before(all[j].Date) })
+	sort.SliceStable(all, func(i, j int) bool { return all[i].Date.Before(all[j].Date) })
 
 	return all, nil
 }
@@ -143,29 +206,40 @@ func (r *gitRepo) LoadRev(version string) (*Rev, error) {
 
 	rev.Infra = graph.NewGraph()
 	rev.Access = graph.NewGraph()
+	rev.Graphs = make(map[string]*graph.Graph)
 
-	if err := unmarshalIntoGraph(rev.Infra, commit, "infra.triples"); err != nil {
-		return rev, err
-	}
-	if err := unmarshalIntoGraph(rev.Access, commit, "access.triples"); err != nil {
+	files, err := commit.Files()
+	if err != nil {
 		return rev, err
 	}
-
-	return rev, nil
-}
-
-func unmarshalIntoGraph(g *graph.Graph, commit *object.Commit, filename string) error {
-	f, err := commit.File(filename)
-	if err != nil && err != object.ErrFileNotFound {
-		return err
-	} else if err == nil {
+	err = files.ForEach(func(f *object.File) error {
+		if !strings.HasSuffix(f.Name, ".triples") {
+			return nil
+		}
 		contents, err := f.Contents()
 		if err != nil {
 			return err
 		}
-		g.Unmarshal([]byte(contents))
-	}
-	return nil
+		g := graph.NewGraph()
+		if contents == "" {
+			rev.Graphs[f.Name] = g
+			return nil
+		}
+		if err := g.Unmarshal([]byte(contents)); err != nil {
+			return fmt.Errorf("loading '%s' at revision %s: %s", f.Name, version, err)
+		}
+		rev.Graphs[f.Name] = g
+
+		switch filepath.Base(f.Name) {
+		case "infra.triples":
+			rev.Infra.AddGraph(g)
+		case "access.triples":
+			rev.Access.AddGraph(g)
+		}
+		return nil
+	})
+
+	return rev, err
 }
 
 func (r *gitRepo) Commit(relativePaths ...string) error {
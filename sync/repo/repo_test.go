@@ -17,11 +17,133 @@ limitations under the License.
 package repo
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"sort"
+	"strings"
 	"testing"
 	"time"
 )
 
+func TestLoadRevWithAllSyncedFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "awless-repo-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	r, err := newGitRepo(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"us-east-1/infra.triples": "<vpc_1> <rdf:type> <cloud-owl:Vpc> .\n<vpc_1> <cloud:id> \"vpc_1\" .\n",
+		"eu-west-1/infra.triples": "<vpc_2> <rdf:type> <cloud-owl:Vpc> .\n<vpc_2> <cloud:id> \"vpc_2\" .\n",
+		"global/access.triples":   "<user_1> <rdf:type> <cloud-owl:User> .\n<user_1> <cloud:id> \"user_1\" .\n",
+		"global/dns.triples":      "",
+	}
+	var paths []string
+	for path, content := range files {
+		os.MkdirAll(filepath.Join(dir, filepath.Dir(path)), 0700)
+		if err := ioutil.WriteFile(filepath.Join(dir, path), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, path)
+	}
+
+	if revs, err := r.List(); err != nil || len(revs) != 0 {
+		t.Fatalf("got %v, %v, want no revisions", revs, err)
+	}
+	if err := r.Commit(paths...); err != nil {
+		t.Fatal(err)
+	}
+	ioutil.WriteFile(filepath.Join(dir, "global/dns.triples"), []byte("<zone_1> <rdf:type> <cloud-owl:Zone> .\n<zone_1> <cloud:id> \"zone_1\" .\n"), 0600)
+	if err := r.Commit("global/dns.triples"); err != nil {
+		t.Fatal(err)
+	}
+	ioutil.WriteFile(filepath.Join(dir, "global/dns.triples"), []byte(""), 0600)
+	if err := r.Commit("global/dns.triples"); err != nil {
+		t.Fatal(err)
+	}
+
+	revs, err := r.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(revs), 3; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+	for i, expZone := range []bool{false, true, false} {
+		rev, err := r.LoadRev(revs[i].Id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res, _ := rev.Graph().FindResource("zone_1"); (res != nil) != expZone {
+			t.Fatalf("revision %d: got %v, want zone present %t", i, res, expZone)
+		}
+	}
+	rev, err := r.LoadRev(revs[0].Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(rev.Graphs), 4; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+	if res, _ := rev.Infra.FindResource("vpc_2"); res == nil {
+		t.Fatal("expected vpc_2 in infra graph")
+	}
+	if res, _ := rev.Access.FindResource("user_1"); res == nil {
+		t.Fatal("expected user_1 in access graph")
+	}
+
+	g := rev.Graph("us-east-1")
+	for id, exp := range map[string]bool{"vpc_1": true, "vpc_2": false, "user_1": true} {
+		if res, _ := g.FindResource(id); (res != nil) != exp {
+			t.Fatalf("%s: got %v, want present %t", id, res, exp)
+		}
+	}
+	if got, want := Region("eu-west-1/infra.triples"), "eu-west-1"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
+
+func TestFindRevs(t *testing.T) {
+	revs := []*Rev{
+		{Id: "4b2c8d", Date: mustParse("2017-01-17 10:05")},
+		{Id: "4b7e21", Date: mustParse("2017-01-18 15:05")},
+		{Id: "a09f3c", Date: mustParse("2017-01-19 09:05")},
+	}
+
+	for id, exp := range map[string]string{"a0": "a09f3c", "4b2": "4b2c8d", "4b7e21": "4b7e21"} {
+		rev, err := FindRev(revs, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := rev.Id, exp; got != want {
+			t.Fatalf("got %s, want %s", got, want)
+		}
+	}
+	for id, expErr := range map[string]string{"4b": "ambiguous", "ff": "no synced revision", "": "no synced revision"} {
+		if _, err := FindRev(revs, id); err == nil || !strings.Contains(err.Error(), expErr) {
+			t.Fatalf("%s: got %v, want error containing %s", id, err, expErr)
+		}
+	}
+
+	for date, exp := range map[string]string{"2017-01-18 15:05": "4b7e21", "2017-01-19 09:00": "4b7e21", "2018-01-01 00:00": "a09f3c"} {
+		rev, err := RevAt(revs, mustParse(date))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := rev.Id, exp; got != want {
+			t.Fatalf("%s: got %s, want %s", date, got, want)
+		}
+	}
+	if _, err := RevAt(revs, mustParse("2017-01-17 10:00")); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
 func TestReduceToLastRevOfEachDay(t *testing.T) {
 	revs := []*Rev{
 		{Id: "1", Date: mustParse("2017-01-18 15:05")},
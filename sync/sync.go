@@ -40,6 +40,9 @@ const fileExt = ".triples"
 
 var DefaultSyncer Syncer
 
+// AtRevision, when set, makes the local graphs load from this revision of the repository instead of the latest synced files
+var AtRevision *repo.Rev
+
 type Syncer interface {
 	repo.Repo
 	Sync(...cloud.Service) (map[string]*graph.Graph, error)
@@ -183,6 +186,12 @@ func LoadLocalGraphForService(serviceName, region string) *graph.Graph {
 	if awsservices.IsGlobalService(serviceName) {
 		regionDir = "global"
 	}
+	if AtRevision != nil {
+		if g, ok := AtRevision.Graphs[fmt.Sprintf("%s/%s%s", regionDir, serviceName, fileExt)]; ok {
+			return g
+		}
+		return graph.NewGraph()
+	}
 	path := filepath.Join(repo.BaseDir(), regionDir, fmt.Sprintf("%s%s", serviceName, fileExt))
 	g, err := graph.NewGraphFromFile(path)
 	if err != nil {
@@ -192,6 +201,9 @@ func LoadLocalGraphForService(serviceName, region string) *graph.Graph {
 }
 
 func LoadLocalGraphs(region string) (*graph.Graph, error) {
+	if AtRevision != nil {
+		return AtRevision.Graph(region), nil
+	}
 	var files []string
 	globalFiles, _ := filepath.Glob(filepath.Join(repo.BaseDir(), "global", fmt.Sprintf("*%s", fileExt)))
 	regionFiles, _ := filepath.Glob(filepath.Join(repo.BaseDir(), region, fmt.Sprintf("*%s", fileExt)))
@@ -215,6 +227,9 @@ func LoadLocalGraphs(region string) (*graph.Graph, error) {
 }
 
 func LoadAllLocalGraphs() (*graph.Graph, error) {
+	if AtRevision != nil {
+		return AtRevision.Graph(), nil
+	}
 	path := filepath.Join(repo.BaseDir(), "*", fmt.Sprintf("*%s", fileExt))
 	files, _ := filepath.Glob(path)
 
@@ -13,8 +13,49 @@ import (
 	"path/filepath"
 
 	"github.com/wallix/awless/graph"
+	"github.com/wallix/awless/graph/resourcetest"
+	"github.com/wallix/awless/sync/repo"
 )
 
+func TestLoadLocalGraphsAtRevision(t *testing.T) {
+	newGraph := func(res *graph.Resource) *graph.Graph {
+		g := graph.NewGraph()
+		g.AddResource(res)
+		return g
+	}
+	AtRevision = &repo.Rev{Id: "1", Graphs: map[string]*graph.Graph{
+		"us-east-1/infra.triples": newGraph(resourcetest.VPC("vpc_1").Build()),
+		"eu-west-1/infra.triples": newGraph(resourcetest.VPC("vpc_2").Build()),
+		"global/access.triples":   newGraph(resourcetest.User("user_1").Build()),
+	}}
+	defer func() { AtRevision = nil }()
+
+	g, err := LoadLocalGraphs("eu-west-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for id, exp := range map[string]bool{"vpc_1": false, "vpc_2": true, "user_1": true} {
+		if res, _ := g.FindResource(id); (res != nil) != exp {
+			t.Fatalf("%s: got %v, want present %t", id, res, exp)
+		}
+	}
+
+	if res, _ := LoadLocalGraphForService("access", "us-east-1").FindResource("user_1"); res == nil {
+		t.Fatal("expected user_1 in access graph")
+	}
+	if res, _ := LoadLocalGraphForService("infra", "ap-south-1").FindResource("vpc_1"); res != nil {
+		t.Fatalf("got %v, want no resource", res)
+	}
+
+	g, err = LoadAllLocalGraphs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resources, _ := g.GetAllResources("vpc"); len(resources) != 2 {
+		t.Fatalf("got %d vpcs, want 2", len(resources))
+	}
+}
+
 func TestSyncTripleFiles(t *testing.T) {
 	tmpDir, err := ioutil.TempDir("", "awlessunittest_")
 	if err != nil {
@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
+	"github.com/wallix/awless/graph"
 	"github.com/wallix/awless/logger"
 	"github.com/wallix/awless/template/driver"
 	"github.com/wallix/awless/template/internal/ast"
@@ -12,6 +14,9 @@ import (
 
 type Env struct {
 	Driver driver.Driver
+	// Concurrency is the maximum number of independent commands run at the same time.
+	// Commands are run sequentially when lower than 2.
+	Concurrency int
 
 	ResolvedVariables map[string]interface{}
 
@@ -19,9 +24,22 @@ type Env struct {
 	DefLookupFunc    DefinitionLookupFunc
 	AliasFunc        func(entity, key, alias string) string
 	MissingHolesFunc func(string) interface{}
-	Log              *logger.Logger
+	IncludeFunc      func(path, from string) (content string, fullPath string, err error)
+	// ReadFileFunc reads the files given to the file() function, relative to the template
+	ReadFileFunc func(path string) ([]byte, error)
+	// FetchGraphFunc fetches the current resources of the given entity, i.e. for
+	// the wait command or to record the state of resources before their update
+	FetchGraphFunc func(entity string) (*graph.Graph, error)
+	// Secrets are the names of the fillers holding secrets: the holes they fill
+	// are masked when the template is printed, as are their processed fillers
+	Secrets []string
+	Log     *logger.Logger
 
 	processedFillers map[string]interface{}
+	includedSources  map[string]string
+	dryRun           bool
+	sleepFunc        func(time.Duration)
+	nowFunc          func() time.Time
 }
 
 func NewEnv() *Env {
@@ -34,6 +52,21 @@ func NewEnv() *Env {
 	}
 }
 
+func (e *Env) sleep(d time.Duration) {
+	if e.sleepFunc != nil {
+		e.sleepFunc(d)
+		return
+	}
+	time.Sleep(d)
+}
+
+func (e *Env) now() time.Time {
+	if e.nowFunc != nil {
+		return e.nowFunc()
+	}
+	return time.Now()
+}
+
 func (e *Env) AddFillers(fills ...map[string]interface{}) {
 	if e.Fillers == nil {
 		e.Fillers = make(map[string]interface{})
@@ -66,22 +99,43 @@ func (e *Env) GetProcessedFillers() (copy map[string]interface{}) {
 	return
 }
 
+func (e *Env) addIncludedSource(path, source string) {
+	if e.includedSources == nil {
+		e.includedSources = make(map[string]string)
+	}
+	e.includedSources[path] = source
+}
+
+func (e *Env) GetIncludedSources() (copy map[string]string) {
+	copy = make(map[string]string)
+	for k, v := range e.includedSources {
+		copy[k] = v
+	}
+	return
+}
+
 type Mode []compileFunc
 
 var (
 	LenientCompileMode = []compileFunc{
+		resolveIncludesPass,
 		resolveAgainstDefinitions,
 		checkInvalidReferenceDeclarations,
 		resolveHolesPass,
 		resolveMissingHolesPass,
 		resolveAliasPass,
+		resolveFunctionsPass,
 		inlineVariableValuePass,
+		expandLoopsPass,
+		resolveConditionalsPass,
 	}
 
 	NormalCompileMode = append(
 		LenientCompileMode,
 		failOnUnresolvedHoles,
 		failOnUnresolvedAlias,
+		failOnUnevaluatedFunctions,
+		checkParamTypesPass,
 	)
 )
 
@@ -127,13 +181,13 @@ func resolveAgainstDefinitions(tpl *Template, env *Env) (*Template, *Env, error)
 
 	verifyValidParamsOnly := func(cmd *ast.CommandNode) error {
 		tplKey := fmt.Sprintf("%s%s", cmd.Action, cmd.Entity)
-		def, ok := env.DefLookupFunc(tplKey)
+		def, ok := lookupDefinition(env.DefLookupFunc, cmd)
 		if !ok {
-			return fmt.Errorf("cannot find template definition for '%s'", tplKey)
+			return errorAt(cmd.Pos, fmt.Errorf("cannot find template definition for '%s'", tplKey))
 		}
 
 		for _, key := range cmd.Keys() {
-			if !foundIn(key, def.Required()) && !foundIn(key, def.Extra()) {
+			if !def.accepts(key) {
 				var extraParams, requiredParams string
 				if len(def.Extra()) > 0 {
 					extraParams = fmt.Sprintf("\n\t- extra params: %s", strings.Join(def.Extra(), ", "))
@@ -141,7 +195,7 @@ func resolveAgainstDefinitions(tpl *Template, env *Env) (*Template, *Env, error)
 				if len(def.Required()) > 0 {
 					requiredParams = fmt.Sprintf("\n\t- required params: %s", strings.Join(def.Required(), ", "))
 				}
-				return fmt.Errorf("%s %s: unexpected param key '%s'%s%s\n", cmd.Action, cmd.Entity, key, requiredParams, extraParams)
+				return errorAt(cmd.Pos, fmt.Errorf("%s %s: unexpected param key '%s'%s%s\n", cmd.Action, cmd.Entity, key, requiredParams, extraParams))
 			}
 		}
 		return nil
@@ -152,8 +206,7 @@ func resolveAgainstDefinitions(tpl *Template, env *Env) (*Template, *Env, error)
 	}
 
 	tpl.visitCommandNodes(func(cmd *ast.CommandNode) {
-		tplKey := fmt.Sprintf("%s%s", cmd.Action, cmd.Entity)
-		def, _ := env.DefLookupFunc(tplKey)
+		def, _ := lookupDefinition(env.DefLookupFunc, cmd)
 		for _, required := range def.Required() {
 			var isInParams bool
 
@@ -175,51 +228,143 @@ func resolveAgainstDefinitions(tpl *Template, env *Env) (*Template, *Env, error)
 	return tpl, env, nil
 }
 
-func checkInvalidReferenceDeclarations(tpl *Template, env *Env) (*Template, *Env, error) {
-	usedRefs := make(map[string]struct{})
+// checkParamTypesPass checks the resolved params values against the types of the definitions.
+// Values only known at run time (i.e. references to commands results) are skipped.
+func checkParamTypesPass(tpl *Template, env *Env) (*Template, *Env, error) {
+	if env.DefLookupFunc == nil {
+		return tpl, env, fmt.Errorf("definition lookup function is undefined")
+	}
+
+	err := tpl.visitCommandNodesE(func(cmd *ast.CommandNode) error {
+		def, ok := lookupDefinition(env.DefLookupFunc, cmd)
+		if !ok {
+			return nil
+		}
+		for _, key := range cmd.Keys() {
+			paramType, typed := def.ParamTypes[key]
+			if !typed || !isResolvedValue(cmd.Params[key]) {
+				continue
+			}
+			checked, err := paramType.Check(cmd.Params[key].Value())
+			if err != nil {
+				return errorAt(cmd.Pos, fmt.Errorf("%s %s: invalid value '%s' for param '%s': %s", cmd.Action, cmd.Entity, cmd.Params[key], key, err))
+			}
+			if paramType.Kind == DurationParam {
+				cmd.Params[key] = ast.NewInterfaceValue(checked)
+			}
+		}
+		return nil
+	})
 
-	for _, withRef := range tpl.WithRefsIterator() {
-		for _, ref := range withRef.GetRefs() {
-			usedRefs[ref] = struct{}{}
+	return tpl, env, err
+}
+
+func isResolvedValue(val ast.CompositeValue) bool {
+	if withRefs, ok := val.(ast.WithRefs); ok && len(withRefs.GetRefs()) > 0 {
+		return false
+	}
+	if withHoles, ok := val.(ast.WithHoles); ok && len(withHoles.GetHoles()) > 0 {
+		return false
+	}
+	if withAlias, ok := val.(ast.WithAlias); ok && len(withAlias.GetAliases()) > 0 {
+		return false
+	}
+	return val.Value() != nil
+}
+
+func checkInvalidReferenceDeclarations(tpl *Template, env *Env) (*Template, *Env, error) {
+	if issues := checkReferencesInStatements(tpl.Statements, make(map[string]bool)); len(issues) > 0 {
+		first := issues[0]
+		if first.redeclared {
+			return tpl, env, errorAt(first.stmt.Pos, fmt.Errorf("using reference '$%s' but '%s' has already been assigned in template\n", first.ref, first.ref))
 		}
+		return tpl, env, errorAt(first.stmt.Pos, fmt.Errorf("using reference '$%s' but '%s' is undefined in template\n", first.ref, first.ref))
 	}
+	return tpl, env, nil
+}
 
-	knownRefs := make(map[string]bool)
+// referenceIssue is a reference used while undefined, or declared more than once
+type referenceIssue struct {
+	ref        string
+	stmt       *ast.Statement
+	redeclared bool
+}
 
-	var each = func(withRef ast.WithRefs) error {
-		for _, ref := range withRef.GetRefs() {
-			if _, ok := knownRefs[ref]; !ok {
-				return fmt.Errorf("using reference '$%s' but '%s' is undefined in template\n", ref, ref)
+// checkReferencesInStatements verifies references are declared before use and only once,
+// returning all the issues in the order of the statements.
+// Each branch of a conditional block starts from the references known before the block,
+// and only references declared in all branches are known after it. References declared
+// in a loop body are only known within the loop body. Not yet resolved includes make
+// known all the references prefixed with their namespace.
+func checkReferencesInStatements(stmts []*ast.Statement, known map[string]bool) (issues []referenceIssue) {
+	isKnown := func(ref string) bool {
+		if known[ref] {
+			return true
+		}
+		for k := range known {
+			if strings.HasSuffix(k, ".") && strings.HasPrefix(ref, k) {
+				return true
 			}
 		}
-		return nil
+		return false
 	}
 
-	for _, st := range tpl.Statements {
-		switch n := st.Node.(type) {
-		case ast.WithRefs:
-			if err := each(n); err != nil {
-				return tpl, env, err
+	for _, st := range stmts {
+		for _, ref := range includingStatementRefs(st) {
+			if !isKnown(ref) {
+				issues = append(issues, referenceIssue{ref: ref, stmt: st})
 			}
+		}
+		switch n := st.Node.(type) {
 		case *ast.DeclarationNode:
-			expr := st.Node.(*ast.DeclarationNode).Expr
-			switch nn := expr.(type) {
-			case ast.WithRefs:
-				if err := each(nn); err != nil {
-					return tpl, env, err
+			if known[n.Ident] {
+				issues = append(issues, referenceIssue{ref: n.Ident, stmt: st, redeclared: true})
+			}
+			known[n.Ident] = true
+		case *ast.IncludeNode:
+			ns := n.Namespace
+			if ns == "" {
+				ns = defaultIncludeNamespace(n.Path)
+			}
+			known[ns+"."] = true
+		case *ast.IfNode:
+			thenKnown, elseKnown := copyKnownRefs(known), copyKnownRefs(known)
+			issues = append(issues, checkReferencesInStatements(n.Then, thenKnown)...)
+			issues = append(issues, checkReferencesInStatements(n.Else, elseKnown)...)
+			for ref := range thenKnown {
+				if elseKnown[ref] {
+					known[ref] = true
 				}
 			}
+		case *ast.ForNode:
+			bodyKnown := copyKnownRefs(known)
+			bodyKnown[n.Var] = true
+			issues = append(issues, checkReferencesInStatements(n.Body, bodyKnown)...)
 		}
-		if decl, isDecl := st.Node.(*ast.DeclarationNode); isDecl {
-			ref := decl.Ident
-			if _, ok := knownRefs[ref]; ok {
-				return tpl, env, fmt.Errorf("using reference '$%s' but '%s' has already been assigned in template\n", ref, ref)
+	}
+	return
+}
+
+// includingStatementRefs returns the references of the statement, including those
+// in the params of a not yet resolved include
+func includingStatementRefs(st *ast.Statement) (refs []string) {
+	if include, ok := st.Node.(*ast.IncludeNode); ok {
+		for _, val := range include.Params {
+			if withRefs, ok := val.(ast.WithRefs); ok {
+				refs = append(refs, withRefs.GetRefs()...)
 			}
-			knownRefs[ref] = true
 		}
+		return
 	}
+	return statementRefs(st)
+}
 
-	return tpl, env, nil
+func copyKnownRefs(refs map[string]bool) map[string]bool {
+	copy := make(map[string]bool)
+	for k, v := range refs {
+		copy[k] = v
+	}
+	return copy
 }
 
 func inlineVariableValuePass(tpl *Template, env *Env) (*Template, *Env, error) {
@@ -235,6 +380,9 @@ func inlineVariableValuePass(tpl *Template, env *Env) (*Template, *Env, error) {
 					env.ResolvedVariables[decl.Ident] = val
 				}
 				for j := i + 1; j < len(tpl.Statements); j++ {
+					if block, ok := tpl.Statements[j].Node.(ast.BlockNode); ok {
+						block.ReplaceRef(decl.Ident, value.Value)
+					}
 					expr := extractExpressionNode(tpl.Statements[j])
 					if expr != nil {
 						if withRef, ok := expr.(ast.WithRefs); ok {
@@ -252,7 +400,150 @@ func inlineVariableValuePass(tpl *Template, env *Env) (*Template, *Env, error) {
 	return newTpl, env, nil
 }
 
+// expandLoopsPass repeats the body of loops for each element of their list.
+// Declarations in the body are indexed per iteration (i.e. 'inst' becomes 'inst_0', 'inst_1', ...)
+// so that each iteration stands as its own commands.
+func expandLoopsPass(tpl *Template, env *Env) (*Template, *Env, error) {
+	stmts, expanded, err := expandLoops(tpl.Statements)
+	if err != nil {
+		return tpl, env, err
+	}
+	if !expanded {
+		return tpl, env, nil
+	}
+	tpl.Statements = stmts
+	if tpl, env, err = checkInvalidReferenceDeclarations(tpl, env); err != nil {
+		return tpl, env, err
+	}
+	if tpl, env, err = resolveFunctionsPass(tpl, env); err != nil {
+		return tpl, env, err
+	}
+	return inlineVariableValuePass(tpl, env)
+}
+
+func expandLoops(stmts []*ast.Statement) (out []*ast.Statement, expanded bool, err error) {
+	for _, st := range stmts {
+		forNode, isFor := st.Node.(*ast.ForNode)
+		if !isFor {
+			if ifNode, isIf := st.Node.(*ast.IfNode); isIf {
+				var thenExpanded, elseExpanded bool
+				if ifNode.Then, thenExpanded, err = expandLoops(ifNode.Then); err != nil {
+					return
+				}
+				if ifNode.Else, elseExpanded, err = expandLoops(ifNode.Else); err != nil {
+					return
+				}
+				expanded = expanded || thenExpanded || elseExpanded
+			}
+			out = append(out, st)
+			continue
+		}
+
+		elems, ok := forNode.Elements()
+		if !ok {
+			return out, expanded, errorAt(st.Pos, fmt.Errorf("loop over $%s: cannot expand unresolved list %s", forNode.Var, forNode.List))
+		}
+
+		for i, elem := range elems {
+			var iteration []*ast.Statement
+			for _, bodySt := range forNode.Body {
+				iteration = append(iteration, bodySt.Clone())
+			}
+			ast.ReplaceRefInStatements(iteration, forNode.Var, elem)
+			renameDeclarations(iteration, func(ident string) string {
+				return fmt.Sprintf("%s_%d", ident, i)
+			})
+			var nested []*ast.Statement
+			if nested, _, err = expandLoops(iteration); err != nil {
+				return
+			}
+			out = append(out, nested...)
+		}
+		expanded = true
+	}
+	return
+}
+
+// renameDeclarations renames the declarations in the statements along with their references
+func renameDeclarations(stmts []*ast.Statement, rename func(string) string) {
+	var declared []string
+	walkStatements(stmts, func(st *ast.Statement) {
+		if decl, ok := st.Node.(*ast.DeclarationNode); ok {
+			declared = append(declared, decl.Ident)
+		}
+	})
+
+	for _, ident := range declared {
+		renamed := rename(ident)
+		ast.ReplaceRefInStatements(stmts, ident, ast.NewRefValue(renamed))
+		walkStatements(stmts, func(st *ast.Statement) {
+			if decl, ok := st.Node.(*ast.DeclarationNode); ok && decl.Ident == ident {
+				decl.Ident = renamed
+			}
+		})
+	}
+}
+
+// resolveConditionalsPass replaces the conditional blocks that can already be evaluated
+// by the statements of their selected branch. Blocks depending on command results
+// are kept to be evaluated at run time.
+func resolveConditionalsPass(tpl *Template, env *Env) (*Template, *Env, error) {
+	for {
+		stmts, flattened, err := flattenResolvedConditionals(tpl.Statements)
+		if err != nil {
+			return tpl, env, err
+		}
+		if !flattened {
+			return tpl, env, nil
+		}
+		tpl.Statements = stmts
+		if tpl, env, err = inlineVariableValuePass(tpl, env); err != nil {
+			return tpl, env, err
+		}
+	}
+}
+
+func flattenResolvedConditionals(stmts []*ast.Statement) (out []*ast.Statement, flattened bool, err error) {
+	for _, st := range stmts {
+		ifNode, isIf := st.Node.(*ast.IfNode)
+		if !isIf {
+			out = append(out, st)
+			continue
+		}
+		if !ifNode.Condition.IsResolved() {
+			if ifNode.Then, _, err = flattenResolvedConditionals(ifNode.Then); err != nil {
+				return
+			}
+			if ifNode.Else, _, err = flattenResolvedConditionals(ifNode.Else); err != nil {
+				return
+			}
+			out = append(out, st)
+			continue
+		}
+		var branch []*ast.Statement
+		if branch, err = ifNode.Branch(); err != nil {
+			return out, flattened, errorAt(st.Pos, err)
+		}
+		out = append(out, branch...)
+		flattened = true
+	}
+	return
+}
+
 func resolveHolesPass(tpl *Template, env *Env) (*Template, *Env, error) {
+	holes := make(map[string]bool)
+	tpl.visitHoles(func(h ast.WithHoles) {
+		for _, hole := range h.GetHoles() {
+			holes[hole] = true
+		}
+	})
+	for _, secret := range env.Secrets {
+		if v, ok := env.Fillers[secret]; ok && holes[secret] {
+			ast.ReplaceHoleInStatements(tpl.Statements, secret, ast.NewSecretValue(v))
+			env.addToProcessedFillers(map[string]interface{}{secret: MaskedSecret})
+		}
+	}
+
 	tpl.visitHoles(func(h ast.WithHoles) {
 		processed := h.ProcessHoles(env.Fillers)
 		env.addToProcessedFillers(processed)
@@ -322,6 +613,10 @@ func resolveAliasPass(tpl *Template, env *Env) (*Template, *Env, error) {
 		}
 	}
 
+	for _, block := range tpl.blockNodesIterator() {
+		block.ResolveAlias(resolvAliasFunc("", ""))
+	}
+
 	if len(emptyResolv) > 0 {
 		return tpl, env, fmt.Errorf("cannot resolve aliases: %q. Maybe you need to update your local model with `awless sync` ?", emptyResolv)
 	}
@@ -368,6 +663,10 @@ func failOnUnresolvedAlias(tpl *Template, env *Env) (*Template, *Env, error) {
 		}
 	}
 
+	for _, block := range tpl.blockNodesIterator() {
+		visitAliases(block)
+	}
+
 	if len(unresolved) > 0 {
 		return tpl, env, fmt.Errorf("template contains unresolved alias: %v", unresolved)
 	}
@@ -375,6 +674,14 @@ func failOnUnresolvedAlias(tpl *Template, env *Env) (*Template, *Env, error) {
 	return tpl, env, nil
 }
 
+// errorAt prefixes the error with the position in the template it originates from, when known
+func errorAt(pos ast.Position, err error) error {
+	if !pos.IsValid() {
+		return err
+	}
+	return fmt.Errorf("%s: %s", pos, err)
+}
+
 func foundIn(key string, slice []string) (found bool) {
 	for _, k := range slice {
 		if k == key {
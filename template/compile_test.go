@@ -1,7 +1,9 @@
 package template
 
 import (
+	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 	"testing"
 
@@ -266,6 +268,372 @@ func TestInlineVariableWithValue(t *testing.T) {
 	}
 }
 
+func TestResolveConditionalsPass(t *testing.T) {
+	tcases := []struct {
+		tpl    string
+		fills  map[string]interface{}
+		expect string
+	}{
+		{
+			tpl: `if {env} == prod {
+  create keypair name=prodkey
+} else {
+  create keypair name=devkey
+}`,
+			fills:  map[string]interface{}{"env": "prod"},
+			expect: "create keypair name=prodkey",
+		},
+		{
+			tpl: `size = {size}
+if $size > 2 && {env} != prod {
+  create keypair name=big
+} else if $size > 1 {
+  create keypair name=medium
+}
+create keypair name=always`,
+			fills:  map[string]interface{}{"env": "prod", "size": 3},
+			expect: "create keypair name=medium\ncreate keypair name=always",
+		},
+		{
+			tpl: `if {env} == dev {
+  name = devkey
+  create keypair name=$name
+}`,
+			fills:  map[string]interface{}{"env": "dev"},
+			expect: "create keypair name=devkey",
+		},
+		{
+			tpl: `sub = create subnet cidr=10.0.0.0/24 vpc=vpc-1234
+if $sub != '' && {env} == dev {
+  create keypair name=key-{env}
+}`,
+			fills: map[string]interface{}{"env": "dev"},
+			expect: `sub = create subnet cidr=10.0.0.0/24 vpc=vpc-1234
+if $sub != '' && dev == dev {
+	create keypair name=key-dev
+}`,
+		},
+	}
+
+	for i, tcase := range tcases {
+		env := NewEnv()
+		env.AddFillers(tcase.fills)
+		env.DefLookupFunc = func(in string) (Definition, bool) {
+			t, ok := DefsExample[in]
+			return t, ok
+		}
+
+		compiled, _, err := Compile(MustParse(tcase.tpl), env)
+		if err != nil {
+			t.Fatalf("%d: %s", i+1, err)
+		}
+		if got, want := compiled.String(), tcase.expect; got != want {
+			t.Fatalf("%d: got\n%s\nwant\n%s", i+1, got, want)
+		}
+	}
+
+	t.Run("Same reference declared in alternative branches", func(t *testing.T) {
+		tpl := MustParse(`if {env} == prod {
+  key = create keypair name=prodkey
+} else {
+  key = create keypair name=devkey
+}
+create instance image=ami-1234 count=1 type=t2.micro subnet=sub-1234 keypair=$key`)
+		if _, _, err := checkInvalidReferenceDeclarations(tpl, NewEnv()); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("Reference declared in a single branch", func(t *testing.T) {
+		tpl := MustParse(`if {env} == prod {
+  key = create keypair name=prodkey
+}
+create instance keypair=$key`)
+		if _, _, err := checkInvalidReferenceDeclarations(tpl, NewEnv()); err == nil {
+			t.Fatal("expected error got none")
+		}
+	})
+}
+
+func TestExpandLoopsPass(t *testing.T) {
+	tcases := []struct {
+		tpl    string
+		fills  map[string]interface{}
+		expect string
+	}{
+		{
+			tpl: `for $name in [key1, key2] {
+  create keypair name=$name
+}`,
+			expect: "create keypair name=key1\ncreate keypair name=key2",
+		},
+		{
+			tpl: `sub1 = create subnet cidr=10.0.0.0/24 vpc=vpc-1234
+subnets = [$sub1, {backup.subnet}]
+for $s in $subnets {
+  inst = create instance count=1 image=ami-1234 type=t2.micro subnet=$s
+  create tag key=Name resource=$inst value=web
+}`,
+			fills: map[string]interface{}{"backup.subnet": "sub-2345"},
+			expect: `sub1 = create subnet cidr=10.0.0.0/24 vpc=vpc-1234
+inst_0 = create instance count=1 image=ami-1234 subnet=$sub1 type=t2.micro
+create tag key=Name resource=$inst_0 value=web
+inst_1 = create instance count=1 image=ami-1234 subnet=sub-2345 type=t2.micro
+create tag key=Name resource=$inst_1 value=web`,
+		},
+		{
+			tpl: `for $env in {envs} {
+  for $n in [a, b] {
+    if $env == prod {
+      key = create keypair name=$n
+    }
+  }
+}`,
+			fills: map[string]interface{}{"envs": []interface{}{"prod", "dev"}},
+			expect: `key_0_0 = create keypair name=a
+key_0_1 = create keypair name=b`,
+		},
+	}
+
+	for i, tcase := range tcases {
+		env := NewEnv()
+		env.AddFillers(tcase.fills)
+		env.DefLookupFunc = func(in string) (Definition, bool) {
+			t, ok := DefsExample[in]
+			return t, ok
+		}
+
+		compiled, _, err := Compile(MustParse(tcase.tpl), env)
+		if err != nil {
+			t.Fatalf("%d: %s", i+1, err)
+		}
+		if got, want := compiled.String(), tcase.expect; got != want {
+			t.Fatalf("%d: got\n%s\nwant\n%s", i+1, got, want)
+		}
+	}
+
+	t.Run("Iterations are counted as distinct commands", func(t *testing.T) {
+		env := NewEnv()
+		env.DefLookupFunc = func(in string) (Definition, bool) {
+			t, ok := DefsExample[in]
+			return t, ok
+		}
+		compiled, _, err := Compile(MustParse("for $n in [a,b,c] {\ncreate keypair name=$n\n}"), env)
+		if err != nil {
+			t.Fatal(err)
+		}
+		stats := (&TemplateExecution{Template: compiled}).Stats()
+		if got, want := stats.CmdCount, 3; got != want {
+			t.Fatalf("got %d, want %d", got, want)
+		}
+		if got, want := stats.ActionEntityCount["create keypair"], 3; got != want {
+			t.Fatalf("got %d, want %d", got, want)
+		}
+	})
+
+	t.Run("Cannot expand unresolved list", func(t *testing.T) {
+		env := NewEnv()
+		env.DefLookupFunc = func(in string) (Definition, bool) {
+			t, ok := DefsExample[in]
+			return t, ok
+		}
+		_, _, err := Compile(MustParse("subs = create subnet cidr=10.0.0.0/24 vpc=vpc-1234\nfor $s in $subs {\ncreate keypair name=$s\n}"), env)
+		if err == nil || !strings.Contains(err.Error(), "cannot expand unresolved list") {
+			t.Fatalf("expected error, got %v", err)
+		}
+	})
+}
+
+func TestResolveIncludesPass(t *testing.T) {
+	templates := map[string]string{
+		"/tpl/network.aws": `vpc = create vpc cidr={cidr}
+create subnet cidr=10.0.0.0/24 vpc=$vpc`,
+		"/tpl/instance.aws": `net = include network.aws with cidr=10.0.0.0/16
+create instance count=1 image=ami-1234 name={name} subnet=$net.vpc type=t2.micro`,
+		"/tpl/vpc.aws": `vpc = create vpc cidr={cidr}
+output vpc_id = $vpc`,
+		"/tpl/cycle.aws": "include loop.aws",
+		"/tpl/loop.aws":  "include cycle.aws",
+		"/tpl/self.aws":  "include self.aws",
+	}
+	newEnv := func() *Env {
+		env := NewEnv()
+		env.DefLookupFunc = func(in string) (Definition, bool) {
+			t, ok := DefsExample[in]
+			return t, ok
+		}
+		env.IncludeFunc = func(path, from string) (string, string, error) {
+			full := "/tpl/" + path
+			content, ok := templates[full]
+			if !ok {
+				return "", "", fmt.Errorf("not found")
+			}
+			return content, full, nil
+		}
+		return env
+	}
+
+	tcases := []struct {
+		tpl      string
+		expect   string
+		included []string
+	}{
+		{
+			tpl: `include network.aws with cidr=10.0.0.0/16
+create keypair name=$network.vpc`,
+			expect: `network.vpc = create vpc cidr=10.0.0.0/16
+create subnet cidr=10.0.0.0/24 vpc=$network.vpc
+create keypair name=$network.vpc`,
+			included: []string{"/tpl/network.aws"},
+		},
+		{
+			tpl: `myvpc = create vpc cidr=10.0.0.0/16
+web = include instance.aws with name=$myvpc`,
+			expect: `myvpc = create vpc cidr=10.0.0.0/16
+web.net.vpc = create vpc cidr=10.0.0.0/16
+create subnet cidr=10.0.0.0/24 vpc=$web.net.vpc
+create instance count=1 image=ami-1234 name=$myvpc subnet=$web.net.vpc type=t2.micro`,
+			included: []string{"/tpl/instance.aws", "/tpl/network.aws"},
+		},
+		{
+			tpl: `net = include vpc.aws with cidr=10.0.0.0/16
+create subnet cidr=10.0.0.0/24 vpc=$net.vpc_id`,
+			expect: `net.vpc = create vpc cidr=10.0.0.0/16
+create subnet cidr=10.0.0.0/24 vpc=$net.vpc`,
+			included: []string{"/tpl/vpc.aws"},
+		},
+		{
+			tpl: `for $c in [10.0.0.0/16,10.1.0.0/16] {
+net = include vpc.aws with cidr=$c
+create subnet cidr=$c vpc=$net.vpc_id
+}`,
+			expect: `net.vpc_0 = create vpc cidr=10.0.0.0/16
+create subnet cidr=10.0.0.0/16 vpc=$net.vpc_0
+net.vpc_1 = create vpc cidr=10.1.0.0/16
+create subnet cidr=10.1.0.0/16 vpc=$net.vpc_1`,
+			included: []string{"/tpl/vpc.aws"},
+		},
+	}
+
+	for i, tcase := range tcases {
+		env := newEnv()
+		compiled, _, err := Compile(MustParse(tcase.tpl), env)
+		if err != nil {
+			t.Fatalf("%d: %s", i+1, err)
+		}
+		if got, want := compiled.String(), tcase.expect; got != want {
+			t.Fatalf("%d: got\n%s\nwant\n%s", i+1, got, want)
+		}
+		var included []string
+		for path, source := range env.GetIncludedSources() {
+			if source != templates[path] {
+				t.Fatalf("%d: %s: got source %q, want %q", i+1, path, source, templates[path])
+			}
+			included = append(included, path)
+		}
+		sort.Strings(included)
+		if got, want := included, tcase.included; !reflect.DeepEqual(got, want) {
+			t.Fatalf("%d: got %v, want %v", i+1, got, want)
+		}
+	}
+
+	errcases := []struct {
+		tpl    string
+		expect string
+	}{
+		{tpl: "include cycle.aws", expect: "include cycle: /tpl/cycle.aws -> /tpl/loop.aws -> /tpl/cycle.aws"},
+		{tpl: "include self.aws", expect: "include cycle: /tpl/self.aws -> /tpl/self.aws"},
+		{tpl: "include unknown.aws", expect: "include 'unknown.aws': not found"},
+	}
+	for i, tcase := range errcases {
+		_, _, err := Compile(MustParse(tcase.tpl), newEnv())
+		if err == nil || err.Error() != tcase.expect {
+			t.Fatalf("%d: got %v, want %s", i+1, err, tcase.expect)
+		}
+	}
+
+	t.Run("Include without include function", func(t *testing.T) {
+		_, _, err := Compile(MustParse("include network.aws"), NewEnv())
+		if err == nil || !strings.Contains(err.Error(), "include function is undefined") {
+			t.Fatalf("expected error, got %v", err)
+		}
+	})
+}
+
+func TestCompileOutputs(t *testing.T) {
+	env := NewEnv()
+	env.AddFillers(map[string]interface{}{"vpc.name": "myvpc"})
+	env.DefLookupFunc = func(in string) (Definition, bool) {
+		t, ok := DefsExample[in]
+		return t, ok
+	}
+	compiled, _, err := Compile(MustParse("cidr = 10.0.0.0/16\nvpc = create vpc cidr=$cidr name={vpc.name}\noutput vpc_id = $vpc\noutput vpc_cidr = $cidr\noutput name = {vpc.name}"), env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := "vpc = create vpc cidr=10.0.0.0/16 name=myvpc\noutput vpc_id = $vpc\noutput vpc_cidr = 10.0.0.0/16\noutput name = myvpc"
+	if got, want := compiled.String(), exp; got != want {
+		t.Fatalf("got\n%s\nwant\n%s", got, want)
+	}
+
+	_, _, err = Compile(MustParse("output vpc_id = $vpc"), env)
+	if err == nil || !strings.Contains(err.Error(), "'$vpc' but 'vpc' is undefined") {
+		t.Fatalf("expected error, got %v", err)
+	}
+}
+
+func TestCheckParamTypesPass(t *testing.T) {
+	defs := map[string]Definition{
+		"createsubnet": {
+			Action: "create", Entity: "subnet", RequiredParams: []string{"cidr", "vpc"},
+			ParamTypes: map[string]ParamType{"cidr": {Kind: CidrParam}, "vpc": {Kind: IdParam, Of: "vpc", Prefix: "vpc-"}},
+		},
+		"checkinstance": {
+			Action: "check", Entity: "instance", RequiredParams: []string{"id", "state", "timeout"},
+			ParamTypes: map[string]ParamType{"state": {Kind: EnumParam, Values: []string{"running", "terminated"}}, "timeout": {Kind: DurationParam}},
+		},
+		"createvpc": {Action: "create", Entity: "vpc", RequiredParams: []string{"cidr"}},
+	}
+	newEnv := func() *Env {
+		env := NewEnv()
+		env.AddFillers(map[string]interface{}{"vpc.id": "vpc-1234", "subnet.cidr": "10.0.0.0/33"})
+		env.DefLookupFunc = func(in string) (Definition, bool) {
+			t, ok := defs[in]
+			return t, ok
+		}
+		return env
+	}
+
+	tcases := []struct {
+		tpl       string
+		expect    string
+		expectErr string
+	}{
+		{tpl: "create subnet cidr=10.0.0.0/24 vpc={vpc.id}", expect: "create subnet cidr=10.0.0.0/24 vpc=vpc-1234"},
+		{tpl: "myvpc = create vpc cidr=10.0.0.0/16\ncreate subnet cidr=10.0.0.0/24 vpc=$myvpc", expect: "myvpc = create vpc cidr=10.0.0.0/16\ncreate subnet cidr=10.0.0.0/24 vpc=$myvpc"},
+		{tpl: "check instance id=i-1234 state=running timeout=5m", expect: "check instance id=i-1234 state=running timeout=300"},
+		{tpl: "create subnet cidr={subnet.cidr} vpc=vpc-1234", expectErr: "create subnet: invalid value '10.0.0.0/33' for param 'cidr': expecting a CIDR block"},
+		{tpl: "create subnet cidr=10.0.0.0/24 vpc=subnet-1234", expectErr: "create subnet: invalid value 'subnet-1234' for param 'vpc': expecting a vpc id (i.e. vpc-...)"},
+		{tpl: "check instance id=i-1234 state=started timeout=180", expectErr: "check instance: invalid value 'started' for param 'state': expecting one of running, terminated"},
+	}
+
+	for i, tcase := range tcases {
+		compiled, _, err := Compile(MustParse(tcase.tpl), newEnv())
+		if tcase.expectErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tcase.expectErr) {
+				t.Fatalf("%d: got %v, want %s", i+1, err, tcase.expectErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%d: %s", i+1, err)
+		}
+		if got, want := compiled.String(), tcase.expect; got != want {
+			t.Fatalf("%d: got\n%s\nwant\n%s", i+1, got, want)
+		}
+	}
+}
+
 func TestDefaultEnvWithNilFunc(t *testing.T) {
 	text := "create instance name={instance.name} subnet=@mysubnet"
 	env := NewEnv()
@@ -329,10 +697,10 @@ func TestCheckInvalidReferencesDeclarationPass(t *testing.T) {
 		expErr string
 	}{
 		{"sub = create subnet\ninst = create instance subnet=$sub\nip = 127.0.0.1\ncreate instance subnet=$inst ip=$ip", ""},
-		{"sub = create subnet\ninst = create instance subnet=$sub\ninst = create instance", "'inst' has already been assigned in template"},
+		{"sub = create subnet\ninst = create instance subnet=$sub\ninst = create instance", "line 3, column 1: using reference '$inst' but 'inst' has already been assigned in template"},
 		{"sub = create subnet\ninst = create instance subnet=$sub\ncreate instance subnet=$inst_2", "'inst_2' is undefined in template"},
 		{"sub = create subnet\ncreate vpc cidr=10.0.0.0/4", ""},
-		{"create instance subnet=$sub\nsub = create subnet", "'sub' is undefined in template"},
+		{"create instance subnet=$sub\nsub = create subnet", "line 1, column 1: using reference '$sub' but 'sub' is undefined in template"},
 		{"create instance\nip = 127.0.0.1", ""},
 		{"new_inst = create instance autoref=$new_inst\n", "'new_inst' is undefined in template"},
 		{"a = $test", "'test' is undefined in template"},
@@ -388,8 +756,8 @@ func TestResolveAgainstDefinitionsPass(t *testing.T) {
 	                        create keypair name={key.name} type=wrong`)
 
 		_, _, err := resolveAgainstDefinitions(tpl, env)
-		if err == nil || !strings.Contains(err.Error(), "type") {
-			t.Fatalf("expected err with message containing 'type'")
+		if err == nil || !strings.HasPrefix(err.Error(), "line 2, column 26: create keypair: unexpected param key 'type'") {
+			t.Fatalf("expected err at line 2 with message containing 'type', got %v", err)
 		}
 	})
 
@@ -0,0 +1,213 @@
+package template
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/wallix/awless/template/internal/ast"
+)
+
+type concurrentTask struct {
+	stmt *ast.Statement
+	// ids are the resources the statement acts on, used the ones appearing in its params
+	ids, used map[string]bool
+	deps      []*concurrentTask
+	done      chan struct{}
+	started   bool
+	err       error
+}
+
+func isBatchable(st *ast.Statement) bool {
+	switch st.Node.(type) {
+	case *ast.CommandNode, *ast.DeclarationNode:
+		return true
+	}
+	return false
+}
+
+// runConcurrently runs the commands and declarations with at most env.Concurrency of them at a time.
+// A statement waits for the statements declaring the references it uses, and for the previous
+// statements acting on a resource it uses or using a resource it acts on (i.e. a wait command is
+// a barrier for the resource it watches). Once a statement fails, no new statement is started.
+// Executed commands are appended to current in the template order whatever their completion order,
+// so that the logged execution and its revert are deterministic.
+func runConcurrently(stmts []*ast.Statement, current *Template, env *Env, vars map[string]interface{}) error {
+	if len(stmts) == 0 {
+		return nil
+	}
+
+	tasks := make([]*concurrentTask, len(stmts))
+	declaredAt := make(map[string]*concurrentTask)
+	for i, st := range stmts {
+		task := &concurrentTask{stmt: st.Clone(), done: make(chan struct{})}
+		task.ids, task.used = statementResources(task.stmt)
+		for _, ref := range statementRefs(task.stmt) {
+			if dep, ok := declaredAt[ref]; ok {
+				task.deps = append(task.deps, dep)
+			}
+		}
+		for _, previous := range tasks[:i] {
+			if sharesResource(task.ids, previous.used) || sharesResource(task.used, previous.ids) {
+				task.deps = append(task.deps, previous)
+			}
+		}
+		if decl, ok := task.stmt.Node.(*ast.DeclarationNode); ok {
+			declaredAt[decl.Ident] = task
+		}
+		tasks[i] = task
+	}
+
+	var (
+		mu     sync.Mutex
+		failed bool
+		wg     sync.WaitGroup
+		sem    = make(chan struct{}, env.Concurrency)
+	)
+
+	for _, task := range tasks {
+		wg.Add(1)
+		go func(task *concurrentTask) {
+			defer wg.Done()
+			defer close(task.done)
+			for _, dep := range task.deps {
+				<-dep.done
+			}
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			mu.Lock()
+			if failed {
+				mu.Unlock()
+				return
+			}
+			task.started = true
+			refs := make(map[string]interface{})
+			for k, v := range vars {
+				refs[k] = v
+			}
+			mu.Unlock()
+
+			ident, result, err := runBatchable(task.stmt, env, refs)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if task.err = err; err != nil {
+				failed = true
+				return
+			}
+			if ident != "" {
+				vars[ident] = result
+			}
+		}(task)
+	}
+	wg.Wait()
+
+	for _, task := range tasks {
+		if _, isValue := isValueDeclaration(task.stmt); task.started && !isValue {
+			current.Statements = append(current.Statements, task.stmt)
+		}
+	}
+	for _, task := range tasks {
+		if task.err != nil {
+			return task.err
+		}
+	}
+	return nil
+}
+
+func runBatchable(st *ast.Statement, env *Env, vars map[string]interface{}) (string, interface{}, error) {
+	switch n := st.Node.(type) {
+	case *ast.CommandNode:
+		return "", nil, runCmd(n, env, vars)
+	case *ast.DeclarationNode:
+		if value, ok := isValueDeclaration(st); ok {
+			value.ProcessRefs(vars)
+			return n.Ident, value.Value.Value(), nil
+		}
+		if cmd, ok := n.Expr.(*ast.CommandNode); ok {
+			if err := runCmd(cmd, env, vars); err != nil {
+				return "", nil, err
+			}
+			return n.Ident, cmd.Result(), nil
+		}
+		return "", nil, fmt.Errorf("unknown type of node: %T", n.Expr)
+	}
+	return "", nil, fmt.Errorf("unknown type of node: %T", st.Node)
+}
+
+func isValueDeclaration(st *ast.Statement) (*ast.ValueNode, bool) {
+	if decl, ok := st.Node.(*ast.DeclarationNode); ok {
+		value, isValue := decl.Expr.(*ast.ValueNode)
+		return value, isValue
+	}
+	return nil, false
+}
+
+func statementRefs(st *ast.Statement) []string {
+	node := st.Node
+	if decl, ok := node.(*ast.DeclarationNode); ok {
+		node = decl.Expr
+	}
+	if withRefs, ok := node.(ast.WithRefs); ok {
+		return withRefs.GetRefs()
+	}
+	return nil
+}
+
+// statementResources returns the values of the id param of a statement (or its declared variable)
+// and the values of all its params, references being prefixed with '$' and aliases with '@'
+func statementResources(st *ast.Statement) (ids, used map[string]bool) {
+	ids, used = make(map[string]bool), make(map[string]bool)
+	node := st.Node
+	if decl, ok := node.(*ast.DeclarationNode); ok {
+		ids["$"+decl.Ident] = true
+		node = decl.Expr
+	}
+	cmd, ok := node.(*ast.CommandNode)
+	if !ok {
+		return
+	}
+	for k, v := range cmd.Params {
+		for _, res := range valueResources(v) {
+			used[res] = true
+			if k == "id" {
+				ids[res] = true
+			}
+		}
+	}
+	return
+}
+
+func valueResources(v ast.CompositeValue) (res []string) {
+	if withRefs, ok := v.(ast.WithRefs); ok {
+		for _, ref := range withRefs.GetRefs() {
+			res = append(res, "$"+ref)
+		}
+	}
+	if withAlias, ok := v.(ast.WithAlias); ok {
+		for _, alias := range withAlias.GetAliases() {
+			res = append(res, "@"+alias)
+		}
+	}
+	switch vv := v.Value().(type) {
+	case string:
+		res = append(res, vv)
+	case []interface{}:
+		for _, elem := range vv {
+			if s, ok := elem.(string); ok {
+				res = append(res, s)
+			}
+		}
+	}
+	return
+}
+
+func sharesResource(ids, used map[string]bool) bool {
+	for id := range ids {
+		if used[id] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,145 @@
+package template
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/wallix/awless/logger"
+	"github.com/wallix/awless/template/driver"
+)
+
+type concurrentDriver struct {
+	mu               sync.Mutex
+	running, maxSeen int
+	finished         []string
+	failing          string
+}
+
+func (d *concurrentDriver) Lookup(lookups ...string) (driver.DriverFn, error) {
+	return func(ctx driver.Context, params map[string]interface{}) (interface{}, error) {
+		name, _ := params["name"].(string)
+		d.mu.Lock()
+		d.running++
+		if d.running > d.maxSeen {
+			d.maxSeen = d.running
+		}
+		d.mu.Unlock()
+
+		time.Sleep(20 * time.Millisecond)
+
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		d.running--
+		d.finished = append(d.finished, name)
+		if name == d.failing {
+			return nil, errors.New("failure")
+		}
+		return "id-" + name, nil
+	}, nil
+}
+func (d *concurrentDriver) SetLogger(*logger.Logger) {}
+func (d *concurrentDriver) SetDryRun(bool)           {}
+
+func TestRunConcurrently(t *testing.T) {
+	tplText := `vpc = create vpc name=vpc
+sub = create subnet name=sub vpc=$vpc
+create keypair name=key1
+create keypair name=key2
+create keypair name=key3
+create instance name=inst subnet=$sub`
+
+	t.Run("Independent commands run concurrently", func(t *testing.T) {
+		d := &concurrentDriver{}
+		ran, err := MustParse(tplText).Run(&Env{Driver: d, Concurrency: 3})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := d.maxSeen, 3; got != want {
+			t.Fatalf("got %d, want %d", got, want)
+		}
+		index := make(map[string]int)
+		for i, name := range d.finished {
+			index[name] = i
+		}
+		if !(index["vpc"] < index["sub"] && index["sub"] < index["inst"]) {
+			t.Fatalf("dependencies not respected: %v", d.finished)
+		}
+		exp := `vpc = create vpc name=vpc
+sub = create subnet name=sub vpc=id-vpc
+create keypair name=key1
+create keypair name=key2
+create keypair name=key3
+create instance name=inst subnet=id-sub`
+		if got, want := ran.String(), exp; got != want {
+			t.Fatalf("got\n%s\nwant\n%s", got, want)
+		}
+		reverted, err := ran.Revert()
+		if err != nil {
+			t.Fatal(err)
+		}
+		expRevert := `delete instance id=id-inst
+check instance id=id-inst state=terminated timeout=180
+delete keypair name=id-key3
+delete keypair name=id-key2
+delete keypair name=id-key1
+delete subnet id=id-sub
+delete vpc id=id-vpc`
+		if got, want := reverted.String(), expRevert; got != want {
+			t.Fatalf("got\n%s\nwant\n%s", got, want)
+		}
+	})
+
+	t.Run("No more commands are started after a failure", func(t *testing.T) {
+		d := &concurrentDriver{failing: "vpc"}
+		ran, err := MustParse(tplText).Run(&Env{Driver: d, Concurrency: 4})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, name := range d.finished {
+			if name == "sub" || name == "inst" {
+				t.Fatalf("%s should not have been run: %v", name, d.finished)
+			}
+		}
+		exp := `vpc = create vpc name=vpc
+create keypair name=key1
+create keypair name=key2
+create keypair name=key3`
+		if got, want := ran.String(), exp; got != want {
+			t.Fatalf("got\n%s\nwant\n%s", got, want)
+		}
+	})
+
+	t.Run("Commands on the same resource run in order", func(t *testing.T) {
+		d := &concurrentDriver{}
+		tpl := MustParse(`update instance id=i-1 name=first
+create keypair name=key1
+update instance id=i-1 name=second
+check instance id=i-1 name=check state=running timeout=10
+create tag resource=i-1 name=tag`)
+		if _, err := tpl.Run(&Env{Driver: d, Concurrency: 4}); err != nil {
+			t.Fatal(err)
+		}
+		if got, want := d.maxSeen, 2; got != want {
+			t.Fatalf("got %d, want %d", got, want)
+		}
+		index := make(map[string]int)
+		for i, name := range d.finished {
+			index[name] = i
+		}
+		if !(index["first"] < index["second"] && index["second"] < index["check"] && index["check"] < index["tag"]) {
+			t.Fatalf("commands on i-1 not run in order: %v", d.finished)
+		}
+	})
+
+	t.Run("Run sequentially without concurrency", func(t *testing.T) {
+		d := &concurrentDriver{}
+		if _, err := MustParse(tplText).Run(&Env{Driver: d}); err != nil {
+			t.Fatal(err)
+		}
+		if got, want := d.maxSeen, 1; got != want {
+			t.Fatalf("got %d, want %d", got, want)
+		}
+	})
+}
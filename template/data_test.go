@@ -1,6 +1,13 @@
 package template
 
 var DefsExample = map[string]Definition{
+	"createvpc": {
+		Action:         "create",
+		Entity:         "vpc",
+		Api:            "ec2",
+		RequiredParams: []string{"cidr"},
+		ExtraParams:    []string{"name"},
+	},
 	"createsubnet": {
 		Action:         "create",
 		Entity:         "subnet",
@@ -18,7 +18,11 @@ package template
 
 import (
 	"fmt"
+	"net"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 )
 
 type DefinitionLookupFunc func(key string) (Definition, bool)
@@ -35,6 +39,7 @@ func (defs Definitions) Map(fn func(Definition) string) (reduced []string) {
 type Definition struct {
 	Action, Entity, Api         string
 	RequiredParams, ExtraParams []string
+	ParamTypes                  map[string]ParamType
 }
 
 func (def Definition) Name() string {
@@ -60,3 +65,120 @@ func (def Definition) Required() []string {
 func (def Definition) Extra() []string {
 	return def.ExtraParams
 }
+
+// accepts reports whether the param key is valid for the definition.
+// The wait command accepts any resource property as param.
+func (def Definition) accepts(key string) bool {
+	return def.Action == waitAction || foundIn(key, def.Required()) || foundIn(key, def.Extra())
+}
+
+const (
+	CidrParam         = "cidr"
+	IntParam          = "int"
+	EnumParam         = "enum"
+	ArnParam          = "arn"
+	DurationParam     = "duration"
+	BoolParam         = "bool"
+	IdParam           = "id"
+	InstanceTypeParam = "instancetype"
+)
+
+var instanceTypeRegex = regexp.MustCompile(`^(?i)[a-z][a-z0-9-]*\.(nano|micro|small|medium|large|[0-9]*xlarge|metal(-[0-9]+xl)?)$`)
+
+// ParamType describes the values accepted for a definition param.
+// Int params are bounded by Min and Max, unless 0. Enum params accept one of Values
+// (case insensitive) and id params expect identifiers of the Of entity starting with Prefix.
+// Instance type params only check the 'family.size' shape, new families being released regularly.
+type ParamType struct {
+	Kind     string
+	Min, Max int
+	Values   []string
+	Of       string
+	Prefix   string
+}
+
+// Check verifies the value against the param type, returning the value normalized
+// (i.e. durations as a number of seconds). Lists are checked element by element.
+func (p ParamType) Check(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case []interface{}:
+		var checked []interface{}
+		for _, elem := range v {
+			c, err := p.Check(elem)
+			if err != nil {
+				return value, err
+			}
+			checked = append(checked, c)
+		}
+		return checked, nil
+	case []string:
+		for _, elem := range v {
+			if _, err := p.Check(elem); err != nil {
+				return value, err
+			}
+		}
+		return value, nil
+	}
+
+	str := fmt.Sprint(value)
+	switch p.Kind {
+	case CidrParam:
+		if _, _, err := net.ParseCIDR(str); err != nil {
+			return value, fmt.Errorf("expecting a CIDR block (e.g. 10.0.0.0/16)")
+		}
+	case IntParam:
+		i, err := strconv.Atoi(str)
+		if err != nil {
+			return value, fmt.Errorf("expecting an integer")
+		}
+		if (p.Min != 0 || p.Max != 0) && i < p.Min {
+			return value, fmt.Errorf("expecting an integer greater than or equal to %d", p.Min)
+		}
+		if p.Max != 0 && i > p.Max {
+			return value, fmt.Errorf("expecting an integer lower than or equal to %d", p.Max)
+		}
+		return i, nil
+	case EnumParam:
+		for _, v := range p.Values {
+			if strings.EqualFold(v, str) {
+				return value, nil
+			}
+		}
+		return value, fmt.Errorf("expecting one of %s", strings.Join(p.Values, ", "))
+	case ArnParam:
+		if parts := strings.SplitN(str, ":", 6); len(parts) < 6 || parts[0] != "arn" {
+			return value, fmt.Errorf("expecting an ARN (i.e. arn:partition:service:region:account:resource)")
+		}
+	case DurationParam:
+		if secs, err := strconv.Atoi(str); err == nil {
+			if secs < 0 {
+				return value, fmt.Errorf("expecting a positive duration")
+			}
+			return secs, nil
+		}
+		d, err := time.ParseDuration(str)
+		if err != nil {
+			return value, fmt.Errorf("expecting a duration in seconds or with a unit (e.g. 180, 30s, 5m)")
+		}
+		if d < 0 {
+			return value, fmt.Errorf("expecting a positive duration")
+		}
+		if d%time.Second != 0 {
+			return value, fmt.Errorf("expecting a duration in whole seconds (e.g. 1s rather than 500ms)")
+		}
+		return int(d / time.Second), nil
+	case BoolParam:
+		if _, err := strconv.ParseBool(str); err != nil {
+			return value, fmt.Errorf("expecting a boolean (true or false)")
+		}
+	case IdParam:
+		if !strings.HasPrefix(str, p.Prefix) {
+			return value, fmt.Errorf("expecting a %s id (i.e. %s...)", p.Of, p.Prefix)
+		}
+	case InstanceTypeParam:
+		if !instanceTypeRegex.MatchString(str) {
+			return value, fmt.Errorf("expecting an instance type (i.e. t2.micro, m5.2xlarge)")
+		}
+	}
+	return value, nil
+}
@@ -1,6 +1,7 @@
 package template
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -17,3 +18,58 @@ func TestGetTemplateFromDef(t *testing.T) {
 		t.Fatalf("\ngot\n%q\n\nwant\n%q\n", got, want)
 	}
 }
+
+func TestCheckParamType(t *testing.T) {
+	tcases := []struct {
+		typ     ParamType
+		value   interface{}
+		expect  interface{}
+		wantErr bool
+	}{
+		{typ: ParamType{Kind: CidrParam}, value: "10.0.0.0/16", expect: "10.0.0.0/16"},
+		{typ: ParamType{Kind: CidrParam}, value: "10.0.0.300/16", wantErr: true},
+		{typ: ParamType{Kind: IntParam}, value: 12, expect: 12},
+		{typ: ParamType{Kind: IntParam}, value: "-5", expect: -5},
+		{typ: ParamType{Kind: IntParam}, value: "five", wantErr: true},
+		{typ: ParamType{Kind: IntParam, Min: 0, Max: 65535}, value: -1, wantErr: true},
+		{typ: ParamType{Kind: IntParam, Min: 0, Max: 65535}, value: 65536, wantErr: true},
+		{typ: ParamType{Kind: IntParam, Min: 1}, value: 100000, expect: 100000},
+		{typ: ParamType{Kind: IntParam, Min: 1}, value: 0, wantErr: true},
+		{typ: ParamType{Kind: EnumParam, Values: []string{"HTTP", "HTTPS"}}, value: "https", expect: "https"},
+		{typ: ParamType{Kind: EnumParam, Values: []string{"t2.micro", "t2.small"}}, value: "t2.mini", wantErr: true},
+		{typ: ParamType{Kind: ArnParam}, value: "arn:aws:iam::aws:policy/AdministratorAccess", expect: "arn:aws:iam::aws:policy/AdministratorAccess"},
+		{typ: ParamType{Kind: ArnParam}, value: "AdministratorAccess", wantErr: true},
+		{typ: ParamType{Kind: DurationParam}, value: 180, expect: 180},
+		{typ: ParamType{Kind: DurationParam}, value: "5m", expect: 300},
+		{typ: ParamType{Kind: DurationParam}, value: "-1", wantErr: true},
+		{typ: ParamType{Kind: DurationParam}, value: "soon", wantErr: true},
+		{typ: ParamType{Kind: DurationParam}, value: "500ms", wantErr: true},
+		{typ: ParamType{Kind: DurationParam}, value: "1m30s", expect: 90},
+		{typ: ParamType{Kind: BoolParam}, value: "true", expect: "true"},
+		{typ: ParamType{Kind: BoolParam}, value: "yes", wantErr: true},
+		{typ: ParamType{Kind: IdParam, Of: "vpc", Prefix: "vpc-"}, value: "vpc-1234", expect: "vpc-1234"},
+		{typ: ParamType{Kind: IdParam, Of: "vpc", Prefix: "vpc-"}, value: "subnet-1234", wantErr: true},
+		{typ: ParamType{Kind: IdParam, Of: "subnet", Prefix: "subnet-"}, value: []interface{}{"subnet-1", "subnet-2"}, expect: []interface{}{"subnet-1", "subnet-2"}},
+		{typ: ParamType{Kind: IdParam, Of: "subnet", Prefix: "subnet-"}, value: []interface{}{"subnet-1", "sub-2"}, wantErr: true},
+		{typ: ParamType{Kind: InstanceTypeParam}, value: "m6i.large", expect: "m6i.large"},
+		{typ: ParamType{Kind: InstanceTypeParam}, value: "u-6tb1.metal", expect: "u-6tb1.metal"},
+		{typ: ParamType{Kind: InstanceTypeParam}, value: "t2micro", wantErr: true},
+		{typ: ParamType{Kind: InstanceTypeParam}, value: "t2.gigantic", wantErr: true},
+	}
+
+	for i, tcase := range tcases {
+		got, err := tcase.typ.Check(tcase.value)
+		if tcase.wantErr {
+			if err == nil {
+				t.Fatalf("%d: expected error for %v", i+1, tcase.value)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%d: %s", i+1, err)
+		}
+		if want := tcase.expect; !reflect.DeepEqual(got, want) {
+			t.Fatalf("%d: got %#v, want %#v", i+1, got, want)
+		}
+	}
+}
@@ -0,0 +1,451 @@
+package template
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/wallix/awless/cloud/properties"
+	"github.com/wallix/awless/graph"
+)
+
+type DriftKind string
+
+const (
+	ResourceDeleted    DriftKind = "deleted"
+	PropertyChanged    DriftKind = "changed"
+	RelationMissing    DriftKind = "missing relation"
+	RelationUnexpected DriftKind = "unexpected relation"
+)
+
+// Drift is a difference between a resource as created or updated by a command and the resource as found in a graph
+type Drift struct {
+	Kind DriftKind
+	// Command is the 1-based index of the last command creating or updating the resource
+	Command          int
+	Entity, Resource string
+	// Param is the param of the command whose value differs, or the type of the related resource
+	Param            string
+	Expected, Actual interface{}
+}
+
+func (d *Drift) String() string {
+	switch d.Kind {
+	case PropertyChanged:
+		return fmt.Sprintf("%s: %s -> %s", d.Param, driftValue(d.Expected), driftValue(d.Actual))
+	case RelationMissing:
+		return fmt.Sprintf("%s %s: %s", d.Kind, d.Param, driftValue(d.Expected))
+	case RelationUnexpected:
+		return fmt.Sprintf("%s %s: %s", d.Kind, d.Param, driftValue(d.Actual))
+	default:
+		return string(d.Kind)
+	}
+}
+
+// driftDef describes how to compare a created resource with the params of its creation
+type driftDef struct {
+	// values extract from the resource the value of each param
+	values map[string]func(*graph.Resource) (interface{}, bool)
+	// relations are the types of the resources referenced by params
+	relations map[string]string
+}
+
+var driftDefs = map[string]driftDef{
+	"vpc": {
+		values: map[string]func(*graph.Resource) (interface{}, bool){
+			"cidr": propertyValue(properties.CIDR),
+			"name": propertyValue(properties.Name),
+		},
+	},
+	"subnet": {
+		values: map[string]func(*graph.Resource) (interface{}, bool){
+			"cidr":             propertyValue(properties.CIDR),
+			"availabilityzone": propertyValue(properties.AvailabilityZone),
+			"name":             propertyValue(properties.Name),
+		},
+		relations: map[string]string{"vpc": "vpc"},
+	},
+	"instance": {
+		values: map[string]func(*graph.Resource) (interface{}, bool){
+			"image": propertyValue(properties.Image),
+			"type":  propertyValue(properties.Type),
+			"ip":    propertyValue(properties.PrivateIP),
+			"name":  propertyValue(properties.Name),
+		},
+		relations: map[string]string{"subnet": "subnet", "securitygroup": "securitygroup", "keypair": "keypair"},
+	},
+	"securitygroup": {
+		values: map[string]func(*graph.Resource) (interface{}, bool){
+			"name":        propertyValue(properties.Name),
+			"description": propertyValue(properties.Description),
+		},
+		relations: map[string]string{"vpc": "vpc"},
+	},
+	"internetgateway": {},
+	"routetable": {
+		relations: map[string]string{"vpc": "vpc"},
+	},
+	"keypair": {},
+	"volume": {
+		values: map[string]func(*graph.Resource) (interface{}, bool){
+			"size":             propertyValue(properties.Size),
+			"availabilityzone": propertyValue(properties.AvailabilityZone),
+		},
+	},
+	"elasticip": {},
+	"natgateway": {
+		relations: map[string]string{"subnet": "subnet"},
+	},
+	"user":            {values: map[string]func(*graph.Resource) (interface{}, bool){"name": propertyValue(properties.Name)}},
+	"group":           {values: map[string]func(*graph.Resource) (interface{}, bool){"name": propertyValue(properties.Name)}},
+	"role":            {values: map[string]func(*graph.Resource) (interface{}, bool){"name": propertyValue(properties.Name)}},
+	"instanceprofile": {values: map[string]func(*graph.Resource) (interface{}, bool){"name": propertyValue(properties.Name)}},
+	"policy": {
+		values: map[string]func(*graph.Resource) (interface{}, bool){
+			"name":        propertyValue(properties.Name),
+			"description": propertyValue(properties.Description),
+		},
+	},
+	"bucket": {},
+	"loadbalancer": {
+		values: map[string]func(*graph.Resource) (interface{}, bool){
+			"name":   propertyValue(properties.Name),
+			"scheme": propertyValue(properties.Scheme),
+			"type":   propertyValue(properties.Type),
+			"iptype": propertyValue(properties.IPType),
+		},
+		relations: map[string]string{"subnets": "subnet", "securitygroups": "securitygroup"},
+	},
+	"targetgroup": {
+		values: map[string]func(*graph.Resource) (interface{}, bool){
+			"name":     propertyValue(properties.Name),
+			"port":     propertyValue(properties.Port),
+			"protocol": propertyValue(properties.Protocol),
+		},
+		relations: map[string]string{"vpc": "vpc"},
+	},
+	"listener": {
+		values: map[string]func(*graph.Resource) (interface{}, bool){
+			"port":     propertyValue(properties.Port),
+			"protocol": propertyValue(properties.Protocol),
+		},
+		relations: map[string]string{"loadbalancer": "loadbalancer"},
+	},
+}
+
+// expectedResource is the state of a resource expected after the commands run
+type expectedResource struct {
+	command    int
+	entity, id string
+	find       func(*graph.Graph) (*graph.Resource, error)
+	values     map[string]interface{}
+	getters    map[string]func(*graph.Resource) (interface{}, bool)
+	relations  map[string][]string
+}
+
+// Drifts compares the resources created or updated by the successful commands of the template,
+// except the given reverted ones, with the resources of the graph: resources not found anymore,
+// properties differing from the params of the commands, and relations to other resources
+// referenced by params (i.e. subnet, securitygroup) missing or unexpected.
+// Resources deleted and relations attached or detached by later commands are taken into account.
+func (s *Template) Drifts(g *graph.Graph, reverted map[int]bool) ([]*Drift, error) {
+	expected := make(map[string]*expectedResource)
+	var ids []string
+	track := func(exp *expectedResource) {
+		if _, ok := expected[exp.id]; !ok {
+			ids = append(ids, exp.id)
+		}
+		expected[exp.id] = exp
+	}
+
+	for i, cmd := range s.CommandNodesIterator() {
+		index := i + 1
+		if cmd.CmdErr != nil || reverted[index] {
+			continue
+		}
+		params := cmd.ToDriverParams()
+
+		switch cmd.Action {
+		case "create":
+			def, ok := driftDefs[cmd.Entity]
+			id, hasResult := cmd.CmdResult.(string)
+			if !ok || !hasResult || id == "" {
+				continue
+			}
+			exp := newExpectedResource(index, cmd.Entity, id, findCreated(cmd.Entity, id))
+			for param, valueFn := range def.values {
+				if v, ok := params[param]; ok {
+					exp.values[param] = v
+					exp.getters[param] = valueFn
+				}
+			}
+			for param, typ := range def.relations {
+				if v, ok := params[param]; ok {
+					exp.relations[typ] = append(exp.relations[typ], driftStrings(v)...)
+				}
+			}
+			track(exp)
+		case "update":
+			def, ok := priorStateDefs[cmd.Entity]
+			if !ok {
+				continue
+			}
+			var idValues []string
+			for _, p := range def.idParams {
+				idValues = append(idValues, fmt.Sprint(params[p]))
+			}
+			id := strings.Join(idValues, " ")
+			exp, ok := expected[id]
+			if !ok {
+				exp = newExpectedResource(index, cmd.Entity, id, func(g *graph.Graph) (*graph.Resource, error) {
+					return def.find(g, params)
+				})
+				track(exp)
+			}
+			exp.command = index
+			for param, v := range params {
+				if valueFn, ok := def.values[param]; ok && !foundIn(param, def.idParams) {
+					exp.values[param] = v
+					exp.getters[param] = valueFn
+				}
+			}
+		case "delete":
+			for _, p := range []string{"id", "name", "arn"} {
+				if v, ok := params[p]; ok {
+					delete(expected, fmt.Sprint(v))
+				}
+			}
+		case "attach", "detach":
+			related := fmt.Sprint(params["id"])
+			for param, v := range params {
+				exp, ok := expected[fmt.Sprint(v)]
+				if param == "id" || !ok {
+					continue
+				}
+				if _, tracked := exp.relations[cmd.Entity]; !tracked {
+					continue
+				}
+				var others []string
+				for _, other := range exp.relations[cmd.Entity] {
+					if other != related {
+						others = append(others, other)
+					}
+				}
+				if cmd.Action == "attach" {
+					others = append(others, related)
+				}
+				exp.relations[cmd.Entity] = others
+			}
+		}
+	}
+
+	var drifts []*Drift
+	for _, id := range ids {
+		exp, ok := expected[id]
+		if !ok {
+			continue
+		}
+		delete(expected, id)
+		found, err := exp.drifts(g)
+		if err != nil {
+			return drifts, err
+		}
+		drifts = append(drifts, found...)
+	}
+	return drifts, nil
+}
+
+// ChangesResource reports whether a successful command of the template updates, deletes,
+// attaches to or detaches from the resource with the given id, or creates it again
+func (s *Template) ChangesResource(id string) bool {
+	for _, cmd := range s.CommandNodesIterator() {
+		if cmd.CmdErr != nil {
+			continue
+		}
+		switch cmd.Action {
+		case "create":
+			if result, ok := cmd.CmdResult.(string); ok && result == id {
+				return true
+			}
+		case "update", "delete", "attach", "detach":
+			if usesValue(cmd, id) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// DeletedResources returns the ids, names and ARNs of the resources deleted by the
+// successful commands following the given 1-based command index (0 for all commands)
+func (s *Template) DeletedResources(after int) map[string]bool {
+	deleted := make(map[string]bool)
+	for i, cmd := range s.CommandNodesIterator() {
+		if i < after || cmd.Action != "delete" || cmd.CmdErr != nil {
+			continue
+		}
+		params := cmd.ToDriverParams()
+		for _, p := range []string{"id", "name", "arn"} {
+			if v, ok := params[p]; ok {
+				deleted[fmt.Sprint(v)] = true
+			}
+		}
+	}
+	return deleted
+}
+
+func newExpectedResource(command int, entity, id string, find func(*graph.Graph) (*graph.Resource, error)) *expectedResource {
+	return &expectedResource{
+		command: command, entity: entity, id: id, find: find,
+		values:    make(map[string]interface{}),
+		getters:   make(map[string]func(*graph.Resource) (interface{}, bool)),
+		relations: make(map[string][]string),
+	}
+}
+
+func (exp *expectedResource) drifts(g *graph.Graph) (drifts []*Drift, err error) {
+	newDrift := func(kind DriftKind, param string, expected, actual interface{}) *Drift {
+		return &Drift{Kind: kind, Command: exp.command, Entity: exp.entity, Resource: exp.id, Param: param, Expected: expected, Actual: actual}
+	}
+
+	res, err := exp.find(g)
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return []*Drift{newDrift(ResourceDeleted, "", nil, nil)}, nil
+	}
+
+	var params []string
+	for param := range exp.values {
+		params = append(params, param)
+	}
+	sort.Strings(params)
+	for _, param := range params {
+		// params whose current value is unknown cannot be compared
+		actual, ok := exp.getters[param](res)
+		if ok && !sameDriftValue(exp.values[param], actual) {
+			drifts = append(drifts, newDrift(PropertyChanged, param, exp.values[param], actual))
+		}
+	}
+
+	related, err := relatedResources(g, res)
+	if err != nil {
+		return drifts, err
+	}
+	var types []string
+	for typ := range exp.relations {
+		types = append(types, typ)
+	}
+	sort.Strings(types)
+	for _, typ := range types {
+		want := make(map[string]bool)
+		for _, id := range exp.relations[typ] {
+			want[id] = true
+			if !related[typ][id] {
+				drifts = append(drifts, newDrift(RelationMissing, typ, id, nil))
+			}
+		}
+		var unexpected []string
+		for id := range related[typ] {
+			if !want[id] {
+				unexpected = append(unexpected, id)
+			}
+		}
+		sort.Strings(unexpected)
+		for _, id := range unexpected {
+			drifts = append(drifts, newDrift(RelationUnexpected, typ, nil, id))
+		}
+	}
+	return drifts, nil
+}
+
+// findCreated finds a created resource by the result of its creation: its id or, when
+// not identified by it in the graph (i.e. scaling groups), its name
+func findCreated(entity, result string) func(*graph.Graph) (*graph.Resource, error) {
+	return func(g *graph.Graph) (*graph.Resource, error) {
+		res, err := g.FindResource(result)
+		if err != nil || res != nil {
+			return res, err
+		}
+		named, err := g.FindResourcesByProperty(properties.Name, result)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range named {
+			if r.Type() == entity {
+				return r, nil
+			}
+		}
+		return nil, nil
+	}
+}
+
+// relatedResources indexes by type the ids of the ancestors of the resource
+// and of the resources it applies on or that apply on it
+func relatedResources(g *graph.Graph, res *graph.Resource) (map[string]map[string]bool, error) {
+	var all []*graph.Resource
+	if err := g.Accept(&graph.ParentsVisitor{From: res, Each: graph.VisitorCollectFunc(&all)}); err != nil {
+		return nil, err
+	}
+	appliedOn, err := g.ListResourcesAppliedOn(res)
+	if err != nil {
+		return nil, err
+	}
+	dependents, err := g.ListResourcesDependingOn(res)
+	if err != nil {
+		return nil, err
+	}
+	all = append(all, appliedOn...)
+	all = append(all, dependents...)
+
+	related := make(map[string]map[string]bool)
+	for _, r := range all {
+		if related[r.Type()] == nil {
+			related[r.Type()] = make(map[string]bool)
+		}
+		related[r.Type()][r.Id()] = true
+	}
+	return related, nil
+}
+
+// sameDriftValue compares a param value with a property value, case insensitively
+// and regardless of the order of lists
+func sameDriftValue(param, property interface{}) bool {
+	expected, actual := driftStrings(param), driftStrings(property)
+	if len(expected) != len(actual) {
+		return false
+	}
+	sort.Strings(expected)
+	sort.Strings(actual)
+	for i := range expected {
+		if !strings.EqualFold(expected[i], actual[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func driftStrings(v interface{}) (list []string) {
+	switch vv := v.(type) {
+	case []interface{}:
+		for _, elem := range vv {
+			list = append(list, fmt.Sprint(elem))
+		}
+	case []string:
+		list = append(list, vv...)
+	default:
+		list = append(list, fmt.Sprint(vv))
+	}
+	return
+}
+
+func driftValue(v interface{}) string {
+	switch vv := v.(type) {
+	case nil:
+		return "(none)"
+	case []interface{}, []string:
+		return "[" + strings.Join(driftStrings(vv), ", ") + "]"
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,122 @@
+package template
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/wallix/awless/cloud/properties"
+	"github.com/wallix/awless/graph"
+)
+
+func TestDrifts(t *testing.T) {
+	tpl := MustParse(`vpc = create vpc cidr=10.0.0.0/16 name=prod
+subnet = create subnet cidr=10.0.0.0/24 vpc=vpc-1
+create instance image=ami-1 type=t2.micro subnet=subnet-1 securitygroup=sg-1 name=web
+create instance image=ami-1 type=t2.micro subnet=subnet-1 name=db
+update instance id=i-2 type=t2.large
+attach securitygroup id=sg-2 instance=i-2
+create instance image=ami-1 type=t2.micro subnet=subnet-1 name=tmp
+delete instance id=i-3
+create instance image=ami-1 type=t2.micro subnet=subnet-1 name=reverted
+update subnet id=subnet-9 public=true
+create volume size=8 availabilityzone=eu-west-1a
+create volume size=abc`)
+	results := []string{"vpc-1", "subnet-1", "i-1", "i-2", "i-2", "", "i-3", "", "i-4", "subnet-9", "vol-1", ""}
+	for i, cmd := range tpl.CommandNodesIterator() {
+		cmd.CmdResult = results[i]
+	}
+	tpl.CommandNodesIterator()[11].CmdErr = errors.New("invalid size")
+
+	g := graph.NewGraph()
+	add := func(entity, id string, props map[string]interface{}) *graph.Resource {
+		res := graph.InitResource(entity, id)
+		for k, v := range props {
+			res.Properties[k] = v
+		}
+		g.AddResource(res)
+		return res
+	}
+	vpc := add("vpc", "vpc-1", map[string]interface{}{properties.CIDR: "10.0.0.0/16", properties.Name: "prod"})
+	subnet := add("subnet", "subnet-1", map[string]interface{}{properties.CIDR: "10.0.0.0/24"})
+	other := add("subnet", "subnet-2", nil)
+	inst1 := add("instance", "i-1", map[string]interface{}{properties.Image: "ami-1", properties.Type: "t2.medium", properties.Name: "web"})
+	inst2 := add("instance", "i-2", map[string]interface{}{properties.Image: "ami-1", properties.Type: "t2.large"})
+	sg1 := add("securitygroup", "sg-1", nil)
+	sg2 := add("securitygroup", "sg-2", nil)
+	sg3 := add("securitygroup", "sg-3", nil)
+	add("subnet", "subnet-9", map[string]interface{}{properties.Public: false})
+	g.AddParentRelation(vpc, subnet)
+	g.AddParentRelation(vpc, other)
+	g.AddParentRelation(subnet, inst1)
+	g.AddParentRelation(other, inst2)
+	g.AddAppliesOnRelation(sg1, inst1)
+	g.AddAppliesOnRelation(sg3, inst1)
+	g.AddAppliesOnRelation(sg2, inst2)
+
+	drifts, err := tpl.Drifts(g, map[int]bool{9: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []*Drift{
+		{Kind: PropertyChanged, Command: 3, Entity: "instance", Resource: "i-1", Param: "type", Expected: "t2.micro", Actual: "t2.medium"},
+		{Kind: RelationUnexpected, Command: 3, Entity: "instance", Resource: "i-1", Param: "securitygroup", Actual: "sg-3"},
+		{Kind: RelationMissing, Command: 5, Entity: "instance", Resource: "i-2", Param: "subnet", Expected: "subnet-1"},
+		{Kind: RelationUnexpected, Command: 5, Entity: "instance", Resource: "i-2", Param: "subnet", Actual: "subnet-2"},
+		{Kind: PropertyChanged, Command: 10, Entity: "subnet", Resource: "subnet-9", Param: "public", Expected: "true", Actual: false},
+		{Kind: ResourceDeleted, Command: 11, Entity: "volume", Resource: "vol-1"},
+	}
+	if got, want := len(drifts), len(expected); got != want {
+		for _, d := range drifts {
+			t.Logf("%#v", d)
+		}
+		t.Fatalf("got %d, want %d", got, want)
+	}
+	for i := range expected {
+		if got, want := drifts[i], expected[i]; !reflect.DeepEqual(got, want) {
+			t.Fatalf("%d: got %#v, want %#v", i+1, got, want)
+		}
+	}
+
+	if got, want := drifts[0].String(), "type: t2.micro -> t2.medium"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := drifts[3].String(), "unexpected relation subnet: subnet-2"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
+
+func TestChangesResource(t *testing.T) {
+	tpl := MustParse(`create instance subnet=subnet-1 image=ami-1
+update instance id=i-2 type=t2.large
+attach securitygroup id=sg-1 instance=i-3
+delete volume id=vol-1
+delete volume id=vol-2`)
+	results := []string{"i-1", "", "", "", ""}
+	for i, cmd := range tpl.CommandNodesIterator() {
+		cmd.CmdResult = results[i]
+	}
+	tpl.CommandNodesIterator()[4].CmdErr = errors.New("not found")
+
+	for id, exp := range map[string]bool{"i-1": true, "subnet-1": false, "i-2": true, "i-3": true, "sg-1": true, "vol-1": true, "vol-2": false} {
+		if got, want := tpl.ChangesResource(id), exp; got != want {
+			t.Fatalf("%s: got %t, want %t", id, got, want)
+		}
+	}
+}
+
+func TestDeletedResources(t *testing.T) {
+	tpl := MustParse(`delete role name=web
+create role name=web
+delete instance id=i-1
+delete volume id=vol-1`)
+	tpl.CommandNodesIterator()[3].CmdErr = errors.New("not found")
+
+	if got, want := tpl.DeletedResources(0), map[string]bool{"web": true, "i-1": true}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if got, want := tpl.DeletedResources(2), map[string]bool{"i-1": true}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,151 @@
+package template
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/wallix/awless/template/internal/ast"
+)
+
+// Format parses the template text and prints it in canonical form:
+// params sorted and consistently quoted, blocks indented with tabs,
+// declarations and trailing comments aligned within groups of lines.
+// Comments are preserved and blank lines between groups collapsed to one.
+func Format(text string) (string, error) {
+	tpl, err := parse(text, true)
+	if err != nil {
+		return "", err
+	}
+	var buff bytes.Buffer
+	formatStatements(&buff, tpl.Statements, "")
+	return buff.String(), nil
+}
+
+// formatLine is a statement to print along with its trailing comment.
+// A nil statement stands for a blank line between groups.
+type formatLine struct {
+	stmt    *ast.Statement
+	comment string
+}
+
+func formatStatements(buff *bytes.Buffer, stmts []*ast.Statement, indent string) {
+	var group []*formatLine
+	flushGroup := func() {
+		formatGroup(buff, group, indent)
+		group = nil
+	}
+
+	for _, l := range formatLines(stmts) {
+		switch {
+		case l.stmt == nil:
+			flushGroup()
+			buff.WriteString("\n")
+		case isFormatBlock(l.stmt):
+			flushGroup()
+			formatGroup(buff, []*formatLine{l}, indent)
+		default:
+			group = append(group, l)
+		}
+	}
+	flushGroup()
+}
+
+// formatLines attaches trailing comments to their statement and
+// collapses blank lines, dropping the ones at the start and at the end
+func formatLines(stmts []*ast.Statement) (lines []*formatLine) {
+	for _, st := range stmts {
+		comment, isCommentNode := st.Node.(*ast.CommentNode)
+		switch {
+		case isCommentNode && comment.IsBlankLine():
+			if len(lines) > 0 && lines[len(lines)-1].stmt != nil {
+				lines = append(lines, &formatLine{})
+			}
+		case isCommentNode && len(lines) > 0:
+			last := lines[len(lines)-1]
+			if last.stmt != nil && last.comment == "" && last.stmt.Pos.Line == st.Pos.Line && !isComment(last.stmt) {
+				last.comment = comment.Text
+				continue
+			}
+			lines = append(lines, &formatLine{stmt: st})
+		default:
+			lines = append(lines, &formatLine{stmt: st})
+		}
+	}
+	if len(lines) > 0 && lines[len(lines)-1].stmt == nil {
+		lines = lines[:len(lines)-1]
+	}
+	return
+}
+
+func formatGroup(buff *bytes.Buffer, lines []*formatLine, indent string) {
+	var identWidth int
+	for _, l := range lines {
+		if decl, ok := l.stmt.Node.(*ast.DeclarationNode); ok && len(decl.Ident) > identWidth {
+			identWidth = len(decl.Ident)
+		}
+	}
+
+	codes := make([]string, len(lines))
+	var codeWidth int
+	for i, l := range lines {
+		var code bytes.Buffer
+		formatStatement(&code, l.stmt, indent, identWidth)
+		codes[i] = code.String()
+		if l.comment != "" && len(codes[i]) > codeWidth {
+			codeWidth = len(codes[i])
+		}
+	}
+
+	for i, l := range lines {
+		buff.WriteString(codes[i])
+		if l.comment != "" {
+			fmt.Fprintf(buff, "%s %s", strings.Repeat(" ", codeWidth-len(codes[i])), l.comment)
+		}
+		buff.WriteString("\n")
+	}
+}
+
+func formatStatement(buff *bytes.Buffer, st *ast.Statement, indent string, identWidth int) {
+	buff.WriteString(indent)
+	switch n := st.Node.(type) {
+	case *ast.DeclarationNode:
+		fmt.Fprintf(buff, "%-*s = %s", identWidth, n.Ident, n.Expr)
+	case *ast.IfNode:
+		formatIf(buff, n, indent)
+	case *ast.ForNode:
+		fmt.Fprintf(buff, "for $%s in %s {\n", n.Var, n.List)
+		formatStatements(buff, n.Body, indent+"\t")
+		fmt.Fprintf(buff, "%s}", indent)
+	default:
+		buff.WriteString(st.String())
+	}
+}
+
+func formatIf(buff *bytes.Buffer, n *ast.IfNode, indent string) {
+	fmt.Fprintf(buff, "if %s {\n", n.Condition)
+	formatStatements(buff, n.Then, indent+"\t")
+	fmt.Fprintf(buff, "%s}", indent)
+	if len(n.Else) == 1 {
+		if elseIf, ok := n.Else[0].Node.(*ast.IfNode); ok {
+			buff.WriteString(" else ")
+			formatIf(buff, elseIf, indent)
+			return
+		}
+	}
+	if len(n.Else) > 0 {
+		buff.WriteString(" else {\n")
+		formatStatements(buff, n.Else, indent+"\t")
+		fmt.Fprintf(buff, "%s}", indent)
+	}
+}
+
+func isFormatBlock(st *ast.Statement) bool {
+	_, ok := st.Node.(ast.BlockNode)
+	return ok
+}
+
+func isComment(st *ast.Statement) bool {
+	_, ok := st.Node.(*ast.CommentNode)
+	return ok
+}
@@ -0,0 +1,85 @@
+package template
+
+import "testing"
+
+func TestFormat(t *testing.T) {
+	tcases := []struct {
+		text, expect string
+	}{
+		{text: "create vpc   name=main cidr=10.0.0.0/16", expect: "create vpc cidr=10.0.0.0/16 name=main\n"},
+		{
+			text: `
+
+# Network
+vpc = create vpc cidr=10.0.0.0/16 name='main'   # the main vpc
+mysubnet=create subnet vpc=$vpc cidr=10.0.0.0/24 name="my subnet" // public
+
+
+// Instances
+create instance subnet=$mysubnet image={instance.image} type=t2.micro count=1
+`,
+			expect: `# Network
+vpc      = create vpc cidr=10.0.0.0/16 name=main                    # the main vpc
+mysubnet = create subnet cidr=10.0.0.0/24 name='my subnet' vpc=$vpc // public
+
+// Instances
+create instance count=1 image={instance.image} subnet=$mysubnet type=t2.micro
+`,
+		},
+		{
+			text: `if {env}=="prod" {
+
+  # production
+    create vpc cidr=10.0.0.0/16
+} else   if {env} == "dev" {
+create vpc cidr=10.1.0.0/16
+
+} else {
+  for $c in [10.2.0.0/16, 10.3.0.0/16] {
+create vpc cidr=$c
+  }
+}
+output id = $vpc`,
+			expect: "if {env} == prod {\n\t# production\n\tcreate vpc cidr=10.0.0.0/16\n} else if {env} == dev {\n\tcreate vpc cidr=10.1.0.0/16\n} else {\n\tfor $c in [10.2.0.0/16,10.3.0.0/16] {\n\t\tcreate vpc cidr=$c\n\t}\n}\noutput id = $vpc\n",
+		},
+		{
+			text:   "create instance userdata=http://example.com/script.sh # fetched\ncreate tag key=k resource=r value='#1'",
+			expect: "create instance userdata=http://example.com/script.sh # fetched\ncreate tag key=k resource=r value='#1'\n",
+		},
+	}
+
+	for i, tcase := range tcases {
+		got, err := Format(tcase.text)
+		if err != nil {
+			t.Fatalf("%d: %s", i+1, err)
+		}
+		if got != tcase.expect {
+			t.Fatalf("%d: got\n%q\nwant\n%q", i+1, got, tcase.expect)
+		}
+		again, err := Format(got)
+		if err != nil {
+			t.Fatalf("%d: %s", i+1, err)
+		}
+		if again != got {
+			t.Fatalf("%d: formatting is not idempotent: got\n%q\nwant\n%q", i+1, again, got)
+		}
+	}
+
+	if _, err := Format("create vpc cidr=\n}"); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestParseKeepingComments(t *testing.T) {
+	text := "# vpc\nvpc = create vpc cidr=10.0.0.0/16 # main\n\n// end"
+	tpl, err := parse(text, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := tpl.String(), "# vpc\nvpc = create vpc cidr=10.0.0.0/16\n# main\n\n// end"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	if got, want := MustParse(text).String(), "vpc = create vpc cidr=10.0.0.0/16"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
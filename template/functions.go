@@ -0,0 +1,261 @@
+package template
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wallix/awless/template/internal/ast"
+)
+
+type builtinFunc struct {
+	minArgs, maxArgs int // maxArgs is -1 for variadic functions
+	eval             func(env *Env, args []interface{}) (interface{}, error)
+}
+
+// builtinFuncs are the functions callable in params values, i.e. cidr=cidrsubnet({vpc.cidr}, 8, 2)
+var builtinFuncs = map[string]builtinFunc{
+	"base64":     {1, 1, base64Func},
+	"cidrsubnet": {3, 3, cidrSubnetFunc},
+	"file":       {1, 1, fileFunc},
+	"join":       {2, -1, joinFunc},
+	"lookup":     {2, 3, lookupFunc},
+	"lower":      {1, 1, lowerFunc},
+	"now":        {0, 0, nowFunc},
+	"uuid":       {0, 0, uuidFunc},
+}
+
+// BuiltinFunctions returns the names of the functions callable in params values
+func BuiltinFunctions() (names []string) {
+	for name := range builtinFuncs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return
+}
+
+// resolveFunctionsPass evaluates the function calls whose arguments are resolved.
+// Calls depending on holes, aliases or references not resolved yet are left for a later pass,
+// as are calls in the body of loops, evaluated once per iteration after the loops expansion.
+func resolveFunctionsPass(tpl *Template, env *Env) (*Template, *Env, error) {
+	eval := func(name string, args []interface{}) (interface{}, error) {
+		fn, ok := builtinFuncs[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown function '%s' (expecting one of %s)", name, strings.Join(BuiltinFunctions(), ", "))
+		}
+		if len(args) < fn.minArgs || (fn.maxArgs >= 0 && len(args) > fn.maxArgs) {
+			return nil, errors.New(expectedArgs(fn))
+		}
+		return fn.eval(env, args)
+	}
+
+	var err error
+	walkStatementsOutsideLoops(tpl.Statements, func(st *ast.Statement) {
+		if err != nil {
+			return
+		}
+		for _, val := range statementValues(st) {
+			if withFuncs, ok := val.(ast.WithFunctions); ok {
+				if ferr := withFuncs.EvaluateFunctions(eval); ferr != nil {
+					err = errorAt(st.Pos, ferr)
+					return
+				}
+			}
+		}
+	})
+
+	return tpl, env, err
+}
+
+func failOnUnevaluatedFunctions(tpl *Template, env *Env) (*Template, *Env, error) {
+	var unevaluated []string
+	walkStatements(tpl.Statements, func(st *ast.Statement) {
+		for _, val := range statementValues(st) {
+			if withFuncs, ok := val.(ast.WithFunctions); ok {
+				unevaluated = append(unevaluated, withFuncs.GetFunctions()...)
+			}
+		}
+	})
+
+	if len(unevaluated) > 0 {
+		return tpl, env, fmt.Errorf("template contains functions that cannot be evaluated before run (arguments must not depend on commands results): %v", unevaluated)
+	}
+
+	return tpl, env, nil
+}
+
+func statementValues(st *ast.Statement) (vals []ast.CompositeValue) {
+	expr, isExpr := st.Node.(ast.ExpressionNode)
+	if decl, isDecl := st.Node.(*ast.DeclarationNode); isDecl {
+		expr, isExpr = decl.Expr, true
+	}
+	if !isExpr {
+		return
+	}
+	switch n := expr.(type) {
+	case *ast.CommandNode:
+		for _, k := range n.Keys() {
+			vals = append(vals, n.Params[k])
+		}
+	case *ast.ValueNode:
+		vals = append(vals, n.Value)
+	}
+	return
+}
+
+func expectedArgs(fn builtinFunc) string {
+	switch {
+	case fn.minArgs == fn.maxArgs && fn.minArgs == 0:
+		return "expecting no argument"
+	case fn.minArgs == fn.maxArgs:
+		return fmt.Sprintf("expecting %d argument(s)", fn.minArgs)
+	case fn.maxArgs < 0:
+		return fmt.Sprintf("expecting at least %d arguments", fn.minArgs)
+	default:
+		return fmt.Sprintf("expecting %d to %d arguments", fn.minArgs, fn.maxArgs)
+	}
+}
+
+func base64Func(env *Env, args []interface{}) (interface{}, error) {
+	return base64.StdEncoding.EncodeToString([]byte(fmt.Sprint(args[0]))), nil
+}
+
+// cidrSubnetFunc computes the subnet number netnum of the given prefix extended by newbits,
+// i.e. cidrsubnet(10.0.0.0/16, 8, 2) is 10.0.2.0/24
+func cidrSubnetFunc(env *Env, args []interface{}) (interface{}, error) {
+	_, network, err := net.ParseCIDR(fmt.Sprint(args[0]))
+	if err != nil {
+		return nil, fmt.Errorf("invalid prefix '%v'", args[0])
+	}
+	newbits, err := intArg(args[1])
+	if err != nil {
+		return nil, fmt.Errorf("newbits: %s", err)
+	}
+	netnum, err := intArg(args[2])
+	if err != nil {
+		return nil, fmt.Errorf("netnum: %s", err)
+	}
+
+	ones, bits := network.Mask.Size()
+	if newbits < 0 || ones+newbits > bits {
+		return nil, fmt.Errorf("cannot extend prefix /%d by %d bits (max /%d)", ones, newbits, bits)
+	}
+	if netnum < 0 || big.NewInt(int64(netnum)).BitLen() > newbits {
+		return nil, fmt.Errorf("netnum %d does not fit in %d bits", netnum, newbits)
+	}
+
+	ip := network.IP
+	if bits == 32 {
+		ip = ip.To4()
+	}
+	num := new(big.Int).SetBytes(ip)
+	num.Or(num, new(big.Int).Lsh(big.NewInt(int64(netnum)), uint(bits-ones-newbits)))
+	subnet := make(net.IP, len(ip))
+	numBytes := num.Bytes()
+	copy(subnet[len(subnet)-len(numBytes):], numBytes)
+
+	return (&net.IPNet{IP: subnet, Mask: net.CIDRMask(ones+newbits, bits)}).String(), nil
+}
+
+// fileFunc reads the content of a file, relative to the template file (or to its URL for remote templates)
+func fileFunc(env *Env, args []interface{}) (interface{}, error) {
+	if env.ReadFileFunc == nil {
+		return nil, fmt.Errorf("read file function is undefined")
+	}
+	content, err := env.ReadFileFunc(fmt.Sprint(args[0]))
+	if err != nil {
+		return nil, err
+	}
+	return string(content), nil
+}
+
+// joinFunc joins with a separator the given lists or values, i.e. join(-, [web, {env}])
+func joinFunc(env *Env, args []interface{}) (interface{}, error) {
+	var elems []string
+	for _, arg := range args[1:] {
+		switch vv := arg.(type) {
+		case []interface{}:
+			for _, elem := range vv {
+				elems = append(elems, fmt.Sprint(elem))
+			}
+		case []string:
+			elems = append(elems, vv...)
+		default:
+			elems = append(elems, fmt.Sprint(vv))
+		}
+	}
+	return strings.Join(elems, fmt.Sprint(args[0])), nil
+}
+
+// lookupFunc returns the value of a key in a map, or in a list of 'key:value' pairs
+// (i.e. lookup([us-east-1:ami-123, eu-west-1:ami-456], {region})), or the optional default
+func lookupFunc(env *Env, args []interface{}) (interface{}, error) {
+	key := fmt.Sprint(args[1])
+	switch vv := args[0].(type) {
+	case map[string]interface{}:
+		if v, ok := vv[key]; ok {
+			return v, nil
+		}
+	case map[string]string:
+		if v, ok := vv[key]; ok {
+			return v, nil
+		}
+	default:
+		pairs, ok := vv.([]interface{})
+		if !ok {
+			pairs = []interface{}{vv}
+		}
+		for _, pair := range pairs {
+			splits := strings.SplitN(fmt.Sprint(pair), ":", 2)
+			if len(splits) != 2 {
+				return nil, fmt.Errorf("invalid map entry '%v' (expecting key:value)", pair)
+			}
+			if splits[0] == key {
+				return splits[1], nil
+			}
+		}
+	}
+	if len(args) > 2 {
+		return args[2], nil
+	}
+	return nil, fmt.Errorf("key '%s' not found", key)
+}
+
+func lowerFunc(env *Env, args []interface{}) (interface{}, error) {
+	return strings.ToLower(fmt.Sprint(args[0])), nil
+}
+
+func nowFunc(env *Env, args []interface{}) (interface{}, error) {
+	return env.now().UTC().Format(time.RFC3339), nil
+}
+
+// uuidFunc generates a random (version 4) UUID
+func uuidFunc(env *Env, args []interface{}) (interface{}, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
+
+func intArg(arg interface{}) (int, error) {
+	switch vv := arg.(type) {
+	case int:
+		return vv, nil
+	default:
+		i, err := strconv.Atoi(fmt.Sprint(vv))
+		if err != nil {
+			return 0, fmt.Errorf("'%v' is not an integer", arg)
+		}
+		return i, nil
+	}
+}
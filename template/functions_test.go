@@ -0,0 +1,152 @@
+package template
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestResolveFunctionsPass(t *testing.T) {
+	newEnv := func() *Env {
+		env := NewEnv()
+		env.AddFillers(map[string]interface{}{
+			"vpc.cidr": "10.0.0.0/16",
+			"env":      "Prod",
+			"region":   "eu-west-1",
+			"amis":     []interface{}{"us-east-1:ami-1234", "eu-west-1:ami-2345"},
+		})
+		env.AliasFunc = func(e, k, v string) string { return map[string]string{"myimage": "ami-3456"}[v] }
+		env.DefLookupFunc = func(in string) (Definition, bool) {
+			t, ok := DefsExample[in]
+			return t, ok
+		}
+		env.ReadFileFunc = func(path string) ([]byte, error) {
+			if path == "userdata.sh" {
+				return []byte("#!/bin/bash\necho hello"), nil
+			}
+			return nil, errors.New("no such file")
+		}
+		env.nowFunc = func() time.Time { return time.Date(2017, 7, 14, 10, 30, 0, 0, time.FixedZone("CEST", 7200)) }
+		return env
+	}
+
+	tcases := []struct {
+		tpl    string
+		expect string
+	}{
+		{
+			tpl:    "create subnet cidr=cidrsubnet({vpc.cidr}, 8, 2) vpc=vpc-1234",
+			expect: "create subnet cidr=10.0.2.0/24 vpc=vpc-1234",
+		},
+		{
+			tpl:    "create subnet cidr=cidrsubnet(10.0.0.0/16, 4, 15) vpc=vpc-1234",
+			expect: "create subnet cidr=10.0.240.0/20 vpc=vpc-1234",
+		},
+		{
+			tpl:    "create keypair name=join(-, [web, lower({env})], 1)",
+			expect: "create keypair name=web-prod-1",
+		},
+		{
+			tpl:    "create keypair name=lower('Key-'+{env})",
+			expect: "create keypair name=key-prod",
+		},
+		{
+			tpl:    "create keypair name=base64(file(userdata.sh))",
+			expect: "create keypair name='IyEvYmluL2Jhc2gKZWNobyBoZWxsbw=='",
+		},
+		{
+			tpl:    "create keypair name=now()",
+			expect: "create keypair name=2017-07-14T08:30:00Z",
+		},
+		{
+			tpl:    "create instance count=1 image=lookup({amis}, {region}) subnet=sub-1234 type=t2.micro",
+			expect: "create instance count=1 image=ami-2345 subnet=sub-1234 type=t2.micro",
+		},
+		{
+			tpl:    "create instance count=1 image=lookup({amis}, ap-south-1, @myimage) subnet=sub-1234 type=t2.micro",
+			expect: "create instance count=1 image=ami-3456 subnet=sub-1234 type=t2.micro",
+		},
+		{
+			tpl:    "name = lower({env})\ncreate keypair name=$name",
+			expect: "create keypair name=prod",
+		},
+		{
+			tpl:    "for $n in [A, B] {\n  create keypair name=lower($n)\n}",
+			expect: "create keypair name=a\ncreate keypair name=b",
+		},
+	}
+
+	for i, tcase := range tcases {
+		env := newEnv()
+		compiled, _, err := Compile(MustParse(tcase.tpl), env)
+		if err != nil {
+			t.Fatalf("%d: %s", i+1, err)
+		}
+		if got, want := compiled.String(), tcase.expect; got != want {
+			t.Fatalf("%d: got\n%s\nwant\n%s", i+1, got, want)
+		}
+	}
+
+	t.Run("uuid", func(t *testing.T) {
+		compiled, _, err := Compile(MustParse("id = uuid()\ncreate keypair name=$id\ncreate tag key=Id resource=$id value=$id"), newEnv())
+		if err != nil {
+			t.Fatal(err)
+		}
+		cmds := compiled.CommandNodesIterator()
+		uuid := cmds[0].Params["name"].Value()
+		if !regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`).MatchString(uuid.(string)) {
+			t.Fatalf("invalid uuid %q", uuid)
+		}
+		if got, want := cmds[1].Params["value"].Value(), uuid; got != want {
+			t.Fatalf("got %v, want %v: a call is evaluated once", got, want)
+		}
+	})
+
+	t.Run("Calls in loops are evaluated per iteration", func(t *testing.T) {
+		compiled, _, err := Compile(MustParse("for $n in [a, b] {\n  create keypair name=uuid()\n}"), newEnv())
+		if err != nil {
+			t.Fatal(err)
+		}
+		cmds := compiled.CommandNodesIterator()
+		if len(cmds) != 2 {
+			t.Fatalf("got %d commands, want 2", len(cmds))
+		}
+		if first, second := cmds[0].Params["name"].Value(), cmds[1].Params["name"].Value(); first == second {
+			t.Fatalf("got the same uuid %v in both iterations", first)
+		}
+	})
+
+	t.Run("Lenient mode keeps calls with unresolved holes", func(t *testing.T) {
+		compiled, _, err := Compile(MustParse("create keypair name=lower({missing})"), newEnv(), LenientCompileMode)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := compiled.String(), "create keypair name=lower({missing})"; got != want {
+			t.Fatalf("got %s, want %s", got, want)
+		}
+	})
+
+	errcases := []struct {
+		tpl    string
+		expErr string
+	}{
+		{"create keypair name=upper(a)", "line 1, column 1: upper(a): unknown function 'upper' (expecting one of base64, cidrsubnet, file, join, lookup, lower, now, uuid)"},
+		{"create keypair name=lower(a, b)", "lower(a, b): expecting 1 argument(s)"},
+		{"create keypair name=uuid(a)", "uuid(a): expecting no argument"},
+		{"create subnet vpc=vpc-1 cidr=cidrsubnet({vpc.cidr}, 20, 2)", "cannot extend prefix /16 by 20 bits (max /32)"},
+		{"create subnet vpc=vpc-1 cidr=cidrsubnet({vpc.cidr}, 2, 4)", "netnum 4 does not fit in 2 bits"},
+		{"create subnet vpc=vpc-1 cidr=cidrsubnet(10.0.0.1, 8, 2)", "invalid prefix '10.0.0.1'"},
+		{"create keypair name=file(other.sh)", "file(other.sh): no such file"},
+		{"create keypair name=lookup({amis}, ap-south-1)", "key 'ap-south-1' not found"},
+		{"key = create keypair name=test\ncreate tag key=Name resource=$key value=lower($key)", "cannot be evaluated before run"},
+	}
+
+	for i, tcase := range errcases {
+		_, _, err := Compile(MustParse(tcase.tpl), newEnv())
+		if err == nil || !strings.Contains(err.Error(), tcase.expErr) {
+			t.Fatalf("%d: expected error containing %q, got %v", i+1, tcase.expErr, err)
+		}
+	}
+}
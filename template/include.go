@@ -0,0 +1,109 @@
+package template
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/wallix/awless/template/internal/ast"
+)
+
+// resolveIncludesPass replaces include statements by the statements of the included templates.
+// Included holes are filled with the include params and included declarations are
+// prefixed with the include namespace (i.e. 'vpc' becomes 'mynamespace.vpc'). Outputs of
+// included templates become declarations, referenced as '$mynamespace.output'.
+func resolveIncludesPass(tpl *Template, env *Env) (*Template, *Env, error) {
+	stmts, err := resolveIncludes(tpl.Statements, env, "", nil)
+	if err != nil {
+		return tpl, env, err
+	}
+	tpl.Statements = stmts
+	return tpl, env, nil
+}
+
+func resolveIncludes(stmts []*ast.Statement, env *Env, from string, stack []string) (out []*ast.Statement, err error) {
+	for _, st := range stmts {
+		switch n := st.Node.(type) {
+		case *ast.IncludeNode:
+			var included []*ast.Statement
+			if included, err = includeTemplate(n, st.Pos, env, from, stack); err != nil {
+				return
+			}
+			out = append(out, included...)
+			continue
+		case *ast.IfNode:
+			if n.Then, err = resolveIncludes(n.Then, env, from, stack); err != nil {
+				return
+			}
+			if n.Else, err = resolveIncludes(n.Else, env, from, stack); err != nil {
+				return
+			}
+		case *ast.ForNode:
+			if n.Body, err = resolveIncludes(n.Body, env, from, stack); err != nil {
+				return
+			}
+		}
+		out = append(out, st)
+	}
+	return
+}
+
+func includeTemplate(n *ast.IncludeNode, pos ast.Position, env *Env, from string, stack []string) ([]*ast.Statement, error) {
+	if env.IncludeFunc == nil {
+		return nil, fmt.Errorf("include '%s': include function is undefined", n.Path)
+	}
+	content, fullPath, err := env.IncludeFunc(n.Path, from)
+	if err != nil {
+		return nil, fmt.Errorf("include '%s': %s", n.Path, err)
+	}
+	for _, p := range stack {
+		if p == fullPath {
+			return nil, fmt.Errorf("include cycle: %s -> %s", strings.Join(stack, " -> "), fullPath)
+		}
+	}
+
+	tpl, err := Parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("include '%s': %s", n.Path, err)
+	}
+	stmts, err := resolveIncludes(tpl.Statements, env, fullPath, append(stack, fullPath))
+	if err != nil {
+		return nil, err
+	}
+
+	namespace := n.Namespace
+	if namespace == "" {
+		namespace = defaultIncludeNamespace(fullPath)
+	}
+	walkStatements(stmts, func(st *ast.Statement) {
+		if output, ok := st.Node.(*ast.OutputNode); ok {
+			st.Node = &ast.DeclarationNode{Ident: output.Name, Expr: output.ValueNode}
+		}
+	})
+	renameDeclarations(stmts, func(ident string) string {
+		return fmt.Sprintf("%s.%s", namespace, ident)
+	})
+	walkStatements(stmts, func(st *ast.Statement) {
+		// errors on included statements are reported at the include line
+		st.Pos = pos
+		if cmd := statementCommand(st); cmd != nil {
+			cmd.Pos = pos
+		}
+	})
+	for hole, value := range n.Params {
+		ast.ReplaceHoleInStatements(stmts, hole, value)
+	}
+
+	env.addIncludedSource(fullPath, content)
+
+	return stmts, nil
+}
+
+var invalidIdentifierChars = regexp.MustCompile("[^a-zA-Z0-9-_]+")
+
+func defaultIncludeNamespace(fullPath string) string {
+	base := path.Base(strings.Replace(fullPath, "\\", "/", -1))
+	base = strings.TrimSuffix(base, path.Ext(base))
+	return invalidIdentifierChars.ReplaceAllString(base, "_")
+}
@@ -20,6 +20,8 @@ const (
 
 	Copy Action = "copy"
 
+	Wait Action = "wait"
+
 	Import       Action = "import"
 	Authenticate Action = "authenticate"
 )
@@ -35,6 +37,7 @@ var actions = map[Action]struct{}{
 	Attach:       {},
 	Detach:       {},
 	Copy:         {},
+	Wait:         {},
 	Import:       {},
 	Authenticate: {},
 }
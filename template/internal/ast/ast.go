@@ -38,10 +38,27 @@ type AST struct {
 	currentKey         string
 	currentListBuilder *listValueBuilder
 	stmtBuilder        *statementBuilder
+	blocks             []*blockBuilder
 }
 
 type Statement struct {
 	Node
+	Pos Position
+}
+
+// Position is the 1-based line and column of a node in the template text.
+// The zero value is an unknown position (i.e. node built programmatically).
+type Position struct {
+	Line, Column int
+}
+
+func (p Position) IsValid() bool { return p.Line > 0 }
+
+func (p Position) String() string {
+	if !p.IsValid() {
+		return ""
+	}
+	return fmt.Sprintf("line %d, column %d", p.Line, p.Column)
 }
 
 type DeclarationNode struct {
@@ -63,9 +80,14 @@ type WithHoles interface {
 type CommandNode struct {
 	CmdResult interface{}
 	CmdErr    error
+	// CmdPriorState holds the values of the updated params before the command ran
+	CmdPriorState map[string]string
 
 	Action, Entity string
 	Params         map[string]CompositeValue
+	Retry          *Retry
+
+	Pos Position
 }
 
 func (c *CommandNode) Result() interface{} { return c.CmdResult }
@@ -95,6 +117,10 @@ func (c *CommandNode) String() string {
 		fmt.Fprintf(&buff, " %s", strings.Join(all, " "))
 	}
 
+	if c.Retry != nil {
+		fmt.Fprintf(&buff, " %s", c.Retry)
+	}
+
 	return buff.String()
 }
 
@@ -102,6 +128,11 @@ func (c *CommandNode) clone() Node {
 	cmd := &CommandNode{
 		Action: c.Action, Entity: c.Entity,
 		Params: make(map[string]CompositeValue),
+		Pos:    c.Pos,
+	}
+	if c.Retry != nil {
+		retry := *c.Retry
+		cmd.Retry = &retry
 	}
 
 	for k, v := range c.Params {
@@ -254,7 +285,7 @@ func (n *ValueNode) GetHoles() []string {
 }
 
 func (s *Statement) Clone() *Statement {
-	newStat := &Statement{}
+	newStat := &Statement{Pos: s.Pos}
 	newStat.Node = s.Node.clone()
 
 	return newStat
@@ -1,10 +1,14 @@
 package ast
 
+// Code generated by peg -switch -inline awless-template-syntax.peg DO NOT EDIT.
+
 import (
 	"fmt"
-	"math"
+	"io"
+	"os"
 	"sort"
 	"strconv"
+	"strings"
 )
 
 const endSymbol rune = 1114112
@@ -15,12 +19,28 @@ type pegRule uint8
 const (
 	ruleUnknown pegRule = iota
 	ruleScript
+	ruleLines
+	ruleStatementsLine
 	ruleStatement
 	ruleAction
 	ruleEntity
 	ruleDeclaration
 	ruleValueExpr
 	ruleCmdExpr
+	ruleInclude
+	ruleIncludePath
+	ruleOutput
+	ruleRetry
+	ruleIfBlock
+	ruleElseBlock
+	ruleForBlock
+	ruleBlockLineEnd
+	ruleBlockEnd
+	ruleCondition
+	ruleAndCondition
+	ruleNotCondition
+	ruleComparisonOperator
+	ruleConditionValue
 	ruleParams
 	ruleParam
 	ruleIdentifier
@@ -46,6 +66,9 @@ const (
 	ruleHole
 	ruleHolesStringValue
 	ruleHoleWithSuffixValue
+	ruleFunctionValue
+	ruleFunctionName
+	ruleFunctionArg
 	ruleComment
 	ruleSingleQuote
 	ruleDoubleQuote
@@ -55,10 +78,11 @@ const (
 	ruleBlankLine
 	ruleWhitespace
 	ruleEndOfLine
+	ruleLineEnd
 	ruleEndOfFile
 	ruleAction0
-	ruleAction1
 	rulePegText
+	ruleAction1
 	ruleAction2
 	ruleAction3
 	ruleAction4
@@ -84,17 +108,68 @@ const (
 	ruleAction24
 	ruleAction25
 	ruleAction26
+	ruleAction27
+	ruleAction28
+	ruleAction29
+	ruleAction30
+	ruleAction31
+	ruleAction32
+	ruleAction33
+	ruleAction34
+	ruleAction35
+	ruleAction36
+	ruleAction37
+	ruleAction38
+	ruleAction39
+	ruleAction40
+	ruleAction41
+	ruleAction42
+	ruleAction43
+	ruleAction44
+	ruleAction45
+	ruleAction46
+	ruleAction47
+	ruleAction48
+	ruleAction49
+	ruleAction50
+	ruleAction51
+	ruleAction52
+	ruleAction53
+	ruleAction54
+	ruleAction55
+	ruleAction56
+	ruleAction57
+	ruleAction58
+	ruleAction59
+	ruleAction60
+	ruleAction61
 )
 
 var rul3s = [...]string{
 	"Unknown",
 	"Script",
+	"Lines",
+	"StatementsLine",
 	"Statement",
 	"Action",
 	"Entity",
 	"Declaration",
 	"ValueExpr",
 	"CmdExpr",
+	"Include",
+	"IncludePath",
+	"Output",
+	"Retry",
+	"IfBlock",
+	"ElseBlock",
+	"ForBlock",
+	"BlockLineEnd",
+	"BlockEnd",
+	"Condition",
+	"AndCondition",
+	"NotCondition",
+	"ComparisonOperator",
+	"ConditionValue",
 	"Params",
 	"Param",
 	"Identifier",
@@ -120,6 +195,9 @@ var rul3s = [...]string{
 	"Hole",
 	"HolesStringValue",
 	"HoleWithSuffixValue",
+	"FunctionValue",
+	"FunctionName",
+	"FunctionArg",
 	"Comment",
 	"SingleQuote",
 	"DoubleQuote",
@@ -129,10 +207,11 @@ var rul3s = [...]string{
 	"BlankLine",
 	"Whitespace",
 	"EndOfLine",
+	"LineEnd",
 	"EndOfFile",
 	"Action0",
-	"Action1",
 	"PegText",
+	"Action1",
 	"Action2",
 	"Action3",
 	"Action4",
@@ -158,6 +237,41 @@ var rul3s = [...]string{
 	"Action24",
 	"Action25",
 	"Action26",
+	"Action27",
+	"Action28",
+	"Action29",
+	"Action30",
+	"Action31",
+	"Action32",
+	"Action33",
+	"Action34",
+	"Action35",
+	"Action36",
+	"Action37",
+	"Action38",
+	"Action39",
+	"Action40",
+	"Action41",
+	"Action42",
+	"Action43",
+	"Action44",
+	"Action45",
+	"Action46",
+	"Action47",
+	"Action48",
+	"Action49",
+	"Action50",
+	"Action51",
+	"Action52",
+	"Action53",
+	"Action54",
+	"Action55",
+	"Action56",
+	"Action57",
+	"Action58",
+	"Action59",
+	"Action60",
+	"Action61",
 }
 
 type token32 struct {
@@ -174,19 +288,19 @@ type node32 struct {
 	up, next *node32
 }
 
-func (node *node32) print(pretty bool, buffer string) {
+func (node *node32) print(w io.Writer, pretty bool, buffer string) {
 	var print func(node *node32, depth int)
 	print = func(node *node32, depth int) {
 		for node != nil {
 			for c := 0; c < depth; c++ {
-				fmt.Printf(" ")
+				fmt.Fprintf(w, " ")
 			}
 			rule := rul3s[node.pegRule]
 			quote := strconv.Quote(string(([]rune(buffer)[node.begin:node.end])))
 			if !pretty {
-				fmt.Printf("%v %v\n", rule, quote)
+				fmt.Fprintf(w, "%v %v\n", rule, quote)
 			} else {
-				fmt.Printf("\x1B[34m%v\x1B[m %v\n", rule, quote)
+				fmt.Fprintf(w, "\x1B[36m%v\x1B[m %v\n", rule, quote)
 			}
 			if node.up != nil {
 				print(node.up, depth+1)
@@ -197,12 +311,12 @@ func (node *node32) print(pretty bool, buffer string) {
 	print(node, 0)
 }
 
-func (node *node32) Print(buffer string) {
-	node.print(false, buffer)
+func (node *node32) Print(w io.Writer, buffer string) {
+	node.print(w, false, buffer)
 }
 
-func (node *node32) PrettyPrint(buffer string) {
-	node.print(true, buffer)
+func (node *node32) PrettyPrint(w io.Writer, buffer string) {
+	node.print(w, true, buffer)
 }
 
 type tokens32 struct {
@@ -245,24 +359,24 @@ func (t *tokens32) AST() *node32 {
 }
 
 func (t *tokens32) PrintSyntaxTree(buffer string) {
-	t.AST().Print(buffer)
+	t.AST().Print(os.Stdout, buffer)
+}
+
+func (t *tokens32) WriteSyntaxTree(w io.Writer, buffer string) {
+	t.AST().Print(w, buffer)
 }
 
 func (t *tokens32) PrettyPrintSyntaxTree(buffer string) {
-	t.AST().PrettyPrint(buffer)
+	t.AST().PrettyPrint(os.Stdout, buffer)
 }
 
 func (t *tokens32) Add(rule pegRule, begin, end, index uint32) {
-	if tree := t.tree; int(index) >= len(tree) {
-		expanded := make([]token32, 2*len(tree))
-		copy(expanded, tree)
-		t.tree = expanded
-	}
-	t.tree[index] = token32{
-		pegRule: rule,
-		begin:   begin,
-		end:     end,
+	tree, i := t.tree, int(index)
+	if i >= len(tree) {
+		t.tree = append(tree, token32{pegRule: rule, begin: begin, end: end})
+		return
 	}
+	tree[i] = token32{pegRule: rule, begin: begin, end: end}
 }
 
 func (t *tokens32) Tokens() []token32 {
@@ -274,7 +388,7 @@ type Peg struct {
 
 	Buffer string
 	buffer []rune
-	rules  [71]func() bool
+	rules  [126]func() bool
 	parse  func(rule ...int) error
 	reset  func()
 	Pretty bool
@@ -326,7 +440,7 @@ type parseError struct {
 }
 
 func (e *parseError) Error() string {
-	tokens, error := []token32{e.max}, "\n"
+	tokens, err := []token32{e.max}, "\n"
 	positions, p := make([]int, 2*len(tokens)), 0
 	for _, token := range tokens {
 		positions[p], p = int(token.begin), p+1
@@ -339,14 +453,14 @@ func (e *parseError) Error() string {
 	}
 	for _, token := range tokens {
 		begin, end := int(token.begin), int(token.end)
-		error += fmt.Sprintf(format,
+		err += fmt.Sprintf(format,
 			rul3s[token.pegRule],
 			translations[begin].line, translations[begin].symbol,
 			translations[end].line, translations[end].symbol,
 			strconv.Quote(string(e.p.buffer[begin:end])))
 	}
 
-	return error
+	return err
 }
 
 func (p *Peg) PrintSyntaxTree() {
@@ -357,6 +471,16 @@ func (p *Peg) PrintSyntaxTree() {
 	}
 }
 
+func (p *Peg) WriteSyntaxTree(w io.Writer) {
+	p.tokens32.WriteSyntaxTree(w, p.Buffer)
+}
+
+func (p *Peg) SprintSyntaxTree() string {
+	var bldr strings.Builder
+	p.WriteSyntaxTree(&bldr)
+	return bldr.String()
+}
+
 func (p *Peg) Execute() {
 	buffer, _buffer, text, begin, end := p.Buffer, p.buffer, "", 0, 0
 	for _, token := range p.Tokens() {
@@ -367,71 +491,160 @@ func (p *Peg) Execute() {
 			text = string(_buffer[begin:end])
 
 		case ruleAction0:
-			p.NewStatement()
+			p.NewStatement(p.positionAt(token.begin))
 		case ruleAction1:
-			p.StatementDone()
+			p.addComment(text)
 		case ruleAction2:
-			p.addDeclarationIdentifier(text)
+			p.StatementDone()
 		case ruleAction3:
-			p.addValue()
+			p.addDeclarationIdentifier(text)
 		case ruleAction4:
-			p.addAction(text)
+			p.addValue()
 		case ruleAction5:
-			p.addEntity(text)
+			p.addAction(text)
 		case ruleAction6:
-			p.addParamKey(text)
+			p.addEntity(text)
 		case ruleAction7:
-			p.addFirstValueInList()
+			p.addDeclarationIdentifier(text)
 		case ruleAction8:
-			p.lastValueInList()
+			p.addIncludePath(text)
 		case ruleAction9:
-			p.addFirstValueInList()
+			p.addIncludePath(text)
 		case ruleAction10:
-			p.lastValueInList()
+			p.addIncludePath(text)
 		case ruleAction11:
-			p.addAliasParam(text)
+			p.addOutputName(text)
 		case ruleAction12:
-			p.addParamRefValue(text)
+			p.addRetryAttempts(text)
 		case ruleAction13:
-			p.addParamCidrValue(text)
+			p.addRetryBackoff(text)
 		case ruleAction14:
-			p.addParamIpValue(text)
+			p.NewStatement(p.positionAt(token.begin))
 		case ruleAction15:
-			p.addParamValue(text)
+			p.startIf()
 		case ruleAction16:
-			p.addParamValue(text)
+			p.NewStatement(p.positionAt(token.begin))
 		case ruleAction17:
-			p.addFirstValueInConcatenation()
+			p.startElseIf()
 		case ruleAction18:
-			p.lastValueInConcatenation()
+			p.startElse()
 		case ruleAction19:
-			p.addFirstValueInConcatenation()
+			p.NewStatement(p.positionAt(token.begin))
 		case ruleAction20:
-			p.lastValueInConcatenation()
+			p.addLoopVariable(text)
 		case ruleAction21:
-			p.addStringValue(text)
+			p.startFor()
 		case ruleAction22:
-			p.addParamHoleValue(text)
+			p.endBlock()
 		case ruleAction23:
-			p.addFirstValueInConcatenation()
+			p.missingBlockEnd()
 		case ruleAction24:
-			p.lastValueInConcatenation()
+			p.startOperands()
 		case ruleAction25:
-			p.addFirstValueInConcatenation()
+			p.endOperands(OrOperator)
 		case ruleAction26:
+			p.startOperands()
+		case ruleAction27:
+			p.endOperands(AndOperator)
+		case ruleAction28:
+			p.addNotCondition()
+		case ruleAction29:
+			p.addConditionValue()
+		case ruleAction30:
+			p.addComparisonOperator(text)
+		case ruleAction31:
+			p.addComparisonCondition()
+		case ruleAction32:
+			p.addTruthCondition()
+		case ruleAction33:
+			p.addParamRefValue(text)
+		case ruleAction34:
+			p.addAliasParam(text)
+		case ruleAction35:
+			p.addParamValue(text)
+		case ruleAction36:
+			p.addParamKey(text)
+		case ruleAction37:
+			p.addFirstValueInList()
+		case ruleAction38:
+			p.lastValueInList()
+		case ruleAction39:
+			p.addFirstValueInList()
+		case ruleAction40:
+			p.lastValueInList()
+		case ruleAction41:
+			p.addAliasParam(text)
+		case ruleAction42:
+			p.addParamRefValue(text)
+		case ruleAction43:
+			p.addParamCidrValue(text)
+		case ruleAction44:
+			p.addParamIpValue(text)
+		case ruleAction45:
+			p.addParamValue(text)
+		case ruleAction46:
+			p.addParamValue(text)
+		case ruleAction47:
+			p.addFirstValueInConcatenation()
+		case ruleAction48:
+			p.lastValueInConcatenation()
+		case ruleAction49:
+			p.addFirstValueInConcatenation()
+		case ruleAction50:
+			p.lastValueInConcatenation()
+		case ruleAction51:
+			p.addStringValue(text)
+		case ruleAction52:
+			p.addParamHoleValue(text)
+		case ruleAction53:
+			p.addFirstValueInConcatenation()
+		case ruleAction54:
 			p.lastValueInConcatenation()
+		case ruleAction55:
+			p.addFirstValueInConcatenation()
+		case ruleAction56:
+			p.lastValueInConcatenation()
+		case ruleAction57:
+			p.addFirstValueInFunction(text)
+		case ruleAction58:
+			p.lastValueInFunction()
+		case ruleAction59:
+			p.NewStatement(p.positionAt(token.begin))
+		case ruleAction60:
+			p.addComment("")
+		case ruleAction61:
+			p.StatementDone()
 
 		}
 	}
 	_, _, _, _, _ = buffer, _buffer, text, begin, end
 }
 
-func (p *Peg) Init() {
+func Pretty(pretty bool) func(*Peg) error {
+	return func(p *Peg) error {
+		p.Pretty = pretty
+		return nil
+	}
+}
+
+func Size(size int) func(*Peg) error {
+	return func(p *Peg) error {
+		p.tokens32 = tokens32{tree: make([]token32, 0, size)}
+		return nil
+	}
+}
+func (p *Peg) Init(options ...func(*Peg) error) error {
 	var (
 		max                  token32
 		position, tokenIndex uint32
 		buffer               []rune
 	)
+	for _, option := range options {
+		err := option(p)
+		if err != nil {
+			return err
+		}
+	}
 	p.reset = func() {
 		max = token32{}
 		position, tokenIndex = 0, 0
@@ -445,7 +658,7 @@ func (p *Peg) Init() {
 	p.reset()
 
 	_rules := p.rules
-	tree := tokens32{tree: make([]token32, math.MaxInt16)}
+	tree := p.tokens32
 	p.parse = func(rule ...int) error {
 		r := 1
 		if len(rule) > 0 {
@@ -494,2067 +707,4170 @@ func (p *Peg) Init() {
 
 	_rules = [...]func() bool{
 		nil,
-		/* 0 Script <- <((BlankLine* Statement BlankLine*)+ WhiteSpacing EndOfFile)> */
+		/* 0 Script <- <(Lines WhiteSpacing EndOfFile)> */
 		func() bool {
 			position0, tokenIndex0 := position, tokenIndex
 			{
 				position1 := position
+				if !_rules[ruleLines]() {
+					goto l0
+				}
+				if !_rules[ruleWhiteSpacing]() {
+					goto l0
+				}
+				if !_rules[ruleEndOfFile]() {
+					goto l0
+				}
+				add(ruleScript, position1)
+			}
+			return true
+		l0:
+			position, tokenIndex = position0, tokenIndex0
+			return false
+		},
+		/* 1 Lines <- <(BlankLine / IfBlock / ForBlock / StatementsLine)*> */
+		func() bool {
+			{
+				position3 := position
 			l4:
 				{
 					position5, tokenIndex5 := position, tokenIndex
-					if !_rules[ruleBlankLine]() {
-						goto l5
-					}
-					goto l4
-				l5:
-					position, tokenIndex = position5, tokenIndex5
-				}
-				{
-					position6 := position
 					{
-						add(ruleAction0, position)
-					}
-					if !_rules[ruleWhiteSpacing]() {
-						goto l0
-					}
-					{
-						position8, tokenIndex8 := position, tokenIndex
-						if !_rules[ruleCmdExpr]() {
-							goto l9
+						position6, tokenIndex6 := position, tokenIndex
+						{
+							position8 := position
+							{
+								add(ruleAction59, position)
+							}
+							if !_rules[ruleWhiteSpacing]() {
+								goto l7
+							}
+							if !_rules[ruleEndOfLine]() {
+								goto l7
+							}
+							{
+								add(ruleAction60, position)
+							}
+							{
+								add(ruleAction61, position)
+							}
+							add(ruleBlankLine, position8)
 						}
-						goto l8
-					l9:
-						position, tokenIndex = position8, tokenIndex8
+						goto l6
+					l7:
+						position, tokenIndex = position6, tokenIndex6
 						{
-							position11 := position
+							position13 := position
 							{
-								position12 := position
-								if !_rules[ruleIdentifier]() {
-									goto l10
-								}
-								add(rulePegText, position12)
+								add(ruleAction14, position)
+							}
+							if !_rules[ruleWhiteSpacing]() {
+								goto l12
+							}
+							if buffer[position] != rune('i') {
+								goto l12
+							}
+							position++
+							if buffer[position] != rune('f') {
+								goto l12
+							}
+							position++
+							if !_rules[ruleMustWhiteSpacing]() {
+								goto l12
 							}
+							if !_rules[ruleCondition]() {
+								goto l12
+							}
+							if !_rules[ruleWhiteSpacing]() {
+								goto l12
+							}
+							if buffer[position] != rune('{') {
+								goto l12
+							}
+							position++
 							{
-								add(ruleAction2, position)
+								add(ruleAction15, position)
+							}
+							if !_rules[ruleBlockLineEnd]() {
+								goto l12
 							}
-							if !_rules[ruleEqual]() {
-								goto l10
+							if !_rules[ruleLines]() {
+								goto l12
 							}
 							{
-								position14, tokenIndex14 := position, tokenIndex
-								if !_rules[ruleCmdExpr]() {
-									goto l15
-								}
-								goto l14
-							l15:
-								position, tokenIndex = position14, tokenIndex14
-								{
-									position16 := position
-									{
-										add(ruleAction3, position)
-									}
-									if !_rules[ruleCompositeValue]() {
-										goto l10
-									}
-									add(ruleValueExpr, position16)
+								position16, tokenIndex16 := position, tokenIndex
+								if !_rules[ruleElseBlock]() {
+									goto l16
 								}
+								goto l17
+							l16:
+								position, tokenIndex = position16, tokenIndex16
+							}
+						l17:
+							if !_rules[ruleBlockEnd]() {
+								goto l12
 							}
-						l14:
-							add(ruleDeclaration, position11)
+							add(ruleIfBlock, position13)
 						}
-						goto l8
-					l10:
-						position, tokenIndex = position8, tokenIndex8
+						goto l6
+					l12:
+						position, tokenIndex = position6, tokenIndex6
 						{
-							position18 := position
+							position19 := position
 							{
-								position19, tokenIndex19 := position, tokenIndex
-								if buffer[position] != rune('#') {
-									goto l20
-								}
-								position++
-							l21:
-								{
-									position22, tokenIndex22 := position, tokenIndex
-									{
-										position23, tokenIndex23 := position, tokenIndex
-										if !_rules[ruleEndOfLine]() {
-											goto l23
-										}
-										goto l22
-									l23:
-										position, tokenIndex = position23, tokenIndex23
-									}
-									if !matchDot() {
-										goto l22
-									}
-									goto l21
-								l22:
-									position, tokenIndex = position22, tokenIndex22
-								}
-								goto l19
-							l20:
-								position, tokenIndex = position19, tokenIndex19
-								if buffer[position] != rune('/') {
-									goto l0
-								}
-								position++
-								if buffer[position] != rune('/') {
-									goto l0
-								}
-								position++
-							l24:
-								{
-									position25, tokenIndex25 := position, tokenIndex
-									{
-										position26, tokenIndex26 := position, tokenIndex
-										if !_rules[ruleEndOfLine]() {
-											goto l26
-										}
-										goto l25
-									l26:
-										position, tokenIndex = position26, tokenIndex26
-									}
-									if !matchDot() {
-										goto l25
-									}
-									goto l24
-								l25:
-									position, tokenIndex = position25, tokenIndex25
+								add(ruleAction19, position)
+							}
+							if !_rules[ruleWhiteSpacing]() {
+								goto l18
+							}
+							if buffer[position] != rune('f') {
+								goto l18
+							}
+							position++
+							if buffer[position] != rune('o') {
+								goto l18
+							}
+							position++
+							if buffer[position] != rune('r') {
+								goto l18
+							}
+							position++
+							if !_rules[ruleMustWhiteSpacing]() {
+								goto l18
+							}
+							if buffer[position] != rune('$') {
+								goto l18
+							}
+							position++
+							{
+								position21 := position
+								if !_rules[ruleIdentifier]() {
+									goto l18
 								}
+								add(rulePegText, position21)
 							}
-						l19:
-							add(ruleComment, position18)
-						}
-					}
-				l8:
-					if !_rules[ruleWhiteSpacing]() {
-						goto l0
-					}
-				l27:
-					{
-						position28, tokenIndex28 := position, tokenIndex
-						if !_rules[ruleEndOfLine]() {
-							goto l28
-						}
-						goto l27
-					l28:
-						position, tokenIndex = position28, tokenIndex28
-					}
-					{
-						add(ruleAction1, position)
-					}
-					add(ruleStatement, position6)
-				}
-			l30:
-				{
-					position31, tokenIndex31 := position, tokenIndex
-					if !_rules[ruleBlankLine]() {
-						goto l31
-					}
-					goto l30
-				l31:
-					position, tokenIndex = position31, tokenIndex31
-				}
-			l2:
-				{
-					position3, tokenIndex3 := position, tokenIndex
-				l32:
-					{
-						position33, tokenIndex33 := position, tokenIndex
-						if !_rules[ruleBlankLine]() {
-							goto l33
-						}
-						goto l32
-					l33:
-						position, tokenIndex = position33, tokenIndex33
-					}
-					{
-						position34 := position
-						{
-							add(ruleAction0, position)
-						}
-						if !_rules[ruleWhiteSpacing]() {
-							goto l3
+							{
+								add(ruleAction20, position)
+							}
+							if !_rules[ruleMustWhiteSpacing]() {
+								goto l18
+							}
+							if buffer[position] != rune('i') {
+								goto l18
+							}
+							position++
+							if buffer[position] != rune('n') {
+								goto l18
+							}
+							position++
+							if !_rules[ruleMustWhiteSpacing]() {
+								goto l18
+							}
+							if !_rules[ruleCompositeValue]() {
+								goto l18
+							}
+							if !_rules[ruleWhiteSpacing]() {
+								goto l18
+							}
+							if buffer[position] != rune('{') {
+								goto l18
+							}
+							position++
+							{
+								add(ruleAction21, position)
+							}
+							if !_rules[ruleBlockLineEnd]() {
+								goto l18
+							}
+							if !_rules[ruleLines]() {
+								goto l18
+							}
+							if !_rules[ruleBlockEnd]() {
+								goto l18
+							}
+							add(ruleForBlock, position19)
 						}
+						goto l6
+					l18:
+						position, tokenIndex = position6, tokenIndex6
 						{
-							position36, tokenIndex36 := position, tokenIndex
-							if !_rules[ruleCmdExpr]() {
-								goto l37
-							}
-							goto l36
-						l37:
-							position, tokenIndex = position36, tokenIndex36
+							position24 := position
 							{
-								position39 := position
+								position27 := position
 								{
-									position40 := position
-									if !_rules[ruleIdentifier]() {
-										goto l38
-									}
-									add(rulePegText, position40)
-								}
-								{
-									add(ruleAction2, position)
+									add(ruleAction0, position)
 								}
-								if !_rules[ruleEqual]() {
-									goto l38
+								if !_rules[ruleWhiteSpacing]() {
+									goto l5
 								}
 								{
-									position42, tokenIndex42 := position, tokenIndex
-									if !_rules[ruleCmdExpr]() {
-										goto l43
-									}
-									goto l42
-								l43:
-									position, tokenIndex = position42, tokenIndex42
+									position29, tokenIndex29 := position, tokenIndex
 									{
-										position44 := position
+										position31 := position
 										{
-											add(ruleAction3, position)
+											position32, tokenIndex32 := position, tokenIndex
+											{
+												position34 := position
+												if !_rules[ruleIdentifier]() {
+													goto l32
+												}
+												add(rulePegText, position34)
+											}
+											{
+												add(ruleAction7, position)
+											}
+											if !_rules[ruleEqual]() {
+												goto l32
+											}
+											goto l33
+										l32:
+											position, tokenIndex = position32, tokenIndex32
 										}
-										if !_rules[ruleCompositeValue]() {
-											goto l38
+									l33:
+										if buffer[position] != rune('i') {
+											goto l30
+										}
+										position++
+										if buffer[position] != rune('n') {
+											goto l30
+										}
+										position++
+										if buffer[position] != rune('c') {
+											goto l30
+										}
+										position++
+										if buffer[position] != rune('l') {
+											goto l30
+										}
+										position++
+										if buffer[position] != rune('u') {
+											goto l30
+										}
+										position++
+										if buffer[position] != rune('d') {
+											goto l30
+										}
+										position++
+										if buffer[position] != rune('e') {
+											goto l30
+										}
+										position++
+										if !_rules[ruleMustWhiteSpacing]() {
+											goto l30
 										}
-										add(ruleValueExpr, position44)
-									}
-								}
-							l42:
-								add(ruleDeclaration, position39)
-							}
-							goto l36
-						l38:
-							position, tokenIndex = position36, tokenIndex36
-							{
-								position46 := position
-								{
-									position47, tokenIndex47 := position, tokenIndex
-									if buffer[position] != rune('#') {
-										goto l48
-									}
-									position++
-								l49:
-									{
-										position50, tokenIndex50 := position, tokenIndex
 										{
-											position51, tokenIndex51 := position, tokenIndex
-											if !_rules[ruleEndOfLine]() {
-												goto l51
+											position36 := position
+											{
+												position37, tokenIndex37 := position, tokenIndex
+												if !_rules[ruleDoubleQuote]() {
+													goto l38
+												}
+												{
+													position39 := position
+												l40:
+													{
+														position41, tokenIndex41 := position, tokenIndex
+														{
+															position42, tokenIndex42 := position, tokenIndex
+															if buffer[position] != rune('"') {
+																goto l42
+															}
+															position++
+															goto l41
+														l42:
+															position, tokenIndex = position42, tokenIndex42
+														}
+														if !matchDot() {
+															goto l41
+														}
+														goto l40
+													l41:
+														position, tokenIndex = position41, tokenIndex41
+													}
+													add(rulePegText, position39)
+												}
+												if !_rules[ruleDoubleQuote]() {
+													goto l38
+												}
+												{
+													add(ruleAction8, position)
+												}
+												goto l37
+											l38:
+												position, tokenIndex = position37, tokenIndex37
+												if !_rules[ruleSingleQuote]() {
+													goto l44
+												}
+												{
+													position45 := position
+												l46:
+													{
+														position47, tokenIndex47 := position, tokenIndex
+														{
+															position48, tokenIndex48 := position, tokenIndex
+															if buffer[position] != rune('\'') {
+																goto l48
+															}
+															position++
+															goto l47
+														l48:
+															position, tokenIndex = position48, tokenIndex48
+														}
+														if !matchDot() {
+															goto l47
+														}
+														goto l46
+													l47:
+														position, tokenIndex = position47, tokenIndex47
+													}
+													add(rulePegText, position45)
+												}
+												if !_rules[ruleSingleQuote]() {
+													goto l44
+												}
+												{
+													add(ruleAction9, position)
+												}
+												goto l37
+											l44:
+												position, tokenIndex = position37, tokenIndex37
+												{
+													position50 := position
+													{
+														position53, tokenIndex53 := position, tokenIndex
+														{
+															switch buffer[position] {
+															case '#':
+																if buffer[position] != rune('#') {
+																	goto l53
+																}
+																position++
+															case '"':
+																if buffer[position] != rune('"') {
+																	goto l53
+																}
+																position++
+															case '\'':
+																if buffer[position] != rune('\'') {
+																	goto l53
+																}
+																position++
+															case '\n':
+																if buffer[position] != rune('\n') {
+																	goto l53
+																}
+																position++
+															case '\r':
+																if buffer[position] != rune('\r') {
+																	goto l53
+																}
+																position++
+															case '\t':
+																if buffer[position] != rune('\t') {
+																	goto l53
+																}
+																position++
+															default:
+																if buffer[position] != rune(' ') {
+																	goto l53
+																}
+																position++
+															}
+														}
+
+														goto l30
+													l53:
+														position, tokenIndex = position53, tokenIndex53
+													}
+													if !matchDot() {
+														goto l30
+													}
+												l51:
+													{
+														position52, tokenIndex52 := position, tokenIndex
+														{
+															position55, tokenIndex55 := position, tokenIndex
+															{
+																switch buffer[position] {
+																case '#':
+																	if buffer[position] != rune('#') {
+																		goto l55
+																	}
+																	position++
+																case '"':
+																	if buffer[position] != rune('"') {
+																		goto l55
+																	}
+																	position++
+																case '\'':
+																	if buffer[position] != rune('\'') {
+																		goto l55
+																	}
+																	position++
+																case '\n':
+																	if buffer[position] != rune('\n') {
+																		goto l55
+																	}
+																	position++
+																case '\r':
+																	if buffer[position] != rune('\r') {
+																		goto l55
+																	}
+																	position++
+																case '\t':
+																	if buffer[position] != rune('\t') {
+																		goto l55
+																	}
+																	position++
+																default:
+																	if buffer[position] != rune(' ') {
+																		goto l55
+																	}
+																	position++
+																}
+															}
+
+															goto l52
+														l55:
+															position, tokenIndex = position55, tokenIndex55
+														}
+														if !matchDot() {
+															goto l52
+														}
+														goto l51
+													l52:
+														position, tokenIndex = position52, tokenIndex52
+													}
+													add(rulePegText, position50)
+												}
+												{
+													add(ruleAction10, position)
+												}
 											}
-											goto l50
-										l51:
-											position, tokenIndex = position51, tokenIndex51
+										l37:
+											add(ruleIncludePath, position36)
 										}
-										if !matchDot() {
-											goto l50
+										{
+											position58, tokenIndex58 := position, tokenIndex
+											if !_rules[ruleMustWhiteSpacing]() {
+												goto l58
+											}
+											if buffer[position] != rune('w') {
+												goto l58
+											}
+											position++
+											if buffer[position] != rune('i') {
+												goto l58
+											}
+											position++
+											if buffer[position] != rune('t') {
+												goto l58
+											}
+											position++
+											if buffer[position] != rune('h') {
+												goto l58
+											}
+											position++
+											if !_rules[ruleMustWhiteSpacing]() {
+												goto l58
+											}
+											if !_rules[ruleParams]() {
+												goto l58
+											}
+											goto l59
+										l58:
+											position, tokenIndex = position58, tokenIndex58
 										}
-										goto l49
-									l50:
-										position, tokenIndex = position50, tokenIndex50
-									}
-									goto l47
-								l48:
-									position, tokenIndex = position47, tokenIndex47
-									if buffer[position] != rune('/') {
-										goto l3
+									l59:
+										add(ruleInclude, position31)
 									}
-									position++
-									if buffer[position] != rune('/') {
-										goto l3
-									}
-									position++
-								l52:
+									goto l29
+								l30:
+									position, tokenIndex = position29, tokenIndex29
 									{
-										position53, tokenIndex53 := position, tokenIndex
-										{
-											position54, tokenIndex54 := position, tokenIndex
-											if !_rules[ruleEndOfLine]() {
-												goto l54
-											}
-											goto l53
-										l54:
-											position, tokenIndex = position54, tokenIndex54
+										position61 := position
+										if buffer[position] != rune('o') {
+											goto l60
 										}
-										if !matchDot() {
-											goto l53
+										position++
+										if buffer[position] != rune('u') {
+											goto l60
 										}
-										goto l52
-									l53:
-										position, tokenIndex = position53, tokenIndex53
-									}
-								}
-							l47:
-								add(ruleComment, position46)
-							}
-						}
-					l36:
-						if !_rules[ruleWhiteSpacing]() {
-							goto l3
+										position++
+										if buffer[position] != rune('t') {
+											goto l60
+										}
+										position++
+										if buffer[position] != rune('p') {
+											goto l60
+										}
+										position++
+										if buffer[position] != rune('u') {
+											goto l60
+										}
+										position++
+										if buffer[position] != rune('t') {
+											goto l60
+										}
+										position++
+										if !_rules[ruleMustWhiteSpacing]() {
+											goto l60
+										}
+										{
+											position62 := position
+											if !_rules[ruleIdentifier]() {
+												goto l60
+											}
+											add(rulePegText, position62)
+										}
+										{
+											add(ruleAction11, position)
+										}
+										if !_rules[ruleEqual]() {
+											goto l60
+										}
+										if !_rules[ruleCompositeValue]() {
+											goto l60
+										}
+										add(ruleOutput, position61)
+									}
+									goto l29
+								l60:
+									position, tokenIndex = position29, tokenIndex29
+									if !_rules[ruleCmdExpr]() {
+										goto l64
+									}
+									goto l29
+								l64:
+									position, tokenIndex = position29, tokenIndex29
+									{
+										position66 := position
+										{
+											position67 := position
+											if !_rules[ruleIdentifier]() {
+												goto l65
+											}
+											add(rulePegText, position67)
+										}
+										{
+											add(ruleAction3, position)
+										}
+										if !_rules[ruleEqual]() {
+											goto l65
+										}
+										{
+											position69, tokenIndex69 := position, tokenIndex
+											if !_rules[ruleCmdExpr]() {
+												goto l70
+											}
+											goto l69
+										l70:
+											position, tokenIndex = position69, tokenIndex69
+											{
+												position71 := position
+												{
+													add(ruleAction4, position)
+												}
+												if !_rules[ruleCompositeValue]() {
+													goto l65
+												}
+												add(ruleValueExpr, position71)
+											}
+										}
+									l69:
+										add(ruleDeclaration, position66)
+									}
+									goto l29
+								l65:
+									position, tokenIndex = position29, tokenIndex29
+									{
+										position73 := position
+										{
+											position74 := position
+											{
+												position75, tokenIndex75 := position, tokenIndex
+												if buffer[position] != rune('#') {
+													goto l76
+												}
+												position++
+											l77:
+												{
+													position78, tokenIndex78 := position, tokenIndex
+													{
+														position79, tokenIndex79 := position, tokenIndex
+														if !_rules[ruleEndOfLine]() {
+															goto l79
+														}
+														goto l78
+													l79:
+														position, tokenIndex = position79, tokenIndex79
+													}
+													if !matchDot() {
+														goto l78
+													}
+													goto l77
+												l78:
+													position, tokenIndex = position78, tokenIndex78
+												}
+												goto l75
+											l76:
+												position, tokenIndex = position75, tokenIndex75
+												if buffer[position] != rune('/') {
+													goto l5
+												}
+												position++
+												if buffer[position] != rune('/') {
+													goto l5
+												}
+												position++
+											l80:
+												{
+													position81, tokenIndex81 := position, tokenIndex
+													{
+														position82, tokenIndex82 := position, tokenIndex
+														if !_rules[ruleEndOfLine]() {
+															goto l82
+														}
+														goto l81
+													l82:
+														position, tokenIndex = position82, tokenIndex82
+													}
+													if !matchDot() {
+														goto l81
+													}
+													goto l80
+												l81:
+													position, tokenIndex = position81, tokenIndex81
+												}
+											}
+										l75:
+											add(ruleComment, position74)
+										}
+										add(rulePegText, position73)
+									}
+									{
+										add(ruleAction1, position)
+									}
+								}
+							l29:
+								{
+									position84, tokenIndex84 := position, tokenIndex
+									{
+										position86 := position
+										if !_rules[ruleWhiteSpacing]() {
+											goto l84
+										}
+										if buffer[position] != rune('r') {
+											goto l84
+										}
+										position++
+										if buffer[position] != rune('e') {
+											goto l84
+										}
+										position++
+										if buffer[position] != rune('t') {
+											goto l84
+										}
+										position++
+										if buffer[position] != rune('r') {
+											goto l84
+										}
+										position++
+										if buffer[position] != rune('y') {
+											goto l84
+										}
+										position++
+										if !_rules[ruleMustWhiteSpacing]() {
+											goto l84
+										}
+										{
+											position87 := position
+											if c := buffer[position]; c < rune('0') || c > rune('9') {
+												goto l84
+											}
+											position++
+										l88:
+											{
+												position89, tokenIndex89 := position, tokenIndex
+												if c := buffer[position]; c < rune('0') || c > rune('9') {
+													goto l89
+												}
+												position++
+												goto l88
+											l89:
+												position, tokenIndex = position89, tokenIndex89
+											}
+											add(rulePegText, position87)
+										}
+										{
+											add(ruleAction12, position)
+										}
+										{
+											position91, tokenIndex91 := position, tokenIndex
+											if !_rules[ruleMustWhiteSpacing]() {
+												goto l91
+											}
+											if buffer[position] != rune('b') {
+												goto l91
+											}
+											position++
+											if buffer[position] != rune('a') {
+												goto l91
+											}
+											position++
+											if buffer[position] != rune('c') {
+												goto l91
+											}
+											position++
+											if buffer[position] != rune('k') {
+												goto l91
+											}
+											position++
+											if buffer[position] != rune('o') {
+												goto l91
+											}
+											position++
+											if buffer[position] != rune('f') {
+												goto l91
+											}
+											position++
+											if buffer[position] != rune('f') {
+												goto l91
+											}
+											position++
+											if !_rules[ruleMustWhiteSpacing]() {
+												goto l91
+											}
+											{
+												position93 := position
+												{
+													position96, tokenIndex96 := position, tokenIndex
+													{
+														switch buffer[position] {
+														case '#':
+															if buffer[position] != rune('#') {
+																goto l96
+															}
+															position++
+														case '\n':
+															if buffer[position] != rune('\n') {
+																goto l96
+															}
+															position++
+														case '\r':
+															if buffer[position] != rune('\r') {
+																goto l96
+															}
+															position++
+														case '\t':
+															if buffer[position] != rune('\t') {
+																goto l96
+															}
+															position++
+														default:
+															if buffer[position] != rune(' ') {
+																goto l96
+															}
+															position++
+														}
+													}
+
+													goto l91
+												l96:
+													position, tokenIndex = position96, tokenIndex96
+												}
+												if !matchDot() {
+													goto l91
+												}
+											l94:
+												{
+													position95, tokenIndex95 := position, tokenIndex
+													{
+														position98, tokenIndex98 := position, tokenIndex
+														{
+															switch buffer[position] {
+															case '#':
+																if buffer[position] != rune('#') {
+																	goto l98
+																}
+																position++
+															case '\n':
+																if buffer[position] != rune('\n') {
+																	goto l98
+																}
+																position++
+															case '\r':
+																if buffer[position] != rune('\r') {
+																	goto l98
+																}
+																position++
+															case '\t':
+																if buffer[position] != rune('\t') {
+																	goto l98
+																}
+																position++
+															default:
+																if buffer[position] != rune(' ') {
+																	goto l98
+																}
+																position++
+															}
+														}
+
+														goto l95
+													l98:
+														position, tokenIndex = position98, tokenIndex98
+													}
+													if !matchDot() {
+														goto l95
+													}
+													goto l94
+												l95:
+													position, tokenIndex = position95, tokenIndex95
+												}
+												add(rulePegText, position93)
+											}
+											{
+												add(ruleAction13, position)
+											}
+											goto l92
+										l91:
+											position, tokenIndex = position91, tokenIndex91
+										}
+									l92:
+										add(ruleRetry, position86)
+									}
+									goto l85
+								l84:
+									position, tokenIndex = position84, tokenIndex84
+								}
+							l85:
+								if !_rules[ruleWhiteSpacing]() {
+									goto l5
+								}
+								{
+									add(ruleAction2, position)
+								}
+								add(ruleStatement, position27)
+							}
+						l25:
+							{
+								position26, tokenIndex26 := position, tokenIndex
+								{
+									position102 := position
+									{
+										add(ruleAction0, position)
+									}
+									if !_rules[ruleWhiteSpacing]() {
+										goto l26
+									}
+									{
+										position104, tokenIndex104 := position, tokenIndex
+										{
+											position106 := position
+											{
+												position107, tokenIndex107 := position, tokenIndex
+												{
+													position109 := position
+													if !_rules[ruleIdentifier]() {
+														goto l107
+													}
+													add(rulePegText, position109)
+												}
+												{
+													add(ruleAction7, position)
+												}
+												if !_rules[ruleEqual]() {
+													goto l107
+												}
+												goto l108
+											l107:
+												position, tokenIndex = position107, tokenIndex107
+											}
+										l108:
+											if buffer[position] != rune('i') {
+												goto l105
+											}
+											position++
+											if buffer[position] != rune('n') {
+												goto l105
+											}
+											position++
+											if buffer[position] != rune('c') {
+												goto l105
+											}
+											position++
+											if buffer[position] != rune('l') {
+												goto l105
+											}
+											position++
+											if buffer[position] != rune('u') {
+												goto l105
+											}
+											position++
+											if buffer[position] != rune('d') {
+												goto l105
+											}
+											position++
+											if buffer[position] != rune('e') {
+												goto l105
+											}
+											position++
+											if !_rules[ruleMustWhiteSpacing]() {
+												goto l105
+											}
+											{
+												position111 := position
+												{
+													position112, tokenIndex112 := position, tokenIndex
+													if !_rules[ruleDoubleQuote]() {
+														goto l113
+													}
+													{
+														position114 := position
+													l115:
+														{
+															position116, tokenIndex116 := position, tokenIndex
+															{
+																position117, tokenIndex117 := position, tokenIndex
+																if buffer[position] != rune('"') {
+																	goto l117
+																}
+																position++
+																goto l116
+															l117:
+																position, tokenIndex = position117, tokenIndex117
+															}
+															if !matchDot() {
+																goto l116
+															}
+															goto l115
+														l116:
+															position, tokenIndex = position116, tokenIndex116
+														}
+														add(rulePegText, position114)
+													}
+													if !_rules[ruleDoubleQuote]() {
+														goto l113
+													}
+													{
+														add(ruleAction8, position)
+													}
+													goto l112
+												l113:
+													position, tokenIndex = position112, tokenIndex112
+													if !_rules[ruleSingleQuote]() {
+														goto l119
+													}
+													{
+														position120 := position
+													l121:
+														{
+															position122, tokenIndex122 := position, tokenIndex
+															{
+																position123, tokenIndex123 := position, tokenIndex
+																if buffer[position] != rune('\'') {
+																	goto l123
+																}
+																position++
+																goto l122
+															l123:
+																position, tokenIndex = position123, tokenIndex123
+															}
+															if !matchDot() {
+																goto l122
+															}
+															goto l121
+														l122:
+															position, tokenIndex = position122, tokenIndex122
+														}
+														add(rulePegText, position120)
+													}
+													if !_rules[ruleSingleQuote]() {
+														goto l119
+													}
+													{
+														add(ruleAction9, position)
+													}
+													goto l112
+												l119:
+													position, tokenIndex = position112, tokenIndex112
+													{
+														position125 := position
+														{
+															position128, tokenIndex128 := position, tokenIndex
+															{
+																switch buffer[position] {
+																case '#':
+																	if buffer[position] != rune('#') {
+																		goto l128
+																	}
+																	position++
+																case '"':
+																	if buffer[position] != rune('"') {
+																		goto l128
+																	}
+																	position++
+																case '\'':
+																	if buffer[position] != rune('\'') {
+																		goto l128
+																	}
+																	position++
+																case '\n':
+																	if buffer[position] != rune('\n') {
+																		goto l128
+																	}
+																	position++
+																case '\r':
+																	if buffer[position] != rune('\r') {
+																		goto l128
+																	}
+																	position++
+																case '\t':
+																	if buffer[position] != rune('\t') {
+																		goto l128
+																	}
+																	position++
+																default:
+																	if buffer[position] != rune(' ') {
+																		goto l128
+																	}
+																	position++
+																}
+															}
+
+															goto l105
+														l128:
+															position, tokenIndex = position128, tokenIndex128
+														}
+														if !matchDot() {
+															goto l105
+														}
+													l126:
+														{
+															position127, tokenIndex127 := position, tokenIndex
+															{
+																position130, tokenIndex130 := position, tokenIndex
+																{
+																	switch buffer[position] {
+																	case '#':
+																		if buffer[position] != rune('#') {
+																			goto l130
+																		}
+																		position++
+																	case '"':
+																		if buffer[position] != rune('"') {
+																			goto l130
+																		}
+																		position++
+																	case '\'':
+																		if buffer[position] != rune('\'') {
+																			goto l130
+																		}
+																		position++
+																	case '\n':
+																		if buffer[position] != rune('\n') {
+																			goto l130
+																		}
+																		position++
+																	case '\r':
+																		if buffer[position] != rune('\r') {
+																			goto l130
+																		}
+																		position++
+																	case '\t':
+																		if buffer[position] != rune('\t') {
+																			goto l130
+																		}
+																		position++
+																	default:
+																		if buffer[position] != rune(' ') {
+																			goto l130
+																		}
+																		position++
+																	}
+																}
+
+																goto l127
+															l130:
+																position, tokenIndex = position130, tokenIndex130
+															}
+															if !matchDot() {
+																goto l127
+															}
+															goto l126
+														l127:
+															position, tokenIndex = position127, tokenIndex127
+														}
+														add(rulePegText, position125)
+													}
+													{
+														add(ruleAction10, position)
+													}
+												}
+											l112:
+												add(ruleIncludePath, position111)
+											}
+											{
+												position133, tokenIndex133 := position, tokenIndex
+												if !_rules[ruleMustWhiteSpacing]() {
+													goto l133
+												}
+												if buffer[position] != rune('w') {
+													goto l133
+												}
+												position++
+												if buffer[position] != rune('i') {
+													goto l133
+												}
+												position++
+												if buffer[position] != rune('t') {
+													goto l133
+												}
+												position++
+												if buffer[position] != rune('h') {
+													goto l133
+												}
+												position++
+												if !_rules[ruleMustWhiteSpacing]() {
+													goto l133
+												}
+												if !_rules[ruleParams]() {
+													goto l133
+												}
+												goto l134
+											l133:
+												position, tokenIndex = position133, tokenIndex133
+											}
+										l134:
+											add(ruleInclude, position106)
+										}
+										goto l104
+									l105:
+										position, tokenIndex = position104, tokenIndex104
+										{
+											position136 := position
+											if buffer[position] != rune('o') {
+												goto l135
+											}
+											position++
+											if buffer[position] != rune('u') {
+												goto l135
+											}
+											position++
+											if buffer[position] != rune('t') {
+												goto l135
+											}
+											position++
+											if buffer[position] != rune('p') {
+												goto l135
+											}
+											position++
+											if buffer[position] != rune('u') {
+												goto l135
+											}
+											position++
+											if buffer[position] != rune('t') {
+												goto l135
+											}
+											position++
+											if !_rules[ruleMustWhiteSpacing]() {
+												goto l135
+											}
+											{
+												position137 := position
+												if !_rules[ruleIdentifier]() {
+													goto l135
+												}
+												add(rulePegText, position137)
+											}
+											{
+												add(ruleAction11, position)
+											}
+											if !_rules[ruleEqual]() {
+												goto l135
+											}
+											if !_rules[ruleCompositeValue]() {
+												goto l135
+											}
+											add(ruleOutput, position136)
+										}
+										goto l104
+									l135:
+										position, tokenIndex = position104, tokenIndex104
+										if !_rules[ruleCmdExpr]() {
+											goto l139
+										}
+										goto l104
+									l139:
+										position, tokenIndex = position104, tokenIndex104
+										{
+											position141 := position
+											{
+												position142 := position
+												if !_rules[ruleIdentifier]() {
+													goto l140
+												}
+												add(rulePegText, position142)
+											}
+											{
+												add(ruleAction3, position)
+											}
+											if !_rules[ruleEqual]() {
+												goto l140
+											}
+											{
+												position144, tokenIndex144 := position, tokenIndex
+												if !_rules[ruleCmdExpr]() {
+													goto l145
+												}
+												goto l144
+											l145:
+												position, tokenIndex = position144, tokenIndex144
+												{
+													position146 := position
+													{
+														add(ruleAction4, position)
+													}
+													if !_rules[ruleCompositeValue]() {
+														goto l140
+													}
+													add(ruleValueExpr, position146)
+												}
+											}
+										l144:
+											add(ruleDeclaration, position141)
+										}
+										goto l104
+									l140:
+										position, tokenIndex = position104, tokenIndex104
+										{
+											position148 := position
+											{
+												position149 := position
+												{
+													position150, tokenIndex150 := position, tokenIndex
+													if buffer[position] != rune('#') {
+														goto l151
+													}
+													position++
+												l152:
+													{
+														position153, tokenIndex153 := position, tokenIndex
+														{
+															position154, tokenIndex154 := position, tokenIndex
+															if !_rules[ruleEndOfLine]() {
+																goto l154
+															}
+															goto l153
+														l154:
+															position, tokenIndex = position154, tokenIndex154
+														}
+														if !matchDot() {
+															goto l153
+														}
+														goto l152
+													l153:
+														position, tokenIndex = position153, tokenIndex153
+													}
+													goto l150
+												l151:
+													position, tokenIndex = position150, tokenIndex150
+													if buffer[position] != rune('/') {
+														goto l26
+													}
+													position++
+													if buffer[position] != rune('/') {
+														goto l26
+													}
+													position++
+												l155:
+													{
+														position156, tokenIndex156 := position, tokenIndex
+														{
+															position157, tokenIndex157 := position, tokenIndex
+															if !_rules[ruleEndOfLine]() {
+																goto l157
+															}
+															goto l156
+														l157:
+															position, tokenIndex = position157, tokenIndex157
+														}
+														if !matchDot() {
+															goto l156
+														}
+														goto l155
+													l156:
+														position, tokenIndex = position156, tokenIndex156
+													}
+												}
+											l150:
+												add(ruleComment, position149)
+											}
+											add(rulePegText, position148)
+										}
+										{
+											add(ruleAction1, position)
+										}
+									}
+								l104:
+									{
+										position159, tokenIndex159 := position, tokenIndex
+										{
+											position161 := position
+											if !_rules[ruleWhiteSpacing]() {
+												goto l159
+											}
+											if buffer[position] != rune('r') {
+												goto l159
+											}
+											position++
+											if buffer[position] != rune('e') {
+												goto l159
+											}
+											position++
+											if buffer[position] != rune('t') {
+												goto l159
+											}
+											position++
+											if buffer[position] != rune('r') {
+												goto l159
+											}
+											position++
+											if buffer[position] != rune('y') {
+												goto l159
+											}
+											position++
+											if !_rules[ruleMustWhiteSpacing]() {
+												goto l159
+											}
+											{
+												position162 := position
+												if c := buffer[position]; c < rune('0') || c > rune('9') {
+													goto l159
+												}
+												position++
+											l163:
+												{
+													position164, tokenIndex164 := position, tokenIndex
+													if c := buffer[position]; c < rune('0') || c > rune('9') {
+														goto l164
+													}
+													position++
+													goto l163
+												l164:
+													position, tokenIndex = position164, tokenIndex164
+												}
+												add(rulePegText, position162)
+											}
+											{
+												add(ruleAction12, position)
+											}
+											{
+												position166, tokenIndex166 := position, tokenIndex
+												if !_rules[ruleMustWhiteSpacing]() {
+													goto l166
+												}
+												if buffer[position] != rune('b') {
+													goto l166
+												}
+												position++
+												if buffer[position] != rune('a') {
+													goto l166
+												}
+												position++
+												if buffer[position] != rune('c') {
+													goto l166
+												}
+												position++
+												if buffer[position] != rune('k') {
+													goto l166
+												}
+												position++
+												if buffer[position] != rune('o') {
+													goto l166
+												}
+												position++
+												if buffer[position] != rune('f') {
+													goto l166
+												}
+												position++
+												if buffer[position] != rune('f') {
+													goto l166
+												}
+												position++
+												if !_rules[ruleMustWhiteSpacing]() {
+													goto l166
+												}
+												{
+													position168 := position
+													{
+														position171, tokenIndex171 := position, tokenIndex
+														{
+															switch buffer[position] {
+															case '#':
+																if buffer[position] != rune('#') {
+																	goto l171
+																}
+																position++
+															case '\n':
+																if buffer[position] != rune('\n') {
+																	goto l171
+																}
+																position++
+															case '\r':
+																if buffer[position] != rune('\r') {
+																	goto l171
+																}
+																position++
+															case '\t':
+																if buffer[position] != rune('\t') {
+																	goto l171
+																}
+																position++
+															default:
+																if buffer[position] != rune(' ') {
+																	goto l171
+																}
+																position++
+															}
+														}
+
+														goto l166
+													l171:
+														position, tokenIndex = position171, tokenIndex171
+													}
+													if !matchDot() {
+														goto l166
+													}
+												l169:
+													{
+														position170, tokenIndex170 := position, tokenIndex
+														{
+															position173, tokenIndex173 := position, tokenIndex
+															{
+																switch buffer[position] {
+																case '#':
+																	if buffer[position] != rune('#') {
+																		goto l173
+																	}
+																	position++
+																case '\n':
+																	if buffer[position] != rune('\n') {
+																		goto l173
+																	}
+																	position++
+																case '\r':
+																	if buffer[position] != rune('\r') {
+																		goto l173
+																	}
+																	position++
+																case '\t':
+																	if buffer[position] != rune('\t') {
+																		goto l173
+																	}
+																	position++
+																default:
+																	if buffer[position] != rune(' ') {
+																		goto l173
+																	}
+																	position++
+																}
+															}
+
+															goto l170
+														l173:
+															position, tokenIndex = position173, tokenIndex173
+														}
+														if !matchDot() {
+															goto l170
+														}
+														goto l169
+													l170:
+														position, tokenIndex = position170, tokenIndex170
+													}
+													add(rulePegText, position168)
+												}
+												{
+													add(ruleAction13, position)
+												}
+												goto l167
+											l166:
+												position, tokenIndex = position166, tokenIndex166
+											}
+										l167:
+											add(ruleRetry, position161)
+										}
+										goto l160
+									l159:
+										position, tokenIndex = position159, tokenIndex159
+									}
+								l160:
+									if !_rules[ruleWhiteSpacing]() {
+										goto l26
+									}
+									{
+										add(ruleAction2, position)
+									}
+									add(ruleStatement, position102)
+								}
+								goto l25
+							l26:
+								position, tokenIndex = position26, tokenIndex26
+							}
+							if !_rules[ruleLineEnd]() {
+								goto l5
+							}
+							add(ruleStatementsLine, position24)
+						}
+					}
+				l6:
+					goto l4
+				l5:
+					position, tokenIndex = position5, tokenIndex5
+				}
+				add(ruleLines, position3)
+			}
+			return true
+		},
+		/* 2 StatementsLine <- <(Statement+ LineEnd)> */
+		nil,
+		/* 3 Statement <- <(Action0 WhiteSpacing (Include / Output / CmdExpr / Declaration / (<Comment> Action1)) Retry? WhiteSpacing Action2)> */
+		nil,
+		/* 4 Action <- <[a-z]+> */
+		nil,
+		/* 5 Entity <- <([a-z] / [0-9])+> */
+		nil,
+		/* 6 Declaration <- <(<Identifier> Action3 Equal (CmdExpr / ValueExpr))> */
+		nil,
+		/* 7 ValueExpr <- <(Action4 CompositeValue)> */
+		nil,
+		/* 8 CmdExpr <- <(<Action> Action5 MustWhiteSpacing <Entity> Action6 (MustWhiteSpacing Params)?)> */
+		func() bool {
+			position183, tokenIndex183 := position, tokenIndex
+			{
+				position184 := position
+				{
+					position185 := position
+					{
+						position186 := position
+						if c := buffer[position]; c < rune('a') || c > rune('z') {
+							goto l183
+						}
+						position++
+					l187:
+						{
+							position188, tokenIndex188 := position, tokenIndex
+							if c := buffer[position]; c < rune('a') || c > rune('z') {
+								goto l188
+							}
+							position++
+							goto l187
+						l188:
+							position, tokenIndex = position188, tokenIndex188
+						}
+						add(ruleAction, position186)
+					}
+					add(rulePegText, position185)
+				}
+				{
+					add(ruleAction5, position)
+				}
+				if !_rules[ruleMustWhiteSpacing]() {
+					goto l183
+				}
+				{
+					position190 := position
+					{
+						position191 := position
+						{
+							position194, tokenIndex194 := position, tokenIndex
+							if c := buffer[position]; c < rune('a') || c > rune('z') {
+								goto l195
+							}
+							position++
+							goto l194
+						l195:
+							position, tokenIndex = position194, tokenIndex194
+							if c := buffer[position]; c < rune('0') || c > rune('9') {
+								goto l183
+							}
+							position++
+						}
+					l194:
+					l192:
+						{
+							position193, tokenIndex193 := position, tokenIndex
+							{
+								position196, tokenIndex196 := position, tokenIndex
+								if c := buffer[position]; c < rune('a') || c > rune('z') {
+									goto l197
+								}
+								position++
+								goto l196
+							l197:
+								position, tokenIndex = position196, tokenIndex196
+								if c := buffer[position]; c < rune('0') || c > rune('9') {
+									goto l193
+								}
+								position++
+							}
+						l196:
+							goto l192
+						l193:
+							position, tokenIndex = position193, tokenIndex193
+						}
+						add(ruleEntity, position191)
+					}
+					add(rulePegText, position190)
+				}
+				{
+					add(ruleAction6, position)
+				}
+				{
+					position199, tokenIndex199 := position, tokenIndex
+					if !_rules[ruleMustWhiteSpacing]() {
+						goto l199
+					}
+					if !_rules[ruleParams]() {
+						goto l199
+					}
+					goto l200
+				l199:
+					position, tokenIndex = position199, tokenIndex199
+				}
+			l200:
+				add(ruleCmdExpr, position184)
+			}
+			return true
+		l183:
+			position, tokenIndex = position183, tokenIndex183
+			return false
+		},
+		/* 9 Include <- <((<Identifier> Action7 Equal)? ('i' 'n' 'c' 'l' 'u' 'd' 'e') MustWhiteSpacing IncludePath (MustWhiteSpacing ('w' 'i' 't' 'h') MustWhiteSpacing Params)?)> */
+		nil,
+		/* 10 IncludePath <- <((DoubleQuote <(!'"' .)*> DoubleQuote Action8) / (SingleQuote <(!'\'' .)*> SingleQuote Action9) / (<(!((&('#') '#') | (&('"') '"') | (&('\'') '\'') | (&('\n') '\n') | (&('\r') '\r') | (&('\t') '\t') | (&(' ') ' ')) .)+> Action10))> */
+		nil,
+		/* 11 Output <- <('o' 'u' 't' 'p' 'u' 't' MustWhiteSpacing <Identifier> Action11 Equal CompositeValue)> */
+		nil,
+		/* 12 Retry <- <(WhiteSpacing ('r' 'e' 't' 'r' 'y') MustWhiteSpacing <[0-9]+> Action12 (MustWhiteSpacing ('b' 'a' 'c' 'k' 'o' 'f' 'f') MustWhiteSpacing <(!((&('#') '#') | (&('\n') '\n') | (&('\r') '\r') | (&('\t') '\t') | (&(' ') ' ')) .)+> Action13)?)> */
+		nil,
+		/* 13 IfBlock <- <(Action14 WhiteSpacing ('i' 'f') MustWhiteSpacing Condition WhiteSpacing '{' Action15 BlockLineEnd Lines ElseBlock? BlockEnd)> */
+		nil,
+		/* 14 ElseBlock <- <((Action16 WhiteSpacing '}' WhiteSpacing ('e' 'l' 's' 'e') MustWhiteSpacing ('i' 'f') MustWhiteSpacing Condition WhiteSpacing '{' Action17 BlockLineEnd Lines ElseBlock?) / (WhiteSpacing '}' WhiteSpacing ('e' 'l' 's' 'e') WhiteSpacing '{' Action18 BlockLineEnd Lines))> */
+		func() bool {
+			position206, tokenIndex206 := position, tokenIndex
+			{
+				position207 := position
+				{
+					position208, tokenIndex208 := position, tokenIndex
+					{
+						add(ruleAction16, position)
+					}
+					if !_rules[ruleWhiteSpacing]() {
+						goto l209
+					}
+					if buffer[position] != rune('}') {
+						goto l209
+					}
+					position++
+					if !_rules[ruleWhiteSpacing]() {
+						goto l209
+					}
+					if buffer[position] != rune('e') {
+						goto l209
+					}
+					position++
+					if buffer[position] != rune('l') {
+						goto l209
+					}
+					position++
+					if buffer[position] != rune('s') {
+						goto l209
+					}
+					position++
+					if buffer[position] != rune('e') {
+						goto l209
+					}
+					position++
+					if !_rules[ruleMustWhiteSpacing]() {
+						goto l209
+					}
+					if buffer[position] != rune('i') {
+						goto l209
+					}
+					position++
+					if buffer[position] != rune('f') {
+						goto l209
+					}
+					position++
+					if !_rules[ruleMustWhiteSpacing]() {
+						goto l209
+					}
+					if !_rules[ruleCondition]() {
+						goto l209
+					}
+					if !_rules[ruleWhiteSpacing]() {
+						goto l209
+					}
+					if buffer[position] != rune('{') {
+						goto l209
+					}
+					position++
+					{
+						add(ruleAction17, position)
+					}
+					if !_rules[ruleBlockLineEnd]() {
+						goto l209
+					}
+					if !_rules[ruleLines]() {
+						goto l209
+					}
+					{
+						position212, tokenIndex212 := position, tokenIndex
+						if !_rules[ruleElseBlock]() {
+							goto l212
 						}
-					l55:
+						goto l213
+					l212:
+						position, tokenIndex = position212, tokenIndex212
+					}
+				l213:
+					goto l208
+				l209:
+					position, tokenIndex = position208, tokenIndex208
+					if !_rules[ruleWhiteSpacing]() {
+						goto l206
+					}
+					if buffer[position] != rune('}') {
+						goto l206
+					}
+					position++
+					if !_rules[ruleWhiteSpacing]() {
+						goto l206
+					}
+					if buffer[position] != rune('e') {
+						goto l206
+					}
+					position++
+					if buffer[position] != rune('l') {
+						goto l206
+					}
+					position++
+					if buffer[position] != rune('s') {
+						goto l206
+					}
+					position++
+					if buffer[position] != rune('e') {
+						goto l206
+					}
+					position++
+					if !_rules[ruleWhiteSpacing]() {
+						goto l206
+					}
+					if buffer[position] != rune('{') {
+						goto l206
+					}
+					position++
+					{
+						add(ruleAction18, position)
+					}
+					if !_rules[ruleBlockLineEnd]() {
+						goto l206
+					}
+					if !_rules[ruleLines]() {
+						goto l206
+					}
+				}
+			l208:
+				add(ruleElseBlock, position207)
+			}
+			return true
+		l206:
+			position, tokenIndex = position206, tokenIndex206
+			return false
+		},
+		/* 15 ForBlock <- <(Action19 WhiteSpacing ('f' 'o' 'r') MustWhiteSpacing '$' <Identifier> Action20 MustWhiteSpacing ('i' 'n') MustWhiteSpacing CompositeValue WhiteSpacing '{' Action21 BlockLineEnd Lines BlockEnd)> */
+		nil,
+		/* 16 BlockLineEnd <- <(WhiteSpacing LineEnd)> */
+		func() bool {
+			position216, tokenIndex216 := position, tokenIndex
+			{
+				position217 := position
+				if !_rules[ruleWhiteSpacing]() {
+					goto l216
+				}
+				if !_rules[ruleLineEnd]() {
+					goto l216
+				}
+				add(ruleBlockLineEnd, position217)
+			}
+			return true
+		l216:
+			position, tokenIndex = position216, tokenIndex216
+			return false
+		},
+		/* 17 BlockEnd <- <((WhiteSpacing '}' Action22 BlockLineEnd) / (WhiteSpacing EndOfFile Action23))> */
+		func() bool {
+			position218, tokenIndex218 := position, tokenIndex
+			{
+				position219 := position
+				{
+					position220, tokenIndex220 := position, tokenIndex
+					if !_rules[ruleWhiteSpacing]() {
+						goto l221
+					}
+					if buffer[position] != rune('}') {
+						goto l221
+					}
+					position++
+					{
+						add(ruleAction22, position)
+					}
+					if !_rules[ruleBlockLineEnd]() {
+						goto l221
+					}
+					goto l220
+				l221:
+					position, tokenIndex = position220, tokenIndex220
+					if !_rules[ruleWhiteSpacing]() {
+						goto l218
+					}
+					if !_rules[ruleEndOfFile]() {
+						goto l218
+					}
+					{
+						add(ruleAction23, position)
+					}
+				}
+			l220:
+				add(ruleBlockEnd, position219)
+			}
+			return true
+		l218:
+			position, tokenIndex = position218, tokenIndex218
+			return false
+		},
+		/* 18 Condition <- <(Action24 AndCondition (WhiteSpacing ('|' '|') WhiteSpacing AndCondition)* Action25)> */
+		func() bool {
+			position224, tokenIndex224 := position, tokenIndex
+			{
+				position225 := position
+				{
+					add(ruleAction24, position)
+				}
+				if !_rules[ruleAndCondition]() {
+					goto l224
+				}
+			l227:
+				{
+					position228, tokenIndex228 := position, tokenIndex
+					if !_rules[ruleWhiteSpacing]() {
+						goto l228
+					}
+					if buffer[position] != rune('|') {
+						goto l228
+					}
+					position++
+					if buffer[position] != rune('|') {
+						goto l228
+					}
+					position++
+					if !_rules[ruleWhiteSpacing]() {
+						goto l228
+					}
+					if !_rules[ruleAndCondition]() {
+						goto l228
+					}
+					goto l227
+				l228:
+					position, tokenIndex = position228, tokenIndex228
+				}
+				{
+					add(ruleAction25, position)
+				}
+				add(ruleCondition, position225)
+			}
+			return true
+		l224:
+			position, tokenIndex = position224, tokenIndex224
+			return false
+		},
+		/* 19 AndCondition <- <(Action26 NotCondition (WhiteSpacing ('&' '&') WhiteSpacing NotCondition)* Action27)> */
+		func() bool {
+			position230, tokenIndex230 := position, tokenIndex
+			{
+				position231 := position
+				{
+					add(ruleAction26, position)
+				}
+				if !_rules[ruleNotCondition]() {
+					goto l230
+				}
+			l233:
+				{
+					position234, tokenIndex234 := position, tokenIndex
+					if !_rules[ruleWhiteSpacing]() {
+						goto l234
+					}
+					if buffer[position] != rune('&') {
+						goto l234
+					}
+					position++
+					if buffer[position] != rune('&') {
+						goto l234
+					}
+					position++
+					if !_rules[ruleWhiteSpacing]() {
+						goto l234
+					}
+					if !_rules[ruleNotCondition]() {
+						goto l234
+					}
+					goto l233
+				l234:
+					position, tokenIndex = position234, tokenIndex234
+				}
+				{
+					add(ruleAction27, position)
+				}
+				add(ruleAndCondition, position231)
+			}
+			return true
+		l230:
+			position, tokenIndex = position230, tokenIndex230
+			return false
+		},
+		/* 20 NotCondition <- <((ConditionValue Action29 WhiteSpacing <ComparisonOperator> Action30 WhiteSpacing ConditionValue Action31) / ((&('(') ('(' WhiteSpacing Condition WhiteSpacing ')')) | (&('!') ('!' WhiteSpacing NotCondition Action28)) | (&('"' | '$' | '\'' | '*' | '+' | '-' | '.' | '/' | '0' | '1' | '2' | '3' | '4' | '5' | '6' | '7' | '8' | '9' | ':' | ';' | '@' | 'A' | 'B' | 'C' | 'D' | 'E' | 'F' | 'G' | 'H' | 'I' | 'J' | 'K' | 'L' | 'M' | 'N' | 'O' | 'P' | 'Q' | 'R' | 'S' | 'T' | 'U' | 'V' | 'W' | 'X' | 'Y' | 'Z' | '[' | '_' | 'a' | 'b' | 'c' | 'd' | 'e' | 'f' | 'g' | 'h' | 'i' | 'j' | 'k' | 'l' | 'm' | 'n' | 'o' | 'p' | 'q' | 'r' | 's' | 't' | 'u' | 'v' | 'w' | 'x' | 'y' | 'z' | '{' | '~') (ConditionValue Action32))))> */
+		func() bool {
+			position236, tokenIndex236 := position, tokenIndex
+			{
+				position237 := position
+				{
+					position238, tokenIndex238 := position, tokenIndex
+					if !_rules[ruleConditionValue]() {
+						goto l239
+					}
+					{
+						add(ruleAction29, position)
+					}
+					if !_rules[ruleWhiteSpacing]() {
+						goto l239
+					}
+					{
+						position241 := position
 						{
-							position56, tokenIndex56 := position, tokenIndex
-							if !_rules[ruleEndOfLine]() {
-								goto l56
+							position242 := position
+							{
+								position243, tokenIndex243 := position, tokenIndex
+								if buffer[position] != rune('<') {
+									goto l244
+								}
+								position++
+								if buffer[position] != rune('=') {
+									goto l244
+								}
+								position++
+								goto l243
+							l244:
+								position, tokenIndex = position243, tokenIndex243
+								if buffer[position] != rune('>') {
+									goto l245
+								}
+								position++
+								if buffer[position] != rune('=') {
+									goto l245
+								}
+								position++
+								goto l243
+							l245:
+								position, tokenIndex = position243, tokenIndex243
+								{
+									switch buffer[position] {
+									case '>':
+										if buffer[position] != rune('>') {
+											goto l239
+										}
+										position++
+									case '<':
+										if buffer[position] != rune('<') {
+											goto l239
+										}
+										position++
+									case '!':
+										if buffer[position] != rune('!') {
+											goto l239
+										}
+										position++
+										if buffer[position] != rune('=') {
+											goto l239
+										}
+										position++
+									default:
+										if buffer[position] != rune('=') {
+											goto l239
+										}
+										position++
+										if buffer[position] != rune('=') {
+											goto l239
+										}
+										position++
+									}
+								}
+
+							}
+						l243:
+							add(ruleComparisonOperator, position242)
+						}
+						add(rulePegText, position241)
+					}
+					{
+						add(ruleAction30, position)
+					}
+					if !_rules[ruleWhiteSpacing]() {
+						goto l239
+					}
+					if !_rules[ruleConditionValue]() {
+						goto l239
+					}
+					{
+						add(ruleAction31, position)
+					}
+					goto l238
+				l239:
+					position, tokenIndex = position238, tokenIndex238
+					{
+						switch buffer[position] {
+						case '(':
+							if buffer[position] != rune('(') {
+								goto l236
+							}
+							position++
+							if !_rules[ruleWhiteSpacing]() {
+								goto l236
+							}
+							if !_rules[ruleCondition]() {
+								goto l236
+							}
+							if !_rules[ruleWhiteSpacing]() {
+								goto l236
+							}
+							if buffer[position] != rune(')') {
+								goto l236
+							}
+							position++
+						case '!':
+							if buffer[position] != rune('!') {
+								goto l236
+							}
+							position++
+							if !_rules[ruleWhiteSpacing]() {
+								goto l236
+							}
+							if !_rules[ruleNotCondition]() {
+								goto l236
+							}
+							{
+								add(ruleAction28, position)
+							}
+						default:
+							if !_rules[ruleConditionValue]() {
+								goto l236
+							}
+							{
+								add(ruleAction32, position)
+							}
+						}
+					}
+
+				}
+			l238:
+				add(ruleNotCondition, position237)
+			}
+			return true
+		l236:
+			position, tokenIndex = position236, tokenIndex236
+			return false
+		},
+		/* 21 ComparisonOperator <- <(('<' '=') / ('>' '=') / ((&('>') '>') | (&('<') '<') | (&('!') ('!' '=')) | (&('=') ('=' '='))))> */
+		nil,
+		/* 22 ConditionValue <- <(FunctionValue / ConcatenationValue / (AliasValue Action34) / (DoubleQuote CustomTypedValue DoubleQuote) / (SingleQuote CustomTypedValue SingleQuote) / CustomTypedValue / ((&('[') ListValue) | (&('$') (RefValue Action33)) | (&('{') HoleValue) | (&('"' | '\'') QuotedStringValue) | (&('*' | '+' | '-' | '.' | '/' | '0' | '1' | '2' | '3' | '4' | '5' | '6' | '7' | '8' | '9' | ':' | ';' | '@' | 'A' | 'B' | 'C' | 'D' | 'E' | 'F' | 'G' | 'H' | 'I' | 'J' | 'K' | 'L' | 'M' | 'N' | 'O' | 'P' | 'Q' | 'R' | 'S' | 'T' | 'U' | 'V' | 'W' | 'X' | 'Y' | 'Z' | '_' | 'a' | 'b' | 'c' | 'd' | 'e' | 'f' | 'g' | 'h' | 'i' | 'j' | 'k' | 'l' | 'm' | 'n' | 'o' | 'p' | 'q' | 'r' | 's' | 't' | 'u' | 'v' | 'w' | 'x' | 'y' | 'z' | '~') (<((&('*') '*') | (&('@') '@') | (&('~') '~') | (&(';') ';') | (&('+') '+') | (&('/') '/') | (&(':') ':') | (&('_') '_') | (&('.') '.') | (&('-') '-') | (&('0' | '1' | '2' | '3' | '4' | '5' | '6' | '7' | '8' | '9') [0-9]) | (&('A' | 'B' | 'C' | 'D' | 'E' | 'F' | 'G' | 'H' | 'I' | 'J' | 'K' | 'L' | 'M' | 'N' | 'O' | 'P' | 'Q' | 'R' | 'S' | 'T' | 'U' | 'V' | 'W' | 'X' | 'Y' | 'Z') [A-Z]) | (&('a' | 'b' | 'c' | 'd' | 'e' | 'f' | 'g' | 'h' | 'i' | 'j' | 'k' | 'l' | 'm' | 'n' | 'o' | 'p' | 'q' | 'r' | 's' | 't' | 'u' | 'v' | 'w' | 'x' | 'y' | 'z') [a-z]))+> Action35))))> */
+		func() bool {
+			position253, tokenIndex253 := position, tokenIndex
+			{
+				position254 := position
+				{
+					position255, tokenIndex255 := position, tokenIndex
+					if !_rules[ruleFunctionValue]() {
+						goto l256
+					}
+					goto l255
+				l256:
+					position, tokenIndex = position255, tokenIndex255
+					if !_rules[ruleConcatenationValue]() {
+						goto l257
+					}
+					goto l255
+				l257:
+					position, tokenIndex = position255, tokenIndex255
+					if !_rules[ruleAliasValue]() {
+						goto l258
+					}
+					{
+						add(ruleAction34, position)
+					}
+					goto l255
+				l258:
+					position, tokenIndex = position255, tokenIndex255
+					if !_rules[ruleDoubleQuote]() {
+						goto l260
+					}
+					if !_rules[ruleCustomTypedValue]() {
+						goto l260
+					}
+					if !_rules[ruleDoubleQuote]() {
+						goto l260
+					}
+					goto l255
+				l260:
+					position, tokenIndex = position255, tokenIndex255
+					if !_rules[ruleSingleQuote]() {
+						goto l261
+					}
+					if !_rules[ruleCustomTypedValue]() {
+						goto l261
+					}
+					if !_rules[ruleSingleQuote]() {
+						goto l261
+					}
+					goto l255
+				l261:
+					position, tokenIndex = position255, tokenIndex255
+					if !_rules[ruleCustomTypedValue]() {
+						goto l262
+					}
+					goto l255
+				l262:
+					position, tokenIndex = position255, tokenIndex255
+					{
+						switch buffer[position] {
+						case '[':
+							if !_rules[ruleListValue]() {
+								goto l253
+							}
+						case '$':
+							if !_rules[ruleRefValue]() {
+								goto l253
+							}
+							{
+								add(ruleAction33, position)
+							}
+						case '{':
+							if !_rules[ruleHoleValue]() {
+								goto l253
+							}
+						case '"', '\'':
+							if !_rules[ruleQuotedStringValue]() {
+								goto l253
+							}
+						default:
+							{
+								position265 := position
+								{
+									switch buffer[position] {
+									case '*':
+										if buffer[position] != rune('*') {
+											goto l253
+										}
+										position++
+									case '@':
+										if buffer[position] != rune('@') {
+											goto l253
+										}
+										position++
+									case '~':
+										if buffer[position] != rune('~') {
+											goto l253
+										}
+										position++
+									case ';':
+										if buffer[position] != rune(';') {
+											goto l253
+										}
+										position++
+									case '+':
+										if buffer[position] != rune('+') {
+											goto l253
+										}
+										position++
+									case '/':
+										if buffer[position] != rune('/') {
+											goto l253
+										}
+										position++
+									case ':':
+										if buffer[position] != rune(':') {
+											goto l253
+										}
+										position++
+									case '_':
+										if buffer[position] != rune('_') {
+											goto l253
+										}
+										position++
+									case '.':
+										if buffer[position] != rune('.') {
+											goto l253
+										}
+										position++
+									case '-':
+										if buffer[position] != rune('-') {
+											goto l253
+										}
+										position++
+									case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+										if c := buffer[position]; c < rune('0') || c > rune('9') {
+											goto l253
+										}
+										position++
+									case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
+										if c := buffer[position]; c < rune('A') || c > rune('Z') {
+											goto l253
+										}
+										position++
+									default:
+										if c := buffer[position]; c < rune('a') || c > rune('z') {
+											goto l253
+										}
+										position++
+									}
+								}
+
+							l266:
+								{
+									position267, tokenIndex267 := position, tokenIndex
+									{
+										switch buffer[position] {
+										case '*':
+											if buffer[position] != rune('*') {
+												goto l267
+											}
+											position++
+										case '@':
+											if buffer[position] != rune('@') {
+												goto l267
+											}
+											position++
+										case '~':
+											if buffer[position] != rune('~') {
+												goto l267
+											}
+											position++
+										case ';':
+											if buffer[position] != rune(';') {
+												goto l267
+											}
+											position++
+										case '+':
+											if buffer[position] != rune('+') {
+												goto l267
+											}
+											position++
+										case '/':
+											if buffer[position] != rune('/') {
+												goto l267
+											}
+											position++
+										case ':':
+											if buffer[position] != rune(':') {
+												goto l267
+											}
+											position++
+										case '_':
+											if buffer[position] != rune('_') {
+												goto l267
+											}
+											position++
+										case '.':
+											if buffer[position] != rune('.') {
+												goto l267
+											}
+											position++
+										case '-':
+											if buffer[position] != rune('-') {
+												goto l267
+											}
+											position++
+										case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+											if c := buffer[position]; c < rune('0') || c > rune('9') {
+												goto l267
+											}
+											position++
+										case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
+											if c := buffer[position]; c < rune('A') || c > rune('Z') {
+												goto l267
+											}
+											position++
+										default:
+											if c := buffer[position]; c < rune('a') || c > rune('z') {
+												goto l267
+											}
+											position++
+										}
+									}
+
+									goto l266
+								l267:
+									position, tokenIndex = position267, tokenIndex267
+								}
+								add(rulePegText, position265)
+							}
+							{
+								add(ruleAction35, position)
 							}
-							goto l55
-						l56:
-							position, tokenIndex = position56, tokenIndex56
-						}
-						{
-							add(ruleAction1, position)
-						}
-						add(ruleStatement, position34)
-					}
-				l58:
-					{
-						position59, tokenIndex59 := position, tokenIndex
-						if !_rules[ruleBlankLine]() {
-							goto l59
-						}
-						goto l58
-					l59:
-						position, tokenIndex = position59, tokenIndex59
-					}
-					goto l2
-				l3:
-					position, tokenIndex = position3, tokenIndex3
-				}
-				if !_rules[ruleWhiteSpacing]() {
-					goto l0
-				}
-				{
-					position60 := position
-					{
-						position61, tokenIndex61 := position, tokenIndex
-						if !matchDot() {
-							goto l61
 						}
-						goto l0
-					l61:
-						position, tokenIndex = position61, tokenIndex61
 					}
-					add(ruleEndOfFile, position60)
+
 				}
-				add(ruleScript, position1)
+			l255:
+				add(ruleConditionValue, position254)
 			}
 			return true
-		l0:
-			position, tokenIndex = position0, tokenIndex0
+		l253:
+			position, tokenIndex = position253, tokenIndex253
 			return false
 		},
-		/* 1 Statement <- <(Action0 WhiteSpacing (CmdExpr / Declaration / Comment) WhiteSpacing EndOfLine* Action1)> */
-		nil,
-		/* 2 Action <- <[a-z]+> */
-		nil,
-		/* 3 Entity <- <([a-z] / [0-9])+> */
-		nil,
-		/* 4 Declaration <- <(<Identifier> Action2 Equal (CmdExpr / ValueExpr))> */
-		nil,
-		/* 5 ValueExpr <- <(Action3 CompositeValue)> */
-		nil,
-		/* 6 CmdExpr <- <(<Action> Action4 MustWhiteSpacing <Entity> Action5 (MustWhiteSpacing Params)?)> */
+		/* 23 Params <- <Param+> */
 		func() bool {
-			position67, tokenIndex67 := position, tokenIndex
+			position271, tokenIndex271 := position, tokenIndex
 			{
-				position68 := position
+				position272 := position
 				{
-					position69 := position
+					position275 := position
 					{
-						position70 := position
-						if c := buffer[position]; c < rune('a') || c > rune('z') {
-							goto l67
-						}
-						position++
-					l71:
-						{
-							position72, tokenIndex72 := position, tokenIndex
-							if c := buffer[position]; c < rune('a') || c > rune('z') {
-								goto l72
-							}
-							position++
-							goto l71
-						l72:
-							position, tokenIndex = position72, tokenIndex72
+						position276 := position
+						if !_rules[ruleIdentifier]() {
+							goto l271
 						}
-						add(ruleAction, position70)
+						add(rulePegText, position276)
 					}
-					add(rulePegText, position69)
-				}
-				{
-					add(ruleAction4, position)
-				}
-				if !_rules[ruleMustWhiteSpacing]() {
-					goto l67
-				}
-				{
-					position74 := position
 					{
-						position75 := position
-						{
-							position78, tokenIndex78 := position, tokenIndex
-							if c := buffer[position]; c < rune('a') || c > rune('z') {
-								goto l79
-							}
-							position++
-							goto l78
-						l79:
-							position, tokenIndex = position78, tokenIndex78
-							if c := buffer[position]; c < rune('0') || c > rune('9') {
-								goto l67
-							}
-							position++
-						}
-					l78:
-					l76:
-						{
-							position77, tokenIndex77 := position, tokenIndex
-							{
-								position80, tokenIndex80 := position, tokenIndex
-								if c := buffer[position]; c < rune('a') || c > rune('z') {
-									goto l81
-								}
-								position++
-								goto l80
-							l81:
-								position, tokenIndex = position80, tokenIndex80
-								if c := buffer[position]; c < rune('0') || c > rune('9') {
-									goto l77
-								}
-								position++
-							}
-						l80:
-							goto l76
-						l77:
-							position, tokenIndex = position77, tokenIndex77
-						}
-						add(ruleEntity, position75)
+						add(ruleAction36, position)
 					}
-					add(rulePegText, position74)
-				}
-				{
-					add(ruleAction5, position)
+					if !_rules[ruleEqual]() {
+						goto l271
+					}
+					if !_rules[ruleCompositeValue]() {
+						goto l271
+					}
+					if !_rules[ruleWhiteSpacing]() {
+						goto l271
+					}
+					add(ruleParam, position275)
 				}
+			l273:
 				{
-					position83, tokenIndex83 := position, tokenIndex
-					if !_rules[ruleMustWhiteSpacing]() {
-						goto l83
-					}
+					position274, tokenIndex274 := position, tokenIndex
 					{
-						position85 := position
+						position278 := position
 						{
-							position88 := position
-							{
-								position89 := position
-								if !_rules[ruleIdentifier]() {
-									goto l83
-								}
-								add(rulePegText, position89)
-							}
-							{
-								add(ruleAction6, position)
-							}
-							if !_rules[ruleEqual]() {
-								goto l83
-							}
-							if !_rules[ruleCompositeValue]() {
-								goto l83
-							}
-							if !_rules[ruleWhiteSpacing]() {
-								goto l83
+							position279 := position
+							if !_rules[ruleIdentifier]() {
+								goto l274
 							}
-							add(ruleParam, position88)
+							add(rulePegText, position279)
 						}
-					l86:
 						{
-							position87, tokenIndex87 := position, tokenIndex
-							{
-								position91 := position
-								{
-									position92 := position
-									if !_rules[ruleIdentifier]() {
-										goto l87
-									}
-									add(rulePegText, position92)
-								}
-								{
-									add(ruleAction6, position)
-								}
-								if !_rules[ruleEqual]() {
-									goto l87
-								}
-								if !_rules[ruleCompositeValue]() {
-									goto l87
-								}
-								if !_rules[ruleWhiteSpacing]() {
-									goto l87
-								}
-								add(ruleParam, position91)
-							}
-							goto l86
-						l87:
-							position, tokenIndex = position87, tokenIndex87
+							add(ruleAction36, position)
+						}
+						if !_rules[ruleEqual]() {
+							goto l274
 						}
-						add(ruleParams, position85)
+						if !_rules[ruleCompositeValue]() {
+							goto l274
+						}
+						if !_rules[ruleWhiteSpacing]() {
+							goto l274
+						}
+						add(ruleParam, position278)
 					}
-					goto l84
-				l83:
-					position, tokenIndex = position83, tokenIndex83
+					goto l273
+				l274:
+					position, tokenIndex = position274, tokenIndex274
 				}
-			l84:
-				add(ruleCmdExpr, position68)
+				add(ruleParams, position272)
 			}
 			return true
-		l67:
-			position, tokenIndex = position67, tokenIndex67
+		l271:
+			position, tokenIndex = position271, tokenIndex271
 			return false
 		},
-		/* 7 Params <- <Param+> */
+		/* 24 Param <- <(<Identifier> Action36 Equal CompositeValue WhiteSpacing)> */
 		nil,
-		/* 8 Param <- <(<Identifier> Action6 Equal CompositeValue WhiteSpacing)> */
-		nil,
-		/* 9 Identifier <- <((&('.') '.') | (&('_') '_') | (&('-') '-') | (&('0' | '1' | '2' | '3' | '4' | '5' | '6' | '7' | '8' | '9') [0-9]) | (&('A' | 'B' | 'C' | 'D' | 'E' | 'F' | 'G' | 'H' | 'I' | 'J' | 'K' | 'L' | 'M' | 'N' | 'O' | 'P' | 'Q' | 'R' | 'S' | 'T' | 'U' | 'V' | 'W' | 'X' | 'Y' | 'Z') [A-Z]) | (&('a' | 'b' | 'c' | 'd' | 'e' | 'f' | 'g' | 'h' | 'i' | 'j' | 'k' | 'l' | 'm' | 'n' | 'o' | 'p' | 'q' | 'r' | 's' | 't' | 'u' | 'v' | 'w' | 'x' | 'y' | 'z') [a-z]))+> */
+		/* 25 Identifier <- <((&('.') '.') | (&('_') '_') | (&('-') '-') | (&('0' | '1' | '2' | '3' | '4' | '5' | '6' | '7' | '8' | '9') [0-9]) | (&('A' | 'B' | 'C' | 'D' | 'E' | 'F' | 'G' | 'H' | 'I' | 'J' | 'K' | 'L' | 'M' | 'N' | 'O' | 'P' | 'Q' | 'R' | 'S' | 'T' | 'U' | 'V' | 'W' | 'X' | 'Y' | 'Z') [A-Z]) | (&('a' | 'b' | 'c' | 'd' | 'e' | 'f' | 'g' | 'h' | 'i' | 'j' | 'k' | 'l' | 'm' | 'n' | 'o' | 'p' | 'q' | 'r' | 's' | 't' | 'u' | 'v' | 'w' | 'x' | 'y' | 'z') [a-z]))+> */
 		func() bool {
-			position96, tokenIndex96 := position, tokenIndex
+			position282, tokenIndex282 := position, tokenIndex
 			{
-				position97 := position
+				position283 := position
 				{
 					switch buffer[position] {
 					case '.':
 						if buffer[position] != rune('.') {
-							goto l96
+							goto l282
 						}
 						position++
-						break
 					case '_':
 						if buffer[position] != rune('_') {
-							goto l96
+							goto l282
 						}
 						position++
-						break
 					case '-':
 						if buffer[position] != rune('-') {
-							goto l96
+							goto l282
 						}
 						position++
-						break
 					case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
 						if c := buffer[position]; c < rune('0') || c > rune('9') {
-							goto l96
+							goto l282
 						}
 						position++
-						break
 					case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
 						if c := buffer[position]; c < rune('A') || c > rune('Z') {
-							goto l96
+							goto l282
 						}
 						position++
-						break
 					default:
 						if c := buffer[position]; c < rune('a') || c > rune('z') {
-							goto l96
+							goto l282
 						}
 						position++
-						break
 					}
 				}
 
-			l98:
+			l284:
 				{
-					position99, tokenIndex99 := position, tokenIndex
+					position285, tokenIndex285 := position, tokenIndex
 					{
 						switch buffer[position] {
 						case '.':
 							if buffer[position] != rune('.') {
-								goto l99
+								goto l285
 							}
 							position++
-							break
 						case '_':
 							if buffer[position] != rune('_') {
-								goto l99
+								goto l285
 							}
 							position++
-							break
 						case '-':
 							if buffer[position] != rune('-') {
-								goto l99
+								goto l285
 							}
 							position++
-							break
 						case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
 							if c := buffer[position]; c < rune('0') || c > rune('9') {
-								goto l99
+								goto l285
 							}
 							position++
-							break
 						case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
 							if c := buffer[position]; c < rune('A') || c > rune('Z') {
-								goto l99
+								goto l285
 							}
 							position++
-							break
 						default:
 							if c := buffer[position]; c < rune('a') || c > rune('z') {
-								goto l99
+								goto l285
 							}
 							position++
-							break
 						}
 					}
 
-					goto l98
-				l99:
-					position, tokenIndex = position99, tokenIndex99
+					goto l284
+				l285:
+					position, tokenIndex = position285, tokenIndex285
 				}
-				add(ruleIdentifier, position97)
+				add(ruleIdentifier, position283)
 			}
 			return true
-		l96:
-			position, tokenIndex = position96, tokenIndex96
+		l282:
+			position, tokenIndex = position282, tokenIndex282
 			return false
 		},
-		/* 10 CompositeValue <- <(ListValue / ListWithoutSquareBrackets / Value)> */
+		/* 26 CompositeValue <- <(ListValue / ListWithoutSquareBrackets / Value)> */
 		func() bool {
-			position102, tokenIndex102 := position, tokenIndex
+			position288, tokenIndex288 := position, tokenIndex
 			{
-				position103 := position
+				position289 := position
 				{
-					position104, tokenIndex104 := position, tokenIndex
-					{
-						position106 := position
-						{
-							add(ruleAction7, position)
-						}
-						if buffer[position] != rune('[') {
-							goto l105
-						}
-						position++
-						{
-							position108, tokenIndex108 := position, tokenIndex
-							if !_rules[ruleWhiteSpacing]() {
-								goto l108
-							}
-							if !_rules[ruleValue]() {
-								goto l108
-							}
-							if !_rules[ruleWhiteSpacing]() {
-								goto l108
-							}
-							goto l109
-						l108:
-							position, tokenIndex = position108, tokenIndex108
-						}
-					l109:
-					l110:
-						{
-							position111, tokenIndex111 := position, tokenIndex
-							if buffer[position] != rune(',') {
-								goto l111
-							}
-							position++
-							if !_rules[ruleWhiteSpacing]() {
-								goto l111
-							}
-							if !_rules[ruleValue]() {
-								goto l111
-							}
-							if !_rules[ruleWhiteSpacing]() {
-								goto l111
-							}
-							goto l110
-						l111:
-							position, tokenIndex = position111, tokenIndex111
-						}
-						if buffer[position] != rune(']') {
-							goto l105
-						}
-						position++
-						{
-							add(ruleAction8, position)
-						}
-						add(ruleListValue, position106)
+					position290, tokenIndex290 := position, tokenIndex
+					if !_rules[ruleListValue]() {
+						goto l291
 					}
-					goto l104
-				l105:
-					position, tokenIndex = position104, tokenIndex104
+					goto l290
+				l291:
+					position, tokenIndex = position290, tokenIndex290
 					{
-						position114 := position
+						position293 := position
 						{
-							add(ruleAction9, position)
+							add(ruleAction39, position)
 						}
 						if !_rules[ruleWhiteSpacing]() {
-							goto l113
+							goto l292
 						}
 						if !_rules[ruleValue]() {
-							goto l113
+							goto l292
 						}
 						if !_rules[ruleWhiteSpacing]() {
-							goto l113
+							goto l292
 						}
 						if buffer[position] != rune(',') {
-							goto l113
+							goto l292
 						}
 						position++
 						if !_rules[ruleWhiteSpacing]() {
-							goto l113
+							goto l292
 						}
 						if !_rules[ruleValue]() {
-							goto l113
+							goto l292
 						}
 						if !_rules[ruleWhiteSpacing]() {
-							goto l113
+							goto l292
 						}
-					l116:
+					l295:
 						{
-							position117, tokenIndex117 := position, tokenIndex
+							position296, tokenIndex296 := position, tokenIndex
 							if buffer[position] != rune(',') {
-								goto l117
+								goto l296
 							}
 							position++
 							if !_rules[ruleWhiteSpacing]() {
-								goto l117
+								goto l296
 							}
 							if !_rules[ruleValue]() {
-								goto l117
+								goto l296
 							}
 							if !_rules[ruleWhiteSpacing]() {
-								goto l117
+								goto l296
 							}
-							goto l116
-						l117:
-							position, tokenIndex = position117, tokenIndex117
+							goto l295
+						l296:
+							position, tokenIndex = position296, tokenIndex296
 						}
 						{
-							add(ruleAction10, position)
+							add(ruleAction40, position)
 						}
-						add(ruleListWithoutSquareBrackets, position114)
+						add(ruleListWithoutSquareBrackets, position293)
 					}
-					goto l104
-				l113:
-					position, tokenIndex = position104, tokenIndex104
+					goto l290
+				l292:
+					position, tokenIndex = position290, tokenIndex290
 					if !_rules[ruleValue]() {
-						goto l102
+						goto l288
 					}
 				}
-			l104:
-				add(ruleCompositeValue, position103)
+			l290:
+				add(ruleCompositeValue, position289)
 			}
 			return true
-		l102:
-			position, tokenIndex = position102, tokenIndex102
+		l288:
+			position, tokenIndex = position288, tokenIndex288
 			return false
 		},
-		/* 11 ListValue <- <(Action7 '[' (WhiteSpacing Value WhiteSpacing)? (',' WhiteSpacing Value WhiteSpacing)* ']' Action8)> */
-		nil,
-		/* 12 ListWithoutSquareBrackets <- <(Action9 (WhiteSpacing Value WhiteSpacing) (',' WhiteSpacing Value WhiteSpacing)+ Action10)> */
+		/* 27 ListValue <- <(Action37 '[' (WhiteSpacing Value WhiteSpacing)? (',' WhiteSpacing Value WhiteSpacing)* ']' Action38)> */
+		func() bool {
+			position298, tokenIndex298 := position, tokenIndex
+			{
+				position299 := position
+				{
+					add(ruleAction37, position)
+				}
+				if buffer[position] != rune('[') {
+					goto l298
+				}
+				position++
+				{
+					position301, tokenIndex301 := position, tokenIndex
+					if !_rules[ruleWhiteSpacing]() {
+						goto l301
+					}
+					if !_rules[ruleValue]() {
+						goto l301
+					}
+					if !_rules[ruleWhiteSpacing]() {
+						goto l301
+					}
+					goto l302
+				l301:
+					position, tokenIndex = position301, tokenIndex301
+				}
+			l302:
+			l303:
+				{
+					position304, tokenIndex304 := position, tokenIndex
+					if buffer[position] != rune(',') {
+						goto l304
+					}
+					position++
+					if !_rules[ruleWhiteSpacing]() {
+						goto l304
+					}
+					if !_rules[ruleValue]() {
+						goto l304
+					}
+					if !_rules[ruleWhiteSpacing]() {
+						goto l304
+					}
+					goto l303
+				l304:
+					position, tokenIndex = position304, tokenIndex304
+				}
+				if buffer[position] != rune(']') {
+					goto l298
+				}
+				position++
+				{
+					add(ruleAction38, position)
+				}
+				add(ruleListValue, position299)
+			}
+			return true
+		l298:
+			position, tokenIndex = position298, tokenIndex298
+			return false
+		},
+		/* 28 ListWithoutSquareBrackets <- <(Action39 (WhiteSpacing Value WhiteSpacing) (',' WhiteSpacing Value WhiteSpacing)+ Action40)> */
 		nil,
-		/* 13 NoRefValue <- <(ConcatenationValue / HoleWithSuffixValue / HoleValue / HolesStringValue / (AliasValue Action11) / (DoubleQuote CustomTypedValue DoubleQuote) / (SingleQuote CustomTypedValue SingleQuote) / CustomTypedValue / QuotedStringValue / UnquotedParamValue)> */
+		/* 29 NoRefValue <- <(FunctionValue / ConcatenationValue / HoleWithSuffixValue / HoleValue / HolesStringValue / (AliasValue Action41) / (DoubleQuote CustomTypedValue DoubleQuote) / (SingleQuote CustomTypedValue SingleQuote) / CustomTypedValue / QuotedStringValue / UnquotedParamValue)> */
 		nil,
-		/* 14 Value <- <((RefValue Action12) / NoRefValue)> */
+		/* 30 Value <- <((RefValue Action42) / NoRefValue)> */
 		func() bool {
-			position122, tokenIndex122 := position, tokenIndex
+			position308, tokenIndex308 := position, tokenIndex
 			{
-				position123 := position
+				position309 := position
 				{
-					position124, tokenIndex124 := position, tokenIndex
-					{
-						position126 := position
-						if buffer[position] != rune('$') {
-							goto l125
-						}
-						position++
-						{
-							position127 := position
-							if !_rules[ruleIdentifier]() {
-								goto l125
-							}
-							add(rulePegText, position127)
-						}
-						add(ruleRefValue, position126)
+					position310, tokenIndex310 := position, tokenIndex
+					if !_rules[ruleRefValue]() {
+						goto l311
 					}
 					{
-						add(ruleAction12, position)
+						add(ruleAction42, position)
 					}
-					goto l124
-				l125:
-					position, tokenIndex = position124, tokenIndex124
+					goto l310
+				l311:
+					position, tokenIndex = position310, tokenIndex310
 					{
-						position129 := position
+						position313 := position
 						{
-							position130, tokenIndex130 := position, tokenIndex
-							{
-								position132 := position
-								{
-									position133, tokenIndex133 := position, tokenIndex
-									{
-										add(ruleAction17, position)
-									}
-									if !_rules[ruleHoleValue]() {
-										goto l134
-									}
-									if !_rules[ruleWhiteSpacing]() {
-										goto l134
-									}
-									if buffer[position] != rune('+') {
-										goto l134
-									}
-									position++
-									if !_rules[ruleWhiteSpacing]() {
-										goto l134
-									}
-									{
-										position138, tokenIndex138 := position, tokenIndex
-										if !_rules[ruleQuotedStringValue]() {
-											goto l139
-										}
-										goto l138
-									l139:
-										position, tokenIndex = position138, tokenIndex138
-										if !_rules[ruleHoleValue]() {
-											goto l134
-										}
-									}
-								l138:
-								l136:
-									{
-										position137, tokenIndex137 := position, tokenIndex
-										if !_rules[ruleWhiteSpacing]() {
-											goto l137
-										}
-										if buffer[position] != rune('+') {
-											goto l137
-										}
-										position++
-										if !_rules[ruleWhiteSpacing]() {
-											goto l137
-										}
-										{
-											position140, tokenIndex140 := position, tokenIndex
-											if !_rules[ruleQuotedStringValue]() {
-												goto l141
-											}
-											goto l140
-										l141:
-											position, tokenIndex = position140, tokenIndex140
-											if !_rules[ruleHoleValue]() {
-												goto l137
-											}
-										}
-									l140:
-										goto l136
-									l137:
-										position, tokenIndex = position137, tokenIndex137
-									}
-									{
-										add(ruleAction18, position)
-									}
-									goto l133
-								l134:
-									position, tokenIndex = position133, tokenIndex133
-									{
-										add(ruleAction19, position)
-									}
-									if !_rules[ruleQuotedStringValue]() {
-										goto l131
-									}
-									if !_rules[ruleWhiteSpacing]() {
-										goto l131
-									}
-									if buffer[position] != rune('+') {
-										goto l131
-									}
-									position++
-									if !_rules[ruleWhiteSpacing]() {
-										goto l131
-									}
-									{
-										position146, tokenIndex146 := position, tokenIndex
-										if !_rules[ruleQuotedStringValue]() {
-											goto l147
-										}
-										goto l146
-									l147:
-										position, tokenIndex = position146, tokenIndex146
-										if !_rules[ruleHoleValue]() {
-											goto l131
-										}
-									}
-								l146:
-								l144:
-									{
-										position145, tokenIndex145 := position, tokenIndex
-										if !_rules[ruleWhiteSpacing]() {
-											goto l145
-										}
-										if buffer[position] != rune('+') {
-											goto l145
-										}
-										position++
-										if !_rules[ruleWhiteSpacing]() {
-											goto l145
-										}
-										{
-											position148, tokenIndex148 := position, tokenIndex
-											if !_rules[ruleQuotedStringValue]() {
-												goto l149
-											}
-											goto l148
-										l149:
-											position, tokenIndex = position148, tokenIndex148
-											if !_rules[ruleHoleValue]() {
-												goto l145
-											}
-										}
-									l148:
-										goto l144
-									l145:
-										position, tokenIndex = position145, tokenIndex145
-									}
-									{
-										add(ruleAction20, position)
-									}
-								}
-							l133:
-								add(ruleConcatenationValue, position132)
+							position314, tokenIndex314 := position, tokenIndex
+							if !_rules[ruleFunctionValue]() {
+								goto l315
+							}
+							goto l314
+						l315:
+							position, tokenIndex = position314, tokenIndex314
+							if !_rules[ruleConcatenationValue]() {
+								goto l316
 							}
-							goto l130
-						l131:
-							position, tokenIndex = position130, tokenIndex130
+							goto l314
+						l316:
+							position, tokenIndex = position314, tokenIndex314
 							{
-								position152 := position
+								position318 := position
 								{
-									add(ruleAction25, position)
+									add(ruleAction55, position)
 								}
 								{
-									position154 := position
+									position320 := position
 									if !_rules[ruleHoleValue]() {
-										goto l151
+										goto l317
 									}
 									if !_rules[ruleUnquotedParamValue]() {
-										goto l151
+										goto l317
 									}
-								l155:
+								l321:
 									{
-										position156, tokenIndex156 := position, tokenIndex
+										position322, tokenIndex322 := position, tokenIndex
 										if !_rules[ruleUnquotedParamValue]() {
-											goto l156
+											goto l322
 										}
-										goto l155
-									l156:
-										position, tokenIndex = position156, tokenIndex156
+										goto l321
+									l322:
+										position, tokenIndex = position322, tokenIndex322
 									}
-								l157:
+								l323:
 									{
-										position158, tokenIndex158 := position, tokenIndex
+										position324, tokenIndex324 := position, tokenIndex
 										{
-											position159, tokenIndex159 := position, tokenIndex
+											position325, tokenIndex325 := position, tokenIndex
 											if !_rules[ruleUnquotedParamValue]() {
-												goto l159
+												goto l325
 											}
-											goto l160
-										l159:
-											position, tokenIndex = position159, tokenIndex159
+											goto l326
+										l325:
+											position, tokenIndex = position325, tokenIndex325
 										}
-									l160:
+									l326:
 										if !_rules[ruleHoleValue]() {
-											goto l158
+											goto l324
 										}
 										{
-											position161, tokenIndex161 := position, tokenIndex
+											position327, tokenIndex327 := position, tokenIndex
 											if !_rules[ruleUnquotedParamValue]() {
-												goto l161
+												goto l327
 											}
-											goto l162
-										l161:
-											position, tokenIndex = position161, tokenIndex161
+											goto l328
+										l327:
+											position, tokenIndex = position327, tokenIndex327
 										}
-									l162:
-										goto l157
-									l158:
-										position, tokenIndex = position158, tokenIndex158
+									l328:
+										goto l323
+									l324:
+										position, tokenIndex = position324, tokenIndex324
 									}
-									add(rulePegText, position154)
+									add(rulePegText, position320)
 								}
 								{
-									add(ruleAction26, position)
+									add(ruleAction56, position)
 								}
-								add(ruleHoleWithSuffixValue, position152)
+								add(ruleHoleWithSuffixValue, position318)
 							}
-							goto l130
-						l151:
-							position, tokenIndex = position130, tokenIndex130
+							goto l314
+						l317:
+							position, tokenIndex = position314, tokenIndex314
 							if !_rules[ruleHoleValue]() {
-								goto l164
+								goto l330
 							}
-							goto l130
-						l164:
-							position, tokenIndex = position130, tokenIndex130
+							goto l314
+						l330:
+							position, tokenIndex = position314, tokenIndex314
 							{
-								position166 := position
+								position332 := position
 								{
-									add(ruleAction23, position)
+									add(ruleAction53, position)
 								}
 								{
-									position168 := position
+									position334 := position
 									{
-										position171, tokenIndex171 := position, tokenIndex
+										position337, tokenIndex337 := position, tokenIndex
 										if !_rules[ruleUnquotedParamValue]() {
-											goto l171
+											goto l337
 										}
-										goto l172
-									l171:
-										position, tokenIndex = position171, tokenIndex171
+										goto l338
+									l337:
+										position, tokenIndex = position337, tokenIndex337
 									}
-								l172:
+								l338:
 									if !_rules[ruleHoleValue]() {
-										goto l165
+										goto l331
 									}
 									{
-										position173, tokenIndex173 := position, tokenIndex
+										position339, tokenIndex339 := position, tokenIndex
 										if !_rules[ruleUnquotedParamValue]() {
-											goto l173
+											goto l339
 										}
-										goto l174
-									l173:
-										position, tokenIndex = position173, tokenIndex173
+										goto l340
+									l339:
+										position, tokenIndex = position339, tokenIndex339
 									}
-								l174:
-								l169:
+								l340:
+								l335:
 									{
-										position170, tokenIndex170 := position, tokenIndex
+										position336, tokenIndex336 := position, tokenIndex
 										{
-											position175, tokenIndex175 := position, tokenIndex
+											position341, tokenIndex341 := position, tokenIndex
 											if !_rules[ruleUnquotedParamValue]() {
-												goto l175
+												goto l341
 											}
-											goto l176
-										l175:
-											position, tokenIndex = position175, tokenIndex175
+											goto l342
+										l341:
+											position, tokenIndex = position341, tokenIndex341
 										}
-									l176:
+									l342:
 										if !_rules[ruleHoleValue]() {
-											goto l170
+											goto l336
 										}
 										{
-											position177, tokenIndex177 := position, tokenIndex
+											position343, tokenIndex343 := position, tokenIndex
 											if !_rules[ruleUnquotedParamValue]() {
-												goto l177
+												goto l343
 											}
-											goto l178
-										l177:
-											position, tokenIndex = position177, tokenIndex177
+											goto l344
+										l343:
+											position, tokenIndex = position343, tokenIndex343
 										}
-									l178:
-										goto l169
-									l170:
-										position, tokenIndex = position170, tokenIndex170
+									l344:
+										goto l335
+									l336:
+										position, tokenIndex = position336, tokenIndex336
 									}
-									add(rulePegText, position168)
+									add(rulePegText, position334)
 								}
 								{
-									add(ruleAction24, position)
+									add(ruleAction54, position)
 								}
-								add(ruleHolesStringValue, position166)
+								add(ruleHolesStringValue, position332)
 							}
-							goto l130
-						l165:
-							position, tokenIndex = position130, tokenIndex130
-							{
-								position181 := position
-								{
-									position182, tokenIndex182 := position, tokenIndex
-									if buffer[position] != rune('@') {
-										goto l183
-									}
-									position++
-									{
-										position184 := position
-										if !_rules[ruleUnquotedParam]() {
-											goto l183
-										}
-										add(rulePegText, position184)
-									}
-									goto l182
-								l183:
-									position, tokenIndex = position182, tokenIndex182
-									if buffer[position] != rune('@') {
-										goto l185
-									}
-									position++
-									if !_rules[ruleDoubleQuotedValue]() {
-										goto l185
-									}
-									goto l182
-								l185:
-									position, tokenIndex = position182, tokenIndex182
-									if buffer[position] != rune('@') {
-										goto l180
-									}
-									position++
-									if !_rules[ruleSingleQuotedValue]() {
-										goto l180
-									}
-								}
-							l182:
-								add(ruleAliasValue, position181)
+							goto l314
+						l331:
+							position, tokenIndex = position314, tokenIndex314
+							if !_rules[ruleAliasValue]() {
+								goto l346
 							}
 							{
-								add(ruleAction11, position)
+								add(ruleAction41, position)
 							}
-							goto l130
-						l180:
-							position, tokenIndex = position130, tokenIndex130
+							goto l314
+						l346:
+							position, tokenIndex = position314, tokenIndex314
 							if !_rules[ruleDoubleQuote]() {
-								goto l187
+								goto l348
 							}
 							if !_rules[ruleCustomTypedValue]() {
-								goto l187
+								goto l348
 							}
 							if !_rules[ruleDoubleQuote]() {
-								goto l187
+								goto l348
 							}
-							goto l130
-						l187:
-							position, tokenIndex = position130, tokenIndex130
+							goto l314
+						l348:
+							position, tokenIndex = position314, tokenIndex314
 							if !_rules[ruleSingleQuote]() {
-								goto l188
+								goto l349
 							}
 							if !_rules[ruleCustomTypedValue]() {
-								goto l188
+								goto l349
 							}
 							if !_rules[ruleSingleQuote]() {
-								goto l188
+								goto l349
 							}
-							goto l130
-						l188:
-							position, tokenIndex = position130, tokenIndex130
+							goto l314
+						l349:
+							position, tokenIndex = position314, tokenIndex314
 							if !_rules[ruleCustomTypedValue]() {
-								goto l189
+								goto l350
 							}
-							goto l130
-						l189:
-							position, tokenIndex = position130, tokenIndex130
+							goto l314
+						l350:
+							position, tokenIndex = position314, tokenIndex314
 							if !_rules[ruleQuotedStringValue]() {
-								goto l190
+								goto l351
 							}
-							goto l130
-						l190:
-							position, tokenIndex = position130, tokenIndex130
+							goto l314
+						l351:
+							position, tokenIndex = position314, tokenIndex314
 							if !_rules[ruleUnquotedParamValue]() {
-								goto l122
+								goto l308
 							}
 						}
-					l130:
-						add(ruleNoRefValue, position129)
+					l314:
+						add(ruleNoRefValue, position313)
 					}
 				}
-			l124:
-				add(ruleValue, position123)
+			l310:
+				add(ruleValue, position309)
 			}
 			return true
-		l122:
-			position, tokenIndex = position122, tokenIndex122
+		l308:
+			position, tokenIndex = position308, tokenIndex308
 			return false
 		},
-		/* 15 CustomTypedValue <- <((<CidrValue> Action13) / (<IpValue> Action14) / (<IntRangeValue> Action15))> */
+		/* 31 CustomTypedValue <- <((<CidrValue> Action43) / (<IpValue> Action44) / (<IntRangeValue> Action45))> */
 		func() bool {
-			position191, tokenIndex191 := position, tokenIndex
+			position352, tokenIndex352 := position, tokenIndex
 			{
-				position192 := position
+				position353 := position
 				{
-					position193, tokenIndex193 := position, tokenIndex
+					position354, tokenIndex354 := position, tokenIndex
 					{
-						position195 := position
+						position356 := position
 						{
-							position196 := position
+							position357 := position
 							if c := buffer[position]; c < rune('0') || c > rune('9') {
-								goto l194
+								goto l355
 							}
 							position++
-						l197:
+						l358:
 							{
-								position198, tokenIndex198 := position, tokenIndex
+								position359, tokenIndex359 := position, tokenIndex
 								if c := buffer[position]; c < rune('0') || c > rune('9') {
-									goto l198
+									goto l359
 								}
 								position++
-								goto l197
-							l198:
-								position, tokenIndex = position198, tokenIndex198
+								goto l358
+							l359:
+								position, tokenIndex = position359, tokenIndex359
 							}
 							if buffer[position] != rune('.') {
-								goto l194
+								goto l355
 							}
 							position++
 							if c := buffer[position]; c < rune('0') || c > rune('9') {
-								goto l194
+								goto l355
 							}
 							position++
-						l199:
+						l360:
 							{
-								position200, tokenIndex200 := position, tokenIndex
+								position361, tokenIndex361 := position, tokenIndex
 								if c := buffer[position]; c < rune('0') || c > rune('9') {
-									goto l200
+									goto l361
 								}
 								position++
-								goto l199
-							l200:
-								position, tokenIndex = position200, tokenIndex200
+								goto l360
+							l361:
+								position, tokenIndex = position361, tokenIndex361
 							}
 							if buffer[position] != rune('.') {
-								goto l194
+								goto l355
 							}
 							position++
 							if c := buffer[position]; c < rune('0') || c > rune('9') {
-								goto l194
+								goto l355
 							}
 							position++
-						l201:
+						l362:
 							{
-								position202, tokenIndex202 := position, tokenIndex
+								position363, tokenIndex363 := position, tokenIndex
 								if c := buffer[position]; c < rune('0') || c > rune('9') {
-									goto l202
+									goto l363
 								}
 								position++
-								goto l201
-							l202:
-								position, tokenIndex = position202, tokenIndex202
+								goto l362
+							l363:
+								position, tokenIndex = position363, tokenIndex363
 							}
 							if buffer[position] != rune('.') {
-								goto l194
+								goto l355
 							}
 							position++
 							if c := buffer[position]; c < rune('0') || c > rune('9') {
-								goto l194
+								goto l355
 							}
 							position++
-						l203:
+						l364:
 							{
-								position204, tokenIndex204 := position, tokenIndex
+								position365, tokenIndex365 := position, tokenIndex
 								if c := buffer[position]; c < rune('0') || c > rune('9') {
-									goto l204
+									goto l365
 								}
 								position++
-								goto l203
-							l204:
-								position, tokenIndex = position204, tokenIndex204
+								goto l364
+							l365:
+								position, tokenIndex = position365, tokenIndex365
 							}
 							if buffer[position] != rune('/') {
-								goto l194
+								goto l355
 							}
 							position++
 							if c := buffer[position]; c < rune('0') || c > rune('9') {
-								goto l194
+								goto l355
 							}
 							position++
-						l205:
+						l366:
 							{
-								position206, tokenIndex206 := position, tokenIndex
+								position367, tokenIndex367 := position, tokenIndex
 								if c := buffer[position]; c < rune('0') || c > rune('9') {
-									goto l206
+									goto l367
 								}
 								position++
-								goto l205
-							l206:
-								position, tokenIndex = position206, tokenIndex206
+								goto l366
+							l367:
+								position, tokenIndex = position367, tokenIndex367
 							}
-							add(ruleCidrValue, position196)
+							add(ruleCidrValue, position357)
 						}
-						add(rulePegText, position195)
+						add(rulePegText, position356)
 					}
 					{
-						add(ruleAction13, position)
+						add(ruleAction43, position)
 					}
-					goto l193
-				l194:
-					position, tokenIndex = position193, tokenIndex193
+					goto l354
+				l355:
+					position, tokenIndex = position354, tokenIndex354
 					{
-						position209 := position
+						position370 := position
 						{
-							position210 := position
+							position371 := position
 							if c := buffer[position]; c < rune('0') || c > rune('9') {
-								goto l208
+								goto l369
 							}
 							position++
-						l211:
+						l372:
 							{
-								position212, tokenIndex212 := position, tokenIndex
+								position373, tokenIndex373 := position, tokenIndex
 								if c := buffer[position]; c < rune('0') || c > rune('9') {
-									goto l212
+									goto l373
 								}
 								position++
-								goto l211
-							l212:
-								position, tokenIndex = position212, tokenIndex212
+								goto l372
+							l373:
+								position, tokenIndex = position373, tokenIndex373
 							}
 							if buffer[position] != rune('.') {
-								goto l208
+								goto l369
 							}
 							position++
 							if c := buffer[position]; c < rune('0') || c > rune('9') {
-								goto l208
+								goto l369
 							}
 							position++
-						l213:
+						l374:
 							{
-								position214, tokenIndex214 := position, tokenIndex
+								position375, tokenIndex375 := position, tokenIndex
 								if c := buffer[position]; c < rune('0') || c > rune('9') {
-									goto l214
+									goto l375
 								}
 								position++
-								goto l213
-							l214:
-								position, tokenIndex = position214, tokenIndex214
+								goto l374
+							l375:
+								position, tokenIndex = position375, tokenIndex375
 							}
 							if buffer[position] != rune('.') {
-								goto l208
+								goto l369
 							}
 							position++
 							if c := buffer[position]; c < rune('0') || c > rune('9') {
-								goto l208
+								goto l369
 							}
 							position++
-						l215:
+						l376:
 							{
-								position216, tokenIndex216 := position, tokenIndex
+								position377, tokenIndex377 := position, tokenIndex
 								if c := buffer[position]; c < rune('0') || c > rune('9') {
-									goto l216
+									goto l377
 								}
 								position++
-								goto l215
-							l216:
-								position, tokenIndex = position216, tokenIndex216
+								goto l376
+							l377:
+								position, tokenIndex = position377, tokenIndex377
 							}
 							if buffer[position] != rune('.') {
-								goto l208
+								goto l369
 							}
 							position++
 							if c := buffer[position]; c < rune('0') || c > rune('9') {
-								goto l208
+								goto l369
 							}
 							position++
-						l217:
+						l378:
 							{
-								position218, tokenIndex218 := position, tokenIndex
+								position379, tokenIndex379 := position, tokenIndex
 								if c := buffer[position]; c < rune('0') || c > rune('9') {
-									goto l218
+									goto l379
 								}
 								position++
-								goto l217
-							l218:
-								position, tokenIndex = position218, tokenIndex218
+								goto l378
+							l379:
+								position, tokenIndex = position379, tokenIndex379
 							}
-							add(ruleIpValue, position210)
+							add(ruleIpValue, position371)
 						}
-						add(rulePegText, position209)
+						add(rulePegText, position370)
 					}
 					{
-						add(ruleAction14, position)
+						add(ruleAction44, position)
 					}
-					goto l193
-				l208:
-					position, tokenIndex = position193, tokenIndex193
+					goto l354
+				l369:
+					position, tokenIndex = position354, tokenIndex354
 					{
-						position220 := position
+						position381 := position
 						{
-							position221 := position
+							position382 := position
 							if c := buffer[position]; c < rune('0') || c > rune('9') {
-								goto l191
+								goto l352
 							}
 							position++
-						l222:
+						l383:
 							{
-								position223, tokenIndex223 := position, tokenIndex
+								position384, tokenIndex384 := position, tokenIndex
 								if c := buffer[position]; c < rune('0') || c > rune('9') {
-									goto l223
+									goto l384
 								}
 								position++
-								goto l222
-							l223:
-								position, tokenIndex = position223, tokenIndex223
+								goto l383
+							l384:
+								position, tokenIndex = position384, tokenIndex384
 							}
 							if buffer[position] != rune('-') {
-								goto l191
+								goto l352
 							}
 							position++
 							if c := buffer[position]; c < rune('0') || c > rune('9') {
-								goto l191
+								goto l352
 							}
 							position++
-						l224:
+						l385:
 							{
-								position225, tokenIndex225 := position, tokenIndex
+								position386, tokenIndex386 := position, tokenIndex
 								if c := buffer[position]; c < rune('0') || c > rune('9') {
-									goto l225
+									goto l386
 								}
 								position++
-								goto l224
-							l225:
-								position, tokenIndex = position225, tokenIndex225
+								goto l385
+							l386:
+								position, tokenIndex = position386, tokenIndex386
 							}
-							add(ruleIntRangeValue, position221)
+							add(ruleIntRangeValue, position382)
 						}
-						add(rulePegText, position220)
+						add(rulePegText, position381)
 					}
 					{
-						add(ruleAction15, position)
+						add(ruleAction45, position)
 					}
 				}
-			l193:
-				add(ruleCustomTypedValue, position192)
+			l354:
+				add(ruleCustomTypedValue, position353)
 			}
 			return true
-		l191:
-			position, tokenIndex = position191, tokenIndex191
+		l352:
+			position, tokenIndex = position352, tokenIndex352
 			return false
 		},
-		/* 16 UnquotedParamValue <- <(<UnquotedParam> Action16)> */
+		/* 32 UnquotedParamValue <- <(<UnquotedParam> Action46)> */
 		func() bool {
-			position227, tokenIndex227 := position, tokenIndex
+			position388, tokenIndex388 := position, tokenIndex
 			{
-				position228 := position
+				position389 := position
 				{
-					position229 := position
+					position390 := position
 					if !_rules[ruleUnquotedParam]() {
-						goto l227
+						goto l388
 					}
-					add(rulePegText, position229)
+					add(rulePegText, position390)
 				}
 				{
-					add(ruleAction16, position)
+					add(ruleAction46, position)
 				}
-				add(ruleUnquotedParamValue, position228)
+				add(ruleUnquotedParamValue, position389)
 			}
 			return true
-		l227:
-			position, tokenIndex = position227, tokenIndex227
+		l388:
+			position, tokenIndex = position388, tokenIndex388
 			return false
 		},
-		/* 17 UnquotedParam <- <((&('*') '*') | (&('>') '>') | (&('<') '<') | (&('@') '@') | (&('~') '~') | (&(';') ';') | (&('+') '+') | (&('/') '/') | (&(':') ':') | (&('_') '_') | (&('.') '.') | (&('-') '-') | (&('0' | '1' | '2' | '3' | '4' | '5' | '6' | '7' | '8' | '9') [0-9]) | (&('A' | 'B' | 'C' | 'D' | 'E' | 'F' | 'G' | 'H' | 'I' | 'J' | 'K' | 'L' | 'M' | 'N' | 'O' | 'P' | 'Q' | 'R' | 'S' | 'T' | 'U' | 'V' | 'W' | 'X' | 'Y' | 'Z') [A-Z]) | (&('a' | 'b' | 'c' | 'd' | 'e' | 'f' | 'g' | 'h' | 'i' | 'j' | 'k' | 'l' | 'm' | 'n' | 'o' | 'p' | 'q' | 'r' | 's' | 't' | 'u' | 'v' | 'w' | 'x' | 'y' | 'z') [a-z]))+> */
+		/* 33 UnquotedParam <- <((&('*') '*') | (&('>') '>') | (&('<') '<') | (&('@') '@') | (&('~') '~') | (&(';') ';') | (&('+') '+') | (&('/') '/') | (&(':') ':') | (&('_') '_') | (&('.') '.') | (&('-') '-') | (&('0' | '1' | '2' | '3' | '4' | '5' | '6' | '7' | '8' | '9') [0-9]) | (&('A' | 'B' | 'C' | 'D' | 'E' | 'F' | 'G' | 'H' | 'I' | 'J' | 'K' | 'L' | 'M' | 'N' | 'O' | 'P' | 'Q' | 'R' | 'S' | 'T' | 'U' | 'V' | 'W' | 'X' | 'Y' | 'Z') [A-Z]) | (&('a' | 'b' | 'c' | 'd' | 'e' | 'f' | 'g' | 'h' | 'i' | 'j' | 'k' | 'l' | 'm' | 'n' | 'o' | 'p' | 'q' | 'r' | 's' | 't' | 'u' | 'v' | 'w' | 'x' | 'y' | 'z') [a-z]))+> */
 		func() bool {
-			position231, tokenIndex231 := position, tokenIndex
+			position392, tokenIndex392 := position, tokenIndex
 			{
-				position232 := position
+				position393 := position
 				{
 					switch buffer[position] {
 					case '*':
 						if buffer[position] != rune('*') {
-							goto l231
+							goto l392
 						}
 						position++
-						break
 					case '>':
 						if buffer[position] != rune('>') {
-							goto l231
+							goto l392
 						}
 						position++
-						break
 					case '<':
 						if buffer[position] != rune('<') {
-							goto l231
+							goto l392
 						}
 						position++
-						break
 					case '@':
 						if buffer[position] != rune('@') {
-							goto l231
+							goto l392
 						}
 						position++
-						break
 					case '~':
 						if buffer[position] != rune('~') {
-							goto l231
+							goto l392
 						}
 						position++
-						break
 					case ';':
 						if buffer[position] != rune(';') {
-							goto l231
+							goto l392
 						}
 						position++
-						break
 					case '+':
 						if buffer[position] != rune('+') {
-							goto l231
+							goto l392
 						}
 						position++
-						break
 					case '/':
 						if buffer[position] != rune('/') {
-							goto l231
+							goto l392
 						}
 						position++
-						break
 					case ':':
 						if buffer[position] != rune(':') {
-							goto l231
+							goto l392
 						}
 						position++
-						break
 					case '_':
 						if buffer[position] != rune('_') {
-							goto l231
+							goto l392
 						}
 						position++
-						break
 					case '.':
 						if buffer[position] != rune('.') {
-							goto l231
+							goto l392
 						}
 						position++
-						break
 					case '-':
 						if buffer[position] != rune('-') {
-							goto l231
+							goto l392
 						}
 						position++
-						break
 					case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
 						if c := buffer[position]; c < rune('0') || c > rune('9') {
-							goto l231
+							goto l392
 						}
 						position++
-						break
 					case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
 						if c := buffer[position]; c < rune('A') || c > rune('Z') {
-							goto l231
+							goto l392
 						}
 						position++
-						break
 					default:
 						if c := buffer[position]; c < rune('a') || c > rune('z') {
-							goto l231
+							goto l392
 						}
 						position++
-						break
 					}
 				}
 
-			l233:
+			l394:
 				{
-					position234, tokenIndex234 := position, tokenIndex
+					position395, tokenIndex395 := position, tokenIndex
 					{
 						switch buffer[position] {
 						case '*':
 							if buffer[position] != rune('*') {
-								goto l234
+								goto l395
 							}
 							position++
-							break
 						case '>':
 							if buffer[position] != rune('>') {
-								goto l234
+								goto l395
 							}
 							position++
-							break
 						case '<':
 							if buffer[position] != rune('<') {
-								goto l234
+								goto l395
 							}
 							position++
-							break
 						case '@':
 							if buffer[position] != rune('@') {
-								goto l234
+								goto l395
 							}
 							position++
-							break
 						case '~':
 							if buffer[position] != rune('~') {
-								goto l234
+								goto l395
 							}
 							position++
-							break
 						case ';':
 							if buffer[position] != rune(';') {
-								goto l234
+								goto l395
 							}
 							position++
-							break
 						case '+':
 							if buffer[position] != rune('+') {
-								goto l234
+								goto l395
 							}
 							position++
-							break
 						case '/':
 							if buffer[position] != rune('/') {
-								goto l234
+								goto l395
 							}
 							position++
-							break
 						case ':':
 							if buffer[position] != rune(':') {
-								goto l234
+								goto l395
 							}
 							position++
-							break
 						case '_':
 							if buffer[position] != rune('_') {
-								goto l234
+								goto l395
 							}
 							position++
-							break
 						case '.':
 							if buffer[position] != rune('.') {
-								goto l234
+								goto l395
 							}
 							position++
-							break
 						case '-':
 							if buffer[position] != rune('-') {
-								goto l234
+								goto l395
 							}
 							position++
-							break
 						case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
 							if c := buffer[position]; c < rune('0') || c > rune('9') {
-								goto l234
+								goto l395
 							}
 							position++
-							break
 						case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
 							if c := buffer[position]; c < rune('A') || c > rune('Z') {
-								goto l234
+								goto l395
 							}
 							position++
-							break
 						default:
 							if c := buffer[position]; c < rune('a') || c > rune('z') {
-								goto l234
+								goto l395
 							}
 							position++
-							break
 						}
 					}
 
-					goto l233
-				l234:
-					position, tokenIndex = position234, tokenIndex234
+					goto l394
+				l395:
+					position, tokenIndex = position395, tokenIndex395
 				}
-				add(ruleUnquotedParam, position232)
+				add(ruleUnquotedParam, position393)
 			}
 			return true
-		l231:
-			position, tokenIndex = position231, tokenIndex231
+		l392:
+			position, tokenIndex = position392, tokenIndex392
 			return false
 		},
-		/* 18 ConcatenationValue <- <((Action17 HoleValue (WhiteSpacing '+' WhiteSpacing (QuotedStringValue / HoleValue))+ Action18) / (Action19 QuotedStringValue (WhiteSpacing '+' WhiteSpacing (QuotedStringValue / HoleValue))+ Action20))> */
-		nil,
-		/* 19 QuotedStringValue <- <(QuotedString Action21)> */
+		/* 34 ConcatenationValue <- <((Action47 HoleValue (WhiteSpacing '+' WhiteSpacing (QuotedStringValue / HoleValue))+ Action48) / (Action49 QuotedStringValue (WhiteSpacing '+' WhiteSpacing (QuotedStringValue / HoleValue))+ Action50))> */
+		func() bool {
+			position398, tokenIndex398 := position, tokenIndex
+			{
+				position399 := position
+				{
+					position400, tokenIndex400 := position, tokenIndex
+					{
+						add(ruleAction47, position)
+					}
+					if !_rules[ruleHoleValue]() {
+						goto l401
+					}
+					if !_rules[ruleWhiteSpacing]() {
+						goto l401
+					}
+					if buffer[position] != rune('+') {
+						goto l401
+					}
+					position++
+					if !_rules[ruleWhiteSpacing]() {
+						goto l401
+					}
+					{
+						position405, tokenIndex405 := position, tokenIndex
+						if !_rules[ruleQuotedStringValue]() {
+							goto l406
+						}
+						goto l405
+					l406:
+						position, tokenIndex = position405, tokenIndex405
+						if !_rules[ruleHoleValue]() {
+							goto l401
+						}
+					}
+				l405:
+				l403:
+					{
+						position404, tokenIndex404 := position, tokenIndex
+						if !_rules[ruleWhiteSpacing]() {
+							goto l404
+						}
+						if buffer[position] != rune('+') {
+							goto l404
+						}
+						position++
+						if !_rules[ruleWhiteSpacing]() {
+							goto l404
+						}
+						{
+							position407, tokenIndex407 := position, tokenIndex
+							if !_rules[ruleQuotedStringValue]() {
+								goto l408
+							}
+							goto l407
+						l408:
+							position, tokenIndex = position407, tokenIndex407
+							if !_rules[ruleHoleValue]() {
+								goto l404
+							}
+						}
+					l407:
+						goto l403
+					l404:
+						position, tokenIndex = position404, tokenIndex404
+					}
+					{
+						add(ruleAction48, position)
+					}
+					goto l400
+				l401:
+					position, tokenIndex = position400, tokenIndex400
+					{
+						add(ruleAction49, position)
+					}
+					if !_rules[ruleQuotedStringValue]() {
+						goto l398
+					}
+					if !_rules[ruleWhiteSpacing]() {
+						goto l398
+					}
+					if buffer[position] != rune('+') {
+						goto l398
+					}
+					position++
+					if !_rules[ruleWhiteSpacing]() {
+						goto l398
+					}
+					{
+						position413, tokenIndex413 := position, tokenIndex
+						if !_rules[ruleQuotedStringValue]() {
+							goto l414
+						}
+						goto l413
+					l414:
+						position, tokenIndex = position413, tokenIndex413
+						if !_rules[ruleHoleValue]() {
+							goto l398
+						}
+					}
+				l413:
+				l411:
+					{
+						position412, tokenIndex412 := position, tokenIndex
+						if !_rules[ruleWhiteSpacing]() {
+							goto l412
+						}
+						if buffer[position] != rune('+') {
+							goto l412
+						}
+						position++
+						if !_rules[ruleWhiteSpacing]() {
+							goto l412
+						}
+						{
+							position415, tokenIndex415 := position, tokenIndex
+							if !_rules[ruleQuotedStringValue]() {
+								goto l416
+							}
+							goto l415
+						l416:
+							position, tokenIndex = position415, tokenIndex415
+							if !_rules[ruleHoleValue]() {
+								goto l412
+							}
+						}
+					l415:
+						goto l411
+					l412:
+						position, tokenIndex = position412, tokenIndex412
+					}
+					{
+						add(ruleAction50, position)
+					}
+				}
+			l400:
+				add(ruleConcatenationValue, position399)
+			}
+			return true
+		l398:
+			position, tokenIndex = position398, tokenIndex398
+			return false
+		},
+		/* 35 QuotedStringValue <- <(QuotedString Action51)> */
 		func() bool {
-			position238, tokenIndex238 := position, tokenIndex
+			position418, tokenIndex418 := position, tokenIndex
 			{
-				position239 := position
+				position419 := position
 				{
-					position240 := position
+					position420 := position
 					{
-						position241, tokenIndex241 := position, tokenIndex
+						position421, tokenIndex421 := position, tokenIndex
 						if !_rules[ruleDoubleQuotedValue]() {
-							goto l242
+							goto l422
 						}
-						goto l241
-					l242:
-						position, tokenIndex = position241, tokenIndex241
+						goto l421
+					l422:
+						position, tokenIndex = position421, tokenIndex421
 						if !_rules[ruleSingleQuotedValue]() {
-							goto l238
+							goto l418
 						}
 					}
-				l241:
-					add(ruleQuotedString, position240)
+				l421:
+					add(ruleQuotedString, position420)
 				}
 				{
-					add(ruleAction21, position)
+					add(ruleAction51, position)
 				}
-				add(ruleQuotedStringValue, position239)
+				add(ruleQuotedStringValue, position419)
 			}
 			return true
-		l238:
-			position, tokenIndex = position238, tokenIndex238
+		l418:
+			position, tokenIndex = position418, tokenIndex418
 			return false
 		},
-		/* 20 QuotedString <- <(DoubleQuotedValue / SingleQuotedValue)> */
+		/* 36 QuotedString <- <(DoubleQuotedValue / SingleQuotedValue)> */
 		nil,
-		/* 21 DoubleQuotedValue <- <(DoubleQuote <(!'"' .)*> DoubleQuote)> */
+		/* 37 DoubleQuotedValue <- <(DoubleQuote <(!'"' .)*> DoubleQuote)> */
 		func() bool {
-			position245, tokenIndex245 := position, tokenIndex
+			position425, tokenIndex425 := position, tokenIndex
 			{
-				position246 := position
+				position426 := position
 				if !_rules[ruleDoubleQuote]() {
-					goto l245
+					goto l425
 				}
 				{
-					position247 := position
-				l248:
+					position427 := position
+				l428:
 					{
-						position249, tokenIndex249 := position, tokenIndex
+						position429, tokenIndex429 := position, tokenIndex
 						{
-							position250, tokenIndex250 := position, tokenIndex
+							position430, tokenIndex430 := position, tokenIndex
 							if buffer[position] != rune('"') {
-								goto l250
+								goto l430
 							}
 							position++
-							goto l249
-						l250:
-							position, tokenIndex = position250, tokenIndex250
+							goto l429
+						l430:
+							position, tokenIndex = position430, tokenIndex430
 						}
 						if !matchDot() {
-							goto l249
+							goto l429
 						}
-						goto l248
-					l249:
-						position, tokenIndex = position249, tokenIndex249
+						goto l428
+					l429:
+						position, tokenIndex = position429, tokenIndex429
 					}
-					add(rulePegText, position247)
+					add(rulePegText, position427)
 				}
 				if !_rules[ruleDoubleQuote]() {
-					goto l245
+					goto l425
 				}
-				add(ruleDoubleQuotedValue, position246)
+				add(ruleDoubleQuotedValue, position426)
 			}
 			return true
-		l245:
-			position, tokenIndex = position245, tokenIndex245
+		l425:
+			position, tokenIndex = position425, tokenIndex425
 			return false
 		},
-		/* 22 SingleQuotedValue <- <(SingleQuote <(!'\'' .)*> SingleQuote)> */
+		/* 38 SingleQuotedValue <- <(SingleQuote <(!'\'' .)*> SingleQuote)> */
 		func() bool {
-			position251, tokenIndex251 := position, tokenIndex
+			position431, tokenIndex431 := position, tokenIndex
 			{
-				position252 := position
+				position432 := position
 				if !_rules[ruleSingleQuote]() {
-					goto l251
+					goto l431
 				}
 				{
-					position253 := position
-				l254:
+					position433 := position
+				l434:
 					{
-						position255, tokenIndex255 := position, tokenIndex
+						position435, tokenIndex435 := position, tokenIndex
 						{
-							position256, tokenIndex256 := position, tokenIndex
+							position436, tokenIndex436 := position, tokenIndex
 							if buffer[position] != rune('\'') {
-								goto l256
+								goto l436
 							}
 							position++
-							goto l255
-						l256:
-							position, tokenIndex = position256, tokenIndex256
+							goto l435
+						l436:
+							position, tokenIndex = position436, tokenIndex436
 						}
 						if !matchDot() {
-							goto l255
+							goto l435
 						}
-						goto l254
-					l255:
-						position, tokenIndex = position255, tokenIndex255
+						goto l434
+					l435:
+						position, tokenIndex = position435, tokenIndex435
 					}
-					add(rulePegText, position253)
+					add(rulePegText, position433)
 				}
 				if !_rules[ruleSingleQuote]() {
-					goto l251
+					goto l431
 				}
-				add(ruleSingleQuotedValue, position252)
+				add(ruleSingleQuotedValue, position432)
 			}
 			return true
-		l251:
-			position, tokenIndex = position251, tokenIndex251
+		l431:
+			position, tokenIndex = position431, tokenIndex431
 			return false
 		},
-		/* 23 CidrValue <- <([0-9]+ '.' [0-9]+ '.' [0-9]+ '.' [0-9]+ '/' [0-9]+)> */
-		nil,
-		/* 24 IpValue <- <([0-9]+ '.' [0-9]+ '.' [0-9]+ '.' [0-9]+)> */
-		nil,
-		/* 25 IntRangeValue <- <([0-9]+ '-' [0-9]+)> */
+		/* 39 CidrValue <- <([0-9]+ '.' [0-9]+ '.' [0-9]+ '.' [0-9]+ '/' [0-9]+)> */
 		nil,
-		/* 26 RefValue <- <('$' <Identifier>)> */
+		/* 40 IpValue <- <([0-9]+ '.' [0-9]+ '.' [0-9]+ '.' [0-9]+)> */
 		nil,
-		/* 27 AliasValue <- <(('@' <UnquotedParam>) / ('@' DoubleQuotedValue) / ('@' SingleQuotedValue))> */
+		/* 41 IntRangeValue <- <([0-9]+ '-' [0-9]+)> */
 		nil,
-		/* 28 HoleValue <- <(Hole Action22)> */
+		/* 42 RefValue <- <('$' <Identifier>)> */
+		func() bool {
+			position440, tokenIndex440 := position, tokenIndex
+			{
+				position441 := position
+				if buffer[position] != rune('$') {
+					goto l440
+				}
+				position++
+				{
+					position442 := position
+					if !_rules[ruleIdentifier]() {
+						goto l440
+					}
+					add(rulePegText, position442)
+				}
+				add(ruleRefValue, position441)
+			}
+			return true
+		l440:
+			position, tokenIndex = position440, tokenIndex440
+			return false
+		},
+		/* 43 AliasValue <- <(('@' <UnquotedParam>) / ('@' DoubleQuotedValue) / ('@' SingleQuotedValue))> */
+		func() bool {
+			position443, tokenIndex443 := position, tokenIndex
+			{
+				position444 := position
+				{
+					position445, tokenIndex445 := position, tokenIndex
+					if buffer[position] != rune('@') {
+						goto l446
+					}
+					position++
+					{
+						position447 := position
+						if !_rules[ruleUnquotedParam]() {
+							goto l446
+						}
+						add(rulePegText, position447)
+					}
+					goto l445
+				l446:
+					position, tokenIndex = position445, tokenIndex445
+					if buffer[position] != rune('@') {
+						goto l448
+					}
+					position++
+					if !_rules[ruleDoubleQuotedValue]() {
+						goto l448
+					}
+					goto l445
+				l448:
+					position, tokenIndex = position445, tokenIndex445
+					if buffer[position] != rune('@') {
+						goto l443
+					}
+					position++
+					if !_rules[ruleSingleQuotedValue]() {
+						goto l443
+					}
+				}
+			l445:
+				add(ruleAliasValue, position444)
+			}
+			return true
+		l443:
+			position, tokenIndex = position443, tokenIndex443
+			return false
+		},
+		/* 44 HoleValue <- <(Hole Action52)> */
 		func() bool {
-			position262, tokenIndex262 := position, tokenIndex
+			position449, tokenIndex449 := position, tokenIndex
 			{
-				position263 := position
+				position450 := position
 				{
-					position264 := position
+					position451 := position
 					if buffer[position] != rune('{') {
-						goto l262
+						goto l449
 					}
 					position++
 					if !_rules[ruleWhiteSpacing]() {
-						goto l262
+						goto l449
 					}
 					{
-						position265 := position
+						position452 := position
 						if !_rules[ruleIdentifier]() {
-							goto l262
+							goto l449
 						}
-						add(rulePegText, position265)
+						add(rulePegText, position452)
 					}
 					if !_rules[ruleWhiteSpacing]() {
-						goto l262
+						goto l449
 					}
 					if buffer[position] != rune('}') {
-						goto l262
+						goto l449
 					}
 					position++
-					add(ruleHole, position264)
+					add(ruleHole, position451)
 				}
 				{
-					add(ruleAction22, position)
+					add(ruleAction52, position)
 				}
-				add(ruleHoleValue, position263)
+				add(ruleHoleValue, position450)
 			}
 			return true
-		l262:
-			position, tokenIndex = position262, tokenIndex262
+		l449:
+			position, tokenIndex = position449, tokenIndex449
 			return false
 		},
-		/* 29 Hole <- <('{' WhiteSpacing <Identifier> WhiteSpacing '}')> */
+		/* 45 Hole <- <('{' WhiteSpacing <Identifier> WhiteSpacing '}')> */
 		nil,
-		/* 30 HolesStringValue <- <(Action23 <(UnquotedParamValue? HoleValue UnquotedParamValue?)+> Action24)> */
+		/* 46 HolesStringValue <- <(Action53 <(UnquotedParamValue? HoleValue UnquotedParamValue?)+> Action54)> */
 		nil,
-		/* 31 HoleWithSuffixValue <- <(Action25 <(HoleValue UnquotedParamValue+ (UnquotedParamValue? HoleValue UnquotedParamValue?)*)> Action26)> */
+		/* 47 HoleWithSuffixValue <- <(Action55 <(HoleValue UnquotedParamValue+ (UnquotedParamValue? HoleValue UnquotedParamValue?)*)> Action56)> */
+		nil,
+		/* 48 FunctionValue <- <(<FunctionName> '(' Action57 WhiteSpacing (FunctionArg WhiteSpacing (',' WhiteSpacing FunctionArg WhiteSpacing)*)? ')' Action58)> */
+		func() bool {
+			position457, tokenIndex457 := position, tokenIndex
+			{
+				position458 := position
+				{
+					position459 := position
+					{
+						position460 := position
+						if c := buffer[position]; c < rune('a') || c > rune('z') {
+							goto l457
+						}
+						position++
+					l461:
+						{
+							position462, tokenIndex462 := position, tokenIndex
+							{
+								position463, tokenIndex463 := position, tokenIndex
+								if c := buffer[position]; c < rune('a') || c > rune('z') {
+									goto l464
+								}
+								position++
+								goto l463
+							l464:
+								position, tokenIndex = position463, tokenIndex463
+								if c := buffer[position]; c < rune('0') || c > rune('9') {
+									goto l462
+								}
+								position++
+							}
+						l463:
+							goto l461
+						l462:
+							position, tokenIndex = position462, tokenIndex462
+						}
+						add(ruleFunctionName, position460)
+					}
+					add(rulePegText, position459)
+				}
+				if buffer[position] != rune('(') {
+					goto l457
+				}
+				position++
+				{
+					add(ruleAction57, position)
+				}
+				if !_rules[ruleWhiteSpacing]() {
+					goto l457
+				}
+				{
+					position466, tokenIndex466 := position, tokenIndex
+					if !_rules[ruleFunctionArg]() {
+						goto l466
+					}
+					if !_rules[ruleWhiteSpacing]() {
+						goto l466
+					}
+				l468:
+					{
+						position469, tokenIndex469 := position, tokenIndex
+						if buffer[position] != rune(',') {
+							goto l469
+						}
+						position++
+						if !_rules[ruleWhiteSpacing]() {
+							goto l469
+						}
+						if !_rules[ruleFunctionArg]() {
+							goto l469
+						}
+						if !_rules[ruleWhiteSpacing]() {
+							goto l469
+						}
+						goto l468
+					l469:
+						position, tokenIndex = position469, tokenIndex469
+					}
+					goto l467
+				l466:
+					position, tokenIndex = position466, tokenIndex466
+				}
+			l467:
+				if buffer[position] != rune(')') {
+					goto l457
+				}
+				position++
+				{
+					add(ruleAction58, position)
+				}
+				add(ruleFunctionValue, position458)
+			}
+			return true
+		l457:
+			position, tokenIndex = position457, tokenIndex457
+			return false
+		},
+		/* 49 FunctionName <- <([a-z] ([a-z] / [0-9])*)> */
 		nil,
-		/* 32 Comment <- <(('#' (!EndOfLine .)*) / ('/' '/' (!EndOfLine .)*))> */
+		/* 50 FunctionArg <- <(ListValue / Value)> */
+		func() bool {
+			position472, tokenIndex472 := position, tokenIndex
+			{
+				position473 := position
+				{
+					position474, tokenIndex474 := position, tokenIndex
+					if !_rules[ruleListValue]() {
+						goto l475
+					}
+					goto l474
+				l475:
+					position, tokenIndex = position474, tokenIndex474
+					if !_rules[ruleValue]() {
+						goto l472
+					}
+				}
+			l474:
+				add(ruleFunctionArg, position473)
+			}
+			return true
+		l472:
+			position, tokenIndex = position472, tokenIndex472
+			return false
+		},
+		/* 51 Comment <- <(('#' (!EndOfLine .)*) / ('/' '/' (!EndOfLine .)*))> */
 		nil,
-		/* 33 SingleQuote <- <'\''> */
+		/* 52 SingleQuote <- <'\''> */
 		func() bool {
-			position271, tokenIndex271 := position, tokenIndex
+			position477, tokenIndex477 := position, tokenIndex
 			{
-				position272 := position
+				position478 := position
 				if buffer[position] != rune('\'') {
-					goto l271
+					goto l477
 				}
 				position++
-				add(ruleSingleQuote, position272)
+				add(ruleSingleQuote, position478)
 			}
 			return true
-		l271:
-			position, tokenIndex = position271, tokenIndex271
+		l477:
+			position, tokenIndex = position477, tokenIndex477
 			return false
 		},
-		/* 34 DoubleQuote <- <'"'> */
+		/* 53 DoubleQuote <- <'"'> */
 		func() bool {
-			position273, tokenIndex273 := position, tokenIndex
+			position479, tokenIndex479 := position, tokenIndex
 			{
-				position274 := position
+				position480 := position
 				if buffer[position] != rune('"') {
-					goto l273
+					goto l479
 				}
 				position++
-				add(ruleDoubleQuote, position274)
+				add(ruleDoubleQuote, position480)
 			}
 			return true
-		l273:
-			position, tokenIndex = position273, tokenIndex273
+		l479:
+			position, tokenIndex = position479, tokenIndex479
 			return false
 		},
-		/* 35 WhiteSpacing <- <Whitespace*> */
+		/* 54 WhiteSpacing <- <Whitespace*> */
 		func() bool {
 			{
-				position276 := position
-			l277:
+				position482 := position
+			l483:
 				{
-					position278, tokenIndex278 := position, tokenIndex
+					position484, tokenIndex484 := position, tokenIndex
 					if !_rules[ruleWhitespace]() {
-						goto l278
+						goto l484
 					}
-					goto l277
-				l278:
-					position, tokenIndex = position278, tokenIndex278
+					goto l483
+				l484:
+					position, tokenIndex = position484, tokenIndex484
 				}
-				add(ruleWhiteSpacing, position276)
+				add(ruleWhiteSpacing, position482)
 			}
 			return true
 		},
-		/* 36 MustWhiteSpacing <- <Whitespace+> */
+		/* 55 MustWhiteSpacing <- <Whitespace+> */
 		func() bool {
-			position279, tokenIndex279 := position, tokenIndex
+			position485, tokenIndex485 := position, tokenIndex
 			{
-				position280 := position
+				position486 := position
 				if !_rules[ruleWhitespace]() {
-					goto l279
+					goto l485
 				}
-			l281:
+			l487:
 				{
-					position282, tokenIndex282 := position, tokenIndex
+					position488, tokenIndex488 := position, tokenIndex
 					if !_rules[ruleWhitespace]() {
-						goto l282
+						goto l488
 					}
-					goto l281
-				l282:
-					position, tokenIndex = position282, tokenIndex282
+					goto l487
+				l488:
+					position, tokenIndex = position488, tokenIndex488
 				}
-				add(ruleMustWhiteSpacing, position280)
+				add(ruleMustWhiteSpacing, position486)
 			}
 			return true
-		l279:
-			position, tokenIndex = position279, tokenIndex279
+		l485:
+			position, tokenIndex = position485, tokenIndex485
 			return false
 		},
-		/* 37 Equal <- <(WhiteSpacing '=' WhiteSpacing)> */
+		/* 56 Equal <- <(WhiteSpacing '=' WhiteSpacing)> */
 		func() bool {
-			position283, tokenIndex283 := position, tokenIndex
+			position489, tokenIndex489 := position, tokenIndex
 			{
-				position284 := position
+				position490 := position
 				if !_rules[ruleWhiteSpacing]() {
-					goto l283
+					goto l489
 				}
 				if buffer[position] != rune('=') {
-					goto l283
+					goto l489
 				}
 				position++
 				if !_rules[ruleWhiteSpacing]() {
-					goto l283
-				}
-				add(ruleEqual, position284)
-			}
-			return true
-		l283:
-			position, tokenIndex = position283, tokenIndex283
-			return false
-		},
-		/* 38 BlankLine <- <(WhiteSpacing EndOfLine)> */
-		func() bool {
-			position285, tokenIndex285 := position, tokenIndex
-			{
-				position286 := position
-				if !_rules[ruleWhiteSpacing]() {
-					goto l285
-				}
-				if !_rules[ruleEndOfLine]() {
-					goto l285
+					goto l489
 				}
-				add(ruleBlankLine, position286)
+				add(ruleEqual, position490)
 			}
 			return true
-		l285:
-			position, tokenIndex = position285, tokenIndex285
+		l489:
+			position, tokenIndex = position489, tokenIndex489
 			return false
 		},
-		/* 39 Whitespace <- <(' ' / '\t')> */
+		/* 57 BlankLine <- <(Action59 WhiteSpacing EndOfLine Action60 Action61)> */
+		nil,
+		/* 58 Whitespace <- <(' ' / '\t')> */
 		func() bool {
-			position287, tokenIndex287 := position, tokenIndex
+			position492, tokenIndex492 := position, tokenIndex
 			{
-				position288 := position
+				position493 := position
 				{
-					position289, tokenIndex289 := position, tokenIndex
+					position494, tokenIndex494 := position, tokenIndex
 					if buffer[position] != rune(' ') {
-						goto l290
+						goto l495
 					}
 					position++
-					goto l289
-				l290:
-					position, tokenIndex = position289, tokenIndex289
+					goto l494
+				l495:
+					position, tokenIndex = position494, tokenIndex494
 					if buffer[position] != rune('\t') {
-						goto l287
+						goto l492
 					}
 					position++
 				}
-			l289:
-				add(ruleWhitespace, position288)
+			l494:
+				add(ruleWhitespace, position493)
 			}
 			return true
-		l287:
-			position, tokenIndex = position287, tokenIndex287
+		l492:
+			position, tokenIndex = position492, tokenIndex492
 			return false
 		},
-		/* 40 EndOfLine <- <(('\r' '\n') / '\n' / '\r')> */
+		/* 59 EndOfLine <- <(('\r' '\n') / '\n' / '\r')> */
 		func() bool {
-			position291, tokenIndex291 := position, tokenIndex
+			position496, tokenIndex496 := position, tokenIndex
 			{
-				position292 := position
+				position497 := position
 				{
-					position293, tokenIndex293 := position, tokenIndex
+					position498, tokenIndex498 := position, tokenIndex
 					if buffer[position] != rune('\r') {
-						goto l294
+						goto l499
 					}
 					position++
 					if buffer[position] != rune('\n') {
-						goto l294
+						goto l499
 					}
 					position++
-					goto l293
-				l294:
-					position, tokenIndex = position293, tokenIndex293
+					goto l498
+				l499:
+					position, tokenIndex = position498, tokenIndex498
 					if buffer[position] != rune('\n') {
-						goto l295
+						goto l500
 					}
 					position++
-					goto l293
-				l295:
-					position, tokenIndex = position293, tokenIndex293
+					goto l498
+				l500:
+					position, tokenIndex = position498, tokenIndex498
 					if buffer[position] != rune('\r') {
-						goto l291
+						goto l496
 					}
 					position++
 				}
-			l293:
-				add(ruleEndOfLine, position292)
+			l498:
+				add(ruleEndOfLine, position497)
+			}
+			return true
+		l496:
+			position, tokenIndex = position496, tokenIndex496
+			return false
+		},
+		/* 60 LineEnd <- <(EndOfLine / EndOfFile)> */
+		func() bool {
+			position501, tokenIndex501 := position, tokenIndex
+			{
+				position502 := position
+				{
+					position503, tokenIndex503 := position, tokenIndex
+					if !_rules[ruleEndOfLine]() {
+						goto l504
+					}
+					goto l503
+				l504:
+					position, tokenIndex = position503, tokenIndex503
+					if !_rules[ruleEndOfFile]() {
+						goto l501
+					}
+				}
+			l503:
+				add(ruleLineEnd, position502)
+			}
+			return true
+		l501:
+			position, tokenIndex = position501, tokenIndex501
+			return false
+		},
+		/* 61 EndOfFile <- <!.> */
+		func() bool {
+			position505, tokenIndex505 := position, tokenIndex
+			{
+				position506 := position
+				{
+					position507, tokenIndex507 := position, tokenIndex
+					if !matchDot() {
+						goto l507
+					}
+					goto l505
+				l507:
+					position, tokenIndex = position507, tokenIndex507
+				}
+				add(ruleEndOfFile, position506)
 			}
 			return true
-		l291:
-			position, tokenIndex = position291, tokenIndex291
+		l505:
+			position, tokenIndex = position505, tokenIndex505
 			return false
 		},
-		/* 41 EndOfFile <- <!.> */
+		/* 63 Action0 <- <{ p.NewStatement(p.positionAt(token.begin)) }> */
+		nil,
+		nil,
+		/* 65 Action1 <- <{ p.addComment(text) }> */
+		nil,
+		/* 66 Action2 <- <{ p.StatementDone() }> */
 		nil,
-		/* 43 Action0 <- <{ p.NewStatement() }> */
+		/* 67 Action3 <- <{ p.addDeclarationIdentifier(text) }> */
 		nil,
-		/* 44 Action1 <- <{ p.StatementDone() }> */
+		/* 68 Action4 <- <{ p.addValue() }> */
 		nil,
+		/* 69 Action5 <- <{ p.addAction(text) }> */
 		nil,
-		/* 46 Action2 <- <{ p.addDeclarationIdentifier(text) }> */
+		/* 70 Action6 <- <{ p.addEntity(text) }> */
 		nil,
-		/* 47 Action3 <- <{ p.addValue() }> */
+		/* 71 Action7 <- <{ p.addDeclarationIdentifier(text) }> */
 		nil,
-		/* 48 Action4 <- <{ p.addAction(text) }> */
+		/* 72 Action8 <- <{ p.addIncludePath(text) }> */
 		nil,
-		/* 49 Action5 <- <{ p.addEntity(text) }> */
+		/* 73 Action9 <- <{ p.addIncludePath(text) }> */
 		nil,
-		/* 50 Action6 <- <{ p.addParamKey(text) }> */
+		/* 74 Action10 <- <{ p.addIncludePath(text) }> */
 		nil,
-		/* 51 Action7 <- <{  p.addFirstValueInList() }> */
+		/* 75 Action11 <- <{ p.addOutputName(text) }> */
 		nil,
-		/* 52 Action8 <- <{  p.lastValueInList() }> */
+		/* 76 Action12 <- <{ p.addRetryAttempts(text) }> */
 		nil,
-		/* 53 Action9 <- <{  p.addFirstValueInList() }> */
+		/* 77 Action13 <- <{ p.addRetryBackoff(text) }> */
 		nil,
-		/* 54 Action10 <- <{  p.lastValueInList() }> */
+		/* 78 Action14 <- <{ p.NewStatement(p.positionAt(token.begin)) }> */
 		nil,
-		/* 55 Action11 <- <{  p.addAliasParam(text) }> */
+		/* 79 Action15 <- <{ p.startIf() }> */
 		nil,
-		/* 56 Action12 <- <{  p.addParamRefValue(text) }> */
+		/* 80 Action16 <- <{ p.NewStatement(p.positionAt(token.begin)) }> */
 		nil,
-		/* 57 Action13 <- <{ p.addParamCidrValue(text) }> */
+		/* 81 Action17 <- <{ p.startElseIf() }> */
 		nil,
-		/* 58 Action14 <- <{ p.addParamIpValue(text) }> */
+		/* 82 Action18 <- <{ p.startElse() }> */
 		nil,
-		/* 59 Action15 <- <{ p.addParamValue(text) }> */
+		/* 83 Action19 <- <{ p.NewStatement(p.positionAt(token.begin)) }> */
 		nil,
-		/* 60 Action16 <- <{ p.addParamValue(text) }> */
+		/* 84 Action20 <- <{ p.addLoopVariable(text) }> */
 		nil,
-		/* 61 Action17 <- <{ p.addFirstValueInConcatenation() }> */
+		/* 85 Action21 <- <{ p.startFor() }> */
 		nil,
-		/* 62 Action18 <- <{  p.lastValueInConcatenation() }> */
+		/* 86 Action22 <- <{ p.endBlock() }> */
 		nil,
-		/* 63 Action19 <- <{ p.addFirstValueInConcatenation() }> */
+		/* 87 Action23 <- <{ p.missingBlockEnd() }> */
 		nil,
-		/* 64 Action20 <- <{  p.lastValueInConcatenation() }> */
+		/* 88 Action24 <- <{ p.startOperands() }> */
 		nil,
-		/* 65 Action21 <- <{ p.addStringValue(text) }> */
+		/* 89 Action25 <- <{ p.endOperands(OrOperator) }> */
 		nil,
-		/* 66 Action22 <- <{  p.addParamHoleValue(text) }> */
+		/* 90 Action26 <- <{ p.startOperands() }> */
 		nil,
-		/* 67 Action23 <- <{ p.addFirstValueInConcatenation() }> */
+		/* 91 Action27 <- <{ p.endOperands(AndOperator) }> */
 		nil,
-		/* 68 Action24 <- <{  p.lastValueInConcatenation() }> */
+		/* 92 Action28 <- <{ p.addNotCondition() }> */
 		nil,
-		/* 69 Action25 <- <{ p.addFirstValueInConcatenation() }> */
+		/* 93 Action29 <- <{ p.addConditionValue() }> */
 		nil,
-		/* 70 Action26 <- <{  p.lastValueInConcatenation() }> */
+		/* 94 Action30 <- <{ p.addComparisonOperator(text) }> */
+		nil,
+		/* 95 Action31 <- <{ p.addComparisonCondition() }> */
+		nil,
+		/* 96 Action32 <- <{ p.addTruthCondition() }> */
+		nil,
+		/* 97 Action33 <- <{ p.addParamRefValue(text) }> */
+		nil,
+		/* 98 Action34 <- <{ p.addAliasParam(text) }> */
+		nil,
+		/* 99 Action35 <- <{ p.addParamValue(text) }> */
+		nil,
+		/* 100 Action36 <- <{ p.addParamKey(text) }> */
+		nil,
+		/* 101 Action37 <- <{  p.addFirstValueInList() }> */
+		nil,
+		/* 102 Action38 <- <{  p.lastValueInList() }> */
+		nil,
+		/* 103 Action39 <- <{  p.addFirstValueInList() }> */
+		nil,
+		/* 104 Action40 <- <{  p.lastValueInList() }> */
+		nil,
+		/* 105 Action41 <- <{  p.addAliasParam(text) }> */
+		nil,
+		/* 106 Action42 <- <{  p.addParamRefValue(text) }> */
+		nil,
+		/* 107 Action43 <- <{ p.addParamCidrValue(text) }> */
+		nil,
+		/* 108 Action44 <- <{ p.addParamIpValue(text) }> */
+		nil,
+		/* 109 Action45 <- <{ p.addParamValue(text) }> */
+		nil,
+		/* 110 Action46 <- <{ p.addParamValue(text) }> */
+		nil,
+		/* 111 Action47 <- <{ p.addFirstValueInConcatenation() }> */
+		nil,
+		/* 112 Action48 <- <{  p.lastValueInConcatenation() }> */
+		nil,
+		/* 113 Action49 <- <{ p.addFirstValueInConcatenation() }> */
+		nil,
+		/* 114 Action50 <- <{  p.lastValueInConcatenation() }> */
+		nil,
+		/* 115 Action51 <- <{ p.addStringValue(text) }> */
+		nil,
+		/* 116 Action52 <- <{  p.addParamHoleValue(text) }> */
+		nil,
+		/* 117 Action53 <- <{ p.addFirstValueInConcatenation() }> */
+		nil,
+		/* 118 Action54 <- <{  p.lastValueInConcatenation() }> */
+		nil,
+		/* 119 Action55 <- <{ p.addFirstValueInConcatenation() }> */
+		nil,
+		/* 120 Action56 <- <{  p.lastValueInConcatenation() }> */
+		nil,
+		/* 121 Action57 <- <{ p.addFirstValueInFunction(text) }> */
+		nil,
+		/* 122 Action58 <- <{ p.lastValueInFunction() }> */
+		nil,
+		/* 123 Action59 <- <{ p.NewStatement(p.positionAt(token.begin)) }> */
+		nil,
+		/* 124 Action60 <- <{ p.addComment("") }> */
+		nil,
+		/* 125 Action61 <- <{ p.StatementDone() }> */
 		nil,
 	}
 	p.rules = _rules
+	return nil
 }
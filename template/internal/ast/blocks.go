@@ -0,0 +1,154 @@
+package ast
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// BlockNode is a statement holding nested statements (i.e. conditionals and loops)
+type BlockNode interface {
+	Node
+	WithRefs
+	WithHoles
+	WithAlias
+	Blocks() [][]*Statement
+}
+
+type ForNode struct {
+	Var  string
+	List CompositeValue
+	Body []*Statement
+}
+
+func (n *ForNode) clone() Node {
+	forn := &ForNode{Var: n.Var, List: n.List.Clone()}
+	for _, st := range n.Body {
+		forn.Body = append(forn.Body, st.Clone())
+	}
+	return forn
+}
+
+func (n *ForNode) String() string {
+	var buff bytes.Buffer
+	fmt.Fprintf(&buff, "for $%s in %s {\n", n.Var, n.List)
+	writeIndentedStatements(&buff, n.Body)
+	buff.WriteString("}")
+	return buff.String()
+}
+
+func (n *ForNode) Blocks() [][]*Statement { return [][]*Statement{n.Body} }
+
+// Elements returns the values to iterate over, or false if the list is not resolved yet.
+// Elements of a list declared in the template can still be references to be resolved at run time.
+func (n *ForNode) Elements() ([]CompositeValue, bool) {
+	switch vv := n.List.(type) {
+	case *listValue:
+		var elems []CompositeValue
+		for _, val := range vv.vals {
+			elems = append(elems, val.Clone())
+		}
+		return elems, true
+	}
+	switch vv := n.List.Value().(type) {
+	case []interface{}:
+		var elems []CompositeValue
+		for _, val := range vv {
+			elems = append(elems, NewInterfaceValue(val))
+		}
+		return elems, true
+	case []string:
+		var elems []CompositeValue
+		for _, val := range vv {
+			elems = append(elems, NewInterfaceValue(val))
+		}
+		return elems, true
+	case nil:
+		return nil, false
+	default:
+		return []CompositeValue{NewInterfaceValue(vv)}, true
+	}
+}
+
+func (n *ForNode) GetHoles() []string {
+	if withHoles, ok := n.List.(WithHoles); ok {
+		return withHoles.GetHoles()
+	}
+	return nil
+}
+
+func (n *ForNode) ProcessHoles(fills map[string]interface{}) map[string]interface{} {
+	if withHoles, ok := n.List.(WithHoles); ok {
+		return withHoles.ProcessHoles(fills)
+	}
+	return make(map[string]interface{})
+}
+
+func (n *ForNode) GetRefs() []string {
+	if withRefs, ok := n.List.(WithRefs); ok {
+		return withRefs.GetRefs()
+	}
+	return nil
+}
+
+func (n *ForNode) ProcessRefs(refs map[string]interface{}) {
+	if withRefs, ok := n.List.(WithRefs); ok {
+		withRefs.ProcessRefs(refs)
+	}
+}
+
+func (n *ForNode) IsRef(key string) bool { return false }
+
+// ReplaceRef replaces the reference in the list and in the loop body, unless shadowed by the loop variable
+func (n *ForNode) ReplaceRef(key string, value CompositeValue) {
+	if withRef, ok := n.List.(WithRefs); ok {
+		if withRef.IsRef(key) {
+			n.List = value
+		} else {
+			withRef.ReplaceRef(key, value)
+		}
+	}
+	if key != n.Var {
+		ReplaceRefInStatements(n.Body, key, value)
+	}
+}
+
+func (n *ForNode) GetAliases() []string {
+	if withAlias, ok := n.List.(WithAlias); ok {
+		return withAlias.GetAliases()
+	}
+	return nil
+}
+
+func (n *ForNode) ResolveAlias(resolvFunc func(string) (string, bool)) {
+	if withAlias, ok := n.List.(WithAlias); ok {
+		withAlias.ResolveAlias(resolvFunc)
+	}
+}
+
+func NewRefValue(ref string) CompositeValue {
+	return &referenceValue{ref: ref}
+}
+
+// ReplaceRefInStatements replaces the reference in the statements, including nested ones
+func ReplaceRefInStatements(stmts []*Statement, key string, value CompositeValue) {
+	for _, st := range stmts {
+		var node Node = st.Node
+		if decl, ok := node.(*DeclarationNode); ok {
+			node = decl.Expr
+		}
+		if withRef, ok := node.(WithRefs); ok {
+			withRef.ReplaceRef(key, value)
+		}
+	}
+}
+
+func writeIndentedStatements(buff *bytes.Buffer, stmts []*Statement) {
+	for _, st := range stmts {
+		for _, line := range strings.Split(st.String(), "\n") {
+			buff.WriteString("\t")
+			buff.WriteString(line)
+			buff.WriteString("\n")
+		}
+	}
+}
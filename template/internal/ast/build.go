@@ -5,6 +5,8 @@ import (
 	"net"
 	"regexp"
 	"strconv"
+	"strings"
+	"time"
 )
 
 type parameter struct {
@@ -13,6 +15,7 @@ type parameter struct {
 }
 
 type statementBuilder struct {
+	pos                   Position
 	action                string
 	entity                string
 	declarationIdentifier string
@@ -22,27 +25,58 @@ type statementBuilder struct {
 	currentValue          CompositeValue
 	listBuilder           *listValueBuilder
 	concatenationBuilder  *concatenationValueBuilder
+	functionBuilders      []*functionValueBuilder
+	retry                 *Retry
+	isComment             bool
+	comment               string
+	isInclude             bool
+	includePath           string
+	outputName            string
+	loopVariable          string
+	condition             *conditionBuilder
 }
 
 func (b *statementBuilder) build() *Statement {
+	if b.isComment {
+		return &Statement{Node: &CommentNode{Text: b.comment}, Pos: b.pos}
+	}
+	if b.isInclude {
+		b.checkNoRetry()
+		return &Statement{Node: &IncludeNode{Namespace: b.declarationIdentifier, Path: b.includePath, Params: b.paramsMap()}, Pos: b.pos}
+	}
+	if b.outputName != "" {
+		b.checkNoRetry()
+		return &Statement{Node: &OutputNode{Name: b.outputName, ValueNode: &ValueNode{Value: b.currentValue}}, Pos: b.pos}
+	}
 	if b.action == "" && b.entity == "" && b.declarationIdentifier == "" && !b.isValue {
 		return nil
 	}
 	var expr ExpressionNode
 	if b.isValue {
+		b.checkNoRetry()
 		expr = &ValueNode{Value: b.currentValue}
 	} else {
-		cmdParams := make(map[string]CompositeValue)
-		for _, param := range b.params {
-			cmdParams[param.key] = param.value
-		}
-		expr = &CommandNode{Action: b.action, Entity: b.entity, Params: cmdParams}
+		expr = &CommandNode{Action: b.action, Entity: b.entity, Params: b.paramsMap(), Retry: b.retry, Pos: b.pos}
 	}
 	if b.declarationIdentifier != "" {
 		decl := &DeclarationNode{Ident: b.declarationIdentifier, Expr: expr}
-		return &Statement{Node: decl}
+		return &Statement{Node: decl, Pos: b.pos}
+	}
+	return &Statement{Node: expr, Pos: b.pos}
+}
+
+func (b *statementBuilder) paramsMap() map[string]CompositeValue {
+	params := make(map[string]CompositeValue)
+	for _, param := range b.params {
+		params[param.key] = param.value
+	}
+	return params
+}
+
+func (b *statementBuilder) checkNoRetry() {
+	if b.retry != nil {
+		panic(fmt.Errorf("%s: retry modifier only applies to commands", b.pos))
 	}
-	return &Statement{Node: expr}
 }
 
 func (b *statementBuilder) addParamKey(key string) *statementBuilder {
@@ -58,6 +92,9 @@ func (b *statementBuilder) addParamValue(val CompositeValue) *statementBuilder {
 	} else if b.listBuilder != nil {
 		b.listBuilder.add(b.currentValue)
 		b.currentValue = nil
+	} else if len(b.functionBuilders) > 0 {
+		b.functionBuilders[len(b.functionBuilders)-1].add(b.currentValue)
+		b.currentValue = nil
 	} else {
 		if b.currentKey != "" {
 			b.params = append(b.params, &parameter{key: b.currentKey, value: b.currentValue})
@@ -105,16 +142,200 @@ func (a *AST) addDeclarationIdentifier(text string) {
 	a.stmtBuilder.declarationIdentifier = text
 }
 
-func (a *AST) NewStatement() {
-	a.stmtBuilder = &statementBuilder{}
+func (a *AST) NewStatement(pos Position) {
+	a.stmtBuilder = &statementBuilder{pos: pos}
 }
 
-func (a *AST) StatementDone() {
+// positionAt returns the position of the first non blank character from the given buffer offset
+func (p *Peg) positionAt(offset uint32) Position {
+	pos := Position{Line: 1, Column: 1}
+	for i, r := range p.buffer {
+		switch {
+		case uint32(i) < offset && r == '\n':
+			pos.Line++
+			pos.Column = 1
+		case uint32(i) < offset || r == ' ' || r == '\t':
+			pos.Column++
+		default:
+			return pos
+		}
+	}
+	return pos
+}
 
+func (a *AST) StatementDone() {
 	if stmt := a.stmtBuilder.build(); stmt != nil {
+		a.addStatement(stmt)
+	}
+	a.stmtBuilder = nil
+}
+
+// addStatement adds the statement to the branch of the innermost block being built, if any
+func (a *AST) addStatement(stmt *Statement) {
+	if len(a.blocks) == 0 {
 		a.Statements = append(a.Statements, stmt)
+		return
+	}
+	branch := a.blocks[len(a.blocks)-1].branch
+	*branch = append(*branch, stmt)
+}
+
+// addComment adds a comment, or a blank line with an empty text
+func (a *AST) addComment(text string) {
+	a.stmtBuilder.isComment = true
+	a.stmtBuilder.comment = strings.TrimSpace(text)
+}
+
+func (a *AST) addIncludePath(text string) {
+	a.stmtBuilder.isInclude = true
+	a.stmtBuilder.includePath = text
+}
+
+func (a *AST) addOutputName(text string) {
+	a.stmtBuilder.outputName = text
+}
+
+func (a *AST) addRetryAttempts(text string) {
+	retry := &Retry{}
+	retry.Attempts, _ = strconv.Atoi(text)
+	a.stmtBuilder.retry = retry
+}
+
+// addRetryBackoff sets the retry backoff, given in seconds or as a duration (i.e. 500ms, 1m)
+func (a *AST) addRetryBackoff(text string) {
+	retry := a.stmtBuilder.retry
+	var err error
+	if secs, serr := strconv.Atoi(text); serr == nil {
+		retry.Backoff = time.Duration(secs) * time.Second
+	} else {
+		retry.Backoff, err = time.ParseDuration(text)
+	}
+	if err != nil || retry.Backoff < 0 {
+		panic(fmt.Errorf("%s: invalid retry backoff '%s'", a.stmtBuilder.pos, text))
 	}
+}
+
+// blockBuilder is an if or for block being built: its nested statements go to the current branch
+type blockBuilder struct {
+	stmt   *Statement
+	ifNode *IfNode
+	branch *[]*Statement
+}
+
+func (a *AST) startBlock(stmt *Statement, branch *[]*Statement) *blockBuilder {
+	a.addStatement(stmt)
+	block := &blockBuilder{stmt: stmt, branch: branch}
+	a.blocks = append(a.blocks, block)
 	a.stmtBuilder = nil
+	return block
+}
+
+func (a *AST) startIf() {
+	ifNode := &IfNode{Condition: a.stmtBuilder.condition.build()}
+	block := a.startBlock(&Statement{Node: ifNode, Pos: a.stmtBuilder.pos}, &ifNode.Then)
+	block.ifNode = ifNode
+}
+
+// startElseIf chains an if statement as the else branch of the current one: both end with the same '}'
+func (a *AST) startElseIf() {
+	block := a.blocks[len(a.blocks)-1]
+	elseIf := &IfNode{Condition: a.stmtBuilder.condition.build()}
+	block.ifNode.Else = []*Statement{{Node: elseIf, Pos: a.stmtBuilder.pos}}
+	block.ifNode, block.branch = elseIf, &elseIf.Then
+	a.stmtBuilder = nil
+}
+
+func (a *AST) startElse() {
+	block := a.blocks[len(a.blocks)-1]
+	block.branch = &block.ifNode.Else
+}
+
+func (a *AST) addLoopVariable(text string) {
+	a.stmtBuilder.loopVariable = text
+}
+
+func (a *AST) startFor() {
+	forNode := &ForNode{Var: a.stmtBuilder.loopVariable, List: a.stmtBuilder.currentValue}
+	a.startBlock(&Statement{Node: forNode, Pos: a.stmtBuilder.pos}, &forNode.Body)
+}
+
+func (a *AST) endBlock() {
+	a.blocks = a.blocks[:len(a.blocks)-1]
+}
+
+func (a *AST) missingBlockEnd() {
+	panic(fmt.Errorf("%s: missing closing '}'", a.blocks[len(a.blocks)-1].stmt.Pos))
+}
+
+// conditionBuilder builds the condition of an if statement, stacking
+// the operands of the logical operators being parsed
+type conditionBuilder struct {
+	conditions []*ConditionNode
+	marks      []int
+	left       CompositeValue
+	operator   string
+}
+
+func (c *conditionBuilder) push(cond *ConditionNode) {
+	c.conditions = append(c.conditions, cond)
+}
+
+func (c *conditionBuilder) pop() *ConditionNode {
+	cond := c.conditions[len(c.conditions)-1]
+	c.conditions = c.conditions[:len(c.conditions)-1]
+	return cond
+}
+
+func (c *conditionBuilder) build() *ConditionNode {
+	return c.conditions[0]
+}
+
+func (a *AST) startOperands() {
+	b := a.stmtBuilder
+	if b.condition == nil {
+		b.condition = &conditionBuilder{}
+	}
+	b.condition.marks = append(b.condition.marks, len(b.condition.conditions))
+}
+
+// endOperands groups the operands stacked since the matching startOperands with the logical operator
+func (a *AST) endOperands(op string) {
+	c := a.stmtBuilder.condition
+	mark := c.marks[len(c.marks)-1]
+	c.marks = c.marks[:len(c.marks)-1]
+	if len(c.conditions)-mark == 1 {
+		return
+	}
+	operands := make([]*ConditionNode, len(c.conditions)-mark)
+	copy(operands, c.conditions[mark:])
+	c.conditions = c.conditions[:mark]
+	c.push(NewLogicalCondition(op, operands...))
+}
+
+func (a *AST) addNotCondition() {
+	c := a.stmtBuilder.condition
+	c.push(NewLogicalCondition(NotOperator, c.pop()))
+}
+
+func (a *AST) addConditionValue() {
+	a.stmtBuilder.condition.left = a.stmtBuilder.currentValue
+	a.stmtBuilder.currentValue = nil
+}
+
+func (a *AST) addComparisonOperator(text string) {
+	a.stmtBuilder.condition.operator = text
+}
+
+func (a *AST) addComparisonCondition() {
+	b := a.stmtBuilder
+	b.condition.push(NewComparisonCondition(b.condition.operator, b.condition.left, b.currentValue))
+	b.condition.left, b.currentValue = nil, nil
+}
+
+func (a *AST) addTruthCondition() {
+	b := a.stmtBuilder
+	b.condition.push(NewTruthCondition(b.currentValue))
+	b.currentValue = nil
 }
 
 func (a *AST) addParamKey(text string) {
@@ -156,6 +377,25 @@ func (a *AST) lastValueInConcatenation() {
 	}
 }
 
+// addFirstValueInFunction starts a function call. The list being built, if any, is set aside
+// until the call is done so that the arguments (possibly lists) go to the function.
+func (a *AST) addFirstValueInFunction(name string) {
+	b := a.stmtBuilder
+	b.functionBuilders = append(b.functionBuilders, &functionValueBuilder{name: name, outerList: b.listBuilder})
+	b.listBuilder = nil
+}
+
+func (a *AST) lastValueInFunction() {
+	b := a.stmtBuilder
+	if len(b.functionBuilders) == 0 {
+		return
+	}
+	fn := b.functionBuilders[len(b.functionBuilders)-1]
+	b.functionBuilders = b.functionBuilders[:len(b.functionBuilders)-1]
+	b.listBuilder = fn.outerList
+	b.addParamValue(fn.build())
+}
+
 func (a *AST) addStringValue(text string) {
 	a.stmtBuilder.addParamValue(&interfaceValue{val: text})
 }
@@ -215,3 +455,18 @@ func (c *concatenationValueBuilder) add(v CompositeValue) *concatenationValueBui
 func (c *concatenationValueBuilder) build() CompositeValue {
 	return &concatenationValue{c.vals}
 }
+
+type functionValueBuilder struct {
+	name      string
+	args      []CompositeValue
+	outerList *listValueBuilder
+}
+
+func (f *functionValueBuilder) add(v CompositeValue) *functionValueBuilder {
+	f.args = append(f.args, v)
+	return f
+}
+
+func (f *functionValueBuilder) build() CompositeValue {
+	return &functionValue{name: f.name, args: f.args}
+}
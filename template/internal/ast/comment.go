@@ -0,0 +1,21 @@
+package ast
+
+// CommentNode is a comment of the template (i.e. '# ...' or '// ...') kept as written.
+// A CommentNode without text stands for a blank line. Comments are only kept in the
+// AST when parsing for formatting: compiled and run templates never contain any.
+type CommentNode struct {
+	Text string
+}
+
+func (n *CommentNode) clone() Node {
+	return &CommentNode{Text: n.Text}
+}
+
+func (n *CommentNode) String() string {
+	return n.Text
+}
+
+// IsBlankLine returns true when the node stands for a blank line
+func (n *CommentNode) IsBlankLine() bool {
+	return n.Text == ""
+}
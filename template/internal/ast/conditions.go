@@ -0,0 +1,342 @@
+package ast
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type IfNode struct {
+	Condition  *ConditionNode
+	Then, Else []*Statement
+}
+
+func (n *IfNode) clone() Node {
+	ifn := &IfNode{Condition: n.Condition.Clone()}
+	for _, st := range n.Then {
+		ifn.Then = append(ifn.Then, st.Clone())
+	}
+	for _, st := range n.Else {
+		ifn.Else = append(ifn.Else, st.Clone())
+	}
+	return ifn
+}
+
+func (n *IfNode) String() string {
+	var buff bytes.Buffer
+	fmt.Fprintf(&buff, "if %s {\n", n.Condition)
+	writeIndentedStatements(&buff, n.Then)
+	buff.WriteString("}")
+	if len(n.Else) == 1 {
+		if elseIf, ok := n.Else[0].Node.(*IfNode); ok {
+			fmt.Fprintf(&buff, " else %s", elseIf)
+			return buff.String()
+		}
+	}
+	if len(n.Else) > 0 {
+		buff.WriteString(" else {\n")
+		writeIndentedStatements(&buff, n.Else)
+		buff.WriteString("}")
+	}
+	return buff.String()
+}
+
+// Branch returns the statements to execute according to the evaluated condition
+func (n *IfNode) Branch() ([]*Statement, error) {
+	ok, err := n.Condition.Evaluate()
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return n.Then, nil
+	}
+	return n.Else, nil
+}
+
+func (n *IfNode) GetHoles() []string { return n.Condition.GetHoles() }
+func (n *IfNode) ProcessHoles(fills map[string]interface{}) map[string]interface{} {
+	return n.Condition.ProcessHoles(fills)
+}
+
+func (n *IfNode) GetRefs() []string                       { return n.Condition.GetRefs() }
+func (n *IfNode) ProcessRefs(refs map[string]interface{}) { n.Condition.ProcessRefs(refs) }
+func (n *IfNode) IsRef(key string) bool                   { return false }
+
+// ReplaceRef replaces the reference in the condition and in all the nested statements
+func (n *IfNode) ReplaceRef(key string, value CompositeValue) {
+	n.Condition.ReplaceRef(key, value)
+	ReplaceRefInStatements(n.Then, key, value)
+	ReplaceRefInStatements(n.Else, key, value)
+}
+
+func (n *IfNode) Blocks() [][]*Statement { return [][]*Statement{n.Then, n.Else} }
+
+func (n *IfNode) GetAliases() []string { return n.Condition.GetAliases() }
+func (n *IfNode) ResolveAlias(resolvFunc func(string) (string, bool)) {
+	n.Condition.ResolveAlias(resolvFunc)
+}
+
+const (
+	AndOperator = "&&"
+	OrOperator  = "||"
+	NotOperator = "!"
+)
+
+// ConditionNode is a boolean expression. With an empty operator, it is the truth value of Left.
+// Comparison operators apply on Left and Right values, while logical operators apply on Operands.
+type ConditionNode struct {
+	Operator    string
+	Left, Right CompositeValue
+	Operands    []*ConditionNode
+}
+
+func NewComparisonCondition(op string, left, right CompositeValue) *ConditionNode {
+	return &ConditionNode{Operator: op, Left: left, Right: right}
+}
+
+func NewLogicalCondition(op string, operands ...*ConditionNode) *ConditionNode {
+	return &ConditionNode{Operator: op, Operands: operands}
+}
+
+func NewTruthCondition(val CompositeValue) *ConditionNode {
+	return &ConditionNode{Left: val}
+}
+
+func IsComparisonOperator(op string) bool {
+	switch op {
+	case "==", "!=", "<", ">", "<=", ">=":
+		return true
+	}
+	return false
+}
+
+func (c *ConditionNode) isLogical() bool {
+	return c.Operator == AndOperator || c.Operator == OrOperator || c.Operator == NotOperator
+}
+
+func (c *ConditionNode) values() (vals []CompositeValue) {
+	if c.Left != nil {
+		vals = append(vals, c.Left)
+	}
+	if c.Right != nil {
+		vals = append(vals, c.Right)
+	}
+	return
+}
+
+func (c *ConditionNode) Evaluate() (bool, error) {
+	switch c.Operator {
+	case AndOperator:
+		for _, op := range c.Operands {
+			if ok, err := op.Evaluate(); err != nil || !ok {
+				return false, err
+			}
+		}
+		return true, nil
+	case OrOperator:
+		for _, op := range c.Operands {
+			if ok, err := op.Evaluate(); err != nil || ok {
+				return ok, err
+			}
+		}
+		return false, nil
+	case NotOperator:
+		ok, err := c.Operands[0].Evaluate()
+		return !ok, err
+	case "":
+		return isTruthy(c.Left.Value()), nil
+	}
+
+	if refs := c.GetRefs(); len(refs) > 0 {
+		return false, fmt.Errorf("condition '%s': unresolved references %q", c, refs)
+	}
+	if holes := c.GetHoles(); len(holes) > 0 {
+		return false, fmt.Errorf("condition '%s': unresolved holes %q", c, holes)
+	}
+
+	left, right := c.Left.Value(), c.Right.Value()
+	switch c.Operator {
+	case "==":
+		return fmt.Sprint(left) == fmt.Sprint(right), nil
+	case "!=":
+		return fmt.Sprint(left) != fmt.Sprint(right), nil
+	}
+
+	l, lerr := strconv.ParseFloat(fmt.Sprint(left), 64)
+	r, rerr := strconv.ParseFloat(fmt.Sprint(right), 64)
+	if lerr != nil || rerr != nil {
+		return false, fmt.Errorf("condition '%s': operator '%s' expects numbers, got '%v' and '%v'", c, c.Operator, left, right)
+	}
+	switch c.Operator {
+	case "<":
+		return l < r, nil
+	case ">":
+		return l > r, nil
+	case "<=":
+		return l <= r, nil
+	case ">=":
+		return l >= r, nil
+	default:
+		return false, fmt.Errorf("condition '%s': unknown operator '%s'", c, c.Operator)
+	}
+}
+
+// IsResolved returns true when the condition can be evaluated without any further processing
+func (c *ConditionNode) IsResolved() bool {
+	return len(c.GetHoles())+len(c.GetRefs())+len(c.GetAliases()) == 0
+}
+
+func isTruthy(v interface{}) bool {
+	switch vv := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return vv
+	case int:
+		return vv != 0
+	case float64:
+		return vv != 0
+	case string:
+		b, err := strconv.ParseBool(vv)
+		if err == nil {
+			return b
+		}
+		return vv != ""
+	case []interface{}:
+		return len(vv) > 0
+	default:
+		return true
+	}
+}
+
+func (c *ConditionNode) String() string {
+	switch c.Operator {
+	case AndOperator, OrOperator:
+		var all []string
+		for _, op := range c.Operands {
+			all = append(all, op.operandString(c.Operator))
+		}
+		return strings.Join(all, fmt.Sprintf(" %s ", c.Operator))
+	case NotOperator:
+		return "!" + c.Operands[0].operandString(NotOperator)
+	case "":
+		return c.Left.String()
+	default:
+		return fmt.Sprintf("%s %s %s", c.Left, c.Operator, c.Right)
+	}
+}
+
+func (c *ConditionNode) operandString(parentOp string) string {
+	if c.isLogical() && c.Operator != NotOperator && c.Operator != parentOp {
+		return fmt.Sprintf("(%s)", c)
+	}
+	if parentOp == NotOperator && c.Operator != "" && c.Operator != NotOperator {
+		return fmt.Sprintf("(%s)", c)
+	}
+	return c.String()
+}
+
+func (c *ConditionNode) Clone() *ConditionNode {
+	clone := &ConditionNode{Operator: c.Operator}
+	if c.Left != nil {
+		clone.Left = c.Left.Clone()
+	}
+	if c.Right != nil {
+		clone.Right = c.Right.Clone()
+	}
+	for _, op := range c.Operands {
+		clone.Operands = append(clone.Operands, op.Clone())
+	}
+	return clone
+}
+
+func (c *ConditionNode) GetHoles() (res []string) {
+	for _, op := range c.Operands {
+		res = append(res, op.GetHoles()...)
+	}
+	for _, val := range c.values() {
+		if withHoles, ok := val.(WithHoles); ok {
+			res = append(res, withHoles.GetHoles()...)
+		}
+	}
+	return
+}
+
+func (c *ConditionNode) ProcessHoles(fills map[string]interface{}) map[string]interface{} {
+	processed := make(map[string]interface{})
+	for _, op := range c.Operands {
+		for k, v := range op.ProcessHoles(fills) {
+			processed[k] = v
+		}
+	}
+	for _, val := range c.values() {
+		if withHoles, ok := val.(WithHoles); ok {
+			for k, v := range withHoles.ProcessHoles(fills) {
+				processed[k] = v
+			}
+		}
+	}
+	return processed
+}
+
+func (c *ConditionNode) GetRefs() (res []string) {
+	for _, op := range c.Operands {
+		res = append(res, op.GetRefs()...)
+	}
+	for _, val := range c.values() {
+		if withRefs, ok := val.(WithRefs); ok {
+			res = append(res, withRefs.GetRefs()...)
+		}
+	}
+	return
+}
+
+func (c *ConditionNode) ProcessRefs(refs map[string]interface{}) {
+	for _, op := range c.Operands {
+		op.ProcessRefs(refs)
+	}
+	for _, val := range c.values() {
+		if withRefs, ok := val.(WithRefs); ok {
+			withRefs.ProcessRefs(refs)
+		}
+	}
+}
+
+func (c *ConditionNode) ReplaceRef(key string, value CompositeValue) {
+	for _, op := range c.Operands {
+		op.ReplaceRef(key, value)
+	}
+	for _, val := range []*CompositeValue{&c.Left, &c.Right} {
+		if withRef, ok := (*val).(WithRefs); ok {
+			if withRef.IsRef(key) {
+				*val = value
+			} else {
+				withRef.ReplaceRef(key, value)
+			}
+		}
+	}
+}
+
+func (c *ConditionNode) GetAliases() (res []string) {
+	for _, op := range c.Operands {
+		res = append(res, op.GetAliases()...)
+	}
+	for _, val := range c.values() {
+		if withAlias, ok := val.(WithAlias); ok {
+			res = append(res, withAlias.GetAliases()...)
+		}
+	}
+	return
+}
+
+func (c *ConditionNode) ResolveAlias(resolvFunc func(string) (string, bool)) {
+	for _, op := range c.Operands {
+		op.ResolveAlias(resolvFunc)
+	}
+	for _, val := range c.values() {
+		if withAlias, ok := val.(WithAlias); ok {
+			withAlias.ResolveAlias(resolvFunc)
+		}
+	}
+}
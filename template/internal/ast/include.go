@@ -0,0 +1,115 @@
+package ast
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+)
+
+// IncludeNode is the inclusion of another template with its holes filled with the given params.
+// Declarations of the included template are prefixed by the namespace.
+type IncludeNode struct {
+	Path      string
+	Namespace string
+	Params    map[string]CompositeValue
+}
+
+func (n *IncludeNode) clone() Node {
+	include := &IncludeNode{Path: n.Path, Namespace: n.Namespace, Params: make(map[string]CompositeValue)}
+	for k, v := range n.Params {
+		include.Params[k] = v.Clone()
+	}
+	return include
+}
+
+func (n *IncludeNode) String() string {
+	var buff bytes.Buffer
+	if n.Namespace != "" {
+		fmt.Fprintf(&buff, "%s = ", n.Namespace)
+	}
+	fmt.Fprintf(&buff, "include %s", quoteString(n.Path))
+
+	var params []string
+	for k, v := range n.Params {
+		params = append(params, fmt.Sprintf("%s=%s", k, v))
+	}
+	sort.Strings(params)
+	if len(params) > 0 {
+		buff.WriteString(" with")
+		for _, p := range params {
+			buff.WriteString(" ")
+			buff.WriteString(p)
+		}
+	}
+	return buff.String()
+}
+
+// ReplaceHoleInStatements replaces the hole by the value in the statements, including nested ones
+func ReplaceHoleInStatements(stmts []*Statement, hole string, value CompositeValue) {
+	for _, st := range stmts {
+		switch n := st.Node.(type) {
+		case *DeclarationNode:
+			replaceHoleInNode(n.Expr, hole, value)
+		default:
+			replaceHoleInNode(n, hole, value)
+		}
+	}
+}
+
+func replaceHoleInNode(node Node, hole string, value CompositeValue) {
+	switch n := node.(type) {
+	case *CommandNode:
+		for k, v := range n.Params {
+			n.Params[k] = replaceHole(v, hole, value)
+		}
+	case *ValueNode:
+		n.Value = replaceHole(n.Value, hole, value)
+	case *OutputNode:
+		n.Value = replaceHole(n.Value, hole, value)
+	case *IfNode:
+		replaceHoleInCondition(n.Condition, hole, value)
+		ReplaceHoleInStatements(n.Then, hole, value)
+		ReplaceHoleInStatements(n.Else, hole, value)
+	case *ForNode:
+		n.List = replaceHole(n.List, hole, value)
+		ReplaceHoleInStatements(n.Body, hole, value)
+	case *IncludeNode:
+		for k, v := range n.Params {
+			n.Params[k] = replaceHole(v, hole, value)
+		}
+	}
+}
+
+func replaceHoleInCondition(c *ConditionNode, hole string, value CompositeValue) {
+	for _, op := range c.Operands {
+		replaceHoleInCondition(op, hole, value)
+	}
+	if c.Left != nil {
+		c.Left = replaceHole(c.Left, hole, value)
+	}
+	if c.Right != nil {
+		c.Right = replaceHole(c.Right, hole, value)
+	}
+}
+
+func replaceHole(v CompositeValue, hole string, value CompositeValue) CompositeValue {
+	switch vv := v.(type) {
+	case *holeValue:
+		if vv.hole == hole {
+			return value.Clone()
+		}
+	case *listValue:
+		for i, val := range vv.vals {
+			vv.vals[i] = replaceHole(val, hole, value)
+		}
+	case *concatenationValue:
+		for i, val := range vv.vals {
+			vv.vals[i] = replaceHole(val, hole, value)
+		}
+	case *functionValue:
+		for i, arg := range vv.args {
+			vv.args[i] = replaceHole(arg, hole, value)
+		}
+	}
+	return v
+}
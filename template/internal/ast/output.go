@@ -0,0 +1,17 @@
+package ast
+
+import "fmt"
+
+// OutputNode exposes a value of the template once run (i.e. output vpc_id = $vpc)
+type OutputNode struct {
+	Name string
+	*ValueNode
+}
+
+func (n *OutputNode) clone() Node {
+	return &OutputNode{Name: n.Name, ValueNode: n.ValueNode.clone().(*ValueNode)}
+}
+
+func (n *OutputNode) String() string {
+	return fmt.Sprintf("output %s = %s", n.Name, n.Value)
+}
@@ -0,0 +1,21 @@
+package ast
+
+import (
+	"fmt"
+	"time"
+)
+
+// Retry is the retry modifier of a command statement (i.e. 'retry 3 backoff 5s'):
+// a failing command is run again up to Attempts times, waiting Backoff before
+// the first retry and doubling the wait after each retry.
+type Retry struct {
+	Attempts int
+	Backoff  time.Duration
+}
+
+func (r *Retry) String() string {
+	if r.Backoff > 0 {
+		return fmt.Sprintf("retry %d backoff %s", r.Attempts, r.Backoff)
+	}
+	return fmt.Sprintf("retry %d", r.Attempts)
+}
@@ -24,6 +24,13 @@ type WithAlias interface {
 	ResolveAlias(func(string) (string, bool))
 }
 
+// WithFunctions is implemented by values calling built-in functions (i.e. lower({name})).
+// Calls are evaluated once all their arguments are resolved.
+type WithFunctions interface {
+	GetFunctions() []string
+	EvaluateFunctions(func(name string, args []interface{}) (interface{}, error)) error
+}
+
 type listValue struct {
 	vals []CompositeValue
 }
@@ -126,6 +133,26 @@ func (l *listValue) ResolveAlias(resolvFunc func(string) (string, bool)) {
 	}
 }
 
+func (l *listValue) GetFunctions() (res []string) {
+	for _, val := range l.vals {
+		if withFuncs, ok := val.(WithFunctions); ok {
+			res = append(res, withFuncs.GetFunctions()...)
+		}
+	}
+	return
+}
+
+func (l *listValue) EvaluateFunctions(eval func(string, []interface{}) (interface{}, error)) error {
+	for _, val := range l.vals {
+		if withFuncs, ok := val.(WithFunctions); ok {
+			if err := withFuncs.EvaluateFunctions(eval); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 func (l *listValue) Clone() CompositeValue {
 	clone := &listValue{}
 	for _, val := range l.vals {
@@ -300,7 +327,7 @@ func (c *concatenationValue) IsRef(key string) bool {
 }
 
 func (c *concatenationValue) String() string {
-	if len(c.GetHoles())+len(c.GetRefs())+len(c.GetAliases()) == 0 {
+	if len(c.GetHoles())+len(c.GetRefs())+len(c.GetAliases()) == 0 && !hasSecret(c) {
 		return quoteStringIfNeeded(c.Value().(string))
 	}
 	var elems []string
@@ -340,6 +367,204 @@ func (c *concatenationValue) Clone() CompositeValue {
 	return clone
 }
 
+type functionValue struct {
+	name string
+	args []CompositeValue
+	val  interface{}
+}
+
+func (f *functionValue) GetHoles() (res []string) {
+	for _, arg := range f.args {
+		if withHoles, ok := arg.(WithHoles); ok {
+			res = append(res, withHoles.GetHoles()...)
+		}
+	}
+	return
+}
+
+func (f *functionValue) ProcessHoles(fills map[string]interface{}) map[string]interface{} {
+	processed := make(map[string]interface{})
+	for _, arg := range f.args {
+		if withHoles, ok := arg.(WithHoles); ok {
+			for k, v := range withHoles.ProcessHoles(fills) {
+				processed[k] = v
+			}
+		}
+	}
+	return processed
+}
+
+func (f *functionValue) GetRefs() (res []string) {
+	for _, arg := range f.args {
+		if withRefs, ok := arg.(WithRefs); ok {
+			res = append(res, withRefs.GetRefs()...)
+		}
+	}
+	return
+}
+
+func (f *functionValue) ProcessRefs(fills map[string]interface{}) {
+	for _, arg := range f.args {
+		if withRefs, ok := arg.(WithRefs); ok {
+			withRefs.ProcessRefs(fills)
+		}
+	}
+}
+
+func (f *functionValue) ReplaceRef(key string, value CompositeValue) {
+	for k, arg := range f.args {
+		if withRef, ok := arg.(WithRefs); ok {
+			if withRef.IsRef(key) {
+				f.args[k] = value
+			} else {
+				withRef.ReplaceRef(key, value)
+			}
+		}
+	}
+}
+
+func (f *functionValue) IsRef(key string) bool {
+	return false
+}
+
+func (f *functionValue) GetAliases() (res []string) {
+	for _, arg := range f.args {
+		if alias, ok := arg.(WithAlias); ok {
+			res = append(res, alias.GetAliases()...)
+		}
+	}
+	return
+}
+
+func (f *functionValue) ResolveAlias(resolvFunc func(string) (string, bool)) {
+	for _, arg := range f.args {
+		if alias, ok := arg.(WithAlias); ok {
+			alias.ResolveAlias(resolvFunc)
+		}
+	}
+}
+
+func (f *functionValue) GetFunctions() (res []string) {
+	if f.val != nil {
+		return
+	}
+	for _, arg := range f.args {
+		if withFuncs, ok := arg.(WithFunctions); ok {
+			res = append(res, withFuncs.GetFunctions()...)
+		}
+	}
+	return append(res, f.String())
+}
+
+// EvaluateFunctions evaluates the nested calls first, then this call when all its arguments are resolved.
+// The result is kept so that the call is only evaluated once (i.e. for uuid() or now()).
+func (f *functionValue) EvaluateFunctions(eval func(string, []interface{}) (interface{}, error)) error {
+	if f.val != nil {
+		return nil
+	}
+	var args []interface{}
+	for _, arg := range f.args {
+		if withFuncs, ok := arg.(WithFunctions); ok {
+			if err := withFuncs.EvaluateFunctions(eval); err != nil {
+				return err
+			}
+		}
+		if !isResolved(arg) {
+			return nil
+		}
+		args = append(args, arg.Value())
+	}
+	val, err := eval(f.name, args)
+	if err != nil {
+		return fmt.Errorf("%s: %s", f, err)
+	}
+	f.val = val
+	return nil
+}
+
+func (f *functionValue) Value() interface{} {
+	return f.val
+}
+
+func (f *functionValue) String() string {
+	if f.val != nil && !hasSecret(f) {
+		return printParamValue(f.val)
+	}
+	var args []string
+	for _, arg := range f.args {
+		args = append(args, arg.String())
+	}
+	return fmt.Sprintf("%s(%s)", f.name, strings.Join(args, ", "))
+}
+
+func (f *functionValue) Clone() CompositeValue {
+	clone := &functionValue{name: f.name, val: f.val}
+	for _, arg := range f.args {
+		clone.args = append(clone.args, arg.Clone())
+	}
+	return clone
+}
+
+// MaskedSecret is printed in place of the values of secrets
+const MaskedSecret = "******"
+
+// secretValue is a secret filled in a hole (i.e. read from an environment variable):
+// given as is to the drivers but masked when printed, and so in the logged executions
+type secretValue struct {
+	val interface{}
+}
+
+func NewSecretValue(i interface{}) CompositeValue {
+	return &secretValue{val: i}
+}
+
+func (s *secretValue) Value() interface{} {
+	return s.val
+}
+
+func (s *secretValue) String() string {
+	return MaskedSecret
+}
+
+func (s *secretValue) Clone() CompositeValue {
+	return &secretValue{val: s.val}
+}
+
+// hasSecret reports whether the value is or contains a secret, as a list element,
+// a part of a concatenation or a function argument
+func hasSecret(val CompositeValue) bool {
+	var parts []CompositeValue
+	switch v := val.(type) {
+	case *secretValue:
+		return true
+	case *listValue:
+		parts = v.vals
+	case *concatenationValue:
+		parts = v.vals
+	case *functionValue:
+		parts = v.args
+	}
+	for _, part := range parts {
+		if hasSecret(part) {
+			return true
+		}
+	}
+	return false
+}
+
+func isResolved(val CompositeValue) bool {
+	if withHoles, ok := val.(WithHoles); ok && len(withHoles.GetHoles()) > 0 {
+		return false
+	}
+	if withRefs, ok := val.(WithRefs); ok && len(withRefs.GetRefs()) > 0 {
+		return false
+	}
+	if withAlias, ok := val.(WithAlias); ok && len(withAlias.GetAliases()) > 0 {
+		return false
+	}
+	return val.Value() != nil
+}
+
 type aliasValue struct {
 	alias string
 	val   interface{}
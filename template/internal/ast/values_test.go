@@ -2,6 +2,7 @@ package ast
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -181,6 +182,15 @@ func TestCompositeValuesStringer(t *testing.T) {
 			},
 			expect: "instance-toto2",
 		},
+		{
+			val:    &functionValue{name: "cidrsubnet", args: []CompositeValue{&holeValue{hole: "vpc.cidr"}, &interfaceValue{val: 8}, &interfaceValue{val: 2}}},
+			expect: "cidrsubnet({vpc.cidr}, 8, 2)",
+		},
+		{
+			val:    &functionValue{name: "join", args: []CompositeValue{&interfaceValue{val: "-"}, newCompositeValue(&referenceValue{ref: "myref"}, &functionValue{name: "uuid"})}},
+			expect: "join(-, [$myref,uuid()])",
+		},
+		{val: &functionValue{name: "lower", args: []CompositeValue{&interfaceValue{val: "My Name"}}, val: "my name"}, expect: "'my name'"},
 	}
 
 	for i, tcase := range tcases {
@@ -224,6 +234,15 @@ func TestCloneValues(t *testing.T) {
 				v.(*concatenationValue).ProcessHoles(map[string]interface{}{"hole1": "myvalue"})
 			},
 		},
+		{
+			from: &functionValue{name: "lower", args: []CompositeValue{&holeValue{hole: "myhole"}}},
+			mutationFn: func(v CompositeValue) {
+				v.(*functionValue).ProcessHoles(map[string]interface{}{"myhole": "MyValue"})
+				v.(*functionValue).EvaluateFunctions(func(name string, args []interface{}) (interface{}, error) {
+					return strings.ToLower(args[0].(string)), nil
+				})
+			},
+		},
 	}
 	for i, tcase := range tcases {
 		clone := tcase.from.Clone()
@@ -0,0 +1,283 @@
+package template
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/wallix/awless/template/internal/ast"
+)
+
+const (
+	LintError   = "error"
+	LintWarning = "warning"
+)
+
+// LintIssue is a problem found in a template without running it.
+// Line and Column are 1-based and are zero when the position is unknown.
+type LintIssue struct {
+	Line, Column   int
+	Severity, Rule string
+	Message        string
+	stmt           *ast.Statement
+	tokenRegex     *regexp.Regexp
+}
+
+func (i *LintIssue) Error() string {
+	if i.Line > 0 {
+		return fmt.Sprintf("line %d, column %d: %s", i.Line, i.Column, i.Message)
+	}
+	return i.Message
+}
+
+func newLintIssue(st *ast.Statement, severity, rule, format string, a ...interface{}) *LintIssue {
+	return &LintIssue{stmt: st, Severity: severity, Rule: rule, Message: fmt.Sprintf(format, a...)}
+}
+
+func (i *LintIssue) at(token *regexp.Regexp) *LintIssue {
+	i.tokenRegex = token
+	return i
+}
+
+// Lint parses the template text and checks it with the given validators.
+// Parsing errors are returned as error, while problems found by validators
+// are returned as issues sorted by position.
+func Lint(text string, validators ...Validator) ([]*LintIssue, error) {
+	tpl, err := Parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := strings.Split(text, "\n")
+
+	var issues []*LintIssue
+	for _, err := range tpl.Validate(validators...) {
+		issue, ok := err.(*LintIssue)
+		if !ok {
+			issue = &LintIssue{Severity: LintWarning, Message: err.Error()}
+		}
+		issue.Line, issue.Column = locateIssue(lines, issue)
+		issues = append(issues, issue)
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].Line != issues[j].Line {
+			return issues[i].Line < issues[j].Line
+		}
+		return issues[i].Column < issues[j].Column
+	})
+
+	return issues, nil
+}
+
+// LintRules returns the validators run by 'awless lint'. They do not need any
+// access to the cloud: only definitions and the given holes fillers are used.
+func LintRules(lookup DefinitionLookupFunc, fillers map[string]interface{}) []Validator {
+	return []Validator{
+		&UndefinedReferenceValidator{},
+		&DuplicateDeclarationValidator{},
+		&UnusedDeclarationValidator{},
+		&UnknownParamValidator{DefLookupFunc: lookup},
+		&UnfilledHoleValidator{Fillers: fillers},
+		&DuplicateNameValidator{},
+		&LintParamIsSetValidator{ParamIsSetValidator{Action: "create", Entity: "instance", Param: "keypair", WarningMessage: "instance has no access keypair: you might not be able to connect to it"}},
+		&NonRevertibleValidator{},
+	}
+}
+
+type UndefinedReferenceValidator struct{}
+
+func (v *UndefinedReferenceValidator) Execute(t *Template) (errs []error) {
+	for _, issue := range checkReferencesInStatements(t.Statements, make(map[string]bool)) {
+		if !issue.redeclared {
+			errs = append(errs, newLintIssue(issue.stmt, LintError, "undefined-reference", "reference '$%s' is undefined", issue.ref).at(refRegex(issue.ref)))
+		}
+	}
+	return
+}
+
+type DuplicateDeclarationValidator struct{}
+
+func (v *DuplicateDeclarationValidator) Execute(t *Template) (errs []error) {
+	for _, issue := range checkReferencesInStatements(t.Statements, make(map[string]bool)) {
+		if issue.redeclared {
+			errs = append(errs, newLintIssue(issue.stmt, LintError, "duplicate-declaration", "'%s' has already been declared", issue.ref).at(declarationRegex(issue.ref)))
+		}
+	}
+	return
+}
+
+type UnusedDeclarationValidator struct{}
+
+func (v *UnusedDeclarationValidator) Execute(t *Template) (errs []error) {
+	used := make(map[string]bool)
+	walkStatements(t.Statements, func(st *ast.Statement) {
+		for _, ref := range includingStatementRefs(st) {
+			used[ref] = true
+		}
+	})
+	walkStatements(t.Statements, func(st *ast.Statement) {
+		if decl, ok := st.Node.(*ast.DeclarationNode); ok && !used[decl.Ident] {
+			errs = append(errs, newLintIssue(st, LintWarning, "unused-declaration", "'%s' is declared but never used", decl.Ident).at(declarationRegex(decl.Ident)))
+		}
+	})
+	return
+}
+
+type UnknownParamValidator struct {
+	DefLookupFunc DefinitionLookupFunc
+}
+
+func (v *UnknownParamValidator) Execute(t *Template) (errs []error) {
+	walkStatements(t.Statements, func(st *ast.Statement) {
+		cmd := statementCommand(st)
+		if cmd == nil {
+			return
+		}
+		def, ok := lookupDefinition(v.DefLookupFunc, cmd)
+		if !ok {
+			errs = append(errs, newLintIssue(st, LintError, "unknown-command", "unknown command '%s %s'", cmd.Action, cmd.Entity))
+			return
+		}
+		keys := cmd.Keys()
+		sort.Strings(keys)
+		for _, key := range keys {
+			if !def.accepts(key) {
+				errs = append(errs, newLintIssue(st, LintError, "unknown-param", "%s %s: unexpected param '%s'", cmd.Action, cmd.Entity, key).at(paramRegex(key)))
+			}
+		}
+		for _, required := range def.Required() {
+			if _, ok := cmd.Params[required]; !ok {
+				errs = append(errs, newLintIssue(st, LintWarning, "missing-param", "%s %s: missing required param '%s' will be prompted", cmd.Action, cmd.Entity, required))
+			}
+		}
+	})
+	return
+}
+
+type UnfilledHoleValidator struct {
+	Fillers map[string]interface{}
+}
+
+func (v *UnfilledHoleValidator) Execute(t *Template) (errs []error) {
+	walkStatements(t.Statements, func(st *ast.Statement) {
+		for _, hole := range lintStatementHoles(st) {
+			if _, ok := v.Fillers[hole]; !ok {
+				errs = append(errs, newLintIssue(st, LintWarning, "unfilled-hole", "hole '{%s}' is never filled and will be prompted", hole).at(holeRegex(hole)))
+			}
+		}
+	})
+	return
+}
+
+// DuplicateNameValidator reports resources created with the same literal name
+type DuplicateNameValidator struct{}
+
+func (v *DuplicateNameValidator) Execute(t *Template) (errs []error) {
+	names := make(map[string]bool)
+	walkStatements(t.Statements, func(st *ast.Statement) {
+		cmd := statementCommand(st)
+		if cmd == nil || cmd.Action != "create" || cmd.Params["name"] == nil || !isResolvedValue(cmd.Params["name"]) {
+			return
+		}
+		key := fmt.Sprintf("%s:%v", cmd.Entity, cmd.Params["name"].Value())
+		if names[key] {
+			errs = append(errs, newLintIssue(st, LintWarning, "duplicate-name", "%s name '%v' is used more than once", cmd.Entity, cmd.Params["name"].Value()).at(paramRegex("name")))
+		}
+		names[key] = true
+	})
+	return
+}
+
+// LintParamIsSetValidator reports the same warnings as ParamIsSetValidator with their positions
+type LintParamIsSetValidator struct {
+	ParamIsSetValidator
+}
+
+func (v *LintParamIsSetValidator) Execute(t *Template) (errs []error) {
+	walkStatements(t.Statements, func(st *ast.Statement) {
+		cmd := statementCommand(st)
+		if cmd == nil || cmd.Action != v.Action || cmd.Entity != v.Entity {
+			return
+		}
+		if _, hasParam := cmd.Params[v.Param]; !hasParam {
+			errs = append(errs, newLintIssue(st, LintWarning, "missing-"+v.Param, "%s", v.WarningMessage))
+		}
+	})
+	return
+}
+
+// NonRevertibleValidator reports commands that 'awless revert' will not be able to revert
+type NonRevertibleValidator struct{}
+
+func (v *NonRevertibleValidator) Execute(t *Template) (errs []error) {
+	walkStatements(t.Statements, func(st *ast.Statement) {
+		cmd := statementCommand(st)
+		if cmd == nil || cmd.Action == "check" || cmd.Action == waitAction {
+			return
+		}
+		executed := *cmd
+		executed.CmdResult, executed.CmdErr = "executed", nil
+		if def, ok := priorStateDefs[cmd.Entity]; ok && cmd.Action == "update" {
+			executed.CmdPriorState = make(map[string]string)
+			for key := range def.values {
+				executed.CmdPriorState[key] = "recorded"
+			}
+		}
+		if !isRevertible(&executed) {
+			errs = append(errs, newLintIssue(st, LintWarning, "non-revertible", "'%s %s' cannot be reverted", cmd.Action, cmd.Entity))
+		}
+	})
+	return
+}
+
+func lintStatementHoles(st *ast.Statement) (holes []string) {
+	node := st.Node
+	if decl, ok := node.(*ast.DeclarationNode); ok {
+		node = decl.Expr
+	}
+	if include, ok := node.(*ast.IncludeNode); ok {
+		for _, val := range include.Params {
+			if withHoles, ok := val.(ast.WithHoles); ok {
+				holes = append(holes, withHoles.GetHoles()...)
+			}
+		}
+		return
+	}
+	if withHoles, ok := node.(ast.WithHoles); ok {
+		holes = withHoles.GetHoles()
+	}
+	return
+}
+
+func refRegex(ref string) *regexp.Regexp {
+	return regexp.MustCompile(`(\$` + regexp.QuoteMeta(ref) + `)(?:[^a-zA-Z0-9-_.]|$)`)
+}
+
+func holeRegex(hole string) *regexp.Regexp {
+	return regexp.MustCompile(`(\{\s*` + regexp.QuoteMeta(hole) + `\s*\})`)
+}
+
+func paramRegex(key string) *regexp.Regexp {
+	return regexp.MustCompile(`(?:^|\s)(` + regexp.QuoteMeta(key) + `)\s*=`)
+}
+
+func declarationRegex(ident string) *regexp.Regexp {
+	return regexp.MustCompile(`^\s*(` + regexp.QuoteMeta(ident) + `)\s*=`)
+}
+
+// locateIssue returns the position of the issue token in the line of its statement,
+// or the position of the statement itself
+func locateIssue(lines []string, issue *LintIssue) (line, column int) {
+	if issue.stmt == nil || !issue.stmt.Pos.IsValid() {
+		return 0, 0
+	}
+	pos := issue.stmt.Pos
+	if issue.tokenRegex != nil && pos.Line <= len(lines) {
+		if loc := issue.tokenRegex.FindStringSubmatchIndex(lines[pos.Line-1]); loc != nil {
+			return pos.Line, loc[2] + 1
+		}
+	}
+	return pos.Line, pos.Column
+}
@@ -0,0 +1,83 @@
+package template
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	text := `# network
+vpc = create vpc cidr=10.0.0.0/16 name=main
+subnet = create subnet cidr={subnet.cidr} vpc=$vpc name=main
+unused = create keypair name=mykey
+update subnet id=$subnett public=true
+create instance image=ami-1 count=1 type=t2.micro subnet=$subnet flavor=big
+vpc = create vpc cidr=10.1.0.0/16 name=main
+for $n in [a,b] {
+  create tag resource=$subnet key=k value=$n
+}
+delete bucket name={bucket.name}`
+
+	lookup := func(key string) (Definition, bool) {
+		t, ok := DefsExample[key]
+		return t, ok
+	}
+	issues, err := Lint(text, LintRules(lookup, map[string]interface{}{"bucket.name": "mybucket"})...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, issue := range issues {
+		got = append(got, fmt.Sprintf("%d:%d %s %s", issue.Line, issue.Column, issue.Severity, issue.Rule))
+	}
+	want := []string{
+		"3:29 warning unfilled-hole",
+		"4:1 warning unused-declaration",
+		"5:18 error undefined-reference",
+		"6:1 warning missing-keypair",
+		"6:66 error unknown-param",
+		"7:1 error duplicate-declaration",
+		"7:35 warning duplicate-name",
+		"11:1 error unknown-command",
+		"11:1 warning non-revertible",
+	}
+	if g, w := strings.Join(got, "\n"), strings.Join(want, "\n"); g != w {
+		t.Fatalf("got\n%s\n\nwant\n%s", g, w)
+	}
+
+	if got, want := issues[2].Error(), "line 5, column 18: reference '$subnett' is undefined"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+
+	if _, err := Lint("create vpc cidr=10.0.0.0/16\nif $a {\n"); err == nil {
+		t.Fatal("expected parsing error")
+	}
+}
+
+func TestUndefinedReferenceValidatorScopes(t *testing.T) {
+	tcases := []struct {
+		tpl  string
+		want []string
+	}{
+		{tpl: "for $n in [a,b] {\n  create tag resource=$n key=k value=v\n}\ncreate tag resource=$n key=k value=v", want: []string{"4:21 n"}},
+		{tpl: "if {cond} {\n  a = 1\n} else {\n  a = 2\n}\ncreate tag resource=$a key=k value=v"},
+		{tpl: "if {cond} {\n  a = 1\n}\ncreate tag resource=$a key=k value=v", want: []string{"4:21 a"}},
+		{tpl: "net = include vpc.awls with cidr=10.0.0.0/16\ncreate subnet vpc=$net.vpc cidr=10.0.1.0/24\ncreate subnet vpc=$other.vpc cidr=10.0.2.0/24", want: []string{"3:19 other.vpc"}},
+	}
+
+	for i, tcase := range tcases {
+		issues, err := Lint(tcase.tpl, &UndefinedReferenceValidator{})
+		if err != nil {
+			t.Fatalf("%d: %s", i+1, err)
+		}
+		var got []string
+		for _, issue := range issues {
+			got = append(got, fmt.Sprintf("%d:%d %s", issue.Line, issue.Column, strings.TrimSuffix(strings.TrimPrefix(issue.Message, "reference '$"), "' is undefined")))
+		}
+		if g, w := strings.Join(got, ","), strings.Join(tcase.want, ","); g != w {
+			t.Fatalf("%d: got %s, want %s", i+1, g, w)
+		}
+	}
+}
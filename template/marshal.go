@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -17,7 +18,14 @@ type TemplateExecution struct {
 	*Template
 	Author, Source, Locale string
 	Profile, Path, Message string
-	Fillers                map[string]interface{}
+	RevertOf, RevertedBy   string
+	// RevertedCommands are the 1-based indexes of the commands of
+	// the RevertOf template reverted by a partial revert
+	RevertedCommands []int
+	// PartiallyRevertedBy are the IDs of the partial or failed reverts of the template
+	PartiallyRevertedBy []string
+	Fillers             map[string]interface{}
+	Includes            map[string]string
 }
 
 // Date extract the date from the ulid template identifier
@@ -57,24 +65,70 @@ func (t *TemplateExecution) MarshalJSON() ([]byte, error) {
 	out.Profile = t.Profile
 	out.Message = t.Message
 	out.Path = t.Path
+	out.RevertOf = t.RevertOf
+	out.RevertedBy = t.RevertedBy
+	out.RevertedCommands = t.RevertedCommands
+	out.PartiallyRevertedBy = t.PartiallyRevertedBy
+	out.Includes = t.Includes
 	out.Fillers = t.Fillers
 	if out.Fillers == nil {
 		out.Fillers = make(map[string]interface{}, 0) // friendlier for json, avoiding "fillers": null,
 	}
 	out.Commands = []command{}
 
+	idents := t.CommandIdents()
 	for _, cmd := range t.CommandNodesIterator() {
-		newCmd := command{}
-		newCmd.Line = cmd.String()
-		if cmd.CmdErr != nil {
-			newCmd.Errors = append(newCmd.Errors, cmd.CmdErr.Error())
+		newCmd := newCommand(cmd)
+		newCmd.Ident = idents[cmd]
+		out.Commands = append(out.Commands, newCmd)
+	}
+
+	if outputs := t.Outputs(); len(outputs) > 0 {
+		out.Outputs = outputs
+	}
+
+	return json.MarshalIndent(out, "", " ")
+}
+
+func newCommand(cmd *ast.CommandNode) command {
+	newCmd := command{}
+	newCmd.Line = cmd.String()
+	if cmd.Pos.IsValid() {
+		newCmd.Position = &position{Line: cmd.Pos.Line, Column: cmd.Pos.Column}
+	}
+	if cmd.CmdErr != nil {
+		newCmd.Errors = append(newCmd.Errors, cmd.CmdErr.Error())
+	}
+	newCmd.PriorState = cmd.CmdPriorState
+	if cmd.CmdResult != nil {
+		if s, ok := cmd.CmdResult.(string); ok {
+			newCmd.Results = append(newCmd.Results, s)
 		}
-		if cmd.CmdResult != nil {
-			if s, ok := cmd.CmdResult.(string); ok {
-				newCmd.Results = append(newCmd.Results, s)
-			}
+	}
+	return newCmd
+}
+
+// ResultJSON returns the machine-readable result of the execution:
+// the status, results and errors of each command along with the template outputs
+func (t *TemplateExecution) ResultJSON() ([]byte, error) {
+	out := &resultJSON{
+		ID:         t.ID,
+		Path:       t.Path,
+		Locale:     t.Locale,
+		Profile:    t.Profile,
+		RevertedBy: t.RevertedBy,
+		Status:     okStatus,
+		Outputs:    t.Outputs(),
+		Commands:   []commandResult{},
+	}
+
+	for _, cmd := range t.CommandNodesIterator() {
+		res := commandResult{command: newCommand(cmd), Status: okStatus}
+		if cmd.CmdErr != nil {
+			res.Status = koStatus
+			out.Status = koStatus
 		}
-		out.Commands = append(out.Commands, newCmd)
+		out.Commands = append(out.Commands, res)
 	}
 
 	return json.MarshalIndent(out, "", " ")
@@ -101,6 +155,11 @@ func (t *TemplateExecution) UnmarshalJSON(b []byte) error {
 	t.Message = v.Message
 	t.Path = v.Path
 	t.Author = v.Author
+	t.RevertOf = v.RevertOf
+	t.RevertedBy = v.RevertedBy
+	t.RevertedCommands = v.RevertedCommands
+	t.PartiallyRevertedBy = v.PartiallyRevertedBy
+	t.Includes = v.Includes
 	t.Fillers = v.Fillers
 
 	tpl := &Template{ID: v.ID, AST: &ast.AST{
@@ -122,10 +181,29 @@ func (t *TemplateExecution) UnmarshalJSON(b []byte) error {
 			if len(c.Errors) > 0 {
 				n.CmdErr = errors.New(c.Errors[0])
 			}
-			tpl.Statements = append(tpl.Statements, &ast.Statement{Node: n})
+			n.CmdPriorState = c.PriorState
+			n.Pos = ast.Position{}
+			if c.Position != nil {
+				n.Pos = ast.Position{Line: c.Position.Line, Column: c.Position.Column}
+			}
+			st := &ast.Statement{Node: n, Pos: n.Pos}
+			if c.Ident != "" {
+				st.Node = &ast.DeclarationNode{Ident: c.Ident, Expr: n}
+			}
+			tpl.Statements = append(tpl.Statements, st)
 		}
 	}
 
+	var outputNames []string
+	for name := range v.Outputs {
+		outputNames = append(outputNames, name)
+	}
+	sort.Strings(outputNames)
+	for _, name := range outputNames {
+		output := &ast.OutputNode{Name: name, ValueNode: &ast.ValueNode{Value: ast.NewInterfaceValue(v.Outputs[name])}}
+		tpl.Statements = append(tpl.Statements, &ast.Statement{Node: output})
+	}
+
 	*(t.Template) = *tpl
 
 	return nil
@@ -164,19 +242,54 @@ func (t *TemplateExecution) Stats() *TemplateExecutionStats {
 }
 
 type toJSON struct {
-	ID       string                 `json:"id"`
-	Author   string                 `json:"author,omitempty"`
-	Source   string                 `json:"source"`
-	Locale   string                 `json:"locale"`
-	Profile  string                 `json:"profile,omitempty"`
-	Message  string                 `json:"message,omitempty"`
-	Path     string                 `json:"path,omitempty"`
-	Fillers  map[string]interface{} `json:"fillers"`
-	Commands []command              `json:"commands"`
+	ID                  string                 `json:"id"`
+	Author              string                 `json:"author,omitempty"`
+	Source              string                 `json:"source"`
+	Locale              string                 `json:"locale"`
+	Profile             string                 `json:"profile,omitempty"`
+	Message             string                 `json:"message,omitempty"`
+	Path                string                 `json:"path,omitempty"`
+	RevertOf            string                 `json:"revertOf,omitempty"`
+	RevertedBy          string                 `json:"revertedBy,omitempty"`
+	RevertedCommands    []int                  `json:"revertedCommands,omitempty"`
+	PartiallyRevertedBy []string               `json:"partiallyRevertedBy,omitempty"`
+	Includes            map[string]string      `json:"includes,omitempty"`
+	Fillers             map[string]interface{} `json:"fillers"`
+	Commands            []command              `json:"commands"`
+	Outputs             map[string]interface{} `json:"outputs,omitempty"`
+}
+
+const (
+	okStatus = "OK"
+	koStatus = "KO"
+)
+
+type resultJSON struct {
+	ID         string                 `json:"id"`
+	Path       string                 `json:"path,omitempty"`
+	Locale     string                 `json:"locale"`
+	Profile    string                 `json:"profile,omitempty"`
+	RevertedBy string                 `json:"revertedBy,omitempty"`
+	Status     string                 `json:"status"`
+	Commands   []commandResult        `json:"commands"`
+	Outputs    map[string]interface{} `json:"outputs"`
+}
+
+type commandResult struct {
+	command
+	Status string `json:"status"`
 }
 
 type command struct {
-	Line    string   `json:"line"`
-	Errors  []string `json:"errors,omitempty"`
-	Results []string `json:"results,omitempty"`
+	Line       string            `json:"line"`
+	Ident      string            `json:"ident,omitempty"`
+	Position   *position         `json:"position,omitempty"`
+	Errors     []string          `json:"errors,omitempty"`
+	Results    []string          `json:"results,omitempty"`
+	PriorState map[string]string `json:"priorState,omitempty"`
+}
+
+type position struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
 }
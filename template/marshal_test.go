@@ -220,9 +220,9 @@ func TestTemplateExecutionMarshalToJSON(t *testing.T) {
 				"id": "12345",
 				"author": "michael",
 				"commands": [
-					{"errors": ["first error"], "results": ["first result"], "line": "create vpc"},
-					{"line": "create subnet"},
-					{"errors": ["third error"], "results": ["third result"], "line": "create instance"}
+					{"errors": ["first error"], "results": ["first result"], "line": "create vpc", "position": {"line": 1, "column": 1}},
+					{"line": "create subnet", "position": {"line": 2, "column": 1}},
+					{"errors": ["third error"], "results": ["third result"], "line": "create instance", "position": {"line": 3, "column": 1}}
 				]
 		     }`,
 		},
@@ -239,8 +239,8 @@ func TestTemplateExecutionMarshalToJSON(t *testing.T) {
 			  "fillers": {"two": "2"},
 			  "id": "",
 			  "commands": [
-			    {"line": "create subnet cidr=10.0.0.0/24"},
-			    {"line": "create instance name=@myinst"}
+			    {"line": "create subnet cidr=10.0.0.0/24", "position": {"line": 1, "column": 1}},
+			    {"line": "create instance name=@myinst", "position": {"line": 2, "column": 1}}
 			  ]
 			}`,
 		},
@@ -258,7 +258,7 @@ func TestTemplateExecutionMarshalToJSON(t *testing.T) {
 			  "fillers": {"three": "3"},
 				"id": "",
 				"commands": [
-					{"line": "create instance name='my instance'"}
+					{"line": "create instance name='my instance'", "position": {"line": 1, "column": 1}}
 				]
 			}`,
 		},
@@ -273,7 +273,7 @@ func TestTemplateExecutionMarshalToJSON(t *testing.T) {
 			  "locale": "eu-central-1",
 				"id": "",
 				"commands": [
-					{"line": "create instance name=\"my instance '$&\\ special) chars\""}
+					{"line": "create instance name=\"my instance '$&\\ special) chars\"", "position": {"line": 1, "column": 1}}
 				]
 			}`,
 		},
@@ -289,7 +289,7 @@ func TestTemplateExecutionMarshalToJSON(t *testing.T) {
 			  "locale": "eu-central-1",
 				"id": "",
 				"commands": [
-					{"line": "create loadbalancer subnets=[subnet-1234,subnet-2345]"}
+					{"line": "create loadbalancer subnets=[subnet-1234,subnet-2345]", "position": {"line": 1, "column": 1}}
 				]
 			}`,
 		},
@@ -321,3 +321,85 @@ func identJSON(content []byte) string {
 	}
 	return string(ident)
 }
+
+func TestTemplateExecutionRevertLinksRoundTrip(t *testing.T) {
+	tplExec := &TemplateExecution{Template: MustParse("create vpc"), RevertOf: "01BA7RV6ES86PZYCM3H28WM6KZ", RevertedBy: "01BA7RV6ES86PZYCM3H28WM6KY"}
+	b, err := tplExec.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	unmarshalled := &TemplateExecution{}
+	if err = unmarshalled.UnmarshalJSON(b); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := unmarshalled.RevertOf, "01BA7RV6ES86PZYCM3H28WM6KZ"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := unmarshalled.RevertedBy, "01BA7RV6ES86PZYCM3H28WM6KY"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+
+	b, err = (&TemplateExecution{Template: MustParse("create vpc")}).MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(b), "revert") {
+		t.Fatalf("unexpected revert links in %s", b)
+	}
+}
+
+func TestTemplateExecutionResultJSON(t *testing.T) {
+	tpl := MustParse("vpc = create vpc\ncreate subnet\noutput vpc_id = $vpc")
+	tpl.ID = "12345"
+	for i, cmd := range tpl.CommandNodesIterator() {
+		if i == 0 {
+			cmd.CmdResult = "vpc-1234"
+		}
+		if i == 1 {
+			cmd.CmdErr = errors.New("subnet error")
+		}
+	}
+	for _, st := range tpl.Statements {
+		if output, ok := st.Node.(*ast.OutputNode); ok {
+			output.ProcessRefs(map[string]interface{}{"vpc": "vpc-1234"})
+		}
+	}
+	tplExec := &TemplateExecution{Template: tpl, Locale: "eu-west-1", Path: "/tmp/infra.aws"}
+
+	b, err := tplExec.ResultJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got, want map[string]interface{}
+	if err = json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	if err = json.Unmarshal([]byte(`{
+		"id": "12345",
+		"path": "/tmp/infra.aws",
+		"locale": "eu-west-1",
+		"status": "KO",
+		"commands": [
+			{"line": "create vpc", "position": {"line": 1, "column": 1}, "status": "OK", "results": ["vpc-1234"]},
+			{"line": "create subnet", "position": {"line": 2, "column": 1}, "status": "KO", "errors": ["subnet error"]}
+		],
+		"outputs": {"vpc_id": "vpc-1234"}
+	}`), &want); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got\n%s\nwant\n%v", b, want)
+	}
+
+	b, err = tplExec.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	unmarshalled := &TemplateExecution{}
+	if err = unmarshalled.UnmarshalJSON(b); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := unmarshalled.Outputs(), map[string]interface{}{"vpc_id": "vpc-1234"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
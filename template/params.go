@@ -0,0 +1,260 @@
+package template
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/wallix/awless/template/internal/ast"
+)
+
+// MaskedSecret replaces the value of secrets in printed templates and persisted template executions
+const MaskedSecret = ast.MaskedSecret
+
+// envSecret matches a value read from an environment variable, i.e. ${DB_PASSWORD}
+var envSecret = regexp.MustCompile(`^\$\{([a-zA-Z_][a-zA-Z0-9_]*)\}$`)
+
+// LoadParamsFiles reads the holes fillers of YAML (.yml, .yaml), JSON (.json) or dotenv (.env) files.
+// Files are layered: a param of a file overrides the same param of the files before it (i.e. common then
+// environment specific values). Nested YAML and JSON objects fill holes with dotted names (instance.type).
+// Values of the form ${VAR} are read from the environment and returned as secrets, to be masked once run.
+func LoadParamsFiles(paths ...string) (params map[string]interface{}, secrets []string, err error) {
+	params = make(map[string]interface{})
+	isSecret := make(map[string]bool)
+
+	for _, path := range paths {
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("params file: %s", err)
+		}
+		var raw map[string]interface{}
+		switch ext := strings.ToLower(filepath.Ext(path)); ext {
+		case ".yml", ".yaml":
+			raw, err = parseYAMLParams(content)
+		case ".json":
+			raw, err = parseJSONParams(content)
+		case ".env":
+			raw, err = parseDotEnvParams(content)
+		default:
+			err = fmt.Errorf("unsupported extension '%s' (expecting .yml, .yaml, .json or .env)", ext)
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("params file %s: %s", path, err)
+		}
+
+		for k, v := range raw {
+			s, isString := v.(string)
+			if !isString {
+				params[k] = v
+				delete(isSecret, k)
+				continue
+			}
+			if matches := envSecret.FindStringSubmatch(s); len(matches) > 1 {
+				secret, ok := os.LookupEnv(matches[1])
+				if !ok {
+					return nil, nil, fmt.Errorf("params file %s: %s: environment variable %s is not set", path, k, matches[1])
+				}
+				params[k] = secret
+				isSecret[k] = true
+				continue
+			}
+			params[k] = v
+			delete(isSecret, k)
+		}
+	}
+
+	for k := range isSecret {
+		secrets = append(secrets, k)
+	}
+	sort.Strings(secrets)
+	return params, secrets, nil
+}
+
+// paramValue converts a raw value as given on the command line (i.e. ints, CIDRs, lists)
+func paramValue(key, raw string) interface{} {
+	if envSecret.MatchString(raw) || !MatchStringParamValue(raw) {
+		return raw
+	}
+	params, err := ParseParams(fmt.Sprintf("%s=%s", key, raw))
+	if err != nil {
+		return raw
+	}
+	return params[key]
+}
+
+func parseJSONParams(content []byte) (map[string]interface{}, error) {
+	var obj map[string]interface{}
+	if err := json.Unmarshal(content, &obj); err != nil {
+		return nil, err
+	}
+	params := make(map[string]interface{})
+	flattenParams("", obj, params)
+	return params, nil
+}
+
+func flattenParams(prefix string, obj map[string]interface{}, params map[string]interface{}) {
+	for k, v := range obj {
+		key := prefix + k
+		switch vv := v.(type) {
+		case map[string]interface{}:
+			flattenParams(key+".", vv, params)
+		case []interface{}:
+			var list []interface{}
+			for _, elem := range vv {
+				list = append(list, jsonNumber(elem))
+			}
+			params[key] = list
+		default:
+			params[key] = jsonNumber(v)
+		}
+	}
+}
+
+func jsonNumber(v interface{}) interface{} {
+	if f, ok := v.(float64); ok && f == math.Trunc(f) {
+		return int(f)
+	}
+	return v
+}
+
+// parseDotEnvParams reads KEY=VALUE lines, optionally prefixed with export
+func parseDotEnvParams(content []byte) (map[string]interface{}, error) {
+	params := make(map[string]interface{})
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimSpace(strings.TrimPrefix(line, "export "))
+		splits := strings.SplitN(line, "=", 2)
+		if len(splits) != 2 || strings.TrimSpace(splits[0]) == "" {
+			return nil, fmt.Errorf("line %d: expecting KEY=VALUE", lineNum)
+		}
+		key, value := strings.TrimSpace(splits[0]), strings.TrimSpace(splits[1])
+		if unquoted, ok := unquote(value); ok {
+			params[key] = unquoted
+		} else {
+			params[key] = paramValue(key, value)
+		}
+	}
+	return params, scanner.Err()
+}
+
+// parseYAMLParams reads the subset of YAML used for params: nested mappings of scalars,
+// flow lists ([a, b]) and block lists (- a), with comments
+func parseYAMLParams(content []byte) (map[string]interface{}, error) {
+	params := make(map[string]interface{})
+	type level struct {
+		indent int
+		prefix string
+	}
+	var levels []level
+	var listKey string
+	var listIndent int
+	// placeholders are the keys without value, until their nested mapping or list items are read
+	placeholders := make(map[string]bool)
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		text := stripYAMLComment(scanner.Text())
+		trimmed := strings.TrimSpace(text)
+		if trimmed == "" || trimmed == "---" {
+			continue
+		}
+		indent := len(text) - len(strings.TrimLeft(text, " "))
+		if strings.ContainsRune(text[:indent], '\t') {
+			return nil, fmt.Errorf("line %d: tabs are not allowed for indentation", lineNum)
+		}
+
+		if strings.HasPrefix(trimmed, "- ") || trimmed == "-" {
+			if listKey == "" || indent < listIndent {
+				return nil, fmt.Errorf("line %d: unexpected list item", lineNum)
+			}
+			item := strings.TrimSpace(strings.TrimPrefix(trimmed, "-"))
+			list, _ := params[listKey].([]interface{})
+			params[listKey] = append(list, yamlScalar(listKey, item))
+			delete(placeholders, listKey)
+			continue
+		}
+		listKey = ""
+
+		for len(levels) > 0 && indent <= levels[len(levels)-1].indent {
+			levels = levels[:len(levels)-1]
+		}
+		var prefix string
+		if len(levels) > 0 {
+			prefix = levels[len(levels)-1].prefix
+		}
+
+		splits := strings.SplitN(trimmed, ":", 2)
+		if len(splits) != 2 || strings.TrimSpace(splits[0]) == "" {
+			return nil, fmt.Errorf("line %d: expecting 'key: value'", lineNum)
+		}
+		key := prefix + strings.TrimSpace(splits[0])
+		value := strings.TrimSpace(splits[1])
+
+		switch {
+		case value == "":
+			// either a nested mapping or a block list
+			levels = append(levels, level{indent: indent, prefix: key + "."})
+			listKey, listIndent = key, indent
+			params[key] = []interface{}{}
+			placeholders[key] = true
+		case strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]"):
+			list := []interface{}{}
+			for _, item := range strings.Split(value[1:len(value)-1], ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					list = append(list, yamlScalar(key, item))
+				}
+			}
+			params[key] = list
+		default:
+			params[key] = yamlScalar(key, value)
+		}
+	}
+
+	for k := range placeholders {
+		delete(params, k)
+	}
+	return params, scanner.Err()
+}
+
+func yamlScalar(key, value string) interface{} {
+	if unquoted, ok := unquote(value); ok {
+		return unquoted
+	}
+	return paramValue(key, value)
+}
+
+func stripYAMLComment(line string) string {
+	var quote rune
+	for i, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return strings.TrimRight(line[:i], " \t")
+		}
+	}
+	return strings.TrimRight(line, " \t")
+}
+
+func unquote(s string) (string, bool) {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1], true
+	}
+	return s, false
+}
@@ -0,0 +1,107 @@
+package template
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadParamsFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "awless-params")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	common := write("common.yml", `# shared by all environments
+vpc:
+  cidr: 10.0.0.0/16
+instance:
+  type: t2.micro   # smallest
+  count: 1
+  name: 'web server'
+subnets: [subnet-1, subnet-2]
+zones:
+  - eu-west-1a
+  - eu-west-1b
+`)
+	staging := write("staging.json", `{"instance": {"type": "t2.small", "count": 2}, "db.password": "${AWLESS_TEST_DB_PASSWORD}"}`)
+	dotenv := write("local.env", "# local overrides\nexport instance.count=3\nkeypair=\"my key\"\n")
+
+	os.Setenv("AWLESS_TEST_DB_PASSWORD", "s3cr3t")
+	defer os.Unsetenv("AWLESS_TEST_DB_PASSWORD")
+
+	params, secrets, err := LoadParamsFiles(common, staging, dotenv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{
+		"vpc.cidr":       "10.0.0.0/16",
+		"instance.type":  "t2.small",
+		"instance.count": 3,
+		"instance.name":  "web server",
+		"subnets":        []interface{}{"subnet-1", "subnet-2"},
+		"zones":          []interface{}{"eu-west-1a", "eu-west-1b"},
+		"db.password":    "s3cr3t",
+		"keypair":        "my key",
+	}
+	if got, want := params, expected; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %#v, want %#v", got, want)
+	}
+	if got, want := secrets, []string{"db.password"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	if _, _, err = LoadParamsFiles(write("bad.yml", "instance type\n")); err == nil {
+		t.Fatal("expected error for invalid yaml")
+	}
+	if _, _, err = LoadParamsFiles(write("params.txt", "a=b")); err == nil {
+		t.Fatal("expected error for unsupported extension")
+	}
+	if _, _, err = LoadParamsFiles(write("unset.env", "password=${AWLESS_TEST_UNSET}")); err == nil {
+		t.Fatal("expected error for unset environment variable")
+	}
+}
+
+func TestSecretsAreMaskedOncePrinted(t *testing.T) {
+	env := NewEnv()
+	env.DefLookupFunc = func(in string) (Definition, bool) {
+		t, ok := DefsExample[in]
+		return t, ok
+	}
+	env.AddFillers(map[string]interface{}{"user.password": "s3cr3t", "user.name": "jdoe", "prefix": "s3cr3t"})
+	env.Secrets = []string{"user.password", "unused"}
+
+	compiled, env, err := Compile(MustParse(`create vpc cidr=10.0.0.0/16 name={user.password}
+create keypair name={prefix}
+create tag key={user.name} resource=i-1234 value={prefix}+'-'+{user.password}`), env)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `create vpc cidr=10.0.0.0/16 name=******
+create keypair name=s3cr3t
+create tag key=jdoe resource=i-1234 value='s3cr3t'+'-'+'******'`
+	if got, want := compiled.String(), expected; got != want {
+		t.Fatalf("got\n%s\nwant\n%s", got, want)
+	}
+	cmds := compiled.CommandNodesIterator()
+	if got, want := cmds[0].ToDriverParams()["name"], "s3cr3t"; got != want {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if got, want := cmds[2].ToDriverParams()["value"], "s3cr3t-s3cr3t"; got != want {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if got, want := env.GetProcessedFillers(), map[string]interface{}{"user.password": MaskedSecret, "user.name": "jdoe", "prefix": "s3cr3t"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
@@ -28,6 +28,12 @@ import (
 )
 
 func Parse(text string) (tmpl *Template, err error) {
+	return parse(text, false)
+}
+
+// parse builds the template from its text. With keepComments, comments and blank
+// lines are kept in the AST as comment nodes (i.e. to format the template).
+func parse(text string, keepComments bool) (tmpl *Template, err error) {
 	defer func() { // as peg lib does not allow errors in Execute, we use panic to build the AST
 		if rerr := recover(); rerr != nil {
 			switch rerr.(type) {
@@ -55,7 +61,26 @@ func Parse(text string) (tmpl *Template, err error) {
 	p.Execute()
 
 	tmpl.AST = p.AST
+	if !keepComments {
+		tmpl.Statements = removeComments(tmpl.Statements)
+	}
+
+	return
+}
 
+// removeComments drops comments and blank lines from the statements, including nested ones
+func removeComments(stmts []*ast.Statement) (out []*ast.Statement) {
+	for _, st := range stmts {
+		switch n := st.Node.(type) {
+		case *ast.CommentNode:
+			continue
+		case *ast.IfNode:
+			n.Then, n.Else = removeComments(n.Then), removeComments(n.Else)
+		case *ast.ForNode:
+			n.Body = removeComments(n.Body)
+		}
+		out = append(out, st)
+	}
 	return
 }
 
@@ -104,31 +129,65 @@ func newParseError(templText, pegErrMsg string) (perr *parseError) {
 	return
 }
 
+// parseErrorContextLines is the number of lines displayed around the failing line
+const parseErrorContextLines = 2
+
 func (pe *parseError) Error() string {
 	if pe.invalidIndexes() {
 		return pe.origMsg
 	}
 
+	failing := pe.lines[pe.line-1]
+	column := pe.end
+	if column < pe.start {
+		column = pe.start
+	}
+
 	var buff bytes.Buffer
-	buff.WriteString(fmt.Sprintf("error parsing template at line %d (char %d):\n", pe.line, pe.start))
+	buff.WriteString(fmt.Sprintf("error parsing template at line %d, column %d: ", pe.line, column))
+	if column > len(failing) {
+		buff.WriteString("unexpected end of line")
+	} else {
+		buff.WriteString(fmt.Sprintf("unexpected '%c'", failing[column-1]))
+	}
 
-	for i, l := range pe.lines {
-		buff.WriteByte('\t')
-		if pe.line == i+1 {
+	first, last := pe.line-parseErrorContextLines, pe.line+parseErrorContextLines
+	if first < 1 {
+		first = 1
+	}
+	if last > len(pe.lines) {
+		last = len(pe.lines)
+	}
+	for i := first; i <= last; i++ {
+		buff.WriteString("\n\t")
+		if i == pe.line {
 			buff.WriteString("-> ")
-			buff.WriteString(l[0:pe.start])
+			buff.WriteString(pe.lines[i-1])
+			buff.WriteString("\n\t   ")
+			buff.WriteString(caretIndent(failing, column))
+			buff.WriteByte('^')
 		} else {
 			buff.WriteString("   ")
-			buff.WriteString(l)
-		}
-		if i < len(pe.lines)-1 {
-			buff.WriteByte('\n')
+			buff.WriteString(pe.lines[i-1])
 		}
 	}
 
 	return buff.String()
 }
 
+// caretIndent returns the blanks to align a caret under the given column, keeping tabs
+func caretIndent(line string, column int) string {
+	var indent bytes.Buffer
+	for i := 0; i < column-1 && i < len(line); i++ {
+		if line[i] == '\t' {
+			indent.WriteByte('\t')
+		} else {
+			indent.WriteByte(' ')
+		}
+	}
+	return indent.String()
+}
+
 func (pe *parseError) invalidIndexes() bool {
 	if pe.line == 0 {
 		return true
@@ -74,6 +74,12 @@ func TestParseVariousTemplatesCorrectly(t *testing.T) {
 		{"support concatenation with '+' of quoted string and holes", "instance = create instance name='prefix-'+{instance.name}+{instance.version}+'-suffix'", ""},
 		{"support concatenation with '+' of quoted string and holes", "instance = create instance name='pre${}fix-' + {instance.name}+'middle-' +{instance.version}+ '-suffix'", "instance = create instance name='pre${}fix-'+{instance.name}+'middle-'+{instance.version}+'-suffix'"},
 		{"support concatenation with '+' of quoted string and holes with a hole as prefix", "instance = create instance name={instance.name}+'midl${}fix-'+'midle2${}fix-'+{instance.version}+'-suffix'", ""},
+		{"support function calls", "create subnet cidr=cidrsubnet({vpc.cidr}, 8, 2) vpc=$vpc", ""},
+		{"support function calls without args", "create instance name=uuid()", ""},
+		{"support function calls spacing", "create instance name=join( '-' ,[web, {env}] )", "create instance name=join(-, [web,{env}])"},
+		{"support nested function calls", "create instance userdata=base64(file(\"user data.sh\")) name=lower('prefix-'+{name})", "create instance name=lower('prefix-'+{name}) userdata=base64(file('user data.sh'))"},
+		{"support function calls in values", "ami = lookup({amis}, @region)", ""},
+		{"support function calls in lists", "create loadbalancer subnets=[lower(A), $sub]", "create loadbalancer subnets=[lower(A),$sub]"},
 	}
 
 	for _, tcase := range tcases {
@@ -248,7 +254,7 @@ func TestWrapPegParseError(t *testing.T) {
 			t.Fatalf("got %d, want %d", got, want)
 		}
 
-		exp := "error parsing template at line 2 (char 23):\n\t   create subnet\n\t-> create instance type= w\n\t   create vpc"
+		exp := "error parsing template at line 2, column 28: unexpected '='\n\t   create subnet\n\t-> create instance type= wrong=\n\t                              ^\n\t   create vpc"
 		if got, want := err.Error(), exp; got != want {
 			t.Fatalf("got\n\n%s\n\nwant\n\n%s\n", got, want)
 		}
@@ -271,6 +277,183 @@ func TestWrapPegParseError(t *testing.T) {
 	})
 }
 
+func TestParseConditionalBlocks(t *testing.T) {
+	tcases := []struct {
+		text, expect string
+	}{
+		{
+			text:   "if {env} == prod {\ncreate vpc cidr=10.0.0.0/16\n}",
+			expect: "if {env} == prod {\n\tcreate vpc cidr=10.0.0.0/16\n}",
+		},
+		{
+			text:   "if {count}>2 && !{dry} {\ncreate vpc cidr=10.0.0.0/16 # counted\n}",
+			expect: "if {count} > 2 && !{dry} {\n\tcreate vpc cidr=10.0.0.0/16\n}",
+		},
+		{
+			text: `vpc = create vpc cidr=10.0.0.0/16
+if $vpc != '' && ({count} > 2 || !{dry}) {
+	# comment
+	create subnet vpc=$vpc
+} else {
+	create subnet vpc=vpc-1234
+}
+create keypair name=test`,
+			expect: `vpc = create vpc cidr=10.0.0.0/16
+if $vpc != '' && ({count} > 2 || !{dry}) {
+	create subnet vpc=$vpc
+} else {
+	create subnet vpc=vpc-1234
+}
+create keypair name=test`,
+		},
+		{
+			text: `if {env} == "prod" {
+  if {size} >= 3 {
+    create instance type=t2.large
+  }
+} else if {env} == staging {
+  create instance type=t2.small
+} else {
+  create instance type=t2.nano
+}`,
+			expect: `if {env} == prod {
+	if {size} >= 3 {
+		create instance type=t2.large
+	}
+} else if {env} == staging {
+	create instance type=t2.small
+} else {
+	create instance type=t2.nano
+}`,
+		},
+	}
+
+	for i, tcase := range tcases {
+		tpl, err := Parse(tcase.text)
+		if err != nil {
+			t.Fatalf("%d: %s", i+1, err)
+		}
+		if got, want := tpl.String(), tcase.expect; got != want {
+			t.Fatalf("%d: got\n%s\nwant\n%s", i+1, got, want)
+		}
+		if _, err := Parse(tpl.String()); err != nil {
+			t.Fatalf("%d: cannot parse printed template: %s", i+1, err)
+		}
+	}
+
+	t.Run("Invalid blocks", func(t *testing.T) {
+		tcases := []struct {
+			text, expErr string
+		}{
+			{text: "if {env} == prod {\ncreate vpc", expErr: "line 1, column 1: missing closing '}'"},
+			{text: "create vpc\n}", expErr: "line 2, column 1: unexpected '}'"},
+			{text: "if {env} == {\ncreate vpc\n}", expErr: "line 1, column 13: unexpected '{'"},
+			{text: "if ({env} {\ncreate vpc\n}", expErr: "line 1, column 11: unexpected '{'"},
+			{text: "if {env} {\ncreate vpc\n} else {\ncreate vpc\n} else {\n}", expErr: "line 5, column 3: unexpected 'e'"},
+		}
+		for i, tcase := range tcases {
+			_, err := Parse(tcase.text)
+			if err == nil {
+				t.Fatalf("%d: expected error", i+1)
+			}
+			if got, want := err.Error(), tcase.expErr; !strings.Contains(got, want) {
+				t.Fatalf("%d: got %s, want %s", i+1, got, want)
+			}
+		}
+	})
+
+	t.Run("Keep line numbers in parse errors", func(t *testing.T) {
+		_, err := Parse("if {env} {\ncreate vpc\n}\ncreate instance type= wrong=")
+		perr, ok := err.(*parseError)
+		if !ok {
+			t.Fatalf("expected parse error, got %T", err)
+		}
+		if got, want := perr.line, 4; got != want {
+			t.Fatalf("got %d, want %d", got, want)
+		}
+	})
+}
+
+func TestParseLoopBlocks(t *testing.T) {
+	tcases := []struct {
+		text, expect string
+	}{
+		{
+			text:   "for $s in [sub-1, sub-2] {\ncreate instance subnet=$s\n}",
+			expect: "for $s in [sub-1,sub-2] {\n\tcreate instance subnet=$s\n}",
+		},
+		{
+			text: `for $s in {subnets} {
+  inst = create instance subnet=$s
+  for $n in $names {
+    create tag key=Name resource=$inst value=$n
+  }
+}`,
+			expect: `for $s in {subnets} {
+	inst = create instance subnet=$s
+	for $n in $names {
+		create tag key=Name resource=$inst value=$n
+	}
+}`,
+		},
+	}
+
+	for i, tcase := range tcases {
+		tpl, err := Parse(tcase.text)
+		if err != nil {
+			t.Fatalf("%d: %s", i+1, err)
+		}
+		if got, want := tpl.String(), tcase.expect; got != want {
+			t.Fatalf("%d: got\n%s\nwant\n%s", i+1, got, want)
+		}
+	}
+
+	if _, err := Parse("for $s in [a,b] {\ncreate vpc\n} else {\n}"); err == nil || !strings.Contains(err.Error(), "line 3, column 3: unexpected 'e'") {
+		t.Fatalf("expected error, got %v", err)
+	}
+}
+
+func TestParseIncludes(t *testing.T) {
+	tcases := []struct {
+		text, expect string
+	}{
+		{text: "include network.aws", expect: "include 'network.aws'"},
+		{text: "include 'network.aws' with cidr=10.0.0.0/16 name={env.name}", expect: "include 'network.aws' with cidr=10.0.0.0/16 name={env.name}"},
+		{text: "create keypair name=k\nnet = include \"../common/network.aws\" with vpc=$myvpc", expect: "create keypair name=k\nnet = include '../common/network.aws' with vpc=$myvpc"},
+		{text: "if {multi} {\n  include multi.aws\n}", expect: "if {multi} {\n\tinclude 'multi.aws'\n}"},
+	}
+
+	for i, tcase := range tcases {
+		tpl, err := Parse(tcase.text)
+		if err != nil {
+			t.Fatalf("%d: %s", i+1, err)
+		}
+		if got, want := tpl.String(), tcase.expect; got != want {
+			t.Fatalf("%d: got\n%s\nwant\n%s", i+1, got, want)
+		}
+	}
+}
+
+func TestParseOutputs(t *testing.T) {
+	tcases := []struct {
+		text, expect string
+	}{
+		{text: "output vpc_id = $vpc", expect: "output vpc_id = $vpc"},
+		{text: "vpc = create vpc cidr=10.0.0.0/16\noutput   vpc_id=$vpc\noutput name = {vpc.name}", expect: "vpc = create vpc cidr=10.0.0.0/16\noutput vpc_id = $vpc\noutput name = {vpc.name}"},
+		{text: "output subnets = [$sub1,$sub2]", expect: "output subnets = [$sub1,$sub2]"},
+	}
+
+	for i, tcase := range tcases {
+		tpl, err := Parse(tcase.text)
+		if err != nil {
+			t.Fatalf("%d: %s", i+1, err)
+		}
+		if got, want := tpl.String(), tcase.expect; got != want {
+			t.Fatalf("%d: got\n%s\nwant\n%s", i+1, got, want)
+		}
+	}
+}
+
 func TestParamsOnlyParsing(t *testing.T) {
 	tcases := []struct {
 		input string
@@ -1013,3 +1196,65 @@ func isValueNode(n ast.Node) error {
 	}
 	return nil
 }
+
+func TestParsePositions(t *testing.T) {
+	text := `# comment
+vpc = create vpc cidr=10.0.0.0/16
+
+if {env} == "prod" {
+  create subnet vpc=$vpc cidr=10.0.0.0/24
+} else if {env} == "dev" {
+    create subnet vpc=$vpc cidr=10.0.1.0/24
+}
+for $n in [a,b] {
+	create tag resource=$vpc key=$n value=v
+}
+output id = $vpc`
+
+	tpl := MustParse(text)
+
+	var got []string
+	walkStatements(tpl.Statements, func(st *ast.Statement) {
+		got = append(got, fmt.Sprintf("%d:%d", st.Pos.Line, st.Pos.Column))
+		if cmd := statementCommand(st); cmd != nil && cmd.Pos != st.Pos {
+			t.Fatalf("command '%s': got %s, want %s", cmd, cmd.Pos, st.Pos)
+		}
+	})
+	if got, want := strings.Join(got, " "), "2:1 4:1 5:3 6:1 7:5 9:1 10:2 12:1"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+
+	if got, want := tpl.Statements[0].Clone().Pos, (ast.Position{Line: 2, Column: 1}); got != want {
+		t.Fatalf("got %#v, want %#v", got, want)
+	}
+}
+
+func TestParseRetryModifiers(t *testing.T) {
+	tcases := []struct {
+		text, expect, expErr string
+	}{
+		{text: "create instance name=web retry 3 backoff 5s", expect: "create instance name=web retry 3 backoff 5s"},
+		{text: "inst = create instance name=web retry 2 backoff 10", expect: "inst = create instance name=web retry 2 backoff 10s"},
+		{text: "delete subnet id=sub-1234 retry 1   # flaky", expect: "delete subnet id=sub-1234 retry 1"},
+		{text: "for $n in [a,b] {\n  create tag key=$n retry 2 backoff 1m\n}", expect: "for $n in [a,b] {\n\tcreate tag key=$n retry 2 backoff 1m0s\n}"},
+		{text: "create instance name=retry", expect: "create instance name=retry"},
+		{text: "create instance name=web retry 3 backoff soon", expErr: "invalid retry backoff 'soon'"},
+		{text: "name = {instance.name} retry 3", expErr: "retry modifier only applies to commands"},
+	}
+
+	for i, tcase := range tcases {
+		tpl, err := Parse(tcase.text)
+		if tcase.expErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tcase.expErr) {
+				t.Fatalf("%d: got %v, want error containing '%s'", i+1, err, tcase.expErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%d: %s", i+1, err)
+		}
+		if got, want := tpl.String(), tcase.expect; got != want {
+			t.Fatalf("%d: got\n%s\nwant\n%s", i+1, got, want)
+		}
+	}
+}
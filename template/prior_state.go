@@ -0,0 +1,246 @@
+package template
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/wallix/awless/cloud/properties"
+	"github.com/wallix/awless/graph"
+	"github.com/wallix/awless/template/internal/ast"
+)
+
+// priorStateDef describes how to record the state of a resource before its update,
+// so that the update can later be reverted by restoring the recorded values
+type priorStateDef struct {
+	// idParams are the params identifying the updated resource, kept as is in the revert
+	idParams []string
+	find     func(g *graph.Graph, params map[string]interface{}) (*graph.Resource, error)
+	// values extract from the resource the value of each param the update may change
+	values map[string]func(*graph.Resource) (interface{}, bool)
+}
+
+var priorStateDefs = map[string]priorStateDef{
+	"instance": {
+		idParams: []string{"id"},
+		find:     findById("id"),
+		values: map[string]func(*graph.Resource) (interface{}, bool){
+			"type": propertyValue(properties.Type),
+		},
+	},
+	"subnet": {
+		idParams: []string{"id"},
+		find:     findById("id"),
+		values: map[string]func(*graph.Resource) (interface{}, bool){
+			"public": propertyValue(properties.Public),
+		},
+	},
+	"targetgroup": {
+		idParams: []string{"id"},
+		find:     findById("id"),
+		values: map[string]func(*graph.Resource) (interface{}, bool){
+			"healthcheckinterval": propertyValue(properties.CheckInterval),
+			"healthcheckpath":     propertyValue(properties.CheckPath),
+			"healthcheckport":     propertyValue(properties.CheckPort),
+			"healthcheckprotocol": propertyValue(properties.CheckProtocol),
+			"healthchecktimeout":  propertyValue(properties.CheckTimeout),
+			"healthythreshold":    propertyValue(properties.HealthyThresholdCount),
+			"unhealthythreshold":  propertyValue(properties.UnhealthyThresholdCount),
+			"matcher":             propertyValue(properties.CheckHTTPCode),
+		},
+	},
+	"scalinggroup": {
+		idParams: []string{"name"},
+		find:     findByName,
+		values: map[string]func(*graph.Resource) (interface{}, bool){
+			"cooldown":                 propertyValue(properties.DefaultCooldown),
+			"desired-capacity":         propertyValue(properties.DesiredCapacity),
+			"healthcheck-grace-period": propertyValue(properties.HealthCheckGracePeriod),
+			"healthcheck-type":         propertyValue(properties.HealthCheckType),
+			"launchconfiguration":      propertyValue(properties.LaunchConfigurationName),
+			"max-size":                 propertyValue(properties.MaxSize),
+			"min-size":                 propertyValue(properties.MinSize),
+			"new-instances-protected":  propertyValue(properties.NewInstancesProtected),
+		},
+	},
+	"record": {
+		idParams: []string{"zone", "name", "type"},
+		find:     findRecord,
+		values: map[string]func(*graph.Resource) (interface{}, bool){
+			"ttl":   propertyValue(properties.TTL),
+			"value": singleRecordValue,
+		},
+	},
+	"bucket": {
+		idParams: []string{"name"},
+		find:     findById("name"),
+		values: map[string]func(*graph.Resource) (interface{}, bool){
+			"acl": cannedACL,
+		},
+	},
+	"distribution": {
+		idParams: []string{"id"},
+		find:     findById("id"),
+		values: map[string]func(*graph.Resource) (interface{}, bool){
+			"enable": propertyValue(properties.Enabled),
+		},
+	},
+}
+
+// recordPriorState records in the update command the current values of the params
+// it is about to change, as found on the freshly fetched target resource.
+// Params whose previous value cannot be known are not recorded, making the command not revertible.
+func recordPriorState(n *ast.CommandNode, env *Env) error {
+	def, ok := priorStateDefs[n.Entity]
+	if !ok || n.Action != "update" || env.FetchGraphFunc == nil {
+		return nil
+	}
+
+	g, err := env.FetchGraphFunc(n.Entity)
+	if err != nil {
+		return err
+	}
+	params := n.ToDriverParams()
+	res, err := def.find(g, params)
+	if err != nil {
+		return err
+	}
+	if res == nil {
+		return fmt.Errorf("%s %s not found", n.Entity, params[def.idParams[0]])
+	}
+
+	state := make(map[string]string)
+	for key := range params {
+		if foundIn(key, def.idParams) {
+			continue
+		}
+		if valueFn, ok := def.values[key]; ok {
+			if v, ok := valueFn(res); ok {
+				state[key] = priorStateValue(v)
+			}
+		}
+	}
+	n.CmdPriorState = state
+	return nil
+}
+
+// isRevertibleFromPriorState reports whether all the params changed by the update command
+// have a recorded prior value
+func isRevertibleFromPriorState(cmd *ast.CommandNode) bool {
+	def, ok := priorStateDefs[cmd.Entity]
+	if !ok || cmd.CmdPriorState == nil {
+		return false
+	}
+	var changed int
+	for key := range cmd.Params {
+		if foundIn(key, def.idParams) {
+			continue
+		}
+		if _, ok := cmd.CmdPriorState[key]; !ok {
+			return false
+		}
+		changed++
+	}
+	return changed > 0
+}
+
+// priorStateValue formats a recorded value as a param value, so that it is stored
+// and restored as is (i.e. numbers not turning into floats through JSON)
+func priorStateValue(v interface{}) string {
+	switch vv := v.(type) {
+	case float64:
+		return strconv.FormatFloat(vv, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(vv), 'f', -1, 32)
+	}
+	return fmt.Sprint(v)
+}
+
+func findById(param string) func(*graph.Graph, map[string]interface{}) (*graph.Resource, error) {
+	return func(g *graph.Graph, params map[string]interface{}) (*graph.Resource, error) {
+		return g.FindResource(fmt.Sprint(params[param]))
+	}
+}
+
+func findByName(g *graph.Graph, params map[string]interface{}) (*graph.Resource, error) {
+	resources, err := g.FindResourcesByProperty(properties.Name, fmt.Sprint(params["name"]))
+	if err != nil || len(resources) == 0 {
+		return nil, err
+	}
+	return resources[0], nil
+}
+
+// findRecord finds the record by name (with or without the trailing dot) and type
+func findRecord(g *graph.Graph, params map[string]interface{}) (*graph.Resource, error) {
+	resources, err := g.GetAllResources("record")
+	if err != nil {
+		return nil, err
+	}
+	name := strings.TrimSuffix(fmt.Sprint(params["name"]), ".")
+	for _, res := range resources {
+		resName := strings.TrimSuffix(fmt.Sprint(res.Properties[properties.Name]), ".")
+		if strings.EqualFold(resName, name) && strings.EqualFold(fmt.Sprint(res.Properties[properties.Type]), fmt.Sprint(params["type"])) {
+			return res, nil
+		}
+	}
+	return nil, nil
+}
+
+func propertyValue(key string) func(*graph.Resource) (interface{}, bool) {
+	return func(res *graph.Resource) (interface{}, bool) {
+		v, ok := res.Properties[key]
+		return v, ok && v != nil && fmt.Sprint(v) != ""
+	}
+}
+
+// singleRecordValue returns the value of records with a single value,
+// the only ones update commands can restore
+func singleRecordValue(res *graph.Resource) (interface{}, bool) {
+	switch v := res.Properties[properties.Records].(type) {
+	case []string:
+		if len(v) == 1 {
+			return v[0], true
+		}
+	case []interface{}:
+		if len(v) == 1 {
+			return v[0], true
+		}
+	}
+	return nil, false
+}
+
+const (
+	allUsersGroup           = "http://acs.amazonaws.com/groups/global/AllUsers"
+	authenticatedUsersGroup = "http://acs.amazonaws.com/groups/global/AuthenticatedUsers"
+)
+
+// cannedACL deduces the canned ACL of a bucket from its grants.
+// Grants matching no canned ACL (i.e. custom grants to other accounts) cannot be restored.
+func cannedACL(res *graph.Resource) (interface{}, bool) {
+	grants, ok := res.Properties[properties.Grants].([]*graph.Grant)
+	if !ok {
+		return nil, false
+	}
+	groups := make(map[string]bool)
+	for _, grant := range grants {
+		switch {
+		case strings.HasSuffix(grant.Grantee.GranteeID, allUsersGroup), strings.HasSuffix(grant.Grantee.GranteeID, authenticatedUsersGroup):
+			groups[grant.Grantee.GranteeID[strings.LastIndex(grant.Grantee.GranteeID, "/")+1:]+" "+grant.Permission] = true
+		case grant.Grantee.GranteeType == "CanonicalUser" && grant.Permission == "FULL_CONTROL":
+		default:
+			return nil, false
+		}
+	}
+
+	switch {
+	case len(groups) == 0:
+		return "private", true
+	case len(groups) == 1 && groups["AllUsers READ"]:
+		return "public-read", true
+	case len(groups) == 2 && groups["AllUsers READ"] && groups["AllUsers WRITE"]:
+		return "public-read-write", true
+	case len(groups) == 1 && groups["AuthenticatedUsers READ"]:
+		return "authenticated-read", true
+	}
+	return nil, false
+}
@@ -0,0 +1,112 @@
+package template
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/wallix/awless/cloud/properties"
+	"github.com/wallix/awless/graph"
+)
+
+func TestRevertUpdatesFromPriorState(t *testing.T) {
+	resources := map[string]*graph.Resource{}
+	add := func(entity, id string, props map[string]interface{}) {
+		res := graph.InitResource(entity, id)
+		for k, v := range props {
+			res.Properties[k] = v
+		}
+		resources[entity] = res
+	}
+	add("instance", "i-1234", map[string]interface{}{properties.Type: "t2.micro"})
+	add("subnet", "subnet-1234", map[string]interface{}{properties.Public: false})
+	add("targetgroup", "arn:aws:tg", map[string]interface{}{properties.CheckPath: "/health", properties.CheckInterval: 30})
+	add("scalinggroup", "arn:aws:asg", map[string]interface{}{properties.Name: "my-asg", properties.MinSize: 1, properties.MaxSize: 3})
+	add("record", "rec-1234", map[string]interface{}{properties.Name: "www.example.com.", properties.Type: "A", properties.TTL: 2592000, properties.Records: []string{"1.2.3.4"}})
+	add("bucket", "my-bucket", map[string]interface{}{properties.Grants: []*graph.Grant{
+		{Permission: "FULL_CONTROL", Grantee: graph.Grantee{GranteeID: "owner", GranteeType: "CanonicalUser"}},
+		{Permission: "READ", Grantee: graph.Grantee{GranteeID: allUsersGroup, GranteeType: "Group"}},
+	}})
+	add("distribution", "dist-1234", map[string]interface{}{properties.Enabled: true})
+
+	env := NewEnv()
+	env.Driver = &noopDriver{}
+	env.FetchGraphFunc = func(entity string) (*graph.Graph, error) {
+		g := graph.NewGraph()
+		if res, ok := resources[entity]; ok {
+			g.AddResource(res)
+		}
+		return g, nil
+	}
+
+	tcases := []struct {
+		in, exp string
+	}{
+		{in: "update instance id=i-1234 type=t2.large", exp: "update instance id=i-1234 type=t2.micro"},
+		{in: "update subnet id=subnet-1234 public=true", exp: "update subnet id=subnet-1234 public=false"},
+		{in: "update targetgroup healthcheckinterval=10 healthcheckpath=/ id=arn:aws:tg", exp: "update targetgroup healthcheckinterval=30 healthcheckpath=/health id=arn:aws:tg"},
+		{in: "update scalinggroup max-size=10 name=my-asg", exp: "update scalinggroup max-size=3 name=my-asg"},
+		{in: "update record name=www.example.com ttl=60 type=A value=5.6.7.8 zone=Z123", exp: "update record name=www.example.com ttl=2592000 type=A value=1.2.3.4 zone=Z123"},
+		{in: "update bucket acl=private name=my-bucket", exp: "update bucket acl=public-read name=my-bucket"},
+		{in: "update distribution enable=false id=dist-1234", exp: "update distribution enable=true id=dist-1234"},
+	}
+
+	for i, tcase := range tcases {
+		ran, err := MustParse(tcase.in).Run(env)
+		if err != nil {
+			t.Fatalf("%d: %s", i+1, err)
+		}
+		// the prior state is stored along with the execution
+		b, err := json.Marshal(&TemplateExecution{Template: ran})
+		if err != nil {
+			t.Fatalf("%d: %s", i+1, err)
+		}
+		stored := &TemplateExecution{}
+		if err = json.Unmarshal(b, stored); err != nil {
+			t.Fatalf("%d: %s", i+1, err)
+		}
+		if !IsRevertible(stored.Template) {
+			t.Fatalf("%d: expected '%s' to be revertible", i+1, tcase.in)
+		}
+		reverted, err := stored.Revert()
+		if err != nil {
+			t.Fatalf("%d: %s", i+1, err)
+		}
+		if got, want := reverted.String(), tcase.exp; got != want {
+			t.Fatalf("%d: got %s, want %s", i+1, got, want)
+		}
+	}
+}
+
+func TestUpdatesWithoutPriorStateAreNotRevertible(t *testing.T) {
+	env := NewEnv()
+	env.Driver = &noopDriver{}
+	env.FetchGraphFunc = func(entity string) (*graph.Graph, error) {
+		g := graph.NewGraph()
+		res := graph.InitResource("instance", "i-1234")
+		res.Properties[properties.Type] = "t2.micro"
+		g.AddResource(res)
+		return g, nil
+	}
+
+	tcases := []string{
+		"update instance id=i-1234 lock=true",
+		"update instance id=i-1234 lock=true type=t2.large",
+		"update instance id=i-5678 type=t2.large",
+		"update loginprofile username=john password=secret",
+	}
+	for i, tcase := range tcases {
+		ran, err := MustParse(tcase).Run(env)
+		if err != nil {
+			t.Fatalf("%d: %s", i+1, err)
+		}
+		if IsRevertible(ran) {
+			t.Fatalf("%d: expected '%s' not to be revertible", i+1, tcase)
+		}
+	}
+
+	ran, _ := MustParse("update instance id=i-1234 type=t2.large").Run(env)
+	if got, want := ran.CommandNodesIterator()[0].CmdPriorState, map[string]string{"type": "t2.micro"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %#v, want %#v", got, want)
+	}
+}
@@ -2,6 +2,8 @@ package template
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/wallix/awless/template/internal/ast"
@@ -160,8 +162,8 @@ func (te *Template) Revert() (*Template, error) {
 					params = append(params, fmt.Sprintf("id=%s", quoteParamIfNeeded(cmd.CmdResult)))
 				}
 			case "update":
-				switch cmd.Entity {
-				case "securitygroup":
+				switch {
+				case cmd.Entity == "securitygroup":
 					for k, v := range cmd.Params {
 						if k == "inbound" || k == "outbound" {
 							if fmt.Sprint(v) == "authorize" {
@@ -173,6 +175,18 @@ func (te *Template) Revert() (*Template, error) {
 						}
 						params = append(params, fmt.Sprintf("%s=%v", k, quoteParamIfNeeded(v)))
 					}
+				default:
+					for _, k := range priorStateDefs[cmd.Entity].idParams {
+						params = append(params, fmt.Sprintf("%s=%v", k, quoteParamIfNeeded(cmd.Params[k])))
+					}
+					var keys []string
+					for k := range cmd.CmdPriorState {
+						keys = append(keys, k)
+					}
+					sort.Strings(keys)
+					for _, k := range keys {
+						params = append(params, fmt.Sprintf("%s=%v", k, quoteParamIfNeeded(cmd.CmdPriorState[k])))
+					}
 				}
 			}
 
@@ -226,6 +240,155 @@ func (te *Template) Revert() (*Template, error) {
 	return tpl, nil
 }
 
+// SelectCommands returns a template holding the selected commands of an executed template,
+// i.e. to revert only part of it, along with their 1-based indexes in the template.
+// A selector is a command index, an entity or a declared variable name. Commands matching
+// one of the only selectors (or all commands when none given) are kept, unless matching a skip selector.
+func (te *Template) SelectCommands(only, skip []string) (*Template, []int, error) {
+	matched := make(map[string]bool)
+	matchAny := func(selectors []string, index int, cmd *ast.CommandNode, ident string) (found bool) {
+		for _, sel := range selectors {
+			if matchesSelector(sel, index, cmd, ident) {
+				matched[sel] = true
+				found = true
+			}
+		}
+		return
+	}
+
+	selected := &Template{ID: te.ID, AST: &ast.AST{}}
+	var indexes []int
+	var index int
+	for _, st := range te.Statements {
+		cmd := statementCommand(st)
+		if cmd == nil {
+			continue
+		}
+		index++
+		var ident string
+		if decl, ok := st.Node.(*ast.DeclarationNode); ok {
+			ident = decl.Ident
+		}
+		isOnly := matchAny(only, index, cmd, ident)
+		isSkipped := matchAny(skip, index, cmd, ident)
+		if (len(only) == 0 || isOnly) && !isSkipped {
+			selected.Statements = append(selected.Statements, st)
+			indexes = append(indexes, index)
+		}
+	}
+
+	for _, sel := range append(append([]string{}, only...), skip...) {
+		if !matched[sel] {
+			return nil, nil, fmt.Errorf("selector '%s' matches no command (expecting a command index, an entity or a variable name)", sel)
+		}
+	}
+	if len(indexes) == 0 {
+		return nil, nil, fmt.Errorf("no command selected")
+	}
+
+	return selected, indexes, nil
+}
+
+func matchesSelector(sel string, index int, cmd *ast.CommandNode, ident string) bool {
+	if i, err := strconv.Atoi(sel); err == nil {
+		return i == index
+	}
+	if ident != "" && strings.TrimPrefix(sel, "$") == ident {
+		return true
+	}
+	return strings.EqualFold(sel, cmd.Entity)
+}
+
+// RevertDependencies returns, by 1-based command index, the indexes of the later commands
+// using the result of the command (i.e. an instance created in a created subnet).
+// Their revert has to run before the revert of the command they depend on.
+func (te *Template) RevertDependencies() map[int][]int {
+	deps := make(map[int][]int)
+	cmds := te.CommandNodesIterator()
+	for i, cmd := range cmds {
+		result, ok := cmd.CmdResult.(string)
+		if !ok || result == "" {
+			continue
+		}
+		for j := i + 1; j < len(cmds); j++ {
+			if usesValue(cmds[j], result) {
+				deps[i+1] = append(deps[i+1], j+1)
+			}
+		}
+	}
+	return deps
+}
+
+func usesValue(cmd *ast.CommandNode, value string) bool {
+	for _, param := range cmd.Params {
+		switch v := param.Value().(type) {
+		case []interface{}:
+			for _, elem := range v {
+				if fmt.Sprint(elem) == value {
+					return true
+				}
+			}
+		default:
+			if fmt.Sprint(v) == value {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+const (
+	AppliedStatus           = "applied"
+	RevertedStatus          = "reverted"
+	PartiallyRevertedStatus = "partially reverted"
+)
+
+// RevertStatus returns whether the execution has been reverted, fully or partially
+func (t *TemplateExecution) RevertStatus() string {
+	switch {
+	case t.RevertedBy != "":
+		return RevertedStatus
+	case len(t.PartiallyRevertedBy) > 0:
+		return PartiallyRevertedStatus
+	default:
+		return AppliedStatus
+	}
+}
+
+// LinkRevert links the execution to the revert of its commands. Partial or failed reverts
+// are linked as partial ones, unless all the revertible commands have now been successfully
+// reverted by the given revert and the previous partial reverts.
+func (t *TemplateExecution) LinkRevert(revert *TemplateExecution, previousPartialReverts ...*TemplateExecution) {
+	revert.RevertOf = t.ID
+	if revert.Stats().KOCount == 0 && len(revert.RevertedCommands) == 0 {
+		t.RevertedBy = revert.ID
+		return
+	}
+
+	t.PartiallyRevertedBy = append(t.PartiallyRevertedBy, revert.ID)
+	reverted := RevertedCommands(append(previousPartialReverts, revert)...)
+	for i, cmd := range t.CommandNodesIterator() {
+		if isRevertible(cmd) && !reverted[i+1] {
+			return
+		}
+	}
+	t.RevertedBy = revert.ID
+}
+
+// RevertedCommands returns the 1-based indexes of the commands successfully reverted by the given partial reverts
+func RevertedCommands(partialReverts ...*TemplateExecution) map[int]bool {
+	reverted := make(map[int]bool)
+	for _, r := range partialReverts {
+		if r.Stats().KOCount > 0 {
+			continue
+		}
+		for _, i := range r.RevertedCommands {
+			reverted[i] = true
+		}
+	}
+	return reverted
+}
+
 func IsRevertible(t *Template) bool {
 	revertible := false
 	t.visitCommandNodes(func(cmd *ast.CommandNode) {
@@ -241,7 +404,7 @@ func isRevertible(cmd *ast.CommandNode) bool {
 		return false
 	}
 
-	if cmd.Action == "check" {
+	if cmd.Action == "check" || cmd.Action == waitAction {
 		return false
 	}
 
@@ -274,8 +437,8 @@ func isRevertible(cmd *ast.CommandNode) bool {
 		return true
 	}
 
-	if cmd.Entity == "securitygroup" && cmd.Action == "update" {
-		return true
+	if cmd.Action == "update" {
+		return cmd.Entity == "securitygroup" || isRevertibleFromPriorState(cmd)
 	}
 
 	if cmd.Entity == "appscalingpolicy" && cmd.Action == "create" {
@@ -2,6 +2,7 @@ package template
 
 import (
 	"errors"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -421,3 +422,115 @@ func TestCmdNodeIsRevertible(t *testing.T) {
 		}
 	}
 }
+
+func TestSelectCommandsToRevert(t *testing.T) {
+	tpl := MustParse("vpc = create vpc cidr=10.0.0.0/16\nsub = create subnet cidr=10.0.0.0/24 vpc=$vpc\ncreate instance subnet=$sub\ncreate instance subnet=$sub")
+
+	tcases := []struct {
+		only, skip []string
+		expIndexes []int
+		expErr     string
+	}{
+		{expIndexes: []int{1, 2, 3, 4}},
+		{only: []string{"instance"}, expIndexes: []int{3, 4}},
+		{only: []string{"2", "$vpc"}, expIndexes: []int{1, 2}},
+		{only: []string{"sub", "instance"}, skip: []string{"4"}, expIndexes: []int{2, 3}},
+		{skip: []string{"vpc"}, expIndexes: []int{2, 3, 4}},
+		{only: []string{"keypair"}, expErr: "selector 'keypair' matches no command"},
+		{only: []string{"vpc"}, skip: []string{"1"}, expErr: "no command selected"},
+	}
+
+	for i, tcase := range tcases {
+		selected, indexes, err := tpl.SelectCommands(tcase.only, tcase.skip)
+		if tcase.expErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tcase.expErr) {
+				t.Fatalf("%d: got %v, want error containing '%s'", i+1, err, tcase.expErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%d: %s", i+1, err)
+		}
+		if got, want := indexes, tcase.expIndexes; !reflect.DeepEqual(got, want) {
+			t.Fatalf("%d: got %v, want %v", i+1, got, want)
+		}
+		if got, want := len(selected.CommandNodesIterator()), len(tcase.expIndexes); got != want {
+			t.Fatalf("%d: got %d commands, want %d", i+1, got, want)
+		}
+	}
+}
+
+func TestRevertDependencies(t *testing.T) {
+	tpl := MustParse("create vpc cidr=10.0.0.0/16\ncreate subnet cidr=10.0.0.0/24 vpc=vpc-1\ncreate instance subnet=sub-1\ncreate securitygroup vpc=vpc-1\nattach securitygroup id=sg-1 instance=[i-1]")
+	for i, res := range []string{"vpc-1", "sub-1", "i-1", "sg-1", ""} {
+		tpl.CommandNodesIterator()[i].CmdResult = res
+	}
+
+	exp := map[int][]int{1: {2, 4}, 2: {3}, 3: {5}, 4: {5}}
+	if got, want := tpl.RevertDependencies(), exp; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	selected, _, err := tpl.SelectCommands([]string{"1", "2"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reverted, err := selected.Revert()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := reverted.String(), "delete subnet id=sub-1\ndelete vpc id=vpc-1"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
+
+func TestLinkRevert(t *testing.T) {
+	newExec := func(id, text string, results ...string) *TemplateExecution {
+		exec := &TemplateExecution{Template: MustParse(text)}
+		exec.ID = id
+		for i, cmd := range exec.CommandNodesIterator() {
+			if i < len(results) {
+				cmd.CmdResult = results[i]
+			}
+		}
+		return exec
+	}
+	run := func() *TemplateExecution {
+		return newExec("run", "create vpc cidr=10.0.0.0/16\ncreate subnet cidr=10.0.0.0/24\ncreate tag key=k value=v resource=vpc-1", "vpc-1", "sub-1")
+	}
+
+	exec := run()
+	if got, want := exec.RevertStatus(), AppliedStatus; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	full := newExec("full", "delete subnet id=sub-1\ndelete vpc id=vpc-1")
+	exec.LinkRevert(full)
+	if got, want := exec.RevertStatus(), RevertedStatus; got != want || exec.RevertedBy != "full" || full.RevertOf != "run" {
+		t.Fatalf("got %s by %s, want %s", got, exec.RevertedBy, want)
+	}
+
+	exec = run()
+	failed := newExec("failed", "delete subnet id=sub-1\ndelete vpc id=vpc-1")
+	failed.CommandNodesIterator()[1].CmdErr = errors.New("dependency violation")
+	exec.LinkRevert(failed)
+	if got, want := exec.RevertStatus(), PartiallyRevertedStatus; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+
+	exec = run()
+	first := newExec("first", "delete subnet id=sub-1")
+	first.RevertedCommands = []int{2}
+	exec.LinkRevert(first)
+	if got, want := exec.RevertStatus(), PartiallyRevertedStatus; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	second := newExec("second", "delete tag key=k value=v resource=vpc-1\ndelete vpc id=vpc-1")
+	second.RevertedCommands = []int{1, 3}
+	exec.LinkRevert(second, first)
+	if got, want := exec.RevertStatus(), RevertedStatus; got != want || exec.RevertedBy != "second" {
+		t.Fatalf("got %s by %s, want %s", got, exec.RevertedBy, want)
+	}
+	if got, want := exec.PartiallyRevertedBy, []string{"first", "second"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
@@ -39,43 +39,120 @@ func (s *Template) Run(env *Env) (*Template, error) {
 	current := &Template{AST: &ast.AST{}}
 	current.ID = ulid.MustNew(ulid.Timestamp(time.Now()), rand.Reader).String()
 
-	for _, sts := range s.Statements {
+	err := runStatements(s.Statements, current, env, vars)
+	if err == driverFunctionFailedErr {
+		return current, nil
+	}
+	return current, err
+}
+
+// runStatements runs the statements appending the executed ones to the current template.
+// Conditional blocks are thus flattened in current to what has been actually executed.
+func runStatements(stmts []*ast.Statement, current *Template, env *Env, vars map[string]interface{}) error {
+	var batch []*ast.Statement
+	for _, sts := range stmts {
+		if env.Concurrency > 1 && !env.dryRun && isBatchable(sts) {
+			batch = append(batch, sts)
+			continue
+		}
+		if err := runConcurrently(batch, current, env, vars); err != nil {
+			return err
+		}
+		batch = nil
+
 		clone := sts.Clone()
-		current.Statements = append(current.Statements, clone)
 		switch n := clone.Node.(type) {
 		case *ast.CommandNode:
+			current.Statements = append(current.Statements, clone)
 			if err := runCmd(n, env, vars); err != nil {
-				if err == driverFunctionFailedErr {
-					return current, nil
-				}
-				return current, err
+				return err
 			}
 		case *ast.DeclarationNode:
 			ident := n.Ident
 			expr := n.Expr
 			switch cmd := expr.(type) {
 			case *ast.CommandNode:
+				current.Statements = append(current.Statements, clone)
 				if err := runCmd(cmd, env, vars); err != nil {
-					if err == driverFunctionFailedErr {
-						return current, nil
-					}
-					return current, err
+					return err
 				}
 				vars[ident] = cmd.Result()
+			case *ast.ValueNode:
+				cmd.ProcessRefs(vars)
+				vars[ident] = cmd.Value.Value()
 			default:
-				return current, fmt.Errorf("unknown type of node: %T", expr)
+				return fmt.Errorf("unknown type of node: %T", expr)
 			}
+		case *ast.OutputNode:
+			n.ProcessRefs(vars)
+			current.Statements = append(current.Statements, clone)
+		case *ast.IfNode:
+			if err := runIf(n, clone.Pos, current, env, vars); err != nil {
+				return err
+			}
+		case *ast.ForNode:
+			return fmt.Errorf("loop over $%s has not been expanded: template needs compilation", n.Var)
+		case *ast.IncludeNode:
+			return fmt.Errorf("include '%s' has not been resolved: template needs compilation", n.Path)
 		default:
-			return current, fmt.Errorf("unknown type of node: %T", clone.Node)
+			return fmt.Errorf("unknown type of node: %T", clone.Node)
 		}
 	}
 
-	return current, nil
+	return runConcurrently(batch, current, env, vars)
+}
+
+// runIf runs the selected branch of the conditional block. When dry running, conditions depending
+// on commands results cannot be evaluated (results are fake) so both branches are dry run. Otherwise
+// only the selected branch is, the other one being checked against the driver without calling it.
+func runIf(n *ast.IfNode, pos ast.Position, current *Template, env *Env, vars map[string]interface{}) error {
+	if env.dryRun && !n.Condition.IsResolved() {
+		n.ProcessRefs(vars)
+		for _, branch := range [][]*ast.Statement{n.Then, n.Else} {
+			if err := runStatements(branch, current, env, vars); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	n.ProcessRefs(vars)
+	ok, err := n.Condition.Evaluate()
+	if err != nil {
+		return errorAt(pos, err)
+	}
+	branch, skipped := n.Then, n.Else
+	if !ok {
+		branch, skipped = n.Else, n.Then
+	}
+	if env.dryRun {
+		if err := lookupCommands(skipped, env); err != nil {
+			return err
+		}
+	}
+	return runStatements(branch, current, env, vars)
+}
+
+// lookupCommands checks that the driver knows the commands of the statements
+func lookupCommands(stmts []*ast.Statement, env *Env) (err error) {
+	walkStatements(stmts, func(st *ast.Statement) {
+		cmd := statementCommand(st)
+		if err != nil || cmd == nil || cmd.Action == waitAction {
+			return
+		}
+		if _, lerr := env.Driver.Lookup(cmd.Action, cmd.Entity); lerr != nil {
+			err = errorAt(cmd.Pos, lerr)
+		}
+	})
+	return
 }
 
 func (s *Template) DryRun(env *Env) error {
-	defer env.Driver.SetDryRun(false)
+	defer func() {
+		env.Driver.SetDryRun(false)
+		env.dryRun = false
+	}()
 	env.Driver.SetDryRun(true)
+	env.dryRun = true
 
 	res, err := s.Run(env)
 	if err != nil {
@@ -85,7 +162,7 @@ func (s *Template) DryRun(env *Env) error {
 	errs := &Errors{}
 	for _, cmd := range res.CommandNodesIterator() {
 		if cmderr := cmd.Err(); cmderr != nil {
-			errs.add(cmderr)
+			errs.add(errorAt(cmd.Pos, cmderr))
 		}
 	}
 
@@ -96,6 +173,17 @@ func (s *Template) DryRun(env *Env) error {
 	return nil
 }
 
+// Outputs returns the values of the outputs declared in the template
+func (s *Template) Outputs() map[string]interface{} {
+	outputs := make(map[string]interface{})
+	walkStatements(s.Statements, func(st *ast.Statement) {
+		if n, ok := st.Node.(*ast.OutputNode); ok {
+			outputs[n.Name] = n.Value.Value()
+		}
+	})
+	return outputs
+}
+
 func (s *Template) Validate(rules ...Validator) (all []error) {
 	for _, rule := range rules {
 		errs := rule.Execute(s)
@@ -132,14 +220,42 @@ func (t *Template) UniqueDefinitions(fn DefinitionLookupFunc) (definitions Defin
 var driverFunctionFailedErr = errors.New("Driver function call failed")
 
 func runCmd(n *ast.CommandNode, env *Env, vars map[string]interface{}) error {
-	fn, err := env.Driver.Lookup(n.Action, n.Entity)
-	if err != nil {
-		return err
+	var fn driver.DriverFn
+	if n.Action == waitAction {
+		fn = func(driver.Context, map[string]interface{}) (interface{}, error) { return runWait(n, env) }
+	} else {
+		var err error
+		if fn, err = env.Driver.Lookup(n.Action, n.Entity); err != nil {
+			return errorAt(n.Pos, err)
+		}
 	}
 	n.ProcessRefs(vars)
 
-	ctx := driver.NewContext(env.ResolvedVariables)
-	n.CmdResult, n.CmdErr = fn(ctx, n.ToDriverParams())
+	if n.Action == "update" && !env.dryRun {
+		if err := recordPriorState(n, env); err != nil && env.Log != nil {
+			env.Log.Warningf("%s %s: cannot record state before update, it will not be revertible: %s", n.Action, n.Entity, err)
+		}
+	}
+
+	attempts := 1
+	var backoff time.Duration
+	if n.Retry != nil && !env.dryRun {
+		attempts, backoff = n.Retry.Attempts+1, n.Retry.Backoff
+	}
+
+	for attempt := 1; ; attempt++ {
+		ctx := driver.NewContext(env.ResolvedVariables)
+		n.CmdResult, n.CmdErr = fn(ctx, n.ToDriverParams())
+		if n.CmdErr == nil || attempt >= attempts {
+			break
+		}
+		if env.Log != nil {
+			env.Log.Warningf("%s %s: attempt %d/%d failed (%s), retrying in %s", n.Action, n.Entity, attempt, attempts, n.CmdErr, backoff)
+		}
+		env.sleep(backoff)
+		backoff *= 2
+	}
+
 	if n.CmdErr != nil {
 		return driverFunctionFailedErr
 	}
@@ -152,6 +268,9 @@ func (s *Template) visitHoles(fn func(n ast.WithHoles)) {
 			fn(h)
 		}
 	}
+	for _, n := range s.blockNodesIterator() {
+		fn(n)
+	}
 }
 
 func (s *Template) visitCommandNodes(fn func(n *ast.CommandNode)) {
@@ -183,50 +302,39 @@ func (s *Template) visitDeclarationNodes(fn func(n *ast.DeclarationNode)) {
 }
 
 func (s *Template) CommandNodesIterator() (nodes []*ast.CommandNode) {
-	for _, sts := range s.Statements {
+	walkStatements(s.Statements, func(sts *ast.Statement) {
 		switch nn := sts.Node.(type) {
 		case *ast.CommandNode:
 			nodes = append(nodes, nn)
 		case *ast.DeclarationNode:
-			expr := sts.Node.(*ast.DeclarationNode).Expr
-			switch expr.(type) {
+			switch expr := nn.Expr.(type) {
 			case *ast.CommandNode:
-				nodes = append(nodes, expr.(*ast.CommandNode))
+				nodes = append(nodes, expr)
 			}
 		}
-	}
+	})
 	return
 }
 
 func (s *Template) WithRefsIterator() (nodes []ast.WithRefs) {
-	for _, sts := range s.Statements {
+	walkStatements(s.Statements, func(sts *ast.Statement) {
 		switch nn := sts.Node.(type) {
 		case ast.WithRefs:
 			nodes = append(nodes, nn)
 		case *ast.DeclarationNode:
-			expr := sts.Node.(*ast.DeclarationNode).Expr
-			switch nnn := expr.(type) {
+			switch nnn := nn.Expr.(type) {
 			case *ast.CommandNode:
 				nodes = append(nodes, nnn)
 			}
 		}
-	}
+	})
 	return
 }
 
 func (s *Template) CommandNodesReverseIterator() (nodes []*ast.CommandNode) {
-	for i := len(s.Statements) - 1; i >= 0; i-- {
-		sts := s.Statements[i]
-		switch sts.Node.(type) {
-		case *ast.CommandNode:
-			nodes = append(nodes, sts.Node.(*ast.CommandNode))
-		case *ast.DeclarationNode:
-			expr := sts.Node.(*ast.DeclarationNode).Expr
-			switch expr.(type) {
-			case *ast.CommandNode:
-				nodes = append(nodes, expr.(*ast.CommandNode))
-			}
-		}
+	cmds := s.CommandNodesIterator()
+	for i := len(cmds) - 1; i >= 0; i-- {
+		nodes = append(nodes, cmds[i])
 	}
 	return
 }
@@ -243,24 +351,82 @@ func (s *Template) commandDeclarationNodesIterator() (nodes []*ast.DeclarationNo
 }
 
 func (s *Template) declarationNodesIterator() (nodes []*ast.DeclarationNode) {
-	for _, sts := range s.Statements {
+	walkStatements(s.Statements, func(sts *ast.Statement) {
 		switch n := sts.Node.(type) {
 		case *ast.DeclarationNode:
 			nodes = append(nodes, n)
 		}
-	}
+	})
 	return
 }
 
 func (s *Template) expressionNodesIterator() (nodes []ast.ExpressionNode) {
-	for _, st := range s.Statements {
+	walkStatements(s.Statements, func(st *ast.Statement) {
 		if expr := extractExpressionNode(st); expr != nil {
 			nodes = append(nodes, expr)
 		}
-	}
+	})
+	return
+}
+
+func (s *Template) blockNodesIterator() (nodes []ast.BlockNode) {
+	walkStatements(s.Statements, func(st *ast.Statement) {
+		if n, ok := st.Node.(ast.BlockNode); ok {
+			nodes = append(nodes, n)
+		}
+	})
 	return
 }
 
+// walkStatements visits statements in order, including the ones nested in blocks
+func walkStatements(stmts []*ast.Statement, fn func(*ast.Statement)) {
+	for _, st := range stmts {
+		fn(st)
+		if n, ok := st.Node.(ast.BlockNode); ok {
+			for _, block := range n.Blocks() {
+				walkStatements(block, fn)
+			}
+		}
+	}
+}
+
+// walkStatementsOutsideLoops is walkStatements not entering the body of loops
+func walkStatementsOutsideLoops(stmts []*ast.Statement, fn func(*ast.Statement)) {
+	for _, st := range stmts {
+		fn(st)
+		if _, isFor := st.Node.(*ast.ForNode); isFor {
+			continue
+		}
+		if n, ok := st.Node.(ast.BlockNode); ok {
+			for _, block := range n.Blocks() {
+				walkStatementsOutsideLoops(block, fn)
+			}
+		}
+	}
+}
+
+// CommandIdents returns the names of the variables declared with the result of commands
+func (s *Template) CommandIdents() map[*ast.CommandNode]string {
+	idents := make(map[*ast.CommandNode]string)
+	for _, decl := range s.commandDeclarationNodesIterator() {
+		idents[decl.Expr.(*ast.CommandNode)] = decl.Ident
+	}
+	return idents
+}
+
+// statementCommand returns the command of a statement, declared or not
+func statementCommand(st *ast.Statement) *ast.CommandNode {
+	switch n := st.Node.(type) {
+	case *ast.CommandNode:
+		return n
+	case *ast.DeclarationNode:
+		if cmd, ok := n.Expr.(*ast.CommandNode); ok {
+			return cmd
+		}
+	}
+	return nil
+}
+
 func extractExpressionNode(st *ast.Statement) ast.ExpressionNode {
 	switch n := st.Node.(type) {
 	case *ast.DeclarationNode:
@@ -20,7 +20,9 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/wallix/awless/logger"
 	"github.com/wallix/awless/template/driver"
@@ -99,6 +101,94 @@ func TestRunDriverReportsInStatement(t *testing.T) {
 	}
 }
 
+func TestDryRunErrorsWithPositions(t *testing.T) {
+	templ := MustParse("# dry run stops at first failure\n  create vpc cidr=10.0.0.0/25\ncreate subnet cidr=10.0.0.0/26")
+	err := templ.DryRun(&Env{Driver: &errorDriver{errors.New("dry run failed")}})
+
+	errs, ok := err.(*Errors)
+	if !ok {
+		t.Fatalf("got %T, want *Errors", err)
+	}
+	all, _ := errs.Errors()
+	var got []string
+	for _, e := range all {
+		got = append(got, e.Error())
+	}
+	if want := []string{"line 2, column 3: dry run failed"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+type knownCommandsDriver struct {
+	known  map[string]bool
+	called []string
+}
+
+func (d *knownCommandsDriver) Lookup(lookups ...string) (driver.DriverFn, error) {
+	cmd := strings.Join(lookups, " ")
+	if !d.known[cmd] {
+		return nil, fmt.Errorf("unknown command '%s'", cmd)
+	}
+	return func(driver.Context, map[string]interface{}) (interface{}, error) {
+		d.called = append(d.called, cmd)
+		return nil, nil
+	}, nil
+}
+func (d *knownCommandsDriver) SetLogger(*logger.Logger) {}
+func (d *knownCommandsDriver) SetDryRun(bool)           {}
+
+func TestDryRunConditionalBlocks(t *testing.T) {
+	tcases := []struct {
+		tpl    string
+		known  []string
+		expErr string
+		called []string
+	}{
+		{
+			tpl:    "if prod == prod {\n  create subnet name=sub\n} else {\n  create vpc name=vpc\n}",
+			known:  []string{"create subnet", "create vpc"},
+			called: []string{"create subnet"},
+		},
+		{
+			tpl:    "if prod == dev {\n  create subnet name=sub\n} else {\n  create vpc name=vpc\n}",
+			known:  []string{"create subnet", "create vpc"},
+			called: []string{"create vpc"},
+		},
+		{
+			tpl:    "if prod == prod {\n  create subnet name=sub\n} else {\n  create vpc name=vpc\n}",
+			known:  []string{"create subnet"},
+			expErr: "line 4, column 3: unknown command 'create vpc'",
+		},
+		{
+			tpl:    "key = create keypair name=key\nif $key == mykey {\n  create subnet name=sub\n} else {\n  create vpc name=vpc\n}",
+			known:  []string{"create keypair", "create subnet", "create vpc"},
+			called: []string{"create keypair", "create subnet", "create vpc"},
+		},
+	}
+	for i, tcase := range tcases {
+		d := &knownCommandsDriver{known: make(map[string]bool)}
+		for _, cmd := range tcase.known {
+			d.known[cmd] = true
+		}
+		err := MustParse(tcase.tpl).DryRun(&Env{Driver: d})
+		if tcase.expErr != "" {
+			if err == nil || err.Error() != tcase.expErr {
+				t.Fatalf("%d: got %v, want %s", i+1, err, tcase.expErr)
+			}
+			if len(d.called) > 0 {
+				t.Fatalf("%d: got %v called, want none", i+1, d.called)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%d: %s", i+1, err)
+		}
+		if got, want := d.called, tcase.called; !reflect.DeepEqual(got, want) {
+			t.Fatalf("%d: got %v, want %v", i+1, got, want)
+		}
+	}
+}
+
 func TestRunDriverOnTemplate(t *testing.T) {
 	t.Run("Driver run TWICE multiline statement", func(t *testing.T) {
 		s, err := Parse(`createdvpc = create vpc count=1
@@ -253,6 +343,77 @@ create vpc name=never-achieved`)
 		}
 	})
 
+	t.Run("Driver runs the selected branch of conditional blocks", func(t *testing.T) {
+		s, err := Parse(`subnet1 = create subnet name=mysubnet
+if $subnet1 == mynewsubnet {
+	create instance name=myinstance subnet=$subnet1
+} else {
+	create vpc name=myvpc
+}`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		mDriver := &mockDriver{t: t, prefix: "mynew", expects: []*expectation{
+			{action: "create", entity: "subnet", expectedParams: map[string]interface{}{"name": "mysubnet"}},
+			{action: "create", entity: "instance", expectedParams: map[string]interface{}{"name": "myinstance", "subnet": "mynewsubnet"}},
+			{action: "create", entity: "vpc", expectedParams: map[string]interface{}{"name": "myvpc"}},
+		},
+		}
+		env := &Env{Driver: mDriver}
+		ran, err := s.Run(env)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expect := mDriver.expects[2]; expect.lookupDone {
+			t.Fatalf("expect %s %s not done, got %t", expect.action, expect.entity, expect.lookupDone)
+		}
+		exp := "subnet1 = create subnet name=mysubnet\ncreate instance name=myinstance subnet=mynewsubnet"
+		if got, want := ran.String(), exp; got != want {
+			t.Fatalf("got %s, want %s", got, want)
+		}
+		reverted, err := ran.Revert()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := reverted.String(), "delete instance id=mynewinstance\ncheck instance id=mynewinstance state=terminated timeout=180\ndelete subnet id=mynewsubnet"; got != want {
+			t.Fatalf("got %s, want %s", got, want)
+		}
+
+		if err := s.DryRun(&Env{Driver: mDriver}); err != nil {
+			t.Fatal(err)
+		}
+		if err := mDriver.lookupsCalled(); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("Driver resolves outputs", func(t *testing.T) {
+		s, err := Parse(`subnet1 = create subnet name=mysubnet
+output subnet_id = $subnet1
+output names = [$subnet1, {instance.name}]
+create instance name=myinstance subnet=$subnet1`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s.visitHoles(func(n ast.WithHoles) { n.ProcessHoles(map[string]interface{}{"instance.name": "myinstance"}) })
+		mDriver := &mockDriver{t: t, prefix: "mynew", expects: []*expectation{
+			{action: "create", entity: "subnet", expectedParams: map[string]interface{}{"name": "mysubnet"}},
+			{action: "create", entity: "instance", expectedParams: map[string]interface{}{"name": "myinstance", "subnet": "mynewsubnet"}},
+		},
+		}
+		ran, err := s.Run(&Env{Driver: mDriver})
+		if err != nil {
+			t.Fatal(err)
+		}
+		exp := map[string]interface{}{"subnet_id": "mynewsubnet", "names": []interface{}{"mynewsubnet", "myinstance"}}
+		if got, want := ran.Outputs(), exp; !reflect.DeepEqual(got, want) {
+			t.Fatalf("got %#v, want %#v", got, want)
+		}
+		if got, want := len(ran.CommandNodesIterator()), 2; got != want {
+			t.Fatalf("got %d, want %d", got, want)
+		}
+	})
+
 	t.Run("Dryrun and run are performed on cloned template", func(t *testing.T) {
 		tplText := `subnet1 = create subnet name=mysubnet
 create instance list=[test,test2] name=myinstance subnet=$subnet1
@@ -347,3 +508,59 @@ func (r *mockDriver) Lookup(lookups ...string) (driver.DriverFn, error) {
 
 func (r *mockDriver) SetLogger(*logger.Logger) {}
 func (r *mockDriver) SetDryRun(bool)           {}
+
+type flakyDriver struct {
+	failures int
+	calls    int
+}
+
+func (d *flakyDriver) Lookup(lookups ...string) (driver.DriverFn, error) {
+	return func(driver.Context, map[string]interface{}) (interface{}, error) {
+		d.calls++
+		if d.calls <= d.failures {
+			return nil, fmt.Errorf("failure %d", d.calls)
+		}
+		return "done", nil
+	}, nil
+}
+func (d *flakyDriver) SetLogger(*logger.Logger) {}
+func (d *flakyDriver) SetDryRun(bool)           {}
+
+func TestRunRetriesCommands(t *testing.T) {
+	tcases := []struct {
+		input     string
+		failures  int
+		expCalls  int
+		expSleeps []time.Duration
+		expErr    string
+	}{
+		{input: "create vpc cidr=10.0.0.0/24 retry 3 backoff 2s", failures: 2, expCalls: 3, expSleeps: []time.Duration{2 * time.Second, 4 * time.Second}},
+		{input: "create vpc cidr=10.0.0.0/24 retry 2", failures: 5, expCalls: 3, expSleeps: []time.Duration{0, 0}, expErr: "failure 3"},
+		{input: "create vpc cidr=10.0.0.0/24", failures: 1, expCalls: 1, expErr: "failure 1"},
+		{input: "create vpc cidr=10.0.0.0/24 retry 3 backoff 1s", expCalls: 1},
+	}
+
+	for i, tcase := range tcases {
+		var sleeps []time.Duration
+		flaky := &flakyDriver{failures: tcase.failures}
+		env := &Env{Driver: flaky, sleepFunc: func(d time.Duration) { sleeps = append(sleeps, d) }}
+
+		ran, err := MustParse(tcase.input).Run(env)
+		if err != nil {
+			t.Fatalf("%d: %s", i+1, err)
+		}
+		if got, want := flaky.calls, tcase.expCalls; got != want {
+			t.Fatalf("%d: got %d calls, want %d", i+1, got, want)
+		}
+		if got, want := sleeps, tcase.expSleeps; !reflect.DeepEqual(got, want) {
+			t.Fatalf("%d: got %v sleeps, want %v", i+1, got, want)
+		}
+		var gotErr string
+		if cmdErr := ran.CommandNodesIterator()[0].Err(); cmdErr != nil {
+			gotErr = cmdErr.Error()
+		}
+		if gotErr != tcase.expErr {
+			t.Fatalf("%d: got error '%s', want '%s'", i+1, gotErr, tcase.expErr)
+		}
+	}
+}
@@ -0,0 +1,662 @@
+package templatetest
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	awsservices "github.com/wallix/awless/aws/services"
+	"github.com/wallix/awless/cloud/properties"
+	"github.com/wallix/awless/cloud/rdf"
+	"github.com/wallix/awless/graph"
+	"github.com/wallix/awless/logger"
+	"github.com/wallix/awless/template/driver"
+)
+
+// simulatedAccount is the account of the simulated resources identified by an ARN
+const simulatedAccount = "000000000000"
+
+var (
+	idPrefixes = map[string]string{
+		"vpc":              "vpc",
+		"subnet":           "subnet",
+		"instance":         "i",
+		"securitygroup":    "sg",
+		"internetgateway":  "igw",
+		"natgateway":       "nat",
+		"routetable":       "rtb",
+		"volume":           "vol",
+		"snapshot":         "snap",
+		"image":            "ami",
+		"elasticip":        "eipalloc",
+		"networkinterface": "eni",
+	}
+	// nameIdentified entities have their name as identifier in the graph
+	nameIdentified = map[string]bool{"keypair": true, "bucket": true}
+	// arnIdentified entities are identified by an ARN of the given service
+	arnIdentified = map[string]string{"policy": "iam", "certificate": "acm"}
+	// nameResults entities return their name when created
+	nameResults = map[string]bool{
+		"keypair": true, "bucket": true, "launchconfiguration": true, "scalinggroup": true,
+		"alarm": true, "dbsubnetgroup": true, "s3object": true,
+	}
+	// parentParams reference the resource parent of the created one, other references
+	// to existing resources are modelled as applies-on relations
+	parentParams = map[string]bool{"vpc": true, "subnet": true}
+	// identifierParams reference the resource a command applies to
+	identifierParams = []string{"id", "arn", "url", "name"}
+	// paramProperties are the params whose property name does not match the param name
+	paramProperties = map[string]string{"securitygroup": properties.SecurityGroups}
+	// initialStates are the states of the resources once created
+	initialStates = map[string]string{
+		"instance": "running", "volume": "available", "natgateway": "available",
+		"database": "available", "loadbalancer": "active",
+	}
+)
+
+// Driver is an in-memory driver simulating the commands of templates on the resources
+// of a graph: created resources are added with generated ids, their properties and their
+// relations to the resources they reference; deleted ones are removed. Entities not modelled
+// in the graph (i.e. tags, routes) and attachments are recorded to be deleted or detached later on.
+type Driver struct {
+	mu      sync.Mutex
+	graph   *graph.Graph
+	dryRun  bool
+	logger  *logger.Logger
+	types   map[string]bool
+	counter map[string]int
+	records []*record
+}
+
+type record struct {
+	Action    string                 `json:"action"`
+	Entity    string                 `json:"entity"`
+	Id        string                 `json:"id"`
+	Params    map[string]interface{} `json:"params,omitempty"`
+	Relations [][2]string            `json:"relations,omitempty"`
+}
+
+// NewDriver returns a driver simulating commands on the resources of the given graph
+func NewDriver(g *graph.Graph) *Driver {
+	d := &Driver{
+		graph:   g,
+		logger:  logger.DiscardLogger,
+		types:   make(map[string]bool),
+		counter: make(map[string]int),
+	}
+	for _, t := range awsservices.ResourceTypes {
+		d.types[t] = true
+	}
+	return d
+}
+
+// Graph returns the resources as simulated by the commands run so far
+func (d *Driver) Graph() *graph.Graph {
+	return d.graph
+}
+
+// FetchGraph returns the simulated resources, to be used as template.Env.FetchGraphFunc
+func (d *Driver) FetchGraph(entity string) (*graph.Graph, error) {
+	return d.graph, nil
+}
+
+// ResolveAlias returns the id of the resource with the given name, to be used as template.Env.AliasFunc.
+// As for aliases resolved in the local graph, a resource of the type given by the param key is preferred.
+func (d *Driver) ResolveAlias(entity, key, alias string) string {
+	resType := key
+	if strings.Contains(key, "id") {
+		resType = entity
+	}
+	resources, err := d.graph.FindResourcesByProperty(properties.Name, alias)
+	if err != nil || len(resources) == 0 {
+		return ""
+	}
+	for _, res := range resources {
+		if res.Type() == resType {
+			return res.Id()
+		}
+	}
+	return resources[0].Id()
+}
+
+// MarshalRecords returns as JSON the entities and attachments recorded so far, to be restored
+// with UnmarshalRecords in a driver simulating the same resources later on
+func (d *Driver) MarshalRecords() ([]byte, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return json.Marshal(d.records)
+}
+
+// UnmarshalRecords restores the records returned by MarshalRecords
+func (d *Driver) UnmarshalRecords(data []byte) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return json.Unmarshal(data, &d.records)
+}
+
+func (d *Driver) SetDryRun(dry bool) { d.dryRun = dry }
+
+func (d *Driver) SetLogger(l *logger.Logger) { d.logger = l }
+
+func (d *Driver) Lookup(lookups ...string) (driver.DriverFn, error) {
+	if len(lookups) != 2 {
+		return nil, driver.ErrDriverFnNotFound
+	}
+	action, entity := lookups[0], lookups[1]
+	if d.dryRun {
+		return func(driver.Context, map[string]interface{}) (interface{}, error) {
+			return fmt.Sprintf("dryrun-%s", entity), nil
+		}, nil
+	}
+	return func(ctx driver.Context, params map[string]interface{}) (interface{}, error) {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		result, err := d.run(action, entity, params)
+		if err != nil {
+			return nil, fmt.Errorf("%s %s: %s", action, entity, err)
+		}
+		d.logger.ExtraVerbosef("simulated %s %s: %v", action, entity, result)
+		return result, nil
+	}, nil
+}
+
+func (d *Driver) run(action, entity string, params map[string]interface{}) (interface{}, error) {
+	switch action {
+	case "create", "copy":
+		return d.create(entity, params)
+	case "delete":
+		return d.delete(entity, params)
+	case "update":
+		return d.update(entity, params)
+	case "start", "stop":
+		return d.startOrStop(action, entity, params)
+	case "attach":
+		return d.attach(entity, params)
+	case "detach":
+		return d.detach(entity, params)
+	case "check":
+		return nil, d.check(entity, params)
+	default:
+		res, err := d.find(entity, params)
+		if err != nil || res == nil {
+			return nil, err
+		}
+		return res.Id(), nil
+	}
+}
+
+func (d *Driver) create(entity string, params map[string]interface{}) (interface{}, error) {
+	id, err := d.newId(entity, params)
+	if err != nil {
+		return nil, err
+	}
+
+	if d.types[entity] {
+		res := graph.InitResource(entity, id)
+		setProperties(res, params)
+		if state, ok := initialStates[entity]; ok {
+			res.Properties[properties.State] = state
+		}
+		if err := d.graph.AddResource(res); err != nil {
+			return nil, err
+		}
+		if err := d.addRelations(res, params); err != nil {
+			return nil, err
+		}
+	} else {
+		d.records = append(d.records, &record{Action: "create", Entity: entity, Id: id, Params: params})
+	}
+
+	if nameResults[entity] {
+		return fmt.Sprint(params["name"]), nil
+	}
+	return id, nil
+}
+
+func (d *Driver) delete(entity string, params map[string]interface{}) (interface{}, error) {
+	res, err := d.find(entity, params)
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		if rec := d.findRecord("create", entity, params); rec != nil {
+			d.removeRecord(rec)
+			return nil, nil
+		}
+		return nil, fmt.Errorf("%s not found", formatParams(params))
+	}
+
+	var children []string
+	for _, tri := range d.graph.AsRDFGraphSnaphot().WithSubjPred(res.Id(), rdf.ParentOf) {
+		if child, ok := tri.Object().Resource(); ok {
+			children = append(children, child)
+		}
+	}
+	if len(children) > 0 {
+		sort.Strings(children)
+		return nil, fmt.Errorf("%s has dependent resources: %s", res.Id(), strings.Join(children, ", "))
+	}
+
+	d.graph.DeleteResource(res)
+	return nil, nil
+}
+
+func (d *Driver) update(entity string, params map[string]interface{}) (interface{}, error) {
+	if entity == "securitygroup" {
+		return d.updateSecurityGroupRules(params)
+	}
+	res, err := d.find(entity, params)
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		if rec := d.findRecord("create", entity, identifiers(params)); rec != nil {
+			for k, v := range params {
+				rec.Params[k] = v
+			}
+			return rec.Id, nil
+		}
+		return nil, fmt.Errorf("%s not found", formatParams(params))
+	}
+
+	updated := graph.InitResource(entity, res.Id())
+	setProperties(updated, params)
+	return res.Id(), d.graph.UpdateResource(updated)
+}
+
+// updateSecurityGroupRules records authorized rules, removed when revoked
+func (d *Driver) updateSecurityGroupRules(params map[string]interface{}) (interface{}, error) {
+	rule := make(map[string]interface{})
+	var way, access string
+	for k, v := range params {
+		if k == "inbound" || k == "outbound" {
+			way, access = k, fmt.Sprint(v)
+			continue
+		}
+		rule[k] = v
+	}
+	rule["way"] = way
+
+	switch access {
+	case "authorize":
+		d.records = append(d.records, &record{Action: "authorize", Entity: "securitygroup", Id: fmt.Sprint(params["id"]), Params: rule})
+	case "revoke":
+		rec := d.findRecord("authorize", "securitygroup", rule)
+		if rec == nil {
+			return nil, fmt.Errorf("no %s rule matching %s", way, formatParams(rule))
+		}
+		d.removeRecord(rec)
+	default:
+		return nil, fmt.Errorf("expecting inbound or outbound param with value authorize or revoke")
+	}
+	return params["id"], nil
+}
+
+func (d *Driver) startOrStop(action, entity string, params map[string]interface{}) (interface{}, error) {
+	res, err := d.find(entity, params)
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return params["id"], nil
+	}
+	if entity == "instance" {
+		state := "running"
+		if action == "stop" {
+			state = "stopped"
+		}
+		updated := graph.InitResource(entity, res.Id())
+		updated.Properties[properties.State] = state
+		if err := d.graph.UpdateResource(updated); err != nil {
+			return nil, err
+		}
+	}
+	return res.Id(), nil
+}
+
+func (d *Driver) attach(entity string, params map[string]interface{}) (interface{}, error) {
+	rec := &record{Action: "attach", Entity: entity, Params: params}
+	for rec.Id == "" || d.findRecordById(rec.Id) != nil {
+		rec.Id = fmt.Sprintf("%s-attach-%d", prefix(entity), d.next("attach"+entity))
+	}
+
+	attached, err := d.find(entity, params)
+	if err != nil {
+		return nil, err
+	}
+	if attached != nil {
+		others, err := d.references(attached, params)
+		if err != nil {
+			return nil, err
+		}
+		for _, other := range others {
+			d.graph.AddAppliesOnRelation(attached, other.res)
+			rec.Relations = append(rec.Relations, [2]string{attached.Id(), other.res.Id()})
+		}
+		if err := d.switchState(attached.Id(), "available", "in-use"); err != nil {
+			return nil, err
+		}
+	}
+	d.records = append(d.records, rec)
+	return rec.Id, nil
+}
+
+func (d *Driver) detach(entity string, params map[string]interface{}) (interface{}, error) {
+	if rec := d.findRecord("attach", entity, params); rec != nil {
+		for _, rel := range rec.Relations {
+			d.graph.RemoveAppliesOnRelation(graph.InitResource("", rel[0]), graph.InitResource("", rel[1]))
+			if err := d.switchState(rel[0], "in-use", "available"); err != nil {
+				return nil, err
+			}
+		}
+		d.removeRecord(rec)
+		return rec.Id, nil
+	}
+
+	detached, err := d.find(entity, params)
+	if err != nil {
+		return nil, err
+	}
+	if detached == nil {
+		return nil, fmt.Errorf("no attachment matching %s", formatParams(params))
+	}
+	others, err := d.references(detached, params)
+	if err != nil {
+		return nil, err
+	}
+	for _, other := range others {
+		d.graph.RemoveAppliesOnRelation(detached, other.res)
+		d.graph.RemoveAppliesOnRelation(other.res, detached)
+	}
+	return detached.Id(), d.switchState(detached.Id(), "in-use", "available")
+}
+
+// check verifies the expected state immediately: the simulated resources do not transition over time
+func (d *Driver) check(entity string, params map[string]interface{}) error {
+	res, err := d.find(entity, params)
+	if err != nil {
+		return err
+	}
+	state := fmt.Sprint(params["state"])
+	if _, ok := params["state"]; !ok {
+		return nil
+	}
+	gone := state == "not-found" || state == "terminated" || state == "deleted"
+	switch {
+	case res == nil && gone:
+		return nil
+	case res == nil:
+		if d.findRecord("create", entity, identifiers(params)) != nil {
+			return nil
+		}
+		return fmt.Errorf("%s not found", formatParams(identifiers(params)))
+	case gone:
+		return fmt.Errorf("%s still exists, expecting state %s", res.Id(), state)
+	case state == "unused":
+		if used := d.graph.AsRDFGraphSnaphot().WithSubjPred(res.Id(), rdf.ApplyOn); len(used) > 0 {
+			return fmt.Errorf("%s is still in use", res.Id())
+		}
+		return nil
+	}
+	if current, ok := res.Properties[properties.State]; ok && !strings.EqualFold(fmt.Sprint(current), state) {
+		return fmt.Errorf("%s is in state %v, expecting %s", res.Id(), current, state)
+	}
+	return nil
+}
+
+// find returns the resource of the given entity identified by the params, or nil when not found
+func (d *Driver) find(entity string, params map[string]interface{}) (*graph.Resource, error) {
+	for _, key := range identifierParams {
+		v, ok := params[key]
+		if !ok {
+			continue
+		}
+		res, err := d.graph.FindResource(fmt.Sprint(v))
+		if err != nil {
+			return nil, err
+		}
+		if res != nil && res.Type() == entity {
+			return res, nil
+		}
+	}
+	if name, ok := params["name"]; ok {
+		resources, err := d.graph.FindResourcesByProperty(properties.Name, fmt.Sprint(name))
+		if err != nil {
+			return nil, err
+		}
+		for _, res := range resources {
+			if res.Type() == entity {
+				return res, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+type reference struct {
+	param string
+	res   *graph.Resource
+}
+
+// references returns the existing resources referenced by the params, other than the given resource
+func (d *Driver) references(from *graph.Resource, params map[string]interface{}) (refs []reference, err error) {
+	for _, k := range sortedKeys(params) {
+		for _, id := range stringList(params[k]) {
+			if id == from.Id() {
+				continue
+			}
+			res, err := d.graph.FindResource(id)
+			if err != nil {
+				return refs, err
+			}
+			if res != nil {
+				refs = append(refs, reference{param: k, res: res})
+			}
+		}
+	}
+	return
+}
+
+func (d *Driver) addRelations(res *graph.Resource, params map[string]interface{}) error {
+	refs, err := d.references(res, params)
+	if err != nil {
+		return err
+	}
+	for _, ref := range refs {
+		if parentParams[ref.param] {
+			err = d.graph.AddParentRelation(ref.res, res)
+		} else {
+			err = d.graph.AddAppliesOnRelation(ref.res, res)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// switchState sets the state of the resource when currently in the from state (i.e. a volume in-use once attached)
+func (d *Driver) switchState(id, from, to string) error {
+	current, err := d.graph.FindResource(id)
+	if err != nil || current == nil {
+		return err
+	}
+	if state, ok := current.Properties[properties.State]; !ok || fmt.Sprint(state) != from {
+		return nil
+	}
+	updated := graph.InitResource(current.Type(), id)
+	updated.Properties[properties.State] = to
+	return d.graph.UpdateResource(updated)
+}
+
+func (d *Driver) newId(entity string, params map[string]interface{}) (string, error) {
+	if name, ok := params["name"]; ok && nameIdentified[entity] {
+		id := fmt.Sprint(name)
+		if existing, _ := d.graph.FindResource(id); existing != nil {
+			return "", fmt.Errorf("%s already exists", id)
+		}
+		return id, nil
+	}
+	for {
+		id := fmt.Sprintf("%s-%d", prefix(entity), d.next(entity))
+		if service, ok := arnIdentified[entity]; ok {
+			id = fmt.Sprintf("arn:aws:%s::%s:%s/%s", service, simulatedAccount, entity, id)
+		}
+		if existing, _ := d.graph.FindResource(id); existing == nil && d.findRecordById(id) == nil {
+			return id, nil
+		}
+	}
+}
+
+func (d *Driver) next(key string) int {
+	d.counter[key]++
+	return d.counter[key]
+}
+
+// findRecord returns the record whose params contain the given ones, or whose id is given
+// as id, association or attachment param (i.e. the revert of an attach routetable)
+func (d *Driver) findRecord(action, entity string, params map[string]interface{}) *record {
+	for _, rec := range d.records {
+		if rec.Action == action && rec.Entity == entity && rec.matches(params) {
+			return rec
+		}
+	}
+	return nil
+}
+
+func (d *Driver) findRecordById(id string) *record {
+	for _, rec := range d.records {
+		if rec.Id == id {
+			return rec
+		}
+	}
+	return nil
+}
+
+func (d *Driver) removeRecord(rec *record) {
+	for i, r := range d.records {
+		if r == rec {
+			d.records = append(d.records[:i], d.records[i+1:]...)
+			return
+		}
+	}
+}
+
+func (r *record) matches(params map[string]interface{}) bool {
+	for k, v := range params {
+		if (k == "id" || k == "association" || k == "attachment") && fmt.Sprint(v) == r.Id {
+			continue
+		}
+		if recorded, ok := r.Params[k]; !ok || fmt.Sprint(recorded) != fmt.Sprint(v) {
+			return false
+		}
+	}
+	return true
+}
+
+func prefix(entity string) string {
+	if p, ok := idPrefixes[entity]; ok {
+		return p
+	}
+	return entity
+}
+
+func identifiers(params map[string]interface{}) map[string]interface{} {
+	ids := make(map[string]interface{})
+	for _, k := range identifierParams {
+		if v, ok := params[k]; ok {
+			ids[k] = v
+		}
+	}
+	return ids
+}
+
+// propertyLabels indexes the properties labels by their lowercased name
+var propertyLabels = func() map[string]string {
+	labels := make(map[string]string)
+	for label := range rdf.Labels {
+		labels[strings.ToLower(label)] = label
+	}
+	return labels
+}()
+
+// setProperties sets the properties matching the params, converting values to the property
+// data type. Params without matching property or with an incompatible value are ignored.
+func setProperties(res *graph.Resource, params map[string]interface{}) {
+	for k, v := range params {
+		if k == "id" {
+			continue
+		}
+		label, ok := paramProperties[k]
+		if !ok {
+			if label, ok = propertyLabels[strings.Replace(k, "-", "", -1)]; !ok {
+				continue
+			}
+		}
+		if value, ok := propertyValue(rdf.Labels[label], v); ok {
+			res.Properties[label] = value
+		}
+	}
+}
+
+func propertyValue(propId string, v interface{}) (interface{}, bool) {
+	definedBy, err := rdf.Properties.GetDefinedBy(propId)
+	if err != nil {
+		return nil, false
+	}
+	dataType, err := rdf.Properties.GetDataType(propId)
+	if err != nil {
+		return nil, false
+	}
+	_, isList := v.([]interface{})
+
+	switch {
+	case definedBy == rdf.RdfsList && (dataType == rdf.XsdString || dataType == rdf.RdfsClass):
+		return stringList(v), true
+	case definedBy == rdf.RdfsList || isList:
+		return nil, false
+	case dataType == rdf.XsdBoolean:
+		b, err := strconv.ParseBool(fmt.Sprint(v))
+		return b, err == nil
+	case dataType == rdf.XsdInt:
+		i, err := strconv.Atoi(fmt.Sprint(v))
+		return i, err == nil
+	case dataType == rdf.XsdString:
+		return fmt.Sprint(v), true
+	default:
+		return nil, false
+	}
+}
+
+func stringList(v interface{}) (list []string) {
+	switch vv := v.(type) {
+	case []interface{}:
+		for _, elem := range vv {
+			list = append(list, fmt.Sprint(elem))
+		}
+	case []string:
+		list = vv
+	default:
+		list = append(list, fmt.Sprint(vv))
+	}
+	return
+}
+
+func sortedKeys(params map[string]interface{}) (keys []string) {
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return
+}
+
+func formatParams(params map[string]interface{}) string {
+	var pairs []string
+	for _, k := range sortedKeys(params) {
+		pairs = append(pairs, fmt.Sprintf("%s=%v", k, params[k]))
+	}
+	return strings.Join(pairs, " ")
+}
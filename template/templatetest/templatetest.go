@@ -0,0 +1,167 @@
+// Package templatetest runs templates against an in-memory driver simulating their
+// commands on a graph of resources, so that templates can be tested without a cloud
+// account: tests assert on the resulting resources and on the revertibility of the run.
+package templatetest
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	awsdriver "github.com/wallix/awless/aws/driver"
+	"github.com/wallix/awless/cloud/rdf"
+	"github.com/wallix/awless/graph"
+	"github.com/wallix/awless/template"
+	tstore "github.com/wallix/triplestore"
+)
+
+// Result is a template run against the simulated resources
+type Result struct {
+	// Template is the executed template, holding the commands results and errors
+	Template *template.Template
+	// Graph holds the simulated resources after the run
+	Graph *graph.Graph
+
+	initial *graph.Graph
+	driver  *Driver
+	env     *template.Env
+}
+
+// Run compiles the template, dry runs it and runs it against a simulation of the resources of
+// the given graph (left untouched, an empty graph when nil). The env holds the fillers and the
+// functions used to compile the template (AWS definitions, and aliases resolved by name in the graph by default).
+func Run(tpl *template.Template, g *graph.Graph, env *template.Env) (*Result, error) {
+	if g == nil {
+		g = graph.NewGraph()
+	}
+	current := graph.NewGraph()
+	current.AddGraph(g)
+	d := NewDriver(current)
+
+	if env == nil {
+		env = template.NewEnv()
+	}
+	if env.DefLookupFunc == nil {
+		env.DefLookupFunc = awsdriver.AWSLookupDefinitions
+	}
+	if env.AliasFunc == nil {
+		env.AliasFunc = d.ResolveAlias
+	}
+	env.Driver = d
+	env.FetchGraphFunc = d.FetchGraph
+
+	compiled, env, err := template.Compile(tpl, env)
+	if err != nil {
+		return nil, err
+	}
+	if err = compiled.DryRun(env); err != nil {
+		return nil, fmt.Errorf("dry run: %s", err)
+	}
+	executed, err := compiled.Run(env)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Result{Template: executed, Graph: current, initial: g, driver: d, env: env}, nil
+}
+
+// Err returns the errors of the commands that failed during the run
+func (r *Result) Err() error {
+	return commandErrors(r.Template)
+}
+
+// Revert runs the revert of the executed template against the simulated resources,
+// updating the result graph, and returns the executed revert template
+func (r *Result) Revert() (*template.Template, error) {
+	if !template.IsRevertible(r.Template) {
+		return nil, errors.New("template is not revertible")
+	}
+	reverted, err := r.Template.Revert()
+	if err != nil {
+		return nil, err
+	}
+
+	env := template.NewEnv()
+	env.DefLookupFunc = r.env.DefLookupFunc
+	env.Driver = r.driver
+	env.FetchGraphFunc = r.driver.FetchGraph
+
+	if reverted, _, err = template.Compile(reverted, env); err != nil {
+		return nil, fmt.Errorf("revert: %s", err)
+	}
+	executed, err := reverted.Run(env)
+	if err != nil {
+		return executed, fmt.Errorf("revert: %s", err)
+	}
+	if err = commandErrors(executed); err != nil {
+		return executed, fmt.Errorf("revert: %s", err)
+	}
+	return executed, nil
+}
+
+// CheckRevertible reverts the executed template and checks that the simulated resources
+// are back to their state before the run
+func (r *Result) CheckRevertible() error {
+	if _, err := r.Revert(); err != nil {
+		return err
+	}
+	if changes := Changes(r.initial, r.Graph); len(changes) > 0 {
+		return fmt.Errorf("resources not restored by revert: %s", strings.Join(changes, ", "))
+	}
+	return nil
+}
+
+// Changes lists the resources extra, missing or changed in a graph compared to another one
+// (i.e. "extra instance i-1", "changed subnet subnet-1")
+func Changes(from, to *graph.Graph) (changes []string) {
+	fromSnap, toSnap := from.AsRDFGraphSnaphot(), to.AsRDFGraphSnaphot()
+	subjects := make(map[string]bool)
+	for _, tri := range fromSnap.Triples() {
+		if !toSnap.Contains(tri) {
+			subjects[tri.Subject()] = true
+		}
+	}
+	for _, tri := range toSnap.Triples() {
+		if !fromSnap.Contains(tri) {
+			subjects[tri.Subject()] = true
+		}
+	}
+
+	for subject := range subjects {
+		fromType, inFrom := resourceType(fromSnap.WithSubjPred(subject, rdf.RdfType))
+		toType, inTo := resourceType(toSnap.WithSubjPred(subject, rdf.RdfType))
+		switch {
+		case inFrom && !inTo:
+			changes = append(changes, fmt.Sprintf("missing %s %s", fromType, subject))
+		case !inFrom && inTo:
+			changes = append(changes, fmt.Sprintf("extra %s %s", toType, subject))
+		default:
+			changes = append(changes, fmt.Sprintf("changed %s %s", toType, subject))
+		}
+	}
+	sort.Strings(changes)
+	return
+}
+
+func resourceType(typeTriples []tstore.Triple) (string, bool) {
+	for _, tri := range typeTriples {
+		if typ, ok := tri.Object().Resource(); ok {
+			return strings.ToLower(typ[strings.Index(typ, ":")+1:]), true
+		}
+	}
+	return "resource", false
+}
+
+func commandErrors(tpl *template.Template) error {
+	var errs []string
+	for _, cmd := range tpl.CommandNodesIterator() {
+		if err := cmd.Err(); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	return nil
+}
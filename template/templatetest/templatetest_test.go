@@ -0,0 +1,191 @@
+package templatetest
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/wallix/awless/cloud/properties"
+	"github.com/wallix/awless/graph"
+	"github.com/wallix/awless/graph/resourcetest"
+	"github.com/wallix/awless/template"
+)
+
+func TestRunTemplate(t *testing.T) {
+	text := `vpc = create vpc cidr=10.0.0.0/16 name=myvpc
+subnet = create subnet cidr={subnet.cidr} vpc=$vpc name=mysubnet
+update subnet id=$subnet public=true
+sg = create securitygroup vpc=$vpc description=web name=websg
+update securitygroup id=$sg inbound=authorize protocol=tcp cidr=0.0.0.0/0 portrange=443
+keypair = create keypair name=mykey encrypted=true
+inst = create instance subnet=$subnet image=ami-1234 type=t2.micro count=1 keypair=$keypair name=web securitygroup=$sg
+create tag resource=$inst key=Env value=test`
+
+	env := template.NewEnv()
+	env.AddFillers(map[string]interface{}{"subnet.cidr": "10.0.1.0/24"})
+	result, err := Run(template.MustParse(text), nil, env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = result.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	var results []interface{}
+	for _, cmd := range result.Template.CommandNodesIterator() {
+		results = append(results, cmd.Result())
+	}
+	if got, want := results, []interface{}{"vpc-1", "subnet-1", "subnet-1", "sg-1", "sg-1", "mykey", "i-1", "tag-1"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	inst, err := result.Graph.GetResource("instance", "i-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for k, want := range map[string]interface{}{"Name": "web", "Type": "t2.micro", "Image": "ami-1234", "State": "running", "Subnet": "subnet-1"} {
+		if got := inst.Properties[k]; got != want {
+			t.Fatalf("%s: got %v, want %v", k, got, want)
+		}
+	}
+	sub, err := result.Graph.GetResource("subnet", "subnet-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := sub.Properties[properties.Public], true; got != want {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if parent := result.Graph.FindAncestor(inst, "vpc"); parent == nil || parent.Id() != "vpc-1" {
+		t.Fatalf("expected vpc-1 ancestor of instance, got %v", parent)
+	}
+	applied, err := result.Graph.ListResourcesDependingOn(inst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := graph.Resources(applied).Map(func(r *graph.Resource) string { return r.Id() }), []string{"mykey", "sg-1"}; !sameElements(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	if err := result.CheckRevertible(); err != nil {
+		t.Fatal(err)
+	}
+	if all, _ := result.Graph.GetAllResources("vpc", "subnet", "instance", "securitygroup", "keypair"); len(all) != 0 {
+		t.Fatalf("expected no resources after revert, got %v", all)
+	}
+}
+
+func TestRunOnExistingResources(t *testing.T) {
+	newGraph := func() *graph.Graph {
+		g := graph.NewGraph()
+		g.AddResource(
+			resourcetest.VPC("vpc-1").Build(),
+			resourcetest.Subnet("subnet-1").Prop(properties.Name, "private").Prop(properties.Public, false).Build(),
+			resourcetest.Instance("i-1").Prop(properties.Name, "db").Prop(properties.Type, "t2.micro").Prop(properties.State, "running").Build(),
+			resourcetest.Volume("vol-1").Prop(properties.State, "available").Build(),
+		)
+		g.AddParentRelation(resourcetest.VPC("vpc-1").Build(), resourcetest.Subnet("subnet-1").Build())
+		g.AddParentRelation(resourcetest.Subnet("subnet-1").Build(), resourcetest.Instance("i-1").Build())
+		return g
+	}
+
+	t.Run("revertible updates, attachments and aliases", func(t *testing.T) {
+		g := newGraph()
+		text := `stop instance id=@db
+update instance id=@db type=t2.large
+start instance id=@db
+attach volume id=vol-1 instance=@db device=/dev/sdh
+update subnet id=@private public=true`
+		result, err := Run(template.MustParse(text), g, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = result.Err(); err != nil {
+			t.Fatal(err)
+		}
+		inst, _ := result.Graph.GetResource("instance", "i-1")
+		if got, want := inst.Properties[properties.Type], "t2.large"; got != want {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+		vol, _ := result.Graph.GetResource("volume", "vol-1")
+		if got, want := vol.Properties[properties.State], "in-use"; got != want {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+		if err := result.CheckRevertible(); err != nil {
+			t.Fatal(err)
+		}
+		if changes := Changes(g, newGraph()); len(changes) != 0 {
+			t.Fatalf("given graph should be left untouched, got %v", changes)
+		}
+	})
+
+	t.Run("not revertible", func(t *testing.T) {
+		result, err := Run(template.MustParse("delete instance id=i-1"), newGraph(), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = result.Err(); err != nil {
+			t.Fatal(err)
+		}
+		if res, _ := result.Graph.FindResource("i-1"); res != nil {
+			t.Fatalf("expected instance deleted, got %v", res)
+		}
+		if err := result.CheckRevertible(); err == nil || !strings.Contains(err.Error(), "not revertible") {
+			t.Fatalf("expected not revertible error, got %v", err)
+		}
+	})
+
+	t.Run("failures", func(t *testing.T) {
+		tcases := []struct {
+			tpl, expErr string
+		}{
+			{"delete vpc id=vpc-1", "delete vpc: vpc-1 has dependent resources: subnet-1"},
+			{"delete instance id=i-2", "delete instance: id=i-2 not found"},
+			{"create keypair name=mykey\ncreate keypair name=mykey", "create keypair: mykey already exists"},
+			{"check instance id=i-1 state=stopped timeout=10", "check instance: i-1 is in state running, expecting stopped"},
+			{"detach volume id=vol-2 instance=i-1 device=/dev/sdh", "no attachment matching device=/dev/sdh id=vol-2 instance=i-1"},
+		}
+		for i, tcase := range tcases {
+			result, err := Run(template.MustParse(tcase.tpl), newGraph(), nil)
+			if err != nil {
+				t.Fatalf("%d: %s", i+1, err)
+			}
+			if err = result.Err(); err == nil || !strings.Contains(err.Error(), tcase.expErr) {
+				t.Fatalf("%d: got %v, want error containing %q", i+1, err, tcase.expErr)
+			}
+		}
+	})
+
+	t.Run("update without known prior state", func(t *testing.T) {
+		g := newGraph()
+		g.UpdateResource(resourcetest.Instance("i-1").Prop(properties.Type, nil).Build())
+		result, err := Run(template.MustParse("update instance id=i-1 type=t2.large"), g, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := result.CheckRevertible(); err == nil || !strings.Contains(err.Error(), "not revertible") {
+			t.Fatalf("expected not revertible error, got %v", err)
+		}
+		if got, want := Changes(g, result.Graph), []string{"changed instance i-1"}; !reflect.DeepEqual(got, want) {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	})
+}
+
+func sameElements(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	count := make(map[string]int)
+	for _, s := range a {
+		count[s]++
+	}
+	for _, s := range b {
+		count[s]--
+	}
+	for _, c := range count {
+		if c != 0 {
+			return false
+		}
+	}
+	return true
+}
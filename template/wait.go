@@ -0,0 +1,160 @@
+package template
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wallix/awless/graph"
+	"github.com/wallix/awless/template/internal/ast"
+)
+
+const (
+	waitAction = "wait"
+
+	defaultWaitTimeout  = 180 * time.Second
+	defaultWaitInterval = 5 * time.Second
+	waitNotFound        = "not-found"
+)
+
+// waitDefinition is the definition of the built-in wait command, available for all entities.
+// Besides its params, the command expects the resource properties to wait for (i.e. state=running).
+func waitDefinition(entity string) Definition {
+	return Definition{
+		Action:         waitAction,
+		Entity:         entity,
+		RequiredParams: []string{"id"},
+		ExtraParams:    []string{"timeout", "interval"},
+		ParamTypes: map[string]ParamType{
+			"timeout":  {Kind: DurationParam},
+			"interval": {Kind: DurationParam},
+		},
+	}
+}
+
+// lookupDefinition returns the definition of the command, resolving the built-in commands
+// before calling the lookup function.
+func lookupDefinition(lookup DefinitionLookupFunc, cmd *ast.CommandNode) (Definition, bool) {
+	if cmd.Action == waitAction {
+		return waitDefinition(cmd.Entity), true
+	}
+	return lookup(fmt.Sprintf("%s%s", cmd.Action, cmd.Entity))
+}
+
+// runWait polls the resource until its properties match the expected ones.
+// Expecting 'not-found' as a property value waits for the resource deletion.
+func runWait(n *ast.CommandNode, env *Env) (interface{}, error) {
+	params := n.ToDriverParams()
+	id, ok := params["id"]
+	if !ok {
+		return nil, fmt.Errorf("wait %s: missing required param 'id'", n.Entity)
+	}
+
+	expected := make(map[string]string)
+	for k, v := range params {
+		if k != "id" && k != "timeout" && k != "interval" {
+			expected[k] = fmt.Sprint(v)
+		}
+	}
+	if len(expected) == 0 {
+		return nil, fmt.Errorf("wait %s: missing properties to wait for (i.e. state=running)", n.Entity)
+	}
+
+	timeout, err := waitDuration(params, "timeout", defaultWaitTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("wait %s: %s", n.Entity, err)
+	}
+	interval, err := waitDuration(params, "interval", defaultWaitInterval)
+	if err != nil {
+		return nil, fmt.Errorf("wait %s: %s", n.Entity, err)
+	}
+	if interval <= 0 {
+		return nil, fmt.Errorf("wait %s: interval must be greater than 0", n.Entity)
+	}
+
+	if env.dryRun {
+		return id, nil
+	}
+	if env.FetchGraphFunc == nil {
+		return nil, errors.New("wait: fetch graph function is undefined")
+	}
+
+	for waited := time.Duration(0); ; waited += interval {
+		res, err := fetchResource(env, n.Entity, fmt.Sprint(id))
+		if err != nil {
+			return nil, fmt.Errorf("wait %s %s: %s", n.Entity, id, err)
+		}
+		var props map[string]interface{}
+		if res != nil {
+			props = res.Properties
+		}
+		if matchWaitedProperties(props, res != nil, expected) {
+			return id, nil
+		}
+		if waited+interval > timeout {
+			return nil, fmt.Errorf("wait %s %s: timeout of %s exceeded waiting for %s", n.Entity, id, timeout, formatWaitedProperties(expected))
+		}
+		env.sleep(interval)
+	}
+}
+
+// fetchResource returns the current state of the resource, or nil when not found
+func fetchResource(env *Env, entity, id string) (*graph.Resource, error) {
+	g, err := env.FetchGraphFunc(entity)
+	if err != nil {
+		return nil, err
+	}
+	return g.FindResource(id)
+}
+
+func matchWaitedProperties(props map[string]interface{}, found bool, expected map[string]string) bool {
+	for key, want := range expected {
+		if !found {
+			if want != waitNotFound {
+				return false
+			}
+			continue
+		}
+		if want == waitNotFound {
+			return false
+		}
+		var matched bool
+		for name, value := range props {
+			if strings.EqualFold(name, key) && strings.EqualFold(fmt.Sprint(value), want) {
+				matched = true
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+func formatWaitedProperties(expected map[string]string) string {
+	var props []string
+	for k, v := range expected {
+		props = append(props, fmt.Sprintf("%s=%s", k, v))
+	}
+	sort.Strings(props)
+	return strings.Join(props, " ")
+}
+
+func waitDuration(params map[string]interface{}, key string, defaultDuration time.Duration) (time.Duration, error) {
+	value, ok := params[key]
+	if !ok {
+		return defaultDuration, nil
+	}
+	str := fmt.Sprint(value)
+	if secs, err := strconv.Atoi(str); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, nil
+	}
+	d, err := time.ParseDuration(str)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid %s '%s'", key, str)
+	}
+	return d, nil
+}
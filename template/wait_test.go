@@ -0,0 +1,110 @@
+package template
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wallix/awless/graph"
+)
+
+func TestRunWait(t *testing.T) {
+	states := func(values ...string) func(entity string) (*graph.Graph, error) {
+		return func(entity string) (*graph.Graph, error) {
+			if len(values) == 0 {
+				return nil, errors.New("no more state")
+			}
+			state := values[0]
+			values = values[1:]
+			g := graph.NewGraph()
+			if state != "" {
+				res := graph.InitResource(entity, "i-1234")
+				res.Properties["State"] = state
+				g.AddResource(res)
+			}
+			return g, nil
+		}
+	}
+
+	tcases := []struct {
+		input     string
+		fetch     func(entity string) (*graph.Graph, error)
+		expSlept  time.Duration
+		expErr    string
+		expResult interface{}
+	}{
+		{
+			input:     "wait instance id=i-1234 state=running",
+			fetch:     states("pending", "pending", "Running"),
+			expSlept:  10 * time.Second,
+			expResult: "i-1234",
+		},
+		{
+			input:     "wait instance id=i-1234 state=not-found interval=1s",
+			fetch:     states("terminated", ""),
+			expSlept:  time.Second,
+			expResult: "i-1234",
+		},
+		{
+			input:    "wait instance id=i-1234 state=running timeout=10 interval=4s",
+			fetch:    states("pending", "pending", "pending", "pending"),
+			expSlept: 8 * time.Second,
+			expErr:   "wait instance i-1234: timeout of 10s exceeded waiting for state=running",
+		},
+		{
+			input:  "wait instance id=i-1234 state=running",
+			fetch:  states(),
+			expErr: "wait instance i-1234: no more state",
+		},
+		{
+			input:  "wait instance id=i-1234",
+			fetch:  states(),
+			expErr: "wait instance: missing properties to wait for",
+		},
+	}
+
+	for i, tcase := range tcases {
+		var slept time.Duration
+		env := &Env{FetchGraphFunc: tcase.fetch, sleepFunc: func(d time.Duration) { slept += d }}
+
+		ran, err := MustParse(tcase.input).Run(env)
+		if err != nil {
+			t.Fatalf("%d: %s", i+1, err)
+		}
+		cmd := ran.CommandNodesIterator()[0]
+		if got, want := slept, tcase.expSlept; got != want {
+			t.Fatalf("%d: slept %s, want %s", i+1, got, want)
+		}
+		if tcase.expErr != "" {
+			if cmd.Err() == nil || !strings.Contains(cmd.Err().Error(), tcase.expErr) {
+				t.Fatalf("%d: got %v, want error containing '%s'", i+1, cmd.Err(), tcase.expErr)
+			}
+			continue
+		}
+		if cmd.Err() != nil {
+			t.Fatalf("%d: %s", i+1, cmd.Err())
+		}
+		if got, want := cmd.Result(), tcase.expResult; got != want {
+			t.Fatalf("%d: got %v, want %v", i+1, got, want)
+		}
+	}
+}
+
+func TestCompileWaitCommand(t *testing.T) {
+	env := NewEnv()
+	env.DefLookupFunc = func(string) (Definition, bool) { return Definition{}, false }
+
+	compiled, _, err := Compile(MustParse("wait instance id=i-1234 state=running timeout=5m"), env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := compiled.String(), "wait instance id=i-1234 state=running timeout=300"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+
+	_, _, err = Compile(MustParse("wait instance id=i-1234 state=running timeout=later"), env)
+	if err == nil || !strings.Contains(err.Error(), "invalid value 'later' for param 'timeout'") {
+		t.Fatalf("got %v", err)
+	}
+}
@@ -5,14 +5,17 @@ import (
 	"fmt"
 	"html/template"
 	"io"
+	"io/ioutil"
 	"log"
 	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/gorilla/mux"
 	"github.com/wallix/awless/aws/services"
 	"github.com/wallix/awless/graph"
+	"github.com/wallix/awless/graph/query/sparql"
 	"github.com/wallix/awless/sync"
 	"github.com/wallix/awless/sync/repo"
 	tstore "github.com/wallix/triplestore"
@@ -45,6 +48,7 @@ func (s *server) routes() http.Handler {
 	r.HandleFunc("/resources", s.listResourcesHandler)
 	r.HandleFunc("/rdf", s.rdfHandler)
 	r.HandleFunc("/graph", s.graphHandler)
+	r.HandleFunc("/sparql", s.sparqlHandler)
 	r.HandleFunc("/", s.homeHandler)
 	return r
 }
@@ -104,6 +108,47 @@ func (s *server) graphHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// sparqlHandler follows the SPARQL protocol: the query is given with the 'query' parameter
+// or as a POST body of type application/sparql-query. Results are in JSON, or in CSV when accepted.
+func (s *server) sparqlHandler(w http.ResponseWriter, r *http.Request) {
+	text := r.FormValue("query")
+	if r.Method == http.MethodPost && strings.HasPrefix(r.Header.Get("Content-Type"), "application/sparql-query") {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		text = string(body)
+	}
+	if strings.TrimSpace(text) == "" {
+		http.Error(w, "missing SPARQL query", http.StatusBadRequest)
+		return
+	}
+
+	q, err := sparql.Parse(text)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	result, err := q.Run(s.gph)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if strings.Contains(r.Header.Get("Accept"), "text/csv") {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		err = result.WriteCSV(w)
+	} else {
+		w.Header().Set("Content-Type", "application/sparql-results+json")
+		err = result.WriteJSON(w)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
 func (s *server) showResourceHandler(w http.ResponseWriter, r *http.Request) {
 	t, err := template.New("show").Parse(showResourceTpl)
 	if err != nil {
@@ -227,6 +272,7 @@ const homeTpl = `<!DOCTYPE html>
 	<li><a href="/rdf">View RDF</a></li>
 	<li><a href="/rdf?namespaced=true">View namespaced RDF</a></li>
 	<li><a href="/graph">View DOT graph (experimental)</a></li>
+	<li><a href="/sparql?query=SELECT+%3Fs+%3Ftype+WHERE+%7B+%3Fs+a+%3Ftype+%7D+LIMIT+20">SPARQL endpoint (example query)</a></li>
 	</ul>
 	</body>
 </html>`